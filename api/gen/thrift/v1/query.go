@@ -38,6 +38,13 @@ var _ = regexp.MatchString
 //   - SrcPort
 //   - DstPort
 //   - Protocol
+//   - ConnState
+//   - TunnelID
+//   - OuterVlan
+//   - InnerVlan
+//   - MplsLabel
+//   - InterfaceID
+//   - ExporterIP
 type AggregationRequest struct {
 	EndTimeUnixNano *int64  `thrift:"end_time_unix_nano,1" db:"end_time_unix_nano" json:"end_time_unix_nano,omitempty"`
 	TaskName        *string `thrift:"task_name,2" db:"task_name" json:"task_name,omitempty"`
@@ -46,6 +53,13 @@ type AggregationRequest struct {
 	SrcPort         *int32  `thrift:"src_port,5" db:"src_port" json:"src_port,omitempty"`
 	DstPort         *int32  `thrift:"dst_port,6" db:"dst_port" json:"dst_port,omitempty"`
 	Protocol        *int32  `thrift:"protocol,7" db:"protocol" json:"protocol,omitempty"`
+	ConnState       *string `thrift:"conn_state,8" db:"conn_state" json:"conn_state,omitempty"`
+	TunnelID        *int64  `thrift:"tunnel_id,9" db:"tunnel_id" json:"tunnel_id,omitempty"`
+	OuterVlan       *int32  `thrift:"outer_vlan,10" db:"outer_vlan" json:"outer_vlan,omitempty"`
+	InnerVlan       *int32  `thrift:"inner_vlan,11" db:"inner_vlan" json:"inner_vlan,omitempty"`
+	MplsLabel       *int32  `thrift:"mpls_label,12" db:"mpls_label" json:"mpls_label,omitempty"`
+	InterfaceID     *int64  `thrift:"interface_id,13" db:"interface_id" json:"interface_id,omitempty"`
+	ExporterIP      *string `thrift:"exporter_ip,14" db:"exporter_ip" json:"exporter_ip,omitempty"`
 }
 
 func NewAggregationRequest() *AggregationRequest {
@@ -115,6 +129,69 @@ func (p *AggregationRequest) GetProtocol() int32 {
 	return *p.Protocol
 }
 
+var AggregationRequest_ConnState_DEFAULT string
+
+func (p *AggregationRequest) GetConnState() string {
+	if !p.IsSetConnState() {
+		return AggregationRequest_ConnState_DEFAULT
+	}
+	return *p.ConnState
+}
+
+var AggregationRequest_TunnelID_DEFAULT int64
+
+func (p *AggregationRequest) GetTunnelID() int64 {
+	if !p.IsSetTunnelID() {
+		return AggregationRequest_TunnelID_DEFAULT
+	}
+	return *p.TunnelID
+}
+
+var AggregationRequest_OuterVlan_DEFAULT int32
+
+func (p *AggregationRequest) GetOuterVlan() int32 {
+	if !p.IsSetOuterVlan() {
+		return AggregationRequest_OuterVlan_DEFAULT
+	}
+	return *p.OuterVlan
+}
+
+var AggregationRequest_InnerVlan_DEFAULT int32
+
+func (p *AggregationRequest) GetInnerVlan() int32 {
+	if !p.IsSetInnerVlan() {
+		return AggregationRequest_InnerVlan_DEFAULT
+	}
+	return *p.InnerVlan
+}
+
+var AggregationRequest_MplsLabel_DEFAULT int32
+
+func (p *AggregationRequest) GetMplsLabel() int32 {
+	if !p.IsSetMplsLabel() {
+		return AggregationRequest_MplsLabel_DEFAULT
+	}
+	return *p.MplsLabel
+}
+
+var AggregationRequest_InterfaceID_DEFAULT int64
+
+func (p *AggregationRequest) GetInterfaceID() int64 {
+	if !p.IsSetInterfaceID() {
+		return AggregationRequest_InterfaceID_DEFAULT
+	}
+	return *p.InterfaceID
+}
+
+var AggregationRequest_ExporterIP_DEFAULT string
+
+func (p *AggregationRequest) GetExporterIP() string {
+	if !p.IsSetExporterIP() {
+		return AggregationRequest_ExporterIP_DEFAULT
+	}
+	return *p.ExporterIP
+}
+
 func (p *AggregationRequest) IsSetEndTimeUnixNano() bool {
 	return p.EndTimeUnixNano != nil
 }
@@ -143,6 +220,34 @@ func (p *AggregationRequest) IsSetProtocol() bool {
 	return p.Protocol != nil
 }
 
+func (p *AggregationRequest) IsSetConnState() bool {
+	return p.ConnState != nil
+}
+
+func (p *AggregationRequest) IsSetTunnelID() bool {
+	return p.TunnelID != nil
+}
+
+func (p *AggregationRequest) IsSetOuterVlan() bool {
+	return p.OuterVlan != nil
+}
+
+func (p *AggregationRequest) IsSetInnerVlan() bool {
+	return p.InnerVlan != nil
+}
+
+func (p *AggregationRequest) IsSetMplsLabel() bool {
+	return p.MplsLabel != nil
+}
+
+func (p *AggregationRequest) IsSetInterfaceID() bool {
+	return p.InterfaceID != nil
+}
+
+func (p *AggregationRequest) IsSetExporterIP() bool {
+	return p.ExporterIP != nil
+}
+
 func (p *AggregationRequest) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -227,6 +332,76 @@ func (p *AggregationRequest) Read(ctx context.Context, iprot thrift.TProtocol) e
 					return err
 				}
 			}
+		case 8:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField8(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 9:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField9(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 10:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField10(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 11:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField11(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 12:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField12(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 13:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField13(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 14:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField14(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
 				return err
@@ -305,6 +480,69 @@ func (p *AggregationRequest) ReadField7(ctx context.Context, iprot thrift.TProto
 	return nil
 }
 
+func (p *AggregationRequest) ReadField8(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.ConnState = &v
+	}
+	return nil
+}
+
+func (p *AggregationRequest) ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 9: ", err)
+	} else {
+		p.TunnelID = &v
+	}
+	return nil
+}
+
+func (p *AggregationRequest) ReadField10(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 10: ", err)
+	} else {
+		p.OuterVlan = &v
+	}
+	return nil
+}
+
+func (p *AggregationRequest) ReadField11(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 11: ", err)
+	} else {
+		p.InnerVlan = &v
+	}
+	return nil
+}
+
+func (p *AggregationRequest) ReadField12(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 12: ", err)
+	} else {
+		p.MplsLabel = &v
+	}
+	return nil
+}
+
+func (p *AggregationRequest) ReadField13(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 13: ", err)
+	} else {
+		p.InterfaceID = &v
+	}
+	return nil
+}
+
+func (p *AggregationRequest) ReadField14(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 14: ", err)
+	} else {
+		p.ExporterIP = &v
+	}
+	return nil
+}
+
 func (p *AggregationRequest) Write(ctx context.Context, oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin(ctx, "AggregationRequest"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -331,6 +569,27 @@ func (p *AggregationRequest) Write(ctx context.Context, oprot thrift.TProtocol)
 		if err := p.writeField7(ctx, oprot); err != nil {
 			return err
 		}
+		if err := p.writeField8(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField9(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField10(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField11(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField12(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField13(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField14(ctx, oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(ctx); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -446,6 +705,111 @@ func (p *AggregationRequest) writeField7(ctx context.Context, oprot thrift.TProt
 	return err
 }
 
+func (p *AggregationRequest) writeField8(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetConnState() {
+		if err := oprot.WriteFieldBegin(ctx, "conn_state", thrift.STRING, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:conn_state: ", p), err)
+		}
+		if err := oprot.WriteString(ctx, string(*p.ConnState)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.conn_state (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:conn_state: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *AggregationRequest) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetTunnelID() {
+		if err := oprot.WriteFieldBegin(ctx, "tunnel_id", thrift.I64, 9); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:tunnel_id: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.TunnelID)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.tunnel_id (9) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 9:tunnel_id: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *AggregationRequest) writeField10(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetOuterVlan() {
+		if err := oprot.WriteFieldBegin(ctx, "outer_vlan", thrift.I32, 10); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 10:outer_vlan: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.OuterVlan)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.outer_vlan (10) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 10:outer_vlan: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *AggregationRequest) writeField11(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetInnerVlan() {
+		if err := oprot.WriteFieldBegin(ctx, "inner_vlan", thrift.I32, 11); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 11:inner_vlan: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.InnerVlan)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.inner_vlan (11) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 11:inner_vlan: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *AggregationRequest) writeField12(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetMplsLabel() {
+		if err := oprot.WriteFieldBegin(ctx, "mpls_label", thrift.I32, 12); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 12:mpls_label: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.MplsLabel)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.mpls_label (12) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 12:mpls_label: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *AggregationRequest) writeField13(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetInterfaceID() {
+		if err := oprot.WriteFieldBegin(ctx, "interface_id", thrift.I64, 13); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 13:interface_id: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.InterfaceID)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.interface_id (13) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 13:interface_id: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *AggregationRequest) writeField14(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetExporterIP() {
+		if err := oprot.WriteFieldBegin(ctx, "exporter_ip", thrift.STRING, 14); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 14:exporter_ip: ", p), err)
+		}
+		if err := oprot.WriteString(ctx, string(*p.ExporterIP)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.exporter_ip (14) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 14:exporter_ip: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *AggregationRequest) Equals(other *AggregationRequest) bool {
 	if p == other {
 		return true
@@ -508,6 +872,62 @@ func (p *AggregationRequest) Equals(other *AggregationRequest) bool {
 			return false
 		}
 	}
+	if p.ConnState != other.ConnState {
+		if p.ConnState == nil || other.ConnState == nil {
+			return false
+		}
+		if (*p.ConnState) != (*other.ConnState) {
+			return false
+		}
+	}
+	if p.TunnelID != other.TunnelID {
+		if p.TunnelID == nil || other.TunnelID == nil {
+			return false
+		}
+		if (*p.TunnelID) != (*other.TunnelID) {
+			return false
+		}
+	}
+	if p.OuterVlan != other.OuterVlan {
+		if p.OuterVlan == nil || other.OuterVlan == nil {
+			return false
+		}
+		if (*p.OuterVlan) != (*other.OuterVlan) {
+			return false
+		}
+	}
+	if p.InnerVlan != other.InnerVlan {
+		if p.InnerVlan == nil || other.InnerVlan == nil {
+			return false
+		}
+		if (*p.InnerVlan) != (*other.InnerVlan) {
+			return false
+		}
+	}
+	if p.MplsLabel != other.MplsLabel {
+		if p.MplsLabel == nil || other.MplsLabel == nil {
+			return false
+		}
+		if (*p.MplsLabel) != (*other.MplsLabel) {
+			return false
+		}
+	}
+	if p.InterfaceID != other.InterfaceID {
+		if p.InterfaceID == nil || other.InterfaceID == nil {
+			return false
+		}
+		if (*p.InterfaceID) != (*other.InterfaceID) {
+			return false
+		}
+	}
+	if p.ExporterIP != other.ExporterIP {
+		if p.ExporterIP == nil || other.ExporterIP == nil {
+			return false
+		}
+		if (*p.ExporterIP) != (*other.ExporterIP) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -540,11 +960,21 @@ func (p *AggregationRequest) Validate() error {
 //   - TotalBytes
 //   - TotalPackets
 //   - FlowCount
+//   - SynCount
+//   - FinCount
+//   - RstCount
+//   - SampleRate
+//   - Estimated
 type TaskSummary struct {
 	TaskName     string `thrift:"task_name,1,required" db:"task_name" json:"task_name"`
 	TotalBytes   int64  `thrift:"total_bytes,2,required" db:"total_bytes" json:"total_bytes"`
 	TotalPackets int64  `thrift:"total_packets,3,required" db:"total_packets" json:"total_packets"`
 	FlowCount    int64  `thrift:"flow_count,4,required" db:"flow_count" json:"flow_count"`
+	SynCount     *int64 `thrift:"syn_count,5" db:"syn_count" json:"syn_count,omitempty"`
+	FinCount     *int64 `thrift:"fin_count,6" db:"fin_count" json:"fin_count,omitempty"`
+	RstCount     *int64 `thrift:"rst_count,7" db:"rst_count" json:"rst_count,omitempty"`
+	SampleRate   *int64 `thrift:"sample_rate,8" db:"sample_rate" json:"sample_rate,omitempty"`
+	Estimated    *bool  `thrift:"estimated,9" db:"estimated" json:"estimated,omitempty"`
 }
 
 func NewTaskSummary() *TaskSummary {
@@ -567,18 +997,83 @@ func (p *TaskSummary) GetFlowCount() int64 {
 	return p.FlowCount
 }
 
-func (p *TaskSummary) Read(ctx context.Context, iprot thrift.TProtocol) error {
-	if _, err := iprot.ReadStructBegin(ctx); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+var TaskSummary_SynCount_DEFAULT int64
+
+func (p *TaskSummary) GetSynCount() int64 {
+	if !p.IsSetSynCount() {
+		return TaskSummary_SynCount_DEFAULT
 	}
+	return *p.SynCount
+}
 
-	var issetTaskName bool = false
-	var issetTotalBytes bool = false
-	var issetTotalPackets bool = false
-	var issetFlowCount bool = false
+var TaskSummary_FinCount_DEFAULT int64
 
-	for {
-		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+func (p *TaskSummary) GetFinCount() int64 {
+	if !p.IsSetFinCount() {
+		return TaskSummary_FinCount_DEFAULT
+	}
+	return *p.FinCount
+}
+
+var TaskSummary_RstCount_DEFAULT int64
+
+func (p *TaskSummary) GetRstCount() int64 {
+	if !p.IsSetRstCount() {
+		return TaskSummary_RstCount_DEFAULT
+	}
+	return *p.RstCount
+}
+
+var TaskSummary_SampleRate_DEFAULT int64
+
+func (p *TaskSummary) GetSampleRate() int64 {
+	if !p.IsSetSampleRate() {
+		return TaskSummary_SampleRate_DEFAULT
+	}
+	return *p.SampleRate
+}
+
+var TaskSummary_Estimated_DEFAULT bool
+
+func (p *TaskSummary) GetEstimated() bool {
+	if !p.IsSetEstimated() {
+		return TaskSummary_Estimated_DEFAULT
+	}
+	return *p.Estimated
+}
+
+func (p *TaskSummary) IsSetSynCount() bool {
+	return p.SynCount != nil
+}
+
+func (p *TaskSummary) IsSetFinCount() bool {
+	return p.FinCount != nil
+}
+
+func (p *TaskSummary) IsSetRstCount() bool {
+	return p.RstCount != nil
+}
+
+func (p *TaskSummary) IsSetSampleRate() bool {
+	return p.SampleRate != nil
+}
+
+func (p *TaskSummary) IsSetEstimated() bool {
+	return p.Estimated != nil
+}
+
+func (p *TaskSummary) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetTaskName bool = false
+	var issetTotalBytes bool = false
+	var issetTotalPackets bool = false
+	var issetFlowCount bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
 		if err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
 		}
@@ -630,6 +1125,56 @@ func (p *TaskSummary) Read(ctx context.Context, iprot thrift.TProtocol) error {
 					return err
 				}
 			}
+		case 5:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField5(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 6:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField6(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 7:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField7(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 8:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField8(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 9:
+			if fieldTypeId == thrift.BOOL {
+				if err := p.ReadField9(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
 				return err
@@ -693,6 +1238,51 @@ func (p *TaskSummary) ReadField4(ctx context.Context, iprot thrift.TProtocol) er
 	return nil
 }
 
+func (p *TaskSummary) ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.SynCount = &v
+	}
+	return nil
+}
+
+func (p *TaskSummary) ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.FinCount = &v
+	}
+	return nil
+}
+
+func (p *TaskSummary) ReadField7(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.RstCount = &v
+	}
+	return nil
+}
+
+func (p *TaskSummary) ReadField8(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.SampleRate = &v
+	}
+	return nil
+}
+
+func (p *TaskSummary) ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(ctx); err != nil {
+		return thrift.PrependError("error reading field 9: ", err)
+	} else {
+		p.Estimated = &v
+	}
+	return nil
+}
+
 func (p *TaskSummary) Write(ctx context.Context, oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin(ctx, "TaskSummary"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -710,6 +1300,21 @@ func (p *TaskSummary) Write(ctx context.Context, oprot thrift.TProtocol) error {
 		if err := p.writeField4(ctx, oprot); err != nil {
 			return err
 		}
+		if err := p.writeField5(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField7(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField8(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField9(ctx, oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(ctx); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -772,6 +1377,81 @@ func (p *TaskSummary) writeField4(ctx context.Context, oprot thrift.TProtocol) (
 	return err
 }
 
+func (p *TaskSummary) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSynCount() {
+		if err := oprot.WriteFieldBegin(ctx, "syn_count", thrift.I64, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:syn_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.SynCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.syn_count (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:syn_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *TaskSummary) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetFinCount() {
+		if err := oprot.WriteFieldBegin(ctx, "fin_count", thrift.I64, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:fin_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.FinCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.fin_count (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:fin_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *TaskSummary) writeField7(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetRstCount() {
+		if err := oprot.WriteFieldBegin(ctx, "rst_count", thrift.I64, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:rst_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.RstCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.rst_count (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:rst_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *TaskSummary) writeField8(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSampleRate() {
+		if err := oprot.WriteFieldBegin(ctx, "sample_rate", thrift.I64, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:sample_rate: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.SampleRate)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.sample_rate (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:sample_rate: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *TaskSummary) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetEstimated() {
+		if err := oprot.WriteFieldBegin(ctx, "estimated", thrift.BOOL, 9); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:estimated: ", p), err)
+		}
+		if err := oprot.WriteBool(ctx, bool(*p.Estimated)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.estimated (9) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 9:estimated: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *TaskSummary) Equals(other *TaskSummary) bool {
 	if p == other {
 		return true
@@ -790,6 +1470,46 @@ func (p *TaskSummary) Equals(other *TaskSummary) bool {
 	if p.FlowCount != other.FlowCount {
 		return false
 	}
+	if p.SynCount != other.SynCount {
+		if p.SynCount == nil || other.SynCount == nil {
+			return false
+		}
+		if (*p.SynCount) != (*other.SynCount) {
+			return false
+		}
+	}
+	if p.FinCount != other.FinCount {
+		if p.FinCount == nil || other.FinCount == nil {
+			return false
+		}
+		if (*p.FinCount) != (*other.FinCount) {
+			return false
+		}
+	}
+	if p.RstCount != other.RstCount {
+		if p.RstCount == nil || other.RstCount == nil {
+			return false
+		}
+		if (*p.RstCount) != (*other.RstCount) {
+			return false
+		}
+	}
+	if p.SampleRate != other.SampleRate {
+		if p.SampleRate == nil || other.SampleRate == nil {
+			return false
+		}
+		if (*p.SampleRate) != (*other.SampleRate) {
+			return false
+		}
+	}
+	if p.Estimated != other.Estimated {
+		if p.Estimated == nil || other.Estimated == nil {
+			return false
+		}
+		if (*p.Estimated) != (*other.Estimated) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -1262,11 +1982,31 @@ func (p *TraceFlowRequest) Validate() error {
 //   - LastSeenUnixNano
 //   - TotalPackets
 //   - TotalBytes
+//   - SynCount
+//   - FinCount
+//   - RstCount
+//   - AckCount
+//   - TCPFlags
+//   - ConnState
+//   - SampleRate
+//   - Estimated
+//   - Lifecycles
+//   - EndReason
 type FlowLifecycle struct {
-	FirstSeenUnixNano int64 `thrift:"first_seen_unix_nano,1,required" db:"first_seen_unix_nano" json:"first_seen_unix_nano"`
-	LastSeenUnixNano  int64 `thrift:"last_seen_unix_nano,2,required" db:"last_seen_unix_nano" json:"last_seen_unix_nano"`
-	TotalPackets      int64 `thrift:"total_packets,3,required" db:"total_packets" json:"total_packets"`
-	TotalBytes        int64 `thrift:"total_bytes,4,required" db:"total_bytes" json:"total_bytes"`
+	FirstSeenUnixNano int64   `thrift:"first_seen_unix_nano,1,required" db:"first_seen_unix_nano" json:"first_seen_unix_nano"`
+	LastSeenUnixNano  int64   `thrift:"last_seen_unix_nano,2,required" db:"last_seen_unix_nano" json:"last_seen_unix_nano"`
+	TotalPackets      int64   `thrift:"total_packets,3,required" db:"total_packets" json:"total_packets"`
+	TotalBytes        int64   `thrift:"total_bytes,4,required" db:"total_bytes" json:"total_bytes"`
+	SynCount          *int64  `thrift:"syn_count,5" db:"syn_count" json:"syn_count,omitempty"`
+	FinCount          *int64  `thrift:"fin_count,6" db:"fin_count" json:"fin_count,omitempty"`
+	RstCount          *int64  `thrift:"rst_count,7" db:"rst_count" json:"rst_count,omitempty"`
+	AckCount          *int64  `thrift:"ack_count,8" db:"ack_count" json:"ack_count,omitempty"`
+	TCPFlags          *int32  `thrift:"tcp_flags,9" db:"tcp_flags" json:"tcp_flags,omitempty"`
+	ConnState         *string `thrift:"conn_state,10" db:"conn_state" json:"conn_state,omitempty"`
+	SampleRate        *int64  `thrift:"sample_rate,11" db:"sample_rate" json:"sample_rate,omitempty"`
+	Estimated         *bool   `thrift:"estimated,12" db:"estimated" json:"estimated,omitempty"`
+	Lifecycles        *int64  `thrift:"lifecycles,13" db:"lifecycles" json:"lifecycles,omitempty"`
+	EndReason         *string `thrift:"end_reason,14" db:"end_reason" json:"end_reason,omitempty"`
 }
 
 func NewFlowLifecycle() *FlowLifecycle {
@@ -1289,6 +2029,136 @@ func (p *FlowLifecycle) GetTotalBytes() int64 {
 	return p.TotalBytes
 }
 
+var FlowLifecycle_SynCount_DEFAULT int64
+
+func (p *FlowLifecycle) GetSynCount() int64 {
+	if !p.IsSetSynCount() {
+		return FlowLifecycle_SynCount_DEFAULT
+	}
+	return *p.SynCount
+}
+
+var FlowLifecycle_FinCount_DEFAULT int64
+
+func (p *FlowLifecycle) GetFinCount() int64 {
+	if !p.IsSetFinCount() {
+		return FlowLifecycle_FinCount_DEFAULT
+	}
+	return *p.FinCount
+}
+
+var FlowLifecycle_RstCount_DEFAULT int64
+
+func (p *FlowLifecycle) GetRstCount() int64 {
+	if !p.IsSetRstCount() {
+		return FlowLifecycle_RstCount_DEFAULT
+	}
+	return *p.RstCount
+}
+
+var FlowLifecycle_AckCount_DEFAULT int64
+
+func (p *FlowLifecycle) GetAckCount() int64 {
+	if !p.IsSetAckCount() {
+		return FlowLifecycle_AckCount_DEFAULT
+	}
+	return *p.AckCount
+}
+
+var FlowLifecycle_TCPFlags_DEFAULT int32
+
+func (p *FlowLifecycle) GetTCPFlags() int32 {
+	if !p.IsSetTCPFlags() {
+		return FlowLifecycle_TCPFlags_DEFAULT
+	}
+	return *p.TCPFlags
+}
+
+var FlowLifecycle_ConnState_DEFAULT string
+
+func (p *FlowLifecycle) GetConnState() string {
+	if !p.IsSetConnState() {
+		return FlowLifecycle_ConnState_DEFAULT
+	}
+	return *p.ConnState
+}
+
+var FlowLifecycle_SampleRate_DEFAULT int64
+
+func (p *FlowLifecycle) GetSampleRate() int64 {
+	if !p.IsSetSampleRate() {
+		return FlowLifecycle_SampleRate_DEFAULT
+	}
+	return *p.SampleRate
+}
+
+var FlowLifecycle_Estimated_DEFAULT bool
+
+func (p *FlowLifecycle) GetEstimated() bool {
+	if !p.IsSetEstimated() {
+		return FlowLifecycle_Estimated_DEFAULT
+	}
+	return *p.Estimated
+}
+
+var FlowLifecycle_Lifecycles_DEFAULT int64
+
+func (p *FlowLifecycle) GetLifecycles() int64 {
+	if !p.IsSetLifecycles() {
+		return FlowLifecycle_Lifecycles_DEFAULT
+	}
+	return *p.Lifecycles
+}
+
+var FlowLifecycle_EndReason_DEFAULT string
+
+func (p *FlowLifecycle) GetEndReason() string {
+	if !p.IsSetEndReason() {
+		return FlowLifecycle_EndReason_DEFAULT
+	}
+	return *p.EndReason
+}
+
+func (p *FlowLifecycle) IsSetSynCount() bool {
+	return p.SynCount != nil
+}
+
+func (p *FlowLifecycle) IsSetFinCount() bool {
+	return p.FinCount != nil
+}
+
+func (p *FlowLifecycle) IsSetRstCount() bool {
+	return p.RstCount != nil
+}
+
+func (p *FlowLifecycle) IsSetAckCount() bool {
+	return p.AckCount != nil
+}
+
+func (p *FlowLifecycle) IsSetTCPFlags() bool {
+	return p.TCPFlags != nil
+}
+
+func (p *FlowLifecycle) IsSetConnState() bool {
+	return p.ConnState != nil
+}
+
+func (p *FlowLifecycle) IsSetSampleRate() bool {
+	return p.SampleRate != nil
+}
+
+func (p *FlowLifecycle) IsSetEstimated() bool {
+	return p.Estimated != nil
+}
+
+func (p *FlowLifecycle) IsSetLifecycles() bool {
+	return p.Lifecycles != nil
+}
+
+func (p *FlowLifecycle) IsSetEndReason() bool {
+	return p.EndReason != nil
+}
+
 func (p *FlowLifecycle) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -1352,10 +2222,110 @@ func (p *FlowLifecycle) Read(ctx context.Context, iprot thrift.TProtocol) error
 					return err
 				}
 			}
-		default:
-			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
-				return err
-			}
+		case 5:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField5(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 6:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField6(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 7:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField7(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 8:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField8(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 9:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField9(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 10:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField10(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 11:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField11(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 12:
+			if fieldTypeId == thrift.BOOL {
+				if err := p.ReadField12(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 13:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField13(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 14:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField14(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
 		}
 		if err := iprot.ReadFieldEnd(ctx); err != nil {
 			return err
@@ -1415,6 +2385,96 @@ func (p *FlowLifecycle) ReadField4(ctx context.Context, iprot thrift.TProtocol)
 	return nil
 }
 
+func (p *FlowLifecycle) ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.SynCount = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.FinCount = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField7(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.RstCount = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField8(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.AckCount = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 9: ", err)
+	} else {
+		p.TCPFlags = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField10(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 10: ", err)
+	} else {
+		p.ConnState = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField11(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 11: ", err)
+	} else {
+		p.SampleRate = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField12(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(ctx); err != nil {
+		return thrift.PrependError("error reading field 12: ", err)
+	} else {
+		p.Estimated = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField13(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 13: ", err)
+	} else {
+		p.Lifecycles = &v
+	}
+	return nil
+}
+
+func (p *FlowLifecycle) ReadField14(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 14: ", err)
+	} else {
+		p.EndReason = &v
+	}
+	return nil
+}
+
 func (p *FlowLifecycle) Write(ctx context.Context, oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin(ctx, "FlowLifecycle"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -1432,6 +2492,36 @@ func (p *FlowLifecycle) Write(ctx context.Context, oprot thrift.TProtocol) error
 		if err := p.writeField4(ctx, oprot); err != nil {
 			return err
 		}
+		if err := p.writeField5(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField7(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField8(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField9(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField10(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField11(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField12(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField13(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField14(ctx, oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(ctx); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -1494,6 +2584,156 @@ func (p *FlowLifecycle) writeField4(ctx context.Context, oprot thrift.TProtocol)
 	return err
 }
 
+func (p *FlowLifecycle) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSynCount() {
+		if err := oprot.WriteFieldBegin(ctx, "syn_count", thrift.I64, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:syn_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.SynCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.syn_count (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:syn_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetFinCount() {
+		if err := oprot.WriteFieldBegin(ctx, "fin_count", thrift.I64, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:fin_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.FinCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.fin_count (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:fin_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField7(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetRstCount() {
+		if err := oprot.WriteFieldBegin(ctx, "rst_count", thrift.I64, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:rst_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.RstCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.rst_count (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:rst_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField8(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetAckCount() {
+		if err := oprot.WriteFieldBegin(ctx, "ack_count", thrift.I64, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:ack_count: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.AckCount)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.ack_count (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:ack_count: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetTCPFlags() {
+		if err := oprot.WriteFieldBegin(ctx, "tcp_flags", thrift.I32, 9); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:tcp_flags: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.TCPFlags)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.tcp_flags (9) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 9:tcp_flags: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField10(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetConnState() {
+		if err := oprot.WriteFieldBegin(ctx, "conn_state", thrift.STRING, 10); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 10:conn_state: ", p), err)
+		}
+		if err := oprot.WriteString(ctx, string(*p.ConnState)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.conn_state (10) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 10:conn_state: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField11(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSampleRate() {
+		if err := oprot.WriteFieldBegin(ctx, "sample_rate", thrift.I64, 11); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 11:sample_rate: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.SampleRate)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.sample_rate (11) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 11:sample_rate: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField12(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetEstimated() {
+		if err := oprot.WriteFieldBegin(ctx, "estimated", thrift.BOOL, 12); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 12:estimated: ", p), err)
+		}
+		if err := oprot.WriteBool(ctx, bool(*p.Estimated)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.estimated (12) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 12:estimated: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField13(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetLifecycles() {
+		if err := oprot.WriteFieldBegin(ctx, "lifecycles", thrift.I64, 13); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 13:lifecycles: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.Lifecycles)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.lifecycles (13) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 13:lifecycles: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *FlowLifecycle) writeField14(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetEndReason() {
+		if err := oprot.WriteFieldBegin(ctx, "end_reason", thrift.STRING, 14); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 14:end_reason: ", p), err)
+		}
+		if err := oprot.WriteString(ctx, string(*p.EndReason)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.end_reason (14) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 14:end_reason: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *FlowLifecycle) Equals(other *FlowLifecycle) bool {
 	if p == other {
 		return true
@@ -1512,8 +2752,88 @@ func (p *FlowLifecycle) Equals(other *FlowLifecycle) bool {
 	if p.TotalBytes != other.TotalBytes {
 		return false
 	}
-	return true
-}
+	if p.SynCount != other.SynCount {
+		if p.SynCount == nil || other.SynCount == nil {
+			return false
+		}
+		if (*p.SynCount) != (*other.SynCount) {
+			return false
+		}
+	}
+	if p.FinCount != other.FinCount {
+		if p.FinCount == nil || other.FinCount == nil {
+			return false
+		}
+		if (*p.FinCount) != (*other.FinCount) {
+			return false
+		}
+	}
+	if p.RstCount != other.RstCount {
+		if p.RstCount == nil || other.RstCount == nil {
+			return false
+		}
+		if (*p.RstCount) != (*other.RstCount) {
+			return false
+		}
+	}
+	if p.AckCount != other.AckCount {
+		if p.AckCount == nil || other.AckCount == nil {
+			return false
+		}
+		if (*p.AckCount) != (*other.AckCount) {
+			return false
+		}
+	}
+	if p.TCPFlags != other.TCPFlags {
+		if p.TCPFlags == nil || other.TCPFlags == nil {
+			return false
+		}
+		if (*p.TCPFlags) != (*other.TCPFlags) {
+			return false
+		}
+	}
+	if p.ConnState != other.ConnState {
+		if p.ConnState == nil || other.ConnState == nil {
+			return false
+		}
+		if (*p.ConnState) != (*other.ConnState) {
+			return false
+		}
+	}
+	if p.SampleRate != other.SampleRate {
+		if p.SampleRate == nil || other.SampleRate == nil {
+			return false
+		}
+		if (*p.SampleRate) != (*other.SampleRate) {
+			return false
+		}
+	}
+	if p.Estimated != other.Estimated {
+		if p.Estimated == nil || other.Estimated == nil {
+			return false
+		}
+		if (*p.Estimated) != (*other.Estimated) {
+			return false
+		}
+	}
+	if p.Lifecycles != other.Lifecycles {
+		if p.Lifecycles == nil || other.Lifecycles == nil {
+			return false
+		}
+		if (*p.Lifecycles) != (*other.Lifecycles) {
+			return false
+		}
+	}
+	if p.EndReason != other.EndReason {
+		if p.EndReason == nil || other.EndReason == nil {
+			return false
+		}
+		if (*p.EndReason) != (*other.EndReason) {
+			return false
+		}
+	}
+	return true
+}
 
 func (p *FlowLifecycle) String() string {
 	if p == nil {
@@ -2437,9 +3757,13 @@ func (p *HeavyHittersRequest) Validate() error {
 // Attributes:
 //   - Flow
 //   - Value
+//   - SampleRate
+//   - Estimated
 type HeavyHitter struct {
-	Flow  string `thrift:"flow,1,required" db:"flow" json:"flow"`
-	Value int64  `thrift:"value,2,required" db:"value" json:"value"`
+	Flow       string `thrift:"flow,1,required" db:"flow" json:"flow"`
+	Value      int64  `thrift:"value,2,required" db:"value" json:"value"`
+	SampleRate *int64 `thrift:"sample_rate,3" db:"sample_rate" json:"sample_rate,omitempty"`
+	Estimated  *bool  `thrift:"estimated,4" db:"estimated" json:"estimated,omitempty"`
 }
 
 func NewHeavyHitter() *HeavyHitter {
@@ -2454,6 +3778,32 @@ func (p *HeavyHitter) GetValue() int64 {
 	return p.Value
 }
 
+var HeavyHitter_SampleRate_DEFAULT int64
+
+func (p *HeavyHitter) GetSampleRate() int64 {
+	if !p.IsSetSampleRate() {
+		return HeavyHitter_SampleRate_DEFAULT
+	}
+	return *p.SampleRate
+}
+
+var HeavyHitter_Estimated_DEFAULT bool
+
+func (p *HeavyHitter) GetEstimated() bool {
+	if !p.IsSetEstimated() {
+		return HeavyHitter_Estimated_DEFAULT
+	}
+	return *p.Estimated
+}
+
+func (p *HeavyHitter) IsSetSampleRate() bool {
+	return p.SampleRate != nil
+}
+
+func (p *HeavyHitter) IsSetEstimated() bool {
+	return p.Estimated != nil
+}
+
 func (p *HeavyHitter) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -2493,6 +3843,26 @@ func (p *HeavyHitter) Read(ctx context.Context, iprot thrift.TProtocol) error {
 					return err
 				}
 			}
+		case 3:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField3(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 4:
+			if fieldTypeId == thrift.BOOL {
+				if err := p.ReadField4(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
 				return err
@@ -2514,130 +3884,1348 @@ func (p *HeavyHitter) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	return nil
 }
 
-func (p *HeavyHitter) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
-	if v, err := iprot.ReadString(ctx); err != nil {
-		return thrift.PrependError("error reading field 1: ", err)
-	} else {
-		p.Flow = v
+func (p *HeavyHitter) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.Flow = v
+	}
+	return nil
+}
+
+func (p *HeavyHitter) ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.Value = v
+	}
+	return nil
+}
+
+func (p *HeavyHitter) ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.SampleRate = &v
+	}
+	return nil
+}
+
+func (p *HeavyHitter) ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadBool(ctx); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.Estimated = &v
+	}
+	return nil
+}
+
+func (p *HeavyHitter) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "HeavyHitter"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *HeavyHitter) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "flow", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:flow: ", p), err)
+	}
+	if err := oprot.WriteString(ctx, string(p.Flow)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.flow (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:flow: ", p), err)
+	}
+	return err
+}
+
+func (p *HeavyHitter) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "value", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:value: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.Value)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.value (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:value: ", p), err)
+	}
+	return err
+}
+
+func (p *HeavyHitter) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSampleRate() {
+		if err := oprot.WriteFieldBegin(ctx, "sample_rate", thrift.I64, 3); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:sample_rate: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.SampleRate)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.sample_rate (3) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 3:sample_rate: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *HeavyHitter) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetEstimated() {
+		if err := oprot.WriteFieldBegin(ctx, "estimated", thrift.BOOL, 4); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:estimated: ", p), err)
+		}
+		if err := oprot.WriteBool(ctx, bool(*p.Estimated)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.estimated (4) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 4:estimated: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *HeavyHitter) Equals(other *HeavyHitter) bool {
+	if p == other {
+		return true
+	} else if p == nil || other == nil {
+		return false
+	}
+	if p.Flow != other.Flow {
+		return false
+	}
+	if p.Value != other.Value {
+		return false
+	}
+	if p.SampleRate != other.SampleRate {
+		if p.SampleRate == nil || other.SampleRate == nil {
+			return false
+		}
+		if (*p.SampleRate) != (*other.SampleRate) {
+			return false
+		}
+	}
+	if p.Estimated != other.Estimated {
+		if p.Estimated == nil || other.Estimated == nil {
+			return false
+		}
+		if (*p.Estimated) != (*other.Estimated) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *HeavyHitter) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("HeavyHitter(%+v)", *p)
+}
+
+func (p *HeavyHitter) LogValue() slog.Value {
+	if p == nil {
+		return slog.AnyValue(nil)
+	}
+	v := thrift.SlogTStructWrapper{
+		Type:  "*v1.HeavyHitter",
+		Value: p,
+	}
+	return slog.AnyValue(v)
+}
+
+var _ slog.LogValuer = (*HeavyHitter)(nil)
+
+func (p *HeavyHitter) Validate() error {
+	return nil
+}
+
+// Attributes:
+//   - Hitters
+type HeavyHittersResponse struct {
+	Hitters []*HeavyHitter `thrift:"hitters,1,required" db:"hitters" json:"hitters"`
+}
+
+func NewHeavyHittersResponse() *HeavyHittersResponse {
+	return &HeavyHittersResponse{}
+}
+
+func (p *HeavyHittersResponse) GetHitters() []*HeavyHitter {
+	return p.Hitters
+}
+
+func (p *HeavyHittersResponse) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetHitters bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.LIST {
+				if err := p.ReadField1(ctx, iprot); err != nil {
+					return err
+				}
+				issetHitters = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetHitters {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Hitters is not set"))
+	}
+	return nil
+}
+
+func (p *HeavyHittersResponse) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin(ctx)
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*HeavyHitter, 0, size)
+	p.Hitters = tSlice
+	for i := 0; i < size; i++ {
+		_elem7 := &HeavyHitter{}
+		if err := _elem7.Read(ctx, iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem7), err)
+		}
+		p.Hitters = append(p.Hitters, _elem7)
+	}
+	if err := iprot.ReadListEnd(ctx); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *HeavyHittersResponse) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "HeavyHittersResponse"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *HeavyHittersResponse) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "hitters", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:hitters: ", p), err)
+	}
+	if err := oprot.WriteListBegin(ctx, thrift.STRUCT, len(p.Hitters)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.Hitters {
+		if err := v.Write(ctx, oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
+	}
+	if err := oprot.WriteListEnd(ctx); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:hitters: ", p), err)
+	}
+	return err
+}
+
+func (p *HeavyHittersResponse) Equals(other *HeavyHittersResponse) bool {
+	if p == other {
+		return true
+	} else if p == nil || other == nil {
+		return false
+	}
+	if len(p.Hitters) != len(other.Hitters) {
+		return false
+	}
+	for i, _tgt := range p.Hitters {
+		_src8 := other.Hitters[i]
+		if !_tgt.Equals(_src8) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *HeavyHittersResponse) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("HeavyHittersResponse(%+v)", *p)
+}
+
+func (p *HeavyHittersResponse) LogValue() slog.Value {
+	if p == nil {
+		return slog.AnyValue(nil)
+	}
+	v := thrift.SlogTStructWrapper{
+		Type:  "*v1.HeavyHittersResponse",
+		Value: p,
+	}
+	return slog.AnyValue(v)
+}
+
+var _ slog.LogValuer = (*HeavyHittersResponse)(nil)
+
+func (p *HeavyHittersResponse) Validate() error {
+	return nil
+}
+
+// Attributes:
+//   - EndTimeUnixNano
+//   - AgentIP
+//   - IfIndex
+type InterfaceCountersRequest struct {
+	EndTimeUnixNano *int64  `thrift:"end_time_unix_nano,1" db:"end_time_unix_nano" json:"end_time_unix_nano,omitempty"`
+	AgentIP         *string `thrift:"agent_ip,2" db:"agent_ip" json:"agent_ip,omitempty"`
+	IfIndex         *int64  `thrift:"if_index,3" db:"if_index" json:"if_index,omitempty"`
+}
+
+func NewInterfaceCountersRequest() *InterfaceCountersRequest {
+	return &InterfaceCountersRequest{}
+}
+
+var InterfaceCountersRequest_EndTimeUnixNano_DEFAULT int64
+
+func (p *InterfaceCountersRequest) GetEndTimeUnixNano() int64 {
+	if !p.IsSetEndTimeUnixNano() {
+		return InterfaceCountersRequest_EndTimeUnixNano_DEFAULT
+	}
+	return *p.EndTimeUnixNano
+}
+
+var InterfaceCountersRequest_AgentIP_DEFAULT string
+
+func (p *InterfaceCountersRequest) GetAgentIP() string {
+	if !p.IsSetAgentIP() {
+		return InterfaceCountersRequest_AgentIP_DEFAULT
+	}
+	return *p.AgentIP
+}
+
+var InterfaceCountersRequest_IfIndex_DEFAULT int64
+
+func (p *InterfaceCountersRequest) GetIfIndex() int64 {
+	if !p.IsSetIfIndex() {
+		return InterfaceCountersRequest_IfIndex_DEFAULT
+	}
+	return *p.IfIndex
+}
+
+func (p *InterfaceCountersRequest) IsSetEndTimeUnixNano() bool {
+	return p.EndTimeUnixNano != nil
+}
+
+func (p *InterfaceCountersRequest) IsSetAgentIP() bool {
+	return p.AgentIP != nil
+}
+
+func (p *InterfaceCountersRequest) IsSetIfIndex() bool {
+	return p.IfIndex != nil
+}
+
+func (p *InterfaceCountersRequest) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField1(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 2:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField2(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 3:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField3(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *InterfaceCountersRequest) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.EndTimeUnixNano = &v
+	}
+	return nil
+}
+
+func (p *InterfaceCountersRequest) ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.AgentIP = &v
+	}
+	return nil
+}
+
+func (p *InterfaceCountersRequest) ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.IfIndex = &v
+	}
+	return nil
+}
+
+func (p *InterfaceCountersRequest) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "InterfaceCountersRequest"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *InterfaceCountersRequest) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetEndTimeUnixNano() {
+		if err := oprot.WriteFieldBegin(ctx, "end_time_unix_nano", thrift.I64, 1); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:end_time_unix_nano: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.EndTimeUnixNano)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.end_time_unix_nano (1) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 1:end_time_unix_nano: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *InterfaceCountersRequest) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetAgentIP() {
+		if err := oprot.WriteFieldBegin(ctx, "agent_ip", thrift.STRING, 2); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:agent_ip: ", p), err)
+		}
+		if err := oprot.WriteString(ctx, string(*p.AgentIP)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.agent_ip (2) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 2:agent_ip: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *InterfaceCountersRequest) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetIfIndex() {
+		if err := oprot.WriteFieldBegin(ctx, "if_index", thrift.I64, 3); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:if_index: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.IfIndex)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.if_index (3) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 3:if_index: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *InterfaceCountersRequest) Equals(other *InterfaceCountersRequest) bool {
+	if p == other {
+		return true
+	} else if p == nil || other == nil {
+		return false
+	}
+	if p.EndTimeUnixNano != other.EndTimeUnixNano {
+		if p.EndTimeUnixNano == nil || other.EndTimeUnixNano == nil {
+			return false
+		}
+		if (*p.EndTimeUnixNano) != (*other.EndTimeUnixNano) {
+			return false
+		}
+	}
+	if p.AgentIP != other.AgentIP {
+		if p.AgentIP == nil || other.AgentIP == nil {
+			return false
+		}
+		if (*p.AgentIP) != (*other.AgentIP) {
+			return false
+		}
+	}
+	if p.IfIndex != other.IfIndex {
+		if p.IfIndex == nil || other.IfIndex == nil {
+			return false
+		}
+		if (*p.IfIndex) != (*other.IfIndex) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *InterfaceCountersRequest) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("InterfaceCountersRequest(%+v)", *p)
+}
+
+func (p *InterfaceCountersRequest) LogValue() slog.Value {
+	if p == nil {
+		return slog.AnyValue(nil)
+	}
+	v := thrift.SlogTStructWrapper{
+		Type:  "*v1.InterfaceCountersRequest",
+		Value: p,
+	}
+	return slog.AnyValue(v)
+}
+
+var _ slog.LogValuer = (*InterfaceCountersRequest)(nil)
+
+func (p *InterfaceCountersRequest) Validate() error {
+	return nil
+}
+
+// Attributes:
+//   - AgentIP
+//   - IfIndex
+//   - TimestampUnixNano
+//   - IfSpeed
+//   - IfStatus
+//   - InOctets
+//   - InPackets
+//   - InErrors
+//   - InDiscards
+//   - OutOctets
+//   - OutPackets
+//   - OutErrors
+//   - OutDiscards
+type InterfaceCounters struct {
+	AgentIP           string `thrift:"agent_ip,1,required" db:"agent_ip" json:"agent_ip"`
+	IfIndex           int64  `thrift:"if_index,2,required" db:"if_index" json:"if_index"`
+	TimestampUnixNano int64  `thrift:"timestamp_unix_nano,3,required" db:"timestamp_unix_nano" json:"timestamp_unix_nano"`
+	IfSpeed           int64  `thrift:"if_speed,4,required" db:"if_speed" json:"if_speed"`
+	IfStatus          int32  `thrift:"if_status,5,required" db:"if_status" json:"if_status"`
+	InOctets          int64  `thrift:"in_octets,6,required" db:"in_octets" json:"in_octets"`
+	InPackets         int64  `thrift:"in_packets,7,required" db:"in_packets" json:"in_packets"`
+	InErrors          int64  `thrift:"in_errors,8,required" db:"in_errors" json:"in_errors"`
+	InDiscards        int64  `thrift:"in_discards,9,required" db:"in_discards" json:"in_discards"`
+	OutOctets         int64  `thrift:"out_octets,10,required" db:"out_octets" json:"out_octets"`
+	OutPackets        int64  `thrift:"out_packets,11,required" db:"out_packets" json:"out_packets"`
+	OutErrors         int64  `thrift:"out_errors,12,required" db:"out_errors" json:"out_errors"`
+	OutDiscards       int64  `thrift:"out_discards,13,required" db:"out_discards" json:"out_discards"`
+}
+
+func NewInterfaceCounters() *InterfaceCounters {
+	return &InterfaceCounters{}
+}
+
+func (p *InterfaceCounters) GetAgentIP() string {
+	return p.AgentIP
+}
+
+func (p *InterfaceCounters) GetIfIndex() int64 {
+	return p.IfIndex
+}
+
+func (p *InterfaceCounters) GetTimestampUnixNano() int64 {
+	return p.TimestampUnixNano
+}
+
+func (p *InterfaceCounters) GetIfSpeed() int64 {
+	return p.IfSpeed
+}
+
+func (p *InterfaceCounters) GetIfStatus() int32 {
+	return p.IfStatus
+}
+
+func (p *InterfaceCounters) GetInOctets() int64 {
+	return p.InOctets
+}
+
+func (p *InterfaceCounters) GetInPackets() int64 {
+	return p.InPackets
+}
+
+func (p *InterfaceCounters) GetInErrors() int64 {
+	return p.InErrors
+}
+
+func (p *InterfaceCounters) GetInDiscards() int64 {
+	return p.InDiscards
+}
+
+func (p *InterfaceCounters) GetOutOctets() int64 {
+	return p.OutOctets
+}
+
+func (p *InterfaceCounters) GetOutPackets() int64 {
+	return p.OutPackets
+}
+
+func (p *InterfaceCounters) GetOutErrors() int64 {
+	return p.OutErrors
+}
+
+func (p *InterfaceCounters) GetOutDiscards() int64 {
+	return p.OutDiscards
+}
+
+func (p *InterfaceCounters) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetAgentIP bool = false
+	var issetIfIndex bool = false
+	var issetTimestampUnixNano bool = false
+	var issetIfSpeed bool = false
+	var issetIfStatus bool = false
+	var issetInOctets bool = false
+	var issetInPackets bool = false
+	var issetInErrors bool = false
+	var issetInDiscards bool = false
+	var issetOutOctets bool = false
+	var issetOutPackets bool = false
+	var issetOutErrors bool = false
+	var issetOutDiscards bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.STRING {
+				if err := p.ReadField1(ctx, iprot); err != nil {
+					return err
+				}
+				issetAgentIP = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 2:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField2(ctx, iprot); err != nil {
+					return err
+				}
+				issetIfIndex = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 3:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField3(ctx, iprot); err != nil {
+					return err
+				}
+				issetTimestampUnixNano = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 4:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField4(ctx, iprot); err != nil {
+					return err
+				}
+				issetIfSpeed = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 5:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField5(ctx, iprot); err != nil {
+					return err
+				}
+				issetIfStatus = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 6:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField6(ctx, iprot); err != nil {
+					return err
+				}
+				issetInOctets = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 7:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField7(ctx, iprot); err != nil {
+					return err
+				}
+				issetInPackets = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 8:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField8(ctx, iprot); err != nil {
+					return err
+				}
+				issetInErrors = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 9:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField9(ctx, iprot); err != nil {
+					return err
+				}
+				issetInDiscards = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 10:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField10(ctx, iprot); err != nil {
+					return err
+				}
+				issetOutOctets = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 11:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField11(ctx, iprot); err != nil {
+					return err
+				}
+				issetOutPackets = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 12:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField12(ctx, iprot); err != nil {
+					return err
+				}
+				issetOutErrors = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 13:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField13(ctx, iprot); err != nil {
+					return err
+				}
+				issetOutDiscards = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetAgentIP {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field AgentIP is not set"))
+	}
+	if !issetIfIndex {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field IfIndex is not set"))
+	}
+	if !issetTimestampUnixNano {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field TimestampUnixNano is not set"))
+	}
+	if !issetIfSpeed {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field IfSpeed is not set"))
+	}
+	if !issetIfStatus {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field IfStatus is not set"))
+	}
+	if !issetInOctets {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field InOctets is not set"))
+	}
+	if !issetInPackets {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field InPackets is not set"))
+	}
+	if !issetInErrors {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field InErrors is not set"))
+	}
+	if !issetInDiscards {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field InDiscards is not set"))
+	}
+	if !issetOutOctets {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field OutOctets is not set"))
+	}
+	if !issetOutPackets {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field OutPackets is not set"))
+	}
+	if !issetOutErrors {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field OutErrors is not set"))
+	}
+	if !issetOutDiscards {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field OutDiscards is not set"))
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadString(ctx); err != nil {
+		return thrift.PrependError("error reading field 1: ", err)
+	} else {
+		p.AgentIP = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 2: ", err)
+	} else {
+		p.IfIndex = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField3(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 3: ", err)
+	} else {
+		p.TimestampUnixNano = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.IfSpeed = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.IfStatus = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.InOctets = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField7(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.InPackets = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField8(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.InErrors = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 9: ", err)
+	} else {
+		p.InDiscards = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField10(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 10: ", err)
+	} else {
+		p.OutOctets = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField11(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 11: ", err)
+	} else {
+		p.OutPackets = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField12(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 12: ", err)
+	} else {
+		p.OutErrors = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) ReadField13(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 13: ", err)
+	} else {
+		p.OutDiscards = v
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "InterfaceCounters"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField2(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField3(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField4(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField7(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField8(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField9(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField10(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField11(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField12(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField13(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *InterfaceCounters) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "agent_ip", thrift.STRING, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:agent_ip: ", p), err)
+	}
+	if err := oprot.WriteString(ctx, string(p.AgentIP)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.agent_ip (1) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:agent_ip: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "if_index", thrift.I64, 2); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:if_index: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.IfIndex)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.if_index (2) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:if_index: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField3(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "timestamp_unix_nano", thrift.I64, 3); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 3:timestamp_unix_nano: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.TimestampUnixNano)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.timestamp_unix_nano (3) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 3:timestamp_unix_nano: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "if_speed", thrift.I64, 4); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:if_speed: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.IfSpeed)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.if_speed (4) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 4:if_speed: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "if_status", thrift.I32, 5); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:if_status: ", p), err)
+	}
+	if err := oprot.WriteI32(ctx, int32(p.IfStatus)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.if_status (5) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 5:if_status: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "in_octets", thrift.I64, 6); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:in_octets: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.InOctets)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.in_octets (6) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 6:in_octets: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField7(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "in_packets", thrift.I64, 7); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:in_packets: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.InPackets)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.in_packets (7) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 7:in_packets: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField8(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "in_errors", thrift.I64, 8); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:in_errors: ", p), err)
+	}
+	if err := oprot.WriteI64(ctx, int64(p.InErrors)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.in_errors (8) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 8:in_errors: ", p), err)
+	}
+	return err
+}
+
+func (p *InterfaceCounters) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "in_discards", thrift.I64, 9); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:in_discards: ", p), err)
 	}
-	return nil
+	if err := oprot.WriteI64(ctx, int64(p.InDiscards)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.in_discards (9) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 9:in_discards: ", p), err)
+	}
+	return err
 }
 
-func (p *HeavyHitter) ReadField2(ctx context.Context, iprot thrift.TProtocol) error {
-	if v, err := iprot.ReadI64(ctx); err != nil {
-		return thrift.PrependError("error reading field 2: ", err)
-	} else {
-		p.Value = v
+func (p *InterfaceCounters) writeField10(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "out_octets", thrift.I64, 10); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 10:out_octets: ", p), err)
 	}
-	return nil
+	if err := oprot.WriteI64(ctx, int64(p.OutOctets)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.out_octets (10) field write error: ", p), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 10:out_octets: ", p), err)
+	}
+	return err
 }
 
-func (p *HeavyHitter) Write(ctx context.Context, oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin(ctx, "HeavyHitter"); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
-	}
-	if p != nil {
-		if err := p.writeField1(ctx, oprot); err != nil {
-			return err
-		}
-		if err := p.writeField2(ctx, oprot); err != nil {
-			return err
-		}
+func (p *InterfaceCounters) writeField11(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "out_packets", thrift.I64, 11); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 11:out_packets: ", p), err)
 	}
-	if err := oprot.WriteFieldStop(ctx); err != nil {
-		return thrift.PrependError("write field stop error: ", err)
+	if err := oprot.WriteI64(ctx, int64(p.OutPackets)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.out_packets (11) field write error: ", p), err)
 	}
-	if err := oprot.WriteStructEnd(ctx); err != nil {
-		return thrift.PrependError("write struct stop error: ", err)
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 11:out_packets: ", p), err)
 	}
-	return nil
+	return err
 }
 
-func (p *HeavyHitter) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin(ctx, "flow", thrift.STRING, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:flow: ", p), err)
+func (p *InterfaceCounters) writeField12(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "out_errors", thrift.I64, 12); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 12:out_errors: ", p), err)
 	}
-	if err := oprot.WriteString(ctx, string(p.Flow)); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T.flow (1) field write error: ", p), err)
+	if err := oprot.WriteI64(ctx, int64(p.OutErrors)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.out_errors (12) field write error: ", p), err)
 	}
 	if err := oprot.WriteFieldEnd(ctx); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:flow: ", p), err)
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 12:out_errors: ", p), err)
 	}
 	return err
 }
 
-func (p *HeavyHitter) writeField2(ctx context.Context, oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin(ctx, "value", thrift.I64, 2); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 2:value: ", p), err)
+func (p *InterfaceCounters) writeField13(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "out_discards", thrift.I64, 13); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 13:out_discards: ", p), err)
 	}
-	if err := oprot.WriteI64(ctx, int64(p.Value)); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T.value (2) field write error: ", p), err)
+	if err := oprot.WriteI64(ctx, int64(p.OutDiscards)); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T.out_discards (13) field write error: ", p), err)
 	}
 	if err := oprot.WriteFieldEnd(ctx); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 2:value: ", p), err)
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 13:out_discards: ", p), err)
 	}
 	return err
 }
 
-func (p *HeavyHitter) Equals(other *HeavyHitter) bool {
+func (p *InterfaceCounters) Equals(other *InterfaceCounters) bool {
 	if p == other {
 		return true
 	} else if p == nil || other == nil {
 		return false
 	}
-	if p.Flow != other.Flow {
+	if p.AgentIP != other.AgentIP {
 		return false
 	}
-	if p.Value != other.Value {
+	if p.IfIndex != other.IfIndex {
+		return false
+	}
+	if p.TimestampUnixNano != other.TimestampUnixNano {
+		return false
+	}
+	if p.IfSpeed != other.IfSpeed {
+		return false
+	}
+	if p.IfStatus != other.IfStatus {
+		return false
+	}
+	if p.InOctets != other.InOctets {
+		return false
+	}
+	if p.InPackets != other.InPackets {
+		return false
+	}
+	if p.InErrors != other.InErrors {
+		return false
+	}
+	if p.InDiscards != other.InDiscards {
+		return false
+	}
+	if p.OutOctets != other.OutOctets {
+		return false
+	}
+	if p.OutPackets != other.OutPackets {
+		return false
+	}
+	if p.OutErrors != other.OutErrors {
+		return false
+	}
+	if p.OutDiscards != other.OutDiscards {
 		return false
 	}
 	return true
 }
 
-func (p *HeavyHitter) String() string {
+func (p *InterfaceCounters) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("HeavyHitter(%+v)", *p)
+	return fmt.Sprintf("InterfaceCounters(%+v)", *p)
 }
 
-func (p *HeavyHitter) LogValue() slog.Value {
+func (p *InterfaceCounters) LogValue() slog.Value {
 	if p == nil {
 		return slog.AnyValue(nil)
 	}
 	v := thrift.SlogTStructWrapper{
-		Type:  "*v1.HeavyHitter",
+		Type:  "*v1.InterfaceCounters",
 		Value: p,
 	}
 	return slog.AnyValue(v)
 }
 
-var _ slog.LogValuer = (*HeavyHitter)(nil)
+var _ slog.LogValuer = (*InterfaceCounters)(nil)
 
-func (p *HeavyHitter) Validate() error {
+func (p *InterfaceCounters) Validate() error {
 	return nil
 }
 
 // Attributes:
-//   - Hitters
-type HeavyHittersResponse struct {
-	Hitters []*HeavyHitter `thrift:"hitters,1,required" db:"hitters" json:"hitters"`
+//   - Counters
+type InterfaceCountersResponse struct {
+	Counters []*InterfaceCounters `thrift:"counters,1,required" db:"counters" json:"counters"`
 }
 
-func NewHeavyHittersResponse() *HeavyHittersResponse {
-	return &HeavyHittersResponse{}
+func NewInterfaceCountersResponse() *InterfaceCountersResponse {
+	return &InterfaceCountersResponse{}
 }
 
-func (p *HeavyHittersResponse) GetHitters() []*HeavyHitter {
-	return p.Hitters
+func (p *InterfaceCountersResponse) GetCounters() []*InterfaceCounters {
+	return p.Counters
 }
 
-func (p *HeavyHittersResponse) Read(ctx context.Context, iprot thrift.TProtocol) error {
+func (p *InterfaceCountersResponse) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
 	}
 
-	var issetHitters bool = false
+	var issetCounters bool = false
 
 	for {
 		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
@@ -2653,7 +5241,7 @@ func (p *HeavyHittersResponse) Read(ctx context.Context, iprot thrift.TProtocol)
 				if err := p.ReadField1(ctx, iprot); err != nil {
 					return err
 				}
-				issetHitters = true
+				issetCounters = true
 			} else {
 				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
 					return err
@@ -2671,25 +5259,25 @@ func (p *HeavyHittersResponse) Read(ctx context.Context, iprot thrift.TProtocol)
 	if err := iprot.ReadStructEnd(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
 	}
-	if !issetHitters {
-		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Hitters is not set"))
+	if !issetCounters {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Counters is not set"))
 	}
 	return nil
 }
 
-func (p *HeavyHittersResponse) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+func (p *InterfaceCountersResponse) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
 	_, size, err := iprot.ReadListBegin(ctx)
 	if err != nil {
 		return thrift.PrependError("error reading list begin: ", err)
 	}
-	tSlice := make([]*HeavyHitter, 0, size)
-	p.Hitters = tSlice
+	tSlice := make([]*InterfaceCounters, 0, size)
+	p.Counters = tSlice
 	for i := 0; i < size; i++ {
-		_elem7 := &HeavyHitter{}
-		if err := _elem7.Read(ctx, iprot); err != nil {
-			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem7), err)
+		_elem9 := &InterfaceCounters{}
+		if err := _elem9.Read(ctx, iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem9), err)
 		}
-		p.Hitters = append(p.Hitters, _elem7)
+		p.Counters = append(p.Counters, _elem9)
 	}
 	if err := iprot.ReadListEnd(ctx); err != nil {
 		return thrift.PrependError("error reading list end: ", err)
@@ -2697,8 +5285,8 @@ func (p *HeavyHittersResponse) ReadField1(ctx context.Context, iprot thrift.TPro
 	return nil
 }
 
-func (p *HeavyHittersResponse) Write(ctx context.Context, oprot thrift.TProtocol) error {
-	if err := oprot.WriteStructBegin(ctx, "HeavyHittersResponse"); err != nil {
+func (p *InterfaceCountersResponse) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "InterfaceCountersResponse"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
 	}
 	if p != nil {
@@ -2715,14 +5303,14 @@ func (p *HeavyHittersResponse) Write(ctx context.Context, oprot thrift.TProtocol
 	return nil
 }
 
-func (p *HeavyHittersResponse) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
-	if err := oprot.WriteFieldBegin(ctx, "hitters", thrift.LIST, 1); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:hitters: ", p), err)
+func (p *InterfaceCountersResponse) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "counters", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:counters: ", p), err)
 	}
-	if err := oprot.WriteListBegin(ctx, thrift.STRUCT, len(p.Hitters)); err != nil {
+	if err := oprot.WriteListBegin(ctx, thrift.STRUCT, len(p.Counters)); err != nil {
 		return thrift.PrependError("error writing list begin: ", err)
 	}
-	for _, v := range p.Hitters {
+	for _, v := range p.Counters {
 		if err := v.Write(ctx, oprot); err != nil {
 			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
 		}
@@ -2731,50 +5319,50 @@ func (p *HeavyHittersResponse) writeField1(ctx context.Context, oprot thrift.TPr
 		return thrift.PrependError("error writing list end: ", err)
 	}
 	if err := oprot.WriteFieldEnd(ctx); err != nil {
-		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:hitters: ", p), err)
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:counters: ", p), err)
 	}
 	return err
 }
 
-func (p *HeavyHittersResponse) Equals(other *HeavyHittersResponse) bool {
+func (p *InterfaceCountersResponse) Equals(other *InterfaceCountersResponse) bool {
 	if p == other {
 		return true
 	} else if p == nil || other == nil {
 		return false
 	}
-	if len(p.Hitters) != len(other.Hitters) {
+	if len(p.Counters) != len(other.Counters) {
 		return false
 	}
-	for i, _tgt := range p.Hitters {
-		_src8 := other.Hitters[i]
-		if !_tgt.Equals(_src8) {
+	for i, _tgt := range p.Counters {
+		_src10 := other.Counters[i]
+		if !_tgt.Equals(_src10) {
 			return false
 		}
 	}
 	return true
 }
 
-func (p *HeavyHittersResponse) String() string {
+func (p *InterfaceCountersResponse) String() string {
 	if p == nil {
 		return "<nil>"
 	}
-	return fmt.Sprintf("HeavyHittersResponse(%+v)", *p)
+	return fmt.Sprintf("InterfaceCountersResponse(%+v)", *p)
 }
 
-func (p *HeavyHittersResponse) LogValue() slog.Value {
+func (p *InterfaceCountersResponse) LogValue() slog.Value {
 	if p == nil {
 		return slog.AnyValue(nil)
 	}
 	v := thrift.SlogTStructWrapper{
-		Type:  "*v1.HeavyHittersResponse",
+		Type:  "*v1.InterfaceCountersResponse",
 		Value: p,
 	}
 	return slog.AnyValue(v)
 }
 
-var _ slog.LogValuer = (*HeavyHittersResponse)(nil)
+var _ slog.LogValuer = (*InterfaceCountersResponse)(nil)
 
-func (p *HeavyHittersResponse) Validate() error {
+func (p *InterfaceCountersResponse) Validate() error {
 	return nil
 }
 
@@ -2799,6 +5387,10 @@ type QueryService interface {
 	//  - Req
 	//
 	QueryHeavyHitters(ctx context.Context, req *HeavyHittersRequest) (_r *HeavyHittersResponse, _err error)
+	// Parameters:
+	//  - Req
+	//
+	QueryInterfaceCounters(ctx context.Context, req *InterfaceCountersRequest) (_r *InterfaceCountersResponse, _err error)
 }
 
 type QueryServiceClient struct {
@@ -2839,17 +5431,17 @@ func (p *QueryServiceClient) SetLastResponseMeta_(meta thrift.ResponseMeta) {
 // Parameters:
 //   - Req
 func (p *QueryServiceClient) HealthCheck(ctx context.Context, req *HealthCheckRequest) (_r *HealthCheckResponse, _err error) {
-	var _args9 QueryServiceHealthCheckArgs
-	_args9.Req = req
-	var _result11 QueryServiceHealthCheckResult
-	var _meta10 thrift.ResponseMeta
-	_meta10, _err = p.Client_().Call(ctx, "HealthCheck", &_args9, &_result11)
-	p.SetLastResponseMeta_(_meta10)
+	var _args11 QueryServiceHealthCheckArgs
+	_args11.Req = req
+	var _result13 QueryServiceHealthCheckResult
+	var _meta12 thrift.ResponseMeta
+	_meta12, _err = p.Client_().Call(ctx, "HealthCheck", &_args11, &_result13)
+	p.SetLastResponseMeta_(_meta12)
 	if _err != nil {
 		return
 	}
-	if _ret12 := _result11.GetSuccess(); _ret12 != nil {
-		return _ret12, nil
+	if _ret14 := _result13.GetSuccess(); _ret14 != nil {
+		return _ret14, nil
 	}
 	return nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "HealthCheck failed: unknown result")
 }
@@ -2857,17 +5449,17 @@ func (p *QueryServiceClient) HealthCheck(ctx context.Context, req *HealthCheckRe
 // Parameters:
 //   - Req
 func (p *QueryServiceClient) SearchTasks(ctx context.Context, req *SearchTasksRequest) (_r *SearchTasksResponse, _err error) {
-	var _args13 QueryServiceSearchTasksArgs
-	_args13.Req = req
-	var _result15 QueryServiceSearchTasksResult
-	var _meta14 thrift.ResponseMeta
-	_meta14, _err = p.Client_().Call(ctx, "SearchTasks", &_args13, &_result15)
-	p.SetLastResponseMeta_(_meta14)
+	var _args15 QueryServiceSearchTasksArgs
+	_args15.Req = req
+	var _result17 QueryServiceSearchTasksResult
+	var _meta16 thrift.ResponseMeta
+	_meta16, _err = p.Client_().Call(ctx, "SearchTasks", &_args15, &_result17)
+	p.SetLastResponseMeta_(_meta16)
 	if _err != nil {
 		return
 	}
-	if _ret16 := _result15.GetSuccess(); _ret16 != nil {
-		return _ret16, nil
+	if _ret18 := _result17.GetSuccess(); _ret18 != nil {
+		return _ret18, nil
 	}
 	return nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "SearchTasks failed: unknown result")
 }
@@ -2875,17 +5467,17 @@ func (p *QueryServiceClient) SearchTasks(ctx context.Context, req *SearchTasksRe
 // Parameters:
 //   - Req
 func (p *QueryServiceClient) AggregateFlows(ctx context.Context, req *AggregationRequest) (_r *QueryTotalCountsResponse, _err error) {
-	var _args17 QueryServiceAggregateFlowsArgs
-	_args17.Req = req
-	var _result19 QueryServiceAggregateFlowsResult
-	var _meta18 thrift.ResponseMeta
-	_meta18, _err = p.Client_().Call(ctx, "AggregateFlows", &_args17, &_result19)
-	p.SetLastResponseMeta_(_meta18)
+	var _args19 QueryServiceAggregateFlowsArgs
+	_args19.Req = req
+	var _result21 QueryServiceAggregateFlowsResult
+	var _meta20 thrift.ResponseMeta
+	_meta20, _err = p.Client_().Call(ctx, "AggregateFlows", &_args19, &_result21)
+	p.SetLastResponseMeta_(_meta20)
 	if _err != nil {
 		return
 	}
-	if _ret20 := _result19.GetSuccess(); _ret20 != nil {
-		return _ret20, nil
+	if _ret22 := _result21.GetSuccess(); _ret22 != nil {
+		return _ret22, nil
 	}
 	return nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "AggregateFlows failed: unknown result")
 }
@@ -2893,17 +5485,17 @@ func (p *QueryServiceClient) AggregateFlows(ctx context.Context, req *Aggregatio
 // Parameters:
 //   - Req
 func (p *QueryServiceClient) TraceFlow(ctx context.Context, req *TraceFlowRequest) (_r *TraceFlowResponse, _err error) {
-	var _args21 QueryServiceTraceFlowArgs
-	_args21.Req = req
-	var _result23 QueryServiceTraceFlowResult
-	var _meta22 thrift.ResponseMeta
-	_meta22, _err = p.Client_().Call(ctx, "TraceFlow", &_args21, &_result23)
-	p.SetLastResponseMeta_(_meta22)
+	var _args23 QueryServiceTraceFlowArgs
+	_args23.Req = req
+	var _result25 QueryServiceTraceFlowResult
+	var _meta24 thrift.ResponseMeta
+	_meta24, _err = p.Client_().Call(ctx, "TraceFlow", &_args23, &_result25)
+	p.SetLastResponseMeta_(_meta24)
 	if _err != nil {
 		return
 	}
-	if _ret24 := _result23.GetSuccess(); _ret24 != nil {
-		return _ret24, nil
+	if _ret26 := _result25.GetSuccess(); _ret26 != nil {
+		return _ret26, nil
 	}
 	return nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "TraceFlow failed: unknown result")
 }
@@ -2911,21 +5503,39 @@ func (p *QueryServiceClient) TraceFlow(ctx context.Context, req *TraceFlowReques
 // Parameters:
 //   - Req
 func (p *QueryServiceClient) QueryHeavyHitters(ctx context.Context, req *HeavyHittersRequest) (_r *HeavyHittersResponse, _err error) {
-	var _args25 QueryServiceQueryHeavyHittersArgs
-	_args25.Req = req
-	var _result27 QueryServiceQueryHeavyHittersResult
-	var _meta26 thrift.ResponseMeta
-	_meta26, _err = p.Client_().Call(ctx, "QueryHeavyHitters", &_args25, &_result27)
-	p.SetLastResponseMeta_(_meta26)
+	var _args27 QueryServiceQueryHeavyHittersArgs
+	_args27.Req = req
+	var _result29 QueryServiceQueryHeavyHittersResult
+	var _meta28 thrift.ResponseMeta
+	_meta28, _err = p.Client_().Call(ctx, "QueryHeavyHitters", &_args27, &_result29)
+	p.SetLastResponseMeta_(_meta28)
 	if _err != nil {
 		return
 	}
-	if _ret28 := _result27.GetSuccess(); _ret28 != nil {
-		return _ret28, nil
+	if _ret30 := _result29.GetSuccess(); _ret30 != nil {
+		return _ret30, nil
 	}
 	return nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "QueryHeavyHitters failed: unknown result")
 }
 
+// Parameters:
+//   - Req
+func (p *QueryServiceClient) QueryInterfaceCounters(ctx context.Context, req *InterfaceCountersRequest) (_r *InterfaceCountersResponse, _err error) {
+	var _args31 QueryServiceQueryInterfaceCountersArgs
+	_args31.Req = req
+	var _result33 QueryServiceQueryInterfaceCountersResult
+	var _meta32 thrift.ResponseMeta
+	_meta32, _err = p.Client_().Call(ctx, "QueryInterfaceCounters", &_args31, &_result33)
+	p.SetLastResponseMeta_(_meta32)
+	if _err != nil {
+		return
+	}
+	if _ret34 := _result33.GetSuccess(); _ret34 != nil {
+		return _ret34, nil
+	}
+	return nil, thrift.NewTApplicationException(thrift.MISSING_RESULT, "QueryInterfaceCounters failed: unknown result")
+}
+
 type QueryServiceProcessor struct {
 	processorMap map[string]thrift.TProcessorFunction
 	handler      QueryService
@@ -2946,13 +5556,14 @@ func (p *QueryServiceProcessor) ProcessorMap() map[string]thrift.TProcessorFunct
 
 func NewQueryServiceProcessor(handler QueryService) *QueryServiceProcessor {
 
-	self29 := &QueryServiceProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
-	self29.processorMap["HealthCheck"] = &queryServiceProcessorHealthCheck{handler: handler}
-	self29.processorMap["SearchTasks"] = &queryServiceProcessorSearchTasks{handler: handler}
-	self29.processorMap["AggregateFlows"] = &queryServiceProcessorAggregateFlows{handler: handler}
-	self29.processorMap["TraceFlow"] = &queryServiceProcessorTraceFlow{handler: handler}
-	self29.processorMap["QueryHeavyHitters"] = &queryServiceProcessorQueryHeavyHitters{handler: handler}
-	return self29
+	self35 := &QueryServiceProcessor{handler: handler, processorMap: make(map[string]thrift.TProcessorFunction)}
+	self35.processorMap["HealthCheck"] = &queryServiceProcessorHealthCheck{handler: handler}
+	self35.processorMap["SearchTasks"] = &queryServiceProcessorSearchTasks{handler: handler}
+	self35.processorMap["AggregateFlows"] = &queryServiceProcessorAggregateFlows{handler: handler}
+	self35.processorMap["TraceFlow"] = &queryServiceProcessorTraceFlow{handler: handler}
+	self35.processorMap["QueryHeavyHitters"] = &queryServiceProcessorQueryHeavyHitters{handler: handler}
+	self35.processorMap["QueryInterfaceCounters"] = &queryServiceProcessorQueryInterfaceCounters{handler: handler}
+	return self35
 }
 
 func (p *QueryServiceProcessor) Process(ctx context.Context, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
@@ -2965,12 +5576,12 @@ func (p *QueryServiceProcessor) Process(ctx context.Context, iprot, oprot thrift
 	}
 	iprot.Skip(ctx, thrift.STRUCT)
 	iprot.ReadMessageEnd(ctx)
-	x30 := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
+	x36 := thrift.NewTApplicationException(thrift.UNKNOWN_METHOD, "Unknown function "+name)
 	oprot.WriteMessageBegin(ctx, name, thrift.EXCEPTION, seqId)
-	x30.Write(ctx, oprot)
+	x36.Write(ctx, oprot)
 	oprot.WriteMessageEnd(ctx)
 	oprot.Flush(ctx)
-	return false, x30
+	return false, x36
 }
 
 type queryServiceProcessorHealthCheck struct {
@@ -2978,7 +5589,7 @@ type queryServiceProcessorHealthCheck struct {
 }
 
 func (p *queryServiceProcessorHealthCheck) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	var _write_err31 thrift.TException
+	var _write_err37 thrift.TException
 	args := QueryServiceHealthCheckArgs{}
 	if err2 := args.Read(ctx, iprot); err2 != nil {
 		iprot.ReadMessageEnd(ctx)
@@ -3035,22 +5646,22 @@ func (p *queryServiceProcessorHealthCheck) Process(ctx context.Context, seqId in
 				}
 			}
 		}
-		_exc32 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing HealthCheck: "+err2.Error())
+		_exc38 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing HealthCheck: "+err2.Error())
 		if err2 := oprot.WriteMessageBegin(ctx, "HealthCheck", thrift.EXCEPTION, seqId); err2 != nil {
-			_write_err31 = thrift.WrapTException(err2)
+			_write_err37 = thrift.WrapTException(err2)
 		}
-		if err2 := _exc32.Write(ctx, oprot); _write_err31 == nil && err2 != nil {
-			_write_err31 = thrift.WrapTException(err2)
+		if err2 := _exc38.Write(ctx, oprot); _write_err37 == nil && err2 != nil {
+			_write_err37 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.WriteMessageEnd(ctx); _write_err31 == nil && err2 != nil {
-			_write_err31 = thrift.WrapTException(err2)
+		if err2 := oprot.WriteMessageEnd(ctx); _write_err37 == nil && err2 != nil {
+			_write_err37 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.Flush(ctx); _write_err31 == nil && err2 != nil {
-			_write_err31 = thrift.WrapTException(err2)
+		if err2 := oprot.Flush(ctx); _write_err37 == nil && err2 != nil {
+			_write_err37 = thrift.WrapTException(err2)
 		}
-		if _write_err31 != nil {
+		if _write_err37 != nil {
 			return false, &thrift.ProcessorError{
-				WriteError:    _write_err31,
+				WriteError:    _write_err37,
 				EndpointError: err,
 			}
 		}
@@ -3060,20 +5671,20 @@ func (p *queryServiceProcessorHealthCheck) Process(ctx context.Context, seqId in
 	}
 	tickerCancel()
 	if err2 := oprot.WriteMessageBegin(ctx, "HealthCheck", thrift.REPLY, seqId); err2 != nil {
-		_write_err31 = thrift.WrapTException(err2)
+		_write_err37 = thrift.WrapTException(err2)
 	}
-	if err2 := result.Write(ctx, oprot); _write_err31 == nil && err2 != nil {
-		_write_err31 = thrift.WrapTException(err2)
+	if err2 := result.Write(ctx, oprot); _write_err37 == nil && err2 != nil {
+		_write_err37 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.WriteMessageEnd(ctx); _write_err31 == nil && err2 != nil {
-		_write_err31 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageEnd(ctx); _write_err37 == nil && err2 != nil {
+		_write_err37 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.Flush(ctx); _write_err31 == nil && err2 != nil {
-		_write_err31 = thrift.WrapTException(err2)
+	if err2 := oprot.Flush(ctx); _write_err37 == nil && err2 != nil {
+		_write_err37 = thrift.WrapTException(err2)
 	}
-	if _write_err31 != nil {
+	if _write_err37 != nil {
 		return false, &thrift.ProcessorError{
-			WriteError:    _write_err31,
+			WriteError:    _write_err37,
 			EndpointError: err,
 		}
 	}
@@ -3085,7 +5696,7 @@ type queryServiceProcessorSearchTasks struct {
 }
 
 func (p *queryServiceProcessorSearchTasks) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	var _write_err33 thrift.TException
+	var _write_err39 thrift.TException
 	args := QueryServiceSearchTasksArgs{}
 	if err2 := args.Read(ctx, iprot); err2 != nil {
 		iprot.ReadMessageEnd(ctx)
@@ -3142,22 +5753,22 @@ func (p *queryServiceProcessorSearchTasks) Process(ctx context.Context, seqId in
 				}
 			}
 		}
-		_exc34 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing SearchTasks: "+err2.Error())
+		_exc40 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing SearchTasks: "+err2.Error())
 		if err2 := oprot.WriteMessageBegin(ctx, "SearchTasks", thrift.EXCEPTION, seqId); err2 != nil {
-			_write_err33 = thrift.WrapTException(err2)
+			_write_err39 = thrift.WrapTException(err2)
 		}
-		if err2 := _exc34.Write(ctx, oprot); _write_err33 == nil && err2 != nil {
-			_write_err33 = thrift.WrapTException(err2)
+		if err2 := _exc40.Write(ctx, oprot); _write_err39 == nil && err2 != nil {
+			_write_err39 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.WriteMessageEnd(ctx); _write_err33 == nil && err2 != nil {
-			_write_err33 = thrift.WrapTException(err2)
+		if err2 := oprot.WriteMessageEnd(ctx); _write_err39 == nil && err2 != nil {
+			_write_err39 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.Flush(ctx); _write_err33 == nil && err2 != nil {
-			_write_err33 = thrift.WrapTException(err2)
+		if err2 := oprot.Flush(ctx); _write_err39 == nil && err2 != nil {
+			_write_err39 = thrift.WrapTException(err2)
 		}
-		if _write_err33 != nil {
+		if _write_err39 != nil {
 			return false, &thrift.ProcessorError{
-				WriteError:    _write_err33,
+				WriteError:    _write_err39,
 				EndpointError: err,
 			}
 		}
@@ -3167,20 +5778,20 @@ func (p *queryServiceProcessorSearchTasks) Process(ctx context.Context, seqId in
 	}
 	tickerCancel()
 	if err2 := oprot.WriteMessageBegin(ctx, "SearchTasks", thrift.REPLY, seqId); err2 != nil {
-		_write_err33 = thrift.WrapTException(err2)
+		_write_err39 = thrift.WrapTException(err2)
 	}
-	if err2 := result.Write(ctx, oprot); _write_err33 == nil && err2 != nil {
-		_write_err33 = thrift.WrapTException(err2)
+	if err2 := result.Write(ctx, oprot); _write_err39 == nil && err2 != nil {
+		_write_err39 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.WriteMessageEnd(ctx); _write_err33 == nil && err2 != nil {
-		_write_err33 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageEnd(ctx); _write_err39 == nil && err2 != nil {
+		_write_err39 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.Flush(ctx); _write_err33 == nil && err2 != nil {
-		_write_err33 = thrift.WrapTException(err2)
+	if err2 := oprot.Flush(ctx); _write_err39 == nil && err2 != nil {
+		_write_err39 = thrift.WrapTException(err2)
 	}
-	if _write_err33 != nil {
+	if _write_err39 != nil {
 		return false, &thrift.ProcessorError{
-			WriteError:    _write_err33,
+			WriteError:    _write_err39,
 			EndpointError: err,
 		}
 	}
@@ -3192,7 +5803,7 @@ type queryServiceProcessorAggregateFlows struct {
 }
 
 func (p *queryServiceProcessorAggregateFlows) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	var _write_err35 thrift.TException
+	var _write_err41 thrift.TException
 	args := QueryServiceAggregateFlowsArgs{}
 	if err2 := args.Read(ctx, iprot); err2 != nil {
 		iprot.ReadMessageEnd(ctx)
@@ -3249,22 +5860,22 @@ func (p *queryServiceProcessorAggregateFlows) Process(ctx context.Context, seqId
 				}
 			}
 		}
-		_exc36 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing AggregateFlows: "+err2.Error())
+		_exc42 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing AggregateFlows: "+err2.Error())
 		if err2 := oprot.WriteMessageBegin(ctx, "AggregateFlows", thrift.EXCEPTION, seqId); err2 != nil {
-			_write_err35 = thrift.WrapTException(err2)
+			_write_err41 = thrift.WrapTException(err2)
 		}
-		if err2 := _exc36.Write(ctx, oprot); _write_err35 == nil && err2 != nil {
-			_write_err35 = thrift.WrapTException(err2)
+		if err2 := _exc42.Write(ctx, oprot); _write_err41 == nil && err2 != nil {
+			_write_err41 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.WriteMessageEnd(ctx); _write_err35 == nil && err2 != nil {
-			_write_err35 = thrift.WrapTException(err2)
+		if err2 := oprot.WriteMessageEnd(ctx); _write_err41 == nil && err2 != nil {
+			_write_err41 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.Flush(ctx); _write_err35 == nil && err2 != nil {
-			_write_err35 = thrift.WrapTException(err2)
+		if err2 := oprot.Flush(ctx); _write_err41 == nil && err2 != nil {
+			_write_err41 = thrift.WrapTException(err2)
 		}
-		if _write_err35 != nil {
+		if _write_err41 != nil {
 			return false, &thrift.ProcessorError{
-				WriteError:    _write_err35,
+				WriteError:    _write_err41,
 				EndpointError: err,
 			}
 		}
@@ -3274,20 +5885,20 @@ func (p *queryServiceProcessorAggregateFlows) Process(ctx context.Context, seqId
 	}
 	tickerCancel()
 	if err2 := oprot.WriteMessageBegin(ctx, "AggregateFlows", thrift.REPLY, seqId); err2 != nil {
-		_write_err35 = thrift.WrapTException(err2)
+		_write_err41 = thrift.WrapTException(err2)
 	}
-	if err2 := result.Write(ctx, oprot); _write_err35 == nil && err2 != nil {
-		_write_err35 = thrift.WrapTException(err2)
+	if err2 := result.Write(ctx, oprot); _write_err41 == nil && err2 != nil {
+		_write_err41 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.WriteMessageEnd(ctx); _write_err35 == nil && err2 != nil {
-		_write_err35 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageEnd(ctx); _write_err41 == nil && err2 != nil {
+		_write_err41 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.Flush(ctx); _write_err35 == nil && err2 != nil {
-		_write_err35 = thrift.WrapTException(err2)
+	if err2 := oprot.Flush(ctx); _write_err41 == nil && err2 != nil {
+		_write_err41 = thrift.WrapTException(err2)
 	}
-	if _write_err35 != nil {
+	if _write_err41 != nil {
 		return false, &thrift.ProcessorError{
-			WriteError:    _write_err35,
+			WriteError:    _write_err41,
 			EndpointError: err,
 		}
 	}
@@ -3299,7 +5910,7 @@ type queryServiceProcessorTraceFlow struct {
 }
 
 func (p *queryServiceProcessorTraceFlow) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	var _write_err37 thrift.TException
+	var _write_err43 thrift.TException
 	args := QueryServiceTraceFlowArgs{}
 	if err2 := args.Read(ctx, iprot); err2 != nil {
 		iprot.ReadMessageEnd(ctx)
@@ -3356,22 +5967,129 @@ func (p *queryServiceProcessorTraceFlow) Process(ctx context.Context, seqId int3
 				}
 			}
 		}
-		_exc38 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing TraceFlow: "+err2.Error())
-		if err2 := oprot.WriteMessageBegin(ctx, "TraceFlow", thrift.EXCEPTION, seqId); err2 != nil {
-			_write_err37 = thrift.WrapTException(err2)
+		_exc44 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing TraceFlow: "+err2.Error())
+		if err2 := oprot.WriteMessageBegin(ctx, "TraceFlow", thrift.EXCEPTION, seqId); err2 != nil {
+			_write_err43 = thrift.WrapTException(err2)
+		}
+		if err2 := _exc44.Write(ctx, oprot); _write_err43 == nil && err2 != nil {
+			_write_err43 = thrift.WrapTException(err2)
+		}
+		if err2 := oprot.WriteMessageEnd(ctx); _write_err43 == nil && err2 != nil {
+			_write_err43 = thrift.WrapTException(err2)
+		}
+		if err2 := oprot.Flush(ctx); _write_err43 == nil && err2 != nil {
+			_write_err43 = thrift.WrapTException(err2)
+		}
+		if _write_err43 != nil {
+			return false, &thrift.ProcessorError{
+				WriteError:    _write_err43,
+				EndpointError: err,
+			}
+		}
+		return true, err
+	} else {
+		result.Success = retval
+	}
+	tickerCancel()
+	if err2 := oprot.WriteMessageBegin(ctx, "TraceFlow", thrift.REPLY, seqId); err2 != nil {
+		_write_err43 = thrift.WrapTException(err2)
+	}
+	if err2 := result.Write(ctx, oprot); _write_err43 == nil && err2 != nil {
+		_write_err43 = thrift.WrapTException(err2)
+	}
+	if err2 := oprot.WriteMessageEnd(ctx); _write_err43 == nil && err2 != nil {
+		_write_err43 = thrift.WrapTException(err2)
+	}
+	if err2 := oprot.Flush(ctx); _write_err43 == nil && err2 != nil {
+		_write_err43 = thrift.WrapTException(err2)
+	}
+	if _write_err43 != nil {
+		return false, &thrift.ProcessorError{
+			WriteError:    _write_err43,
+			EndpointError: err,
+		}
+	}
+	return true, err
+}
+
+type queryServiceProcessorQueryHeavyHitters struct {
+	handler QueryService
+}
+
+func (p *queryServiceProcessorQueryHeavyHitters) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	var _write_err45 thrift.TException
+	args := QueryServiceQueryHeavyHittersArgs{}
+	if err2 := args.Read(ctx, iprot); err2 != nil {
+		iprot.ReadMessageEnd(ctx)
+		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err2.Error())
+		oprot.WriteMessageBegin(ctx, "QueryHeavyHitters", thrift.EXCEPTION, seqId)
+		x.Write(ctx, oprot)
+		oprot.WriteMessageEnd(ctx)
+		oprot.Flush(ctx)
+		return false, thrift.WrapTException(err2)
+	}
+	iprot.ReadMessageEnd(ctx)
+
+	tickerCancel := func() {}
+	// Start a goroutine to do server side connectivity check.
+	if thrift.ServerConnectivityCheckInterval > 0 {
+		var cancel context.CancelCauseFunc
+		ctx, cancel = context.WithCancelCause(ctx)
+		defer cancel(nil)
+		var tickerCtx context.Context
+		tickerCtx, tickerCancel = context.WithCancel(context.Background())
+		defer tickerCancel()
+		go func(ctx context.Context, cancel context.CancelCauseFunc) {
+			ticker := time.NewTicker(thrift.ServerConnectivityCheckInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					if !iprot.Transport().IsOpen() {
+						cancel(thrift.ErrAbandonRequest)
+						return
+					}
+				}
+			}
+		}(tickerCtx, cancel)
+	}
+
+	result := QueryServiceQueryHeavyHittersResult{}
+	if retval, err2 := p.handler.QueryHeavyHitters(ctx, args.Req); err2 != nil {
+		tickerCancel()
+		err = thrift.WrapTException(err2)
+		if errors.Is(err2, thrift.ErrAbandonRequest) {
+			return false, &thrift.ProcessorError{
+				WriteError:    thrift.WrapTException(err2),
+				EndpointError: err,
+			}
+		}
+		if errors.Is(err2, context.Canceled) {
+			if err3 := context.Cause(ctx); errors.Is(err3, thrift.ErrAbandonRequest) {
+				return false, &thrift.ProcessorError{
+					WriteError:    thrift.WrapTException(err3),
+					EndpointError: err,
+				}
+			}
+		}
+		_exc46 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing QueryHeavyHitters: "+err2.Error())
+		if err2 := oprot.WriteMessageBegin(ctx, "QueryHeavyHitters", thrift.EXCEPTION, seqId); err2 != nil {
+			_write_err45 = thrift.WrapTException(err2)
 		}
-		if err2 := _exc38.Write(ctx, oprot); _write_err37 == nil && err2 != nil {
-			_write_err37 = thrift.WrapTException(err2)
+		if err2 := _exc46.Write(ctx, oprot); _write_err45 == nil && err2 != nil {
+			_write_err45 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.WriteMessageEnd(ctx); _write_err37 == nil && err2 != nil {
-			_write_err37 = thrift.WrapTException(err2)
+		if err2 := oprot.WriteMessageEnd(ctx); _write_err45 == nil && err2 != nil {
+			_write_err45 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.Flush(ctx); _write_err37 == nil && err2 != nil {
-			_write_err37 = thrift.WrapTException(err2)
+		if err2 := oprot.Flush(ctx); _write_err45 == nil && err2 != nil {
+			_write_err45 = thrift.WrapTException(err2)
 		}
-		if _write_err37 != nil {
+		if _write_err45 != nil {
 			return false, &thrift.ProcessorError{
-				WriteError:    _write_err37,
+				WriteError:    _write_err45,
 				EndpointError: err,
 			}
 		}
@@ -3380,38 +6098,38 @@ func (p *queryServiceProcessorTraceFlow) Process(ctx context.Context, seqId int3
 		result.Success = retval
 	}
 	tickerCancel()
-	if err2 := oprot.WriteMessageBegin(ctx, "TraceFlow", thrift.REPLY, seqId); err2 != nil {
-		_write_err37 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageBegin(ctx, "QueryHeavyHitters", thrift.REPLY, seqId); err2 != nil {
+		_write_err45 = thrift.WrapTException(err2)
 	}
-	if err2 := result.Write(ctx, oprot); _write_err37 == nil && err2 != nil {
-		_write_err37 = thrift.WrapTException(err2)
+	if err2 := result.Write(ctx, oprot); _write_err45 == nil && err2 != nil {
+		_write_err45 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.WriteMessageEnd(ctx); _write_err37 == nil && err2 != nil {
-		_write_err37 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageEnd(ctx); _write_err45 == nil && err2 != nil {
+		_write_err45 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.Flush(ctx); _write_err37 == nil && err2 != nil {
-		_write_err37 = thrift.WrapTException(err2)
+	if err2 := oprot.Flush(ctx); _write_err45 == nil && err2 != nil {
+		_write_err45 = thrift.WrapTException(err2)
 	}
-	if _write_err37 != nil {
+	if _write_err45 != nil {
 		return false, &thrift.ProcessorError{
-			WriteError:    _write_err37,
+			WriteError:    _write_err45,
 			EndpointError: err,
 		}
 	}
 	return true, err
 }
 
-type queryServiceProcessorQueryHeavyHitters struct {
+type queryServiceProcessorQueryInterfaceCounters struct {
 	handler QueryService
 }
 
-func (p *queryServiceProcessorQueryHeavyHitters) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
-	var _write_err39 thrift.TException
-	args := QueryServiceQueryHeavyHittersArgs{}
+func (p *queryServiceProcessorQueryInterfaceCounters) Process(ctx context.Context, seqId int32, iprot, oprot thrift.TProtocol) (success bool, err thrift.TException) {
+	var _write_err47 thrift.TException
+	args := QueryServiceQueryInterfaceCountersArgs{}
 	if err2 := args.Read(ctx, iprot); err2 != nil {
 		iprot.ReadMessageEnd(ctx)
 		x := thrift.NewTApplicationException(thrift.PROTOCOL_ERROR, err2.Error())
-		oprot.WriteMessageBegin(ctx, "QueryHeavyHitters", thrift.EXCEPTION, seqId)
+		oprot.WriteMessageBegin(ctx, "QueryInterfaceCounters", thrift.EXCEPTION, seqId)
 		x.Write(ctx, oprot)
 		oprot.WriteMessageEnd(ctx)
 		oprot.Flush(ctx)
@@ -3445,8 +6163,8 @@ func (p *queryServiceProcessorQueryHeavyHitters) Process(ctx context.Context, se
 		}(tickerCtx, cancel)
 	}
 
-	result := QueryServiceQueryHeavyHittersResult{}
-	if retval, err2 := p.handler.QueryHeavyHitters(ctx, args.Req); err2 != nil {
+	result := QueryServiceQueryInterfaceCountersResult{}
+	if retval, err2 := p.handler.QueryInterfaceCounters(ctx, args.Req); err2 != nil {
 		tickerCancel()
 		err = thrift.WrapTException(err2)
 		if errors.Is(err2, thrift.ErrAbandonRequest) {
@@ -3463,22 +6181,22 @@ func (p *queryServiceProcessorQueryHeavyHitters) Process(ctx context.Context, se
 				}
 			}
 		}
-		_exc40 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing QueryHeavyHitters: "+err2.Error())
-		if err2 := oprot.WriteMessageBegin(ctx, "QueryHeavyHitters", thrift.EXCEPTION, seqId); err2 != nil {
-			_write_err39 = thrift.WrapTException(err2)
+		_exc48 := thrift.NewTApplicationException(thrift.INTERNAL_ERROR, "Internal error processing QueryInterfaceCounters: "+err2.Error())
+		if err2 := oprot.WriteMessageBegin(ctx, "QueryInterfaceCounters", thrift.EXCEPTION, seqId); err2 != nil {
+			_write_err47 = thrift.WrapTException(err2)
 		}
-		if err2 := _exc40.Write(ctx, oprot); _write_err39 == nil && err2 != nil {
-			_write_err39 = thrift.WrapTException(err2)
+		if err2 := _exc48.Write(ctx, oprot); _write_err47 == nil && err2 != nil {
+			_write_err47 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.WriteMessageEnd(ctx); _write_err39 == nil && err2 != nil {
-			_write_err39 = thrift.WrapTException(err2)
+		if err2 := oprot.WriteMessageEnd(ctx); _write_err47 == nil && err2 != nil {
+			_write_err47 = thrift.WrapTException(err2)
 		}
-		if err2 := oprot.Flush(ctx); _write_err39 == nil && err2 != nil {
-			_write_err39 = thrift.WrapTException(err2)
+		if err2 := oprot.Flush(ctx); _write_err47 == nil && err2 != nil {
+			_write_err47 = thrift.WrapTException(err2)
 		}
-		if _write_err39 != nil {
+		if _write_err47 != nil {
 			return false, &thrift.ProcessorError{
-				WriteError:    _write_err39,
+				WriteError:    _write_err47,
 				EndpointError: err,
 			}
 		}
@@ -3487,21 +6205,21 @@ func (p *queryServiceProcessorQueryHeavyHitters) Process(ctx context.Context, se
 		result.Success = retval
 	}
 	tickerCancel()
-	if err2 := oprot.WriteMessageBegin(ctx, "QueryHeavyHitters", thrift.REPLY, seqId); err2 != nil {
-		_write_err39 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageBegin(ctx, "QueryInterfaceCounters", thrift.REPLY, seqId); err2 != nil {
+		_write_err47 = thrift.WrapTException(err2)
 	}
-	if err2 := result.Write(ctx, oprot); _write_err39 == nil && err2 != nil {
-		_write_err39 = thrift.WrapTException(err2)
+	if err2 := result.Write(ctx, oprot); _write_err47 == nil && err2 != nil {
+		_write_err47 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.WriteMessageEnd(ctx); _write_err39 == nil && err2 != nil {
-		_write_err39 = thrift.WrapTException(err2)
+	if err2 := oprot.WriteMessageEnd(ctx); _write_err47 == nil && err2 != nil {
+		_write_err47 = thrift.WrapTException(err2)
 	}
-	if err2 := oprot.Flush(ctx); _write_err39 == nil && err2 != nil {
-		_write_err39 = thrift.WrapTException(err2)
+	if err2 := oprot.Flush(ctx); _write_err47 == nil && err2 != nil {
+		_write_err47 = thrift.WrapTException(err2)
 	}
-	if _write_err39 != nil {
+	if _write_err47 != nil {
 		return false, &thrift.ProcessorError{
-			WriteError:    _write_err39,
+			WriteError:    _write_err47,
 			EndpointError: err,
 		}
 	}
@@ -4729,3 +7447,247 @@ func (p *QueryServiceQueryHeavyHittersResult) LogValue() slog.Value {
 }
 
 var _ slog.LogValuer = (*QueryServiceQueryHeavyHittersResult)(nil)
+
+// Attributes:
+//   - Req
+type QueryServiceQueryInterfaceCountersArgs struct {
+	Req *InterfaceCountersRequest `thrift:"req,1" db:"req" json:"req"`
+}
+
+func NewQueryServiceQueryInterfaceCountersArgs() *QueryServiceQueryInterfaceCountersArgs {
+	return &QueryServiceQueryInterfaceCountersArgs{}
+}
+
+var QueryServiceQueryInterfaceCountersArgs_Req_DEFAULT *InterfaceCountersRequest
+
+func (p *QueryServiceQueryInterfaceCountersArgs) GetReq() *InterfaceCountersRequest {
+	if !p.IsSetReq() {
+		return QueryServiceQueryInterfaceCountersArgs_Req_DEFAULT
+	}
+	return p.Req
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) IsSetReq() bool {
+	return p.Req != nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.STRUCT {
+				if err := p.ReadField1(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+	p.Req = &InterfaceCountersRequest{}
+	if err := p.Req.Read(ctx, iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Req), err)
+	}
+	return nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "QueryInterfaceCounters_args"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "req", thrift.STRUCT, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:req: ", p), err)
+	}
+	if err := p.Req.Write(ctx, oprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Req), err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:req: ", p), err)
+	}
+	return err
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("QueryServiceQueryInterfaceCountersArgs(%+v)", *p)
+}
+
+func (p *QueryServiceQueryInterfaceCountersArgs) LogValue() slog.Value {
+	if p == nil {
+		return slog.AnyValue(nil)
+	}
+	v := thrift.SlogTStructWrapper{
+		Type:  "*v1.QueryServiceQueryInterfaceCountersArgs",
+		Value: p,
+	}
+	return slog.AnyValue(v)
+}
+
+var _ slog.LogValuer = (*QueryServiceQueryInterfaceCountersArgs)(nil)
+
+// Attributes:
+//   - Success
+type QueryServiceQueryInterfaceCountersResult struct {
+	Success *InterfaceCountersResponse `thrift:"success,0" db:"success" json:"success,omitempty"`
+}
+
+func NewQueryServiceQueryInterfaceCountersResult() *QueryServiceQueryInterfaceCountersResult {
+	return &QueryServiceQueryInterfaceCountersResult{}
+}
+
+var QueryServiceQueryInterfaceCountersResult_Success_DEFAULT *InterfaceCountersResponse
+
+func (p *QueryServiceQueryInterfaceCountersResult) GetSuccess() *InterfaceCountersResponse {
+	if !p.IsSetSuccess() {
+		return QueryServiceQueryInterfaceCountersResult_Success_DEFAULT
+	}
+	return p.Success
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) IsSetSuccess() bool {
+	return p.Success != nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 0:
+			if fieldTypeId == thrift.STRUCT {
+				if err := p.ReadField0(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	return nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) ReadField0(ctx context.Context, iprot thrift.TProtocol) error {
+	p.Success = &InterfaceCountersResponse{}
+	if err := p.Success.Read(ctx, iprot); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", p.Success), err)
+	}
+	return nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "QueryInterfaceCounters_result"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField0(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) writeField0(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSuccess() {
+		if err := oprot.WriteFieldBegin(ctx, "success", thrift.STRUCT, 0); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 0:success: ", p), err)
+		}
+		if err := p.Success.Write(ctx, oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", p.Success), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 0:success: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("QueryServiceQueryInterfaceCountersResult(%+v)", *p)
+}
+
+func (p *QueryServiceQueryInterfaceCountersResult) LogValue() slog.Value {
+	if p == nil {
+		return slog.AnyValue(nil)
+	}
+	v := thrift.SlogTStructWrapper{
+		Type:  "*v1.QueryServiceQueryInterfaceCountersResult",
+		Value: p,
+	}
+	return slog.AnyValue(v)
+}
+
+var _ slog.LogValuer = (*QueryServiceQueryInterfaceCountersResult)(nil)
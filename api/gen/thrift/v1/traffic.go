@@ -365,10 +365,24 @@ func (p *FiveTuple) Validate() error {
 //   - TimestampUnixNano
 //   - FiveTuple
 //   - Length
+//   - TCPFlags
+//   - TunnelID
+//   - OuterVlan
+//   - InnerVlan
+//   - MplsLabel
+//   - InterfaceID
+//   - SampleRate
 type PacketInfo struct {
 	TimestampUnixNano int64      `thrift:"timestamp_unix_nano,1,required" db:"timestamp_unix_nano" json:"timestamp_unix_nano"`
 	FiveTuple         *FiveTuple `thrift:"five_tuple,2,required" db:"five_tuple" json:"five_tuple"`
 	Length            int64      `thrift:"length,3,required" db:"length" json:"length"`
+	TCPFlags          *int32     `thrift:"tcp_flags,4" db:"tcp_flags" json:"tcp_flags,omitempty"`
+	TunnelID          *int64     `thrift:"tunnel_id,5" db:"tunnel_id" json:"tunnel_id,omitempty"`
+	OuterVlan         *int32     `thrift:"outer_vlan,6" db:"outer_vlan" json:"outer_vlan,omitempty"`
+	InnerVlan         *int32     `thrift:"inner_vlan,7" db:"inner_vlan" json:"inner_vlan,omitempty"`
+	MplsLabel         *int32     `thrift:"mpls_label,8" db:"mpls_label" json:"mpls_label,omitempty"`
+	InterfaceID       *int64     `thrift:"interface_id,9" db:"interface_id" json:"interface_id,omitempty"`
+	SampleRate        *int64     `thrift:"sample_rate,10" db:"sample_rate" json:"sample_rate,omitempty"`
 }
 
 func NewPacketInfo() *PacketInfo {
@@ -392,10 +406,101 @@ func (p *PacketInfo) GetLength() int64 {
 	return p.Length
 }
 
+var PacketInfo_TCPFlags_DEFAULT int32
+
+func (p *PacketInfo) GetTCPFlags() int32 {
+	if !p.IsSetTCPFlags() {
+		return PacketInfo_TCPFlags_DEFAULT
+	}
+	return *p.TCPFlags
+}
+
+var PacketInfo_TunnelID_DEFAULT int64
+
+func (p *PacketInfo) GetTunnelID() int64 {
+	if !p.IsSetTunnelID() {
+		return PacketInfo_TunnelID_DEFAULT
+	}
+	return *p.TunnelID
+}
+
+var PacketInfo_OuterVlan_DEFAULT int32
+
+func (p *PacketInfo) GetOuterVlan() int32 {
+	if !p.IsSetOuterVlan() {
+		return PacketInfo_OuterVlan_DEFAULT
+	}
+	return *p.OuterVlan
+}
+
+var PacketInfo_InnerVlan_DEFAULT int32
+
+func (p *PacketInfo) GetInnerVlan() int32 {
+	if !p.IsSetInnerVlan() {
+		return PacketInfo_InnerVlan_DEFAULT
+	}
+	return *p.InnerVlan
+}
+
+var PacketInfo_MplsLabel_DEFAULT int32
+
+func (p *PacketInfo) GetMplsLabel() int32 {
+	if !p.IsSetMplsLabel() {
+		return PacketInfo_MplsLabel_DEFAULT
+	}
+	return *p.MplsLabel
+}
+
+var PacketInfo_InterfaceID_DEFAULT int64
+
+func (p *PacketInfo) GetInterfaceID() int64 {
+	if !p.IsSetInterfaceID() {
+		return PacketInfo_InterfaceID_DEFAULT
+	}
+	return *p.InterfaceID
+}
+
+var PacketInfo_SampleRate_DEFAULT int64
+
+func (p *PacketInfo) GetSampleRate() int64 {
+	if !p.IsSetSampleRate() {
+		return PacketInfo_SampleRate_DEFAULT
+	}
+	return *p.SampleRate
+}
+
 func (p *PacketInfo) IsSetFiveTuple() bool {
 	return p.FiveTuple != nil
 }
 
+func (p *PacketInfo) IsSetTCPFlags() bool {
+	return p.TCPFlags != nil
+}
+
+func (p *PacketInfo) IsSetTunnelID() bool {
+	return p.TunnelID != nil
+}
+
+func (p *PacketInfo) IsSetOuterVlan() bool {
+	return p.OuterVlan != nil
+}
+
+func (p *PacketInfo) IsSetInnerVlan() bool {
+	return p.InnerVlan != nil
+}
+
+func (p *PacketInfo) IsSetMplsLabel() bool {
+	return p.MplsLabel != nil
+}
+
+func (p *PacketInfo) IsSetInterfaceID() bool {
+	return p.InterfaceID != nil
+}
+
+func (p *PacketInfo) IsSetSampleRate() bool {
+	return p.SampleRate != nil
+}
+
 func (p *PacketInfo) Read(ctx context.Context, iprot thrift.TProtocol) error {
 	if _, err := iprot.ReadStructBegin(ctx); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
@@ -447,6 +552,76 @@ func (p *PacketInfo) Read(ctx context.Context, iprot thrift.TProtocol) error {
 					return err
 				}
 			}
+		case 4:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField4(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 5:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField5(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 6:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField6(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 7:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField7(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 8:
+			if fieldTypeId == thrift.I32 {
+				if err := p.ReadField8(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 9:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField9(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		case 10:
+			if fieldTypeId == thrift.I64 {
+				if err := p.ReadField10(ctx, iprot); err != nil {
+					return err
+				}
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
 		default:
 			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
 				return err
@@ -497,6 +672,69 @@ func (p *PacketInfo) ReadField3(ctx context.Context, iprot thrift.TProtocol) err
 	return nil
 }
 
+func (p *PacketInfo) ReadField4(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 4: ", err)
+	} else {
+		p.TCPFlags = &v
+	}
+	return nil
+}
+
+func (p *PacketInfo) ReadField5(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 5: ", err)
+	} else {
+		p.TunnelID = &v
+	}
+	return nil
+}
+
+func (p *PacketInfo) ReadField6(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 6: ", err)
+	} else {
+		p.OuterVlan = &v
+	}
+	return nil
+}
+
+func (p *PacketInfo) ReadField7(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 7: ", err)
+	} else {
+		p.InnerVlan = &v
+	}
+	return nil
+}
+
+func (p *PacketInfo) ReadField8(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI32(ctx); err != nil {
+		return thrift.PrependError("error reading field 8: ", err)
+	} else {
+		p.MplsLabel = &v
+	}
+	return nil
+}
+
+func (p *PacketInfo) ReadField9(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 9: ", err)
+	} else {
+		p.InterfaceID = &v
+	}
+	return nil
+}
+
+func (p *PacketInfo) ReadField10(ctx context.Context, iprot thrift.TProtocol) error {
+	if v, err := iprot.ReadI64(ctx); err != nil {
+		return thrift.PrependError("error reading field 10: ", err)
+	} else {
+		p.SampleRate = &v
+	}
+	return nil
+}
+
 func (p *PacketInfo) Write(ctx context.Context, oprot thrift.TProtocol) error {
 	if err := oprot.WriteStructBegin(ctx, "PacketInfo"); err != nil {
 		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
@@ -511,6 +749,27 @@ func (p *PacketInfo) Write(ctx context.Context, oprot thrift.TProtocol) error {
 		if err := p.writeField3(ctx, oprot); err != nil {
 			return err
 		}
+		if err := p.writeField4(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField5(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField6(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField7(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField8(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField9(ctx, oprot); err != nil {
+			return err
+		}
+		if err := p.writeField10(ctx, oprot); err != nil {
+			return err
+		}
 	}
 	if err := oprot.WriteFieldStop(ctx); err != nil {
 		return thrift.PrependError("write field stop error: ", err)
@@ -560,6 +819,111 @@ func (p *PacketInfo) writeField3(ctx context.Context, oprot thrift.TProtocol) (e
 	return err
 }
 
+func (p *PacketInfo) writeField4(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetTCPFlags() {
+		if err := oprot.WriteFieldBegin(ctx, "tcp_flags", thrift.I32, 4); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 4:tcp_flags: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.TCPFlags)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.tcp_flags (4) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 4:tcp_flags: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *PacketInfo) writeField5(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetTunnelID() {
+		if err := oprot.WriteFieldBegin(ctx, "tunnel_id", thrift.I64, 5); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 5:tunnel_id: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.TunnelID)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.tunnel_id (5) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 5:tunnel_id: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *PacketInfo) writeField6(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetOuterVlan() {
+		if err := oprot.WriteFieldBegin(ctx, "outer_vlan", thrift.I32, 6); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 6:outer_vlan: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.OuterVlan)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.outer_vlan (6) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 6:outer_vlan: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *PacketInfo) writeField7(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetInnerVlan() {
+		if err := oprot.WriteFieldBegin(ctx, "inner_vlan", thrift.I32, 7); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 7:inner_vlan: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.InnerVlan)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.inner_vlan (7) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 7:inner_vlan: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *PacketInfo) writeField8(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetMplsLabel() {
+		if err := oprot.WriteFieldBegin(ctx, "mpls_label", thrift.I32, 8); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 8:mpls_label: ", p), err)
+		}
+		if err := oprot.WriteI32(ctx, int32(*p.MplsLabel)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.mpls_label (8) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 8:mpls_label: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *PacketInfo) writeField9(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetInterfaceID() {
+		if err := oprot.WriteFieldBegin(ctx, "interface_id", thrift.I64, 9); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 9:interface_id: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.InterfaceID)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.interface_id (9) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 9:interface_id: ", p), err)
+		}
+	}
+	return err
+}
+
+func (p *PacketInfo) writeField10(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if p.IsSetSampleRate() {
+		if err := oprot.WriteFieldBegin(ctx, "sample_rate", thrift.I64, 10); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field begin error 10:sample_rate: ", p), err)
+		}
+		if err := oprot.WriteI64(ctx, int64(*p.SampleRate)); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T.sample_rate (10) field write error: ", p), err)
+		}
+		if err := oprot.WriteFieldEnd(ctx); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T write field end error 10:sample_rate: ", p), err)
+		}
+	}
+	return err
+}
+
 func (p *PacketInfo) Equals(other *PacketInfo) bool {
 	if p == other {
 		return true
@@ -575,6 +939,62 @@ func (p *PacketInfo) Equals(other *PacketInfo) bool {
 	if p.Length != other.Length {
 		return false
 	}
+	if p.TCPFlags != other.TCPFlags {
+		if p.TCPFlags == nil || other.TCPFlags == nil {
+			return false
+		}
+		if (*p.TCPFlags) != (*other.TCPFlags) {
+			return false
+		}
+	}
+	if p.TunnelID != other.TunnelID {
+		if p.TunnelID == nil || other.TunnelID == nil {
+			return false
+		}
+		if (*p.TunnelID) != (*other.TunnelID) {
+			return false
+		}
+	}
+	if p.OuterVlan != other.OuterVlan {
+		if p.OuterVlan == nil || other.OuterVlan == nil {
+			return false
+		}
+		if (*p.OuterVlan) != (*other.OuterVlan) {
+			return false
+		}
+	}
+	if p.InnerVlan != other.InnerVlan {
+		if p.InnerVlan == nil || other.InnerVlan == nil {
+			return false
+		}
+		if (*p.InnerVlan) != (*other.InnerVlan) {
+			return false
+		}
+	}
+	if p.MplsLabel != other.MplsLabel {
+		if p.MplsLabel == nil || other.MplsLabel == nil {
+			return false
+		}
+		if (*p.MplsLabel) != (*other.MplsLabel) {
+			return false
+		}
+	}
+	if p.InterfaceID != other.InterfaceID {
+		if p.InterfaceID == nil || other.InterfaceID == nil {
+			return false
+		}
+		if (*p.InterfaceID) != (*other.InterfaceID) {
+			return false
+		}
+	}
+	if p.SampleRate != other.SampleRate {
+		if p.SampleRate == nil || other.SampleRate == nil {
+			return false
+		}
+		if (*p.SampleRate) != (*other.SampleRate) {
+			return false
+		}
+	}
 	return true
 }
 
@@ -601,3 +1021,163 @@ var _ slog.LogValuer = (*PacketInfo)(nil)
 func (p *PacketInfo) Validate() error {
 	return nil
 }
+
+// Attributes:
+//   - Packets
+type PacketBatch struct {
+	Packets []*PacketInfo `thrift:"packets,1,required" db:"packets" json:"packets"`
+}
+
+func NewPacketBatch() *PacketBatch {
+	return &PacketBatch{}
+}
+
+func (p *PacketBatch) GetPackets() []*PacketInfo {
+	return p.Packets
+}
+
+func (p *PacketBatch) Read(ctx context.Context, iprot thrift.TProtocol) error {
+	if _, err := iprot.ReadStructBegin(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read error: ", p), err)
+	}
+
+	var issetPackets bool = false
+
+	for {
+		_, fieldTypeId, fieldId, err := iprot.ReadFieldBegin(ctx)
+		if err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T field %d read error: ", p, fieldId), err)
+		}
+		if fieldTypeId == thrift.STOP {
+			break
+		}
+		switch fieldId {
+		case 1:
+			if fieldTypeId == thrift.LIST {
+				if err := p.ReadField1(ctx, iprot); err != nil {
+					return err
+				}
+				issetPackets = true
+			} else {
+				if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := iprot.Skip(ctx, fieldTypeId); err != nil {
+				return err
+			}
+		}
+		if err := iprot.ReadFieldEnd(ctx); err != nil {
+			return err
+		}
+	}
+	if err := iprot.ReadStructEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T read struct end error: ", p), err)
+	}
+	if !issetPackets {
+		return thrift.NewTProtocolExceptionWithType(thrift.INVALID_DATA, fmt.Errorf("Required field Packets is not set"))
+	}
+	return nil
+}
+
+func (p *PacketBatch) ReadField1(ctx context.Context, iprot thrift.TProtocol) error {
+	_, size, err := iprot.ReadListBegin(ctx)
+	if err != nil {
+		return thrift.PrependError("error reading list begin: ", err)
+	}
+	tSlice := make([]*PacketInfo, 0, size)
+	p.Packets = tSlice
+	for i := 0; i < size; i++ {
+		_elem0 := &PacketInfo{}
+		if err := _elem0.Read(ctx, iprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error reading struct: ", _elem0), err)
+		}
+		p.Packets = append(p.Packets, _elem0)
+	}
+	if err := iprot.ReadListEnd(ctx); err != nil {
+		return thrift.PrependError("error reading list end: ", err)
+	}
+	return nil
+}
+
+func (p *PacketBatch) Write(ctx context.Context, oprot thrift.TProtocol) error {
+	if err := oprot.WriteStructBegin(ctx, "PacketBatch"); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write struct begin error: ", p), err)
+	}
+	if p != nil {
+		if err := p.writeField1(ctx, oprot); err != nil {
+			return err
+		}
+	}
+	if err := oprot.WriteFieldStop(ctx); err != nil {
+		return thrift.PrependError("write field stop error: ", err)
+	}
+	if err := oprot.WriteStructEnd(ctx); err != nil {
+		return thrift.PrependError("write struct stop error: ", err)
+	}
+	return nil
+}
+
+func (p *PacketBatch) writeField1(ctx context.Context, oprot thrift.TProtocol) (err error) {
+	if err := oprot.WriteFieldBegin(ctx, "packets", thrift.LIST, 1); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field begin error 1:packets: ", p), err)
+	}
+	if err := oprot.WriteListBegin(ctx, thrift.STRUCT, len(p.Packets)); err != nil {
+		return thrift.PrependError("error writing list begin: ", err)
+	}
+	for _, v := range p.Packets {
+		if err := v.Write(ctx, oprot); err != nil {
+			return thrift.PrependError(fmt.Sprintf("%T error writing struct: ", v), err)
+		}
+	}
+	if err := oprot.WriteListEnd(ctx); err != nil {
+		return thrift.PrependError("error writing list end: ", err)
+	}
+	if err := oprot.WriteFieldEnd(ctx); err != nil {
+		return thrift.PrependError(fmt.Sprintf("%T write field end error 1:packets: ", p), err)
+	}
+	return err
+}
+
+func (p *PacketBatch) Equals(other *PacketBatch) bool {
+	if p == other {
+		return true
+	} else if p == nil || other == nil {
+		return false
+	}
+	if len(p.Packets) != len(other.Packets) {
+		return false
+	}
+	for i, _tgt := range p.Packets {
+		_src1 := other.Packets[i]
+		if !_tgt.Equals(_src1) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *PacketBatch) String() string {
+	if p == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("PacketBatch(%+v)", *p)
+}
+
+func (p *PacketBatch) LogValue() slog.Value {
+	if p == nil {
+		return slog.AnyValue(nil)
+	}
+	v := thrift.SlogTStructWrapper{
+		Type:  "*v1.PacketBatch",
+		Value: p,
+	}
+	return slog.AnyValue(v)
+}
+
+var _ slog.LogValuer = (*PacketBatch)(nil)
+
+func (p *PacketBatch) Validate() error {
+	return nil
+}
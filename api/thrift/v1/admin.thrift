@@ -0,0 +1,21 @@
+namespace go v1
+
+struct ReloadConfigRequest {
+}
+
+struct ReloadConfigResponse {
+  1: required bool applied
+  2: required list<string> tasks_added
+  3: required list<string> tasks_removed
+  4: required list<string> tasks_kept
+  5: required i32 writers_started
+  6: required i32 writers_stopped
+  7: required i32 writers_kept
+  8: required i32 alert_rules
+  9: required list<string> warnings
+  10: optional string error_text
+}
+
+service AdminService {
+  ReloadConfigResponse ReloadConfig(1: ReloadConfigRequest req)
+}
@@ -8,6 +8,13 @@ struct AggregationRequest {
   5: optional i32 src_port
   6: optional i32 dst_port
   7: optional i32 protocol
+  8: optional string conn_state
+  9: optional i64 tunnel_id
+  10: optional i32 outer_vlan
+  11: optional i32 inner_vlan
+  12: optional i32 mpls_label
+  13: optional i64 interface_id
+  14: optional string exporter_ip
 }
 
 struct TaskSummary {
@@ -15,6 +22,11 @@ struct TaskSummary {
   2: required i64 total_bytes
   3: required i64 total_packets
   4: required i64 flow_count
+  5: optional i64 syn_count
+  6: optional i64 fin_count
+  7: optional i64 rst_count
+  8: optional i64 sample_rate
+  9: optional bool estimated
 }
 
 struct QueryTotalCountsResponse {
@@ -32,6 +44,16 @@ struct FlowLifecycle {
   2: required i64 last_seen_unix_nano
   3: required i64 total_packets
   4: required i64 total_bytes
+  5: optional i64 syn_count
+  6: optional i64 fin_count
+  7: optional i64 rst_count
+  8: optional i64 ack_count
+  9: optional i32 tcp_flags
+  10: optional string conn_state
+  11: optional i64 sample_rate
+  12: optional bool estimated
+  13: optional i64 lifecycles
+  14: optional string end_reason
 }
 
 struct TraceFlowResponse {
@@ -60,16 +82,45 @@ struct HeavyHittersRequest {
 struct HeavyHitter {
   1: required string flow
   2: required i64 value
+  3: optional i64 sample_rate
+  4: optional bool estimated
 }
 
 struct HeavyHittersResponse {
   1: required list<HeavyHitter> hitters
 }
 
+struct InterfaceCountersRequest {
+  1: optional i64 end_time_unix_nano
+  2: optional string agent_ip
+  3: optional i64 if_index
+}
+
+struct InterfaceCounters {
+  1: required string agent_ip
+  2: required i64 if_index
+  3: required i64 timestamp_unix_nano
+  4: required i64 if_speed
+  5: required i32 if_status
+  6: required i64 in_octets
+  7: required i64 in_packets
+  8: required i64 in_errors
+  9: required i64 in_discards
+  10: required i64 out_octets
+  11: required i64 out_packets
+  12: required i64 out_errors
+  13: required i64 out_discards
+}
+
+struct InterfaceCountersResponse {
+  1: required list<InterfaceCounters> counters
+}
+
 service QueryService {
   HealthCheckResponse HealthCheck(1: HealthCheckRequest req)
   SearchTasksResponse SearchTasks(1: SearchTasksRequest req)
   QueryTotalCountsResponse AggregateFlows(1: AggregationRequest req)
   TraceFlowResponse TraceFlow(1: TraceFlowRequest req)
   HeavyHittersResponse QueryHeavyHitters(1: HeavyHittersRequest req)
+  InterfaceCountersResponse QueryInterfaceCounters(1: InterfaceCountersRequest req)
 }
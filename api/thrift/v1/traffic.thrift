@@ -12,4 +12,15 @@ struct PacketInfo {
   1: required i64 timestamp_unix_nano
   2: required FiveTuple five_tuple
   3: required i64 length
+  4: optional i32 tcp_flags
+  5: optional i64 tunnel_id
+  6: optional i32 outer_vlan
+  7: optional i32 inner_vlan
+  8: optional i32 mpls_label
+  9: optional i64 interface_id
+  10: optional i64 sample_rate
+}
+
+struct PacketBatch {
+  1: required list<PacketInfo> packets
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os/signal"
 	"syscall"
@@ -10,16 +11,40 @@ import (
 	"Go2NetSpectra/internal/engine/app"
 )
 
+// configPath is read at startup and again on SIGHUP or an admin reload request.
+const configPath = "configs/config.yaml"
+
 func main() {
-	cfg, err := config.LoadConfig("configs/config.yaml")
+	source := flag.String("source", "nats", "Where traffic comes from: \"nats\" for ns-probe packets, \"collector\" for NetFlow/IPFIX exports received per the collector config.")
+	replayFrom := flag.String("replay-from", "", "Reprocess the JetStream stream from an RFC3339 time or stream sequence. Overrides probe.jetstream.replay_from.")
+	flag.Parse()
+
+	cfg, err := config.LoadConfig(configPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "replay-from" {
+			cfg.Probe.JetStream.ReplayFrom = *replayFrom
+		}
+	})
+	if cfg.Probe.JetStream.ReplayFrom != "" && !cfg.Probe.JetStream.Enabled {
+		log.Fatalf("replaying from %q requires probe.jetstream.enabled", cfg.Probe.JetStream.ReplayFrom)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	if err := app.RunStreamEngine(ctx, cfg); err != nil {
+	switch *source {
+	case "nats":
+		err = app.RunStreamEngine(ctx, cfg, configPath)
+	case "collector":
+		err = app.RunCollectorEngine(ctx, cfg, configPath)
+	default:
+		log.Fatalf("unknown source %q, want nats or collector", *source)
+	}
+	if err != nil {
 		log.Fatalf("ns-engine exited with error: %v", err)
 	}
 }
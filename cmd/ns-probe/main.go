@@ -4,32 +4,49 @@ import (
 	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
+	"net"
 	"os"
 	"os/signal"
+	"strings"
+	"sync"
 	"syscall"
 
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/metrics"
 	"Go2NetSpectra/internal/model"
 	"Go2NetSpectra/internal/probe"
 	"Go2NetSpectra/internal/protocol"
+	"Go2NetSpectra/pkg/pcap"
 
-	"github.com/google/gopacket"
-	"github.com/google/gopacket/pcap"
+	gopcap "github.com/google/gopacket/pcap"
 )
 
 const (
-	snapshotLen   int32 = 1600
-	promiscuous         = true
-	timeout             = pcap.BlockForever
-	modePublish         = "pub"
-	modeSubscribe       = "sub"
+	modePublish   = "pub"
+	modeSubscribe = "sub"
+)
+
+var (
+	packetsCaptured = metrics.NewCounter(metrics.Namespace+"_probe_packets_captured_total",
+		"Packets read from the capture handle, by interface.", "interface")
+	parseFailures = metrics.NewCounter(metrics.Namespace+"_probe_parse_failures_total",
+		"Captured packets that could not be decoded, by interface.", "interface")
+	publishErrors = metrics.NewCounter(metrics.Namespace+"_probe_publish_errors_total",
+		"Packets that could not be published to NATS, by interface.", "interface")
 )
 
 func main() {
 	// --- Command-Line Flag Parsing ---
 	mode := flag.String("mode", modeSubscribe, "Operating mode: 'pub' captures and publishes packets, 'sub' subscribes and prints.")
-	iface := flag.String("iface", "", "Interface to capture packets from. Required in pub mode.")
+	ifaces := flag.String("iface", "", "Comma-separated interfaces to capture packets from. Overrides probe.interfaces; one is required in pub mode.")
+	filter := flag.String("filter", "", "BPF filter expression. Overrides probe.capture.bpf_filter.")
+	snapLen := flag.Int("snaplen", 0, "Bytes captured per packet. Overrides probe.capture.snaplen.")
+	promisc := flag.Bool("promisc", true, "Capture in promiscuous mode. Overrides probe.capture.promiscuous.")
+	bufferSize := flag.Int("buffer-size", 0, "Capture buffer size in bytes. Overrides probe.capture.buffer_size.")
+	sampleMode := flag.String("sample-mode", "", "Sampling mode: 'none', 'packet' or 'flow'. Overrides probe.sampling.mode.")
+	sampleRate := flag.Uint("sample-rate", 0, "Forward 1 in N packets or flows. Overrides probe.sampling.rate.")
 	flag.Parse()
 
 	// Load configuration
@@ -38,10 +55,36 @@ func main() {
 		log.Fatalf("failed to load configuration: %v", err)
 	}
 
+	// Only flags given on the command line override the configuration file.
+	capture := &cfg.Probe.Capture
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "filter":
+			capture.BPFFilter = *filter
+		case "snaplen":
+			capture.SnapLen = int32(*snapLen)
+		case "promisc":
+			capture.Promiscuous = promisc
+		case "buffer-size":
+			capture.BufferSize = *bufferSize
+		case "sample-mode":
+			cfg.Probe.Sampling.Mode = *sampleMode
+		case "sample-rate":
+			cfg.Probe.Sampling.Rate = uint32(*sampleRate)
+		case "iface":
+			cfg.Probe.Interfaces = nil
+			for _, name := range strings.Split(*ifaces, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					cfg.Probe.Interfaces = append(cfg.Probe.Interfaces, config.InterfaceConfig{Name: name})
+				}
+			}
+		}
+	})
+
 	// --- Mode Dispatch ---
 	switch *mode {
 	case modePublish:
-		runProbe(cfg.Probe, *iface)
+		runProbe(cfg.Probe)
 	case modeSubscribe:
 		runSubscriber(cfg.Probe)
 	default:
@@ -52,13 +95,34 @@ func main() {
 }
 
 // runProbe contains the logic for capturing packets and publishing them to NATS.
-func runProbe(cfg config.ProbeConfig, interfaceName string) {
-	if interfaceName == "" {
-		log.Println("error: -iface flag is required in pub mode")
+// Every configured interface gets its own handle and decoder; all of them
+// publish through the same NATS connection.
+func runProbe(cfg config.ProbeConfig) {
+	if len(cfg.Interfaces) == 0 {
+		log.Println("error: -iface flag or probe.interfaces is required in pub mode")
 		flag.Usage()
 		os.Exit(1)
 	}
-	log.Printf("Starting ns-probe in publisher mode on interface %s", interfaceName)
+
+	parser, err := protocol.NewParser(cfg.Decap)
+	if err != nil {
+		log.Fatalf("invalid decap config: %v", err)
+	}
+	if err := pcap.ValidateCapture(cfg.Capture); err != nil {
+		log.Fatalf("invalid capture config: %v", err)
+	}
+	ids := make(map[uint32]string, len(cfg.Interfaces))
+	for i := range cfg.Interfaces {
+		iface := &cfg.Interfaces[i]
+		if err := pcap.ValidateInterface(*iface, cfg.Capture); err != nil {
+			log.Fatalf("invalid capture config: %v", err)
+		}
+		iface.ID = interfaceID(*iface)
+		if other, ok := ids[iface.ID]; ok {
+			log.Fatalf("invalid capture config: interfaces %s and %s share id %d", other, iface.Name, iface.ID)
+		}
+		ids[iface.ID] = iface.Name
+	}
 
 	// Initialize NATS Publisher
 	pub, err := probe.NewPublisher(cfg)
@@ -67,40 +131,115 @@ func runProbe(cfg config.ProbeConfig, interfaceName string) {
 	}
 	defer pub.Close()
 
-	// Open device for live capture
-	handle, err := pcap.OpenLive(interfaceName, snapshotLen, promiscuous, timeout)
-	if err != nil {
-		log.Fatalf("failed to open device %s: %v", interfaceName, err)
+	// Open every device before capturing from any of them
+	captures := make([]*interfaceCapture, 0, len(cfg.Interfaces))
+	for _, iface := range cfg.Interfaces {
+		handle, err := pcap.OpenInterface(iface, cfg.Capture)
+		if err != nil {
+			log.Fatalf("failed to open device %s: %v", iface.Name, err)
+		}
+		defer handle.Close()
+
+		decoder, err := parser.NewDecoder(handle.LinkType())
+		if err != nil {
+			log.Fatalf("failed to create decoder for %s: %v", iface.Name, err)
+		}
+		captures = append(captures, &interfaceCapture{name: iface.Name, id: iface.ID, handle: handle, decoder: decoder})
+		log.Printf("Capturing on interface %s (id %d, link type %s)", iface.Name, iface.ID, handle.LinkType())
 	}
-	defer handle.Close()
 
 	log.Println("Capture started successfully. Publishing packets to NATS...")
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	// Start processing packets in a separate goroutine
-	go func() {
-		packetSource := gopacket.NewPacketSource(handle, handle.LinkType())
-		packetsPublished := 0
-		for packet := range packetSource.Packets() {
-			info, err := protocol.ParsePacket(packet)
-			if err != nil {
-				continue
-			}
-			if err := pub.Publish(packet, info); err != nil {
-				log.Printf("failed to publish packet: %v", err)
-			}
-			packetsPublished++
-			if packetsPublished%1000 == 0 {
-				log.Printf("%d packets published...", packetsPublished)
+	metrics.NewCounterFunc(metrics.Namespace+"_probe_pcap_dropped_packets_total",
+		"Packets dropped by the kernel or the interface before capture, by interface.",
+		[]string{"interface"}, func(emit func(float64, ...string)) {
+			for _, c := range captures {
+				stats, err := c.handle.Stats()
+				if err != nil {
+					continue
+				}
+				emit(float64(stats.PacketsDropped+stats.PacketsIfDropped), c.name)
 			}
-		}
-	}()
+		})
+	metrics.Start(ctx, cfg.MetricsListenAddr)
+
+	// Process each interface in its own goroutine
+	var wg sync.WaitGroup
+	for _, c := range captures {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			c.run(pub)
+		}()
+	}
 
 	// Wait for a shutdown signal
 	<-ctx.Done()
 	log.Println("Shutdown signal received, cleaning up...")
+
+	// Closing a handle ends its capture loop; the publisher is closed only
+	// once no capture can publish any more.
+	for _, c := range captures {
+		c.handle.Close()
+	}
+	wg.Wait()
+}
+
+// interfaceCapture reads one live interface and stamps its packets with the interface ID.
+type interfaceCapture struct {
+	name    string
+	id      uint32
+	handle  *gopcap.Handle
+	decoder *protocol.Decoder
+}
+
+func (c *interfaceCapture) run(pub *probe.Publisher) {
+	// The decoder copies into info's address buffers, so one PacketInfo serves every frame.
+	var info model.PacketInfo
+	captured := packetsCaptured.With(c.name)
+	packetsPublished := 0
+	for {
+		data, ci, err := c.handle.ReadPacketData()
+		if err == gopcap.NextErrorTimeoutExpired {
+			continue
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("capture on %s stopped: %v", c.name, err)
+			}
+			return
+		}
+		captured.Inc()
+		if err := c.decoder.DecodeInto(data, ci, &info); err != nil {
+			parseFailures.Inc(c.name)
+			continue
+		}
+		info.InterfaceID = c.id
+		if err := pub.Publish(ci, data, &info); err != nil {
+			publishErrors.Inc(c.name)
+			log.Printf("failed to publish packet from %s: %v", c.name, err)
+			continue
+		}
+		packetsPublished++
+		if packetsPublished%1000 == 0 {
+			log.Printf("%d packets published from %s...", packetsPublished, c.name)
+		}
+	}
+}
+
+// interfaceID returns the configured ID of iface, falling back to its OS interface index.
+func interfaceID(iface config.InterfaceConfig) uint32 {
+	if iface.ID != 0 {
+		return iface.ID
+	}
+	netIface, err := net.InterfaceByName(iface.Name)
+	if err != nil {
+		return 0
+	}
+	return uint32(netIface.Index)
 }
 
 // runSubscriber contains the logic for subscribing to NATS and printing messages.
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"log"
 	"os"
@@ -10,12 +11,15 @@ import (
 )
 
 func main() {
-	// 1. Get pcap file path from command-line arguments
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run ./cmd/pcap-analyzer/main.go <path_to_pcap_file>")
+	// 1. Get pcap file path and options from command-line arguments
+	filter := flag.String("filter", "", "BPF filter expression. Overrides probe.capture.bpf_filter.")
+	eventTime := flag.Bool("event-time", true, "Drive windows, snapshots and resets by packet timestamps instead of the wall clock.")
+	flag.Parse()
+	if flag.NArg() < 1 {
+		fmt.Println("Usage: go run ./cmd/pcap-analyzer/main.go [-filter <bpf>] [-event-time=false] <path_to_pcap_file>")
 		os.Exit(1)
 	}
-	pcapFilePath := os.Args[1]
+	pcapFilePath := flag.Arg(0)
 
 	// 2. Load configuration
 	cfg, err := config.LoadConfig("configs/config.yaml")
@@ -24,6 +28,13 @@ func main() {
 	}
 	log.Println("Configuration loaded successfully.")
 
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "filter" {
+			cfg.Probe.Capture.BPFFilter = *filter
+		}
+	})
+	cfg.Aggregator.EventTime.Enabled = *eventTime
+
 	if err := offline.RunAnalyzer(cfg, pcapFilePath); err != nil {
 		log.Fatalf("Offline analyzer exited with error: %v", err)
 	}
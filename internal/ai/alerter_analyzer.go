@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"Go2NetSpectra/internal/config"
 
@@ -53,6 +54,7 @@ func (a *AlerterAnalyzer) AnalyzeTraffic(ctx context.Context, input string) (str
 			"--- Alert Data ---\n%s\n--- End of Alert Data ---", input,
 	)
 
+	start := time.Now()
 	resp, err := a.client.CreateChatCompletion(
 		ctx,
 		openai.ChatCompletionRequest{
@@ -65,6 +67,7 @@ func (a *AlerterAnalyzer) AnalyzeTraffic(ctx context.Context, input string) (str
 			},
 		},
 	)
+	observeLLM("alerter", start, err)
 	if err != nil {
 		if errors.Is(err, context.DeadlineExceeded) {
 			return "", fmt.Errorf("ai request timeout: %w", err)
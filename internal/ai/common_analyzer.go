@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"Go2NetSpectra/internal/config"
 
@@ -47,8 +48,10 @@ func (a *CommonAnalyzer) AnalyzeStream(ctx context.Context, prompt string, sendC
 		Stream: true,
 	}
 
+	start := time.Now()
 	stream, err := a.client.CreateChatCompletionStream(ctx, req)
 	if err != nil {
+		observeLLM("common", start, err)
 		return fmt.Errorf("failed to create chat completion stream: %w", err)
 	}
 	defer stream.Close()
@@ -56,9 +59,11 @@ func (a *CommonAnalyzer) AnalyzeStream(ctx context.Context, prompt string, sendC
 	for {
 		response, err := stream.Recv()
 		if errors.Is(err, io.EOF) {
+			observeLLM("common", start, nil)
 			return nil
 		}
 		if err != nil {
+			observeLLM("common", start, err)
 			return fmt.Errorf("stream error: %w", err)
 		}
 
@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"time"
+
+	"Go2NetSpectra/internal/metrics"
+)
+
+// llmBuckets cover LLM completions, which take seconds to minutes.
+var llmBuckets = []float64{.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300}
+
+var (
+	llmDuration = metrics.NewHistogram(metrics.Namespace+"_ai_llm_request_duration_seconds",
+		"Time for the LLM to complete a request, by analyzer.", llmBuckets, "analyzer")
+	llmErrors = metrics.NewCounter(metrics.Namespace+"_ai_llm_errors_total",
+		"LLM requests that failed, by analyzer.", "analyzer")
+	promptSessionsStarted = metrics.NewCounter(metrics.Namespace+"_ai_prompt_sessions_total",
+		"Prompt analysis sessions started.")
+)
+
+// observeLLM records an LLM request of analyzer that began at start.
+func observeLLM(analyzer string, start time.Time, err error) {
+	if err != nil {
+		llmErrors.Inc(analyzer)
+		return
+	}
+	llmDuration.Observe(time.Since(start).Seconds(), analyzer)
+}
+
+// registerMetrics reports the sessions held by s at scrape time.
+func (s *promptSessionStore) registerMetrics() {
+	metrics.NewGaugeFunc(metrics.Namespace+"_ai_prompt_sessions_active",
+		"Prompt analysis sessions held, running or waiting to be read.", nil, func(emit func(float64, ...string)) {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			emit(float64(len(s.sessions)))
+		})
+}
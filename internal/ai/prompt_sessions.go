@@ -83,6 +83,7 @@ func (s *promptSessionStore) Start(ctx context.Context, prompt string) (*v1.Prom
 		notify:      make(chan struct{}),
 	}
 	s.sessions[sessionID] = session
+	promptSessionsStarted.Inc()
 
 	s.wg.Add(1)
 	go s.runSession(sessionCtx, session, prompt)
@@ -7,6 +7,7 @@ import (
 
 	v1 "Go2NetSpectra/api/gen/thrift/v1"
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/metrics"
 
 	thrift "github.com/apache/thrift/lib/go/thrift"
 )
@@ -52,9 +53,12 @@ func RunServer(ctx context.Context, cfg *config.Config) error {
 
 	transportFactory := thrift.NewTBufferedTransportFactory(thriftBufferSize)
 	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(&thrift.TConfiguration{})
-	processor := v1.NewAIServiceProcessor(service)
+	processor := thrift.WrapProcessor(v1.NewAIServiceProcessor(service), metrics.ThriftMiddleware("ai"))
 	server := thrift.NewTSimpleServer4(processor, serverTransport, transportFactory, protocolFactory)
 
+	service.promptSessions.registerMetrics()
+	metrics.Start(ctx, cfg.AI.MetricsListenAddr)
+
 	errCh := make(chan error, 1)
 	go func() {
 		log.Printf("AI RPC server starting on %s", cfg.AI.RPCListenAddr)
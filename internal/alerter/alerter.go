@@ -21,6 +21,7 @@ const aiClientBufferSize = 32 * 1024
 // Alerter is responsible for evaluating task snapshots against predefined rules
 // and triggering notifications if rules are violated.
 type Alerter struct {
+	mu            sync.RWMutex // guards tasks and rules, which Update replaces
 	tasks         []model.Task
 	rules         []config.AlerterRule
 	notifier      model.Notifier
@@ -100,18 +101,31 @@ func (a *Alerter) Stop() {
 	}
 }
 
+// Update replaces the rules and the tasks they are evaluated against. An
+// evaluation already in progress finishes with the previous set.
+func (a *Alerter) Update(rules []config.AlerterRule, tasks []model.Task) {
+	a.mu.Lock()
+	a.rules = rules
+	a.tasks = tasks
+	a.mu.Unlock()
+}
+
 // evaluateAllTasks orchestrates the concurrent evaluation of all tasks against the rules.
 func (a *Alerter) evaluateAllTasks() {
+	a.mu.RLock()
+	tasks, rules := a.tasks, a.rules
+	a.mu.RUnlock()
+
 	var wg sync.WaitGroup
-	resultsChan := make(chan string, len(a.tasks)) // Buffered channel
+	resultsChan := make(chan string, len(tasks)) // Buffered channel
 
-	for _, task := range a.tasks {
+	for _, task := range tasks {
 		wg.Add(1)
 		go func(t model.Task) {
 			defer wg.Done()
 			// Find rules relevant to this task
 			var relevantRules []config.AlerterRule
-			for _, rule := range a.rules {
+			for _, rule := range rules {
 				if rule.TaskName == t.Name() {
 					relevantRules = append(relevantRules, rule)
 				}
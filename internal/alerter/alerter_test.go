@@ -2,10 +2,39 @@ package alerter
 
 import (
 	"net"
+	"strings"
 	"testing"
 	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
 )
 
+// ruleTask reports every rule it is given as triggered.
+type ruleTask struct {
+	model.Task
+	name string
+}
+
+func (t *ruleTask) Name() string { return t.name }
+
+func (t *ruleTask) AlerterMsg(rules []config.AlerterRule) string {
+	names := make([]string, len(rules))
+	for i, rule := range rules {
+		names[i] = rule.Name
+	}
+	return strings.Join(names, ",")
+}
+
+type recordingNotifier struct {
+	bodies []string
+}
+
+func (n *recordingNotifier) Send(subject, body string) error {
+	n.bodies = append(n.bodies, body)
+	return nil
+}
+
 func TestNewAIClientConnectsToReachableAddress(t *testing.T) {
 	listener, err := net.Listen("tcp", "127.0.0.1:0")
 	if err != nil {
@@ -52,3 +81,29 @@ func TestNewAIClientRejectsUnreachableAddress(t *testing.T) {
 		t.Fatalf("newAIClient() client = %#v, transport = %#v, want connection error", client, transport)
 	}
 }
+
+func TestAlerterUpdateSwapsRulesAndTasks(t *testing.T) {
+	notifier := &recordingNotifier{}
+	cfg := &config.AlerterConfig{
+		CheckInterval: "1h",
+		Rules:         []config.AlerterRule{{Name: "old_rule", TaskName: "old_task"}},
+	}
+	a, err := NewAlerter(cfg, []model.Task{&ruleTask{name: "old_task"}}, notifier)
+	if err != nil {
+		t.Fatalf("NewAlerter() unexpected error: %v", err)
+	}
+
+	a.evaluateAllTasks()
+	a.Update([]config.AlerterRule{{Name: "new_rule", TaskName: "new_task"}}, []model.Task{&ruleTask{name: "new_task"}})
+	a.evaluateAllTasks()
+
+	if len(notifier.bodies) != 2 {
+		t.Fatalf("notifications = %d, want 2", len(notifier.bodies))
+	}
+	if !strings.Contains(notifier.bodies[0], "old_rule") {
+		t.Fatalf("first notification = %q, want old_rule", notifier.bodies[0])
+	}
+	if strings.Contains(notifier.bodies[1], "old_rule") || !strings.Contains(notifier.bodies[1], "new_rule") {
+		t.Fatalf("notification after Update() = %q, want only new_rule", notifier.bodies[1])
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	v1 "Go2NetSpectra/api/gen/thrift/v1"
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/metrics"
 	"Go2NetSpectra/internal/query"
 
 	thrift "github.com/apache/thrift/lib/go/thrift"
@@ -38,7 +39,7 @@ func RunQueryAPIServers(ctx context.Context, cfg *config.Config) error {
 
 	transportFactory := thrift.NewTBufferedTransportFactory(queryRPCBufferSize)
 	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(&thrift.TConfiguration{})
-	processor := v1.NewQueryServiceProcessor(service)
+	processor := thrift.WrapProcessor(v1.NewQueryServiceProcessor(service), metrics.ThriftMiddleware("query"))
 	rpcServer := thrift.NewTSimpleServer4(processor, serverTransport, transportFactory, protocolFactory)
 
 	httpServer := &http.Server{
@@ -46,6 +47,8 @@ func RunQueryAPIServers(ctx context.Context, cfg *config.Config) error {
 		Handler: newGrafanaHTTPHandler(service),
 	}
 
+	metrics.Start(ctx, cfg.API.MetricsListenAddr)
+
 	errCh := make(chan error, 2)
 	go func() {
 		log.Printf("Query RPC server starting on %s", cfg.API.RPCListenAddr)
@@ -128,6 +131,15 @@ func (s *QueryServiceServer) QueryHeavyHitters(ctx context.Context, req *v1.Heav
 	return heavyHittersResponseToThrift(result), nil
 }
 
+// QueryInterfaceCounters returns the latest sFlow interface counters.
+func (s *QueryServiceServer) QueryInterfaceCounters(ctx context.Context, req *v1.InterfaceCountersRequest) (*v1.InterfaceCountersResponse, error) {
+	result, err := s.queryInterfaceCounters(ctx, interfaceCountersRequestFromThrift(req))
+	if err != nil {
+		return nil, err
+	}
+	return interfaceCountersResponseToThrift(result), nil
+}
+
 func (s *QueryServiceServer) aggregateFlows(ctx context.Context, req *query.AggregationRequest) (*query.QueryTotalCountsResponse, error) {
 	if s.exactQuerier == nil {
 		return nil, fmt.Errorf("exact aggregator is not configured, cannot perform aggregation query")
@@ -152,6 +164,20 @@ func (s *QueryServiceServer) queryHeavyHitters(ctx context.Context, req *query.H
 	return s.sketchQuerier.QueryHeavyHitters(ctx, req)
 }
 
+// queryInterfaceCounters reads the counter table through whichever querier is
+// configured; both connect to the database the counter writer uses.
+func (s *QueryServiceServer) queryInterfaceCounters(ctx context.Context, req *query.InterfaceCountersRequest) (*query.InterfaceCountersResponse, error) {
+	querier := s.exactQuerier
+	if querier == nil {
+		querier = s.sketchQuerier
+	}
+	if querier == nil {
+		return nil, fmt.Errorf("no clickhouse querier is configured, cannot perform interface counters query")
+	}
+	log.Printf("Received QueryInterfaceCounters request for agent: %q, end: %v", req.AgentIP, req.EndTime)
+	return querier.QueryInterfaceCounters(ctx, req)
+}
+
 func newQueryServiceServer(cfg *config.Config) (*QueryServiceServer, error) {
 	var exactQuerier query.Querier
 	if slices.Contains(cfg.Aggregator.Types, "exact") {
@@ -208,14 +234,35 @@ func aggregationRequestFromThrift(req *v1.AggregationRequest) *query.Aggregation
 		return &query.AggregationRequest{}
 	}
 
+	// The thrift request names its key filters; the query keys them by field.
+	flowKeys := make(map[string]any)
+	setString := func(name string, isSet bool, value string) {
+		if isSet && value != "" {
+			flowKeys[name] = value
+		}
+	}
+	setNumber := func(name string, isSet bool, value any) {
+		if isSet {
+			flowKeys[name] = value
+		}
+	}
+	setString("SrcIP", req.IsSetSrcIP(), req.GetSrcIP())
+	setString("DstIP", req.IsSetDstIP(), req.GetDstIP())
+	setNumber("SrcPort", req.IsSetSrcPort(), req.GetSrcPort())
+	setNumber("DstPort", req.IsSetDstPort(), req.GetDstPort())
+	setNumber("Protocol", req.IsSetProtocol(), req.GetProtocol())
+	setNumber("TunnelID", req.IsSetTunnelID(), req.GetTunnelID())
+	setNumber("OuterVLAN", req.IsSetOuterVlan(), req.GetOuterVlan())
+	setNumber("InnerVLAN", req.IsSetInnerVlan(), req.GetInnerVlan())
+	setNumber("MPLSLabel", req.IsSetMplsLabel(), req.GetMplsLabel())
+	setNumber("InterfaceID", req.IsSetInterfaceID(), req.GetInterfaceID())
+	setString("ExporterIP", req.IsSetExporterIP(), req.GetExporterIP())
+
 	return &query.AggregationRequest{
-		EndTime:  timePtrFromOptionalUnixNano(req.IsSetEndTimeUnixNano(), req.GetEndTimeUnixNano()),
-		TaskName: req.GetTaskName(),
-		SrcIP:    optionalString(req.IsSetSrcIP(), req.GetSrcIP()),
-		DstIP:    optionalString(req.IsSetDstIP(), req.GetDstIP()),
-		SrcPort:  int32PtrFromOptional(req.IsSetSrcPort(), req.GetSrcPort()),
-		DstPort:  int32PtrFromOptional(req.IsSetDstPort(), req.GetDstPort()),
-		Protocol: int32PtrFromOptional(req.IsSetProtocol(), req.GetProtocol()),
+		EndTime:   timePtrFromOptionalUnixNano(req.IsSetEndTimeUnixNano(), req.GetEndTimeUnixNano()),
+		TaskName:  req.GetTaskName(),
+		FlowKeys:  flowKeys,
+		ConnState: optionalString(req.IsSetConnState(), req.GetConnState()),
 	}
 }
 
@@ -249,6 +296,18 @@ func heavyHittersRequestFromThrift(req *v1.HeavyHittersRequest) *query.HeavyHitt
 	}
 }
 
+func interfaceCountersRequestFromThrift(req *v1.InterfaceCountersRequest) *query.InterfaceCountersRequest {
+	if req == nil {
+		return &query.InterfaceCountersRequest{}
+	}
+
+	return &query.InterfaceCountersRequest{
+		EndTime: timePtrFromOptionalUnixNano(req.IsSetEndTimeUnixNano(), req.GetEndTimeUnixNano()),
+		AgentIP: optionalString(req.IsSetAgentIP(), req.GetAgentIP()),
+		IfIndex: int64PtrFromOptional(req.IsSetIfIndex(), req.GetIfIndex()),
+	}
+}
+
 func queryTotalCountsResponseToThrift(resp *query.QueryTotalCountsResponse) *v1.QueryTotalCountsResponse {
 	if resp == nil {
 		return &v1.QueryTotalCountsResponse{Summaries: []*v1.TaskSummary{}}
@@ -261,6 +320,11 @@ func queryTotalCountsResponseToThrift(resp *query.QueryTotalCountsResponse) *v1.
 			TotalBytes:   summary.TotalBytes,
 			TotalPackets: summary.TotalPackets,
 			FlowCount:    summary.FlowCount,
+			SynCount:     thrift.Int64Ptr(summary.SYNCount),
+			FinCount:     thrift.Int64Ptr(summary.FINCount),
+			RstCount:     thrift.Int64Ptr(summary.RSTCount),
+			SampleRate:   thrift.Int64Ptr(int64(max(summary.SampleRate, 1))),
+			Estimated:    thrift.BoolPtr(summary.Estimated),
 		})
 	}
 
@@ -277,6 +341,16 @@ func flowLifecycleToThrift(lifecycle *query.FlowLifecycle) *v1.FlowLifecycle {
 		LastSeenUnixNano:  lifecycle.LastSeen.UnixNano(),
 		TotalPackets:      lifecycle.TotalPackets,
 		TotalBytes:        lifecycle.TotalBytes,
+		SynCount:          thrift.Int64Ptr(lifecycle.SYNCount),
+		FinCount:          thrift.Int64Ptr(lifecycle.FINCount),
+		RstCount:          thrift.Int64Ptr(lifecycle.RSTCount),
+		AckCount:          thrift.Int64Ptr(lifecycle.ACKCount),
+		TCPFlags:          thrift.Int32Ptr(int32(lifecycle.TCPFlags)),
+		ConnState:         thrift.StringPtr(lifecycle.ConnState),
+		SampleRate:        thrift.Int64Ptr(int64(max(lifecycle.SampleRate, 1))),
+		Estimated:         thrift.BoolPtr(lifecycle.Estimated),
+		Lifecycles:        thrift.Int64Ptr(lifecycle.Lifecycles),
+		EndReason:         thrift.StringPtr(lifecycle.EndReason),
 	}
 }
 
@@ -288,14 +362,43 @@ func heavyHittersResponseToThrift(resp *query.HeavyHittersResponse) *v1.HeavyHit
 	hitters := make([]*v1.HeavyHitter, 0, len(resp.Hitters))
 	for _, hitter := range resp.Hitters {
 		hitters = append(hitters, &v1.HeavyHitter{
-			Flow:  hitter.Flow,
-			Value: hitter.Value,
+			Flow:       hitter.Flow,
+			Value:      hitter.Value,
+			SampleRate: thrift.Int64Ptr(int64(max(hitter.SampleRate, 1))),
+			Estimated:  thrift.BoolPtr(hitter.Estimated),
 		})
 	}
 
 	return &v1.HeavyHittersResponse{Hitters: hitters}
 }
 
+func interfaceCountersResponseToThrift(resp *query.InterfaceCountersResponse) *v1.InterfaceCountersResponse {
+	if resp == nil {
+		return &v1.InterfaceCountersResponse{Counters: []*v1.InterfaceCounters{}}
+	}
+
+	counters := make([]*v1.InterfaceCounters, 0, len(resp.Counters))
+	for _, c := range resp.Counters {
+		counters = append(counters, &v1.InterfaceCounters{
+			AgentIP:           c.AgentIP,
+			IfIndex:           int64(c.IfIndex),
+			TimestampUnixNano: c.Timestamp.UnixNano(),
+			IfSpeed:           int64(c.Speed),
+			IfStatus:          int32(c.Status),
+			InOctets:          int64(c.InOctets),
+			InPackets:         int64(c.InPackets),
+			InErrors:          int64(c.InErrors),
+			InDiscards:        int64(c.InDiscards),
+			OutOctets:         int64(c.OutOctets),
+			OutPackets:        int64(c.OutPackets),
+			OutErrors:         int64(c.OutErrors),
+			OutDiscards:       int64(c.OutDiscards),
+		})
+	}
+
+	return &v1.InterfaceCountersResponse{Counters: counters}
+}
+
 func timePtrFromOptionalUnixNano(isSet bool, unixNano int64) *time.Time {
 	if !isSet {
 		return nil
@@ -304,7 +407,7 @@ func timePtrFromOptionalUnixNano(isSet bool, unixNano int64) *time.Time {
 	return &value
 }
 
-func int32PtrFromOptional(isSet bool, value int32) *int32 {
+func int64PtrFromOptional(isSet bool, value int64) *int64 {
 	if !isSet {
 		return nil
 	}
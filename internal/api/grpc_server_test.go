@@ -0,0 +1,56 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v1 "Go2NetSpectra/api/gen/thrift/v1"
+	"Go2NetSpectra/internal/query"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+func TestQueryInterfaceCountersUsesSketchQuerierWithoutExact(t *testing.T) {
+	stub := &stubQuerier{
+		countersResp: &query.InterfaceCountersResponse{
+			Counters: []query.InterfaceCounters{{
+				AgentIP:   "192.0.2.1",
+				IfIndex:   7,
+				Timestamp: time.Unix(1700000000, 0),
+				Speed:     10_000_000_000,
+				Status:    3,
+				InOctets:  1 << 40,
+				OutErrors: 2,
+			}},
+		},
+	}
+	server := &QueryServiceServer{sketchQuerier: stub}
+
+	resp, err := server.QueryInterfaceCounters(context.Background(), &v1.InterfaceCountersRequest{
+		AgentIP: thrift.StringPtr("192.0.2.1"),
+		IfIndex: thrift.Int64Ptr(7),
+	})
+	if err != nil {
+		t.Fatalf("QueryInterfaceCounters() unexpected error: %v", err)
+	}
+	if stub.countersReq.AgentIP != "192.0.2.1" || stub.countersReq.IfIndex == nil || *stub.countersReq.IfIndex != 7 || stub.countersReq.EndTime != nil {
+		t.Fatalf("querier request = %+v, want agent 192.0.2.1, interface 7, no end time", stub.countersReq)
+	}
+	if len(resp.Counters) != 1 {
+		t.Fatalf("QueryInterfaceCounters() counters = %d, want 1", len(resp.Counters))
+	}
+	got := resp.Counters[0]
+	if got.IfIndex != 7 || got.IfSpeed != 10_000_000_000 || got.IfStatus != 3 || got.InOctets != 1<<40 || got.OutErrors != 2 {
+		t.Fatalf("QueryInterfaceCounters() = %+v, want the stub's counters", got)
+	}
+	if got.TimestampUnixNano != time.Unix(1700000000, 0).UnixNano() {
+		t.Fatalf("QueryInterfaceCounters() timestamp = %d, want %d", got.TimestampUnixNano, time.Unix(1700000000, 0).UnixNano())
+	}
+}
+
+func TestQueryInterfaceCountersRequiresQuerier(t *testing.T) {
+	if _, err := (&QueryServiceServer{}).QueryInterfaceCounters(context.Background(), &v1.InterfaceCountersRequest{}); err == nil {
+		t.Fatal("QueryInterfaceCounters(no querier) error = nil, want non-nil")
+	}
+}
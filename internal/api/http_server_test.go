@@ -16,6 +16,8 @@ import (
 
 type stubQuerier struct {
 	aggregateResp *query.QueryTotalCountsResponse
+	countersResp  *query.InterfaceCountersResponse
+	countersReq   *query.InterfaceCountersRequest
 }
 
 func (s *stubQuerier) AggregateFlows(ctx context.Context, req *query.AggregationRequest) (*query.QueryTotalCountsResponse, error) {
@@ -30,6 +32,11 @@ func (s *stubQuerier) QueryHeavyHitters(ctx context.Context, req *query.HeavyHit
 	return nil, nil
 }
 
+func (s *stubQuerier) QueryInterfaceCounters(ctx context.Context, req *query.InterfaceCountersRequest) (*query.InterfaceCountersResponse, error) {
+	s.countersReq = req
+	return s.countersResp, nil
+}
+
 func TestRunLegacyHTTPServerReturnsUnsupportedError(t *testing.T) {
 	err := RunLegacyHTTPServer(context.Background(), &config.Config{})
 	if err == nil {
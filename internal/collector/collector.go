@@ -0,0 +1,300 @@
+package collector
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+const (
+	// maxDatagramSize fits any UDP payload.
+	maxDatagramSize = 65535
+	// pruneInterval is how often expired templates are dropped.
+	pruneInterval = time.Minute
+	// errorLogInterval rate-limits decode error logging per listener.
+	errorLogInterval = 10 * time.Second
+	// defaultCounterInterval is how often interface counters reach the counter sink.
+	defaultCounterInterval = time.Minute
+)
+
+// Stats counts what a collector received. All fields are updated atomically.
+type Stats struct {
+	Datagrams       atomic.Uint64
+	Records         atomic.Uint64 // flow records accepted by the manager
+	DecodeErrors    atomic.Uint64
+	MissingTemplate atomic.Uint64 // data sets dropped because their template had not arrived
+	CounterSamples  atomic.Uint64 // sFlow interface counter samples received
+}
+
+// decodeFunc decodes one datagram from exporter.
+type decodeFunc func(data []byte, exporter net.IP, now time.Time) ([]model.PacketInfo, error)
+
+// RecordSink takes the flow records the collector decodes and reports
+// whether it accepted each one. The manager's Input is one, applying the
+// configured overload policy.
+type RecordSink interface {
+	Enqueue(packet *model.PacketInfo) bool
+}
+
+// Collector listens for flow exports and feeds each flow record to the
+// manager as a single weighted update, so packet and byte counts are kept.
+type Collector struct {
+	cfg         config.CollectorConfig
+	out         RecordSink
+	netflow     *netflowDecoder
+	ipfix       *ipfixDecoder
+	sflow       *sflowDecoder
+	counters    *counterTable
+	netflowConn *net.UDPConn
+	ipfixConn   *net.UDPConn
+	sflowConn   *net.UDPConn
+	stats       Stats
+	wg          sync.WaitGroup
+
+	counterSink     CounterSink
+	counterInterval time.Duration
+	stopCounters    chan struct{}
+	countersDone    chan struct{}
+}
+
+// New creates a collector that sends records to out, typically the manager's Input.
+func New(cfg config.CollectorConfig, out RecordSink) (*Collector, error) {
+	if cfg.NetFlowAddr == "" && cfg.IPFIXAddr == "" && cfg.SFlowAddr == "" {
+		return nil, errors.New("collector has no listen address, set collector.netflow_addr, ipfix_addr or sflow_addr")
+	}
+	var templateTimeout time.Duration
+	if cfg.TemplateTimeout != "" {
+		var err error
+		templateTimeout, err = time.ParseDuration(cfg.TemplateTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid collector template_timeout %q: %w", cfg.TemplateTimeout, err)
+		}
+	}
+
+	counterInterval := defaultCounterInterval
+	if cfg.CounterInterval != "" {
+		var err error
+		counterInterval, err = time.ParseDuration(cfg.CounterInterval)
+		if err != nil || counterInterval <= 0 {
+			return nil, fmt.Errorf("invalid collector counter_interval %q", cfg.CounterInterval)
+		}
+	}
+
+	c := &Collector{
+		cfg:             cfg,
+		out:             out,
+		netflow:         newNetflowDecoder(templateTimeout),
+		ipfix:           newIPFIXDecoder(templateTimeout),
+		counterInterval: counterInterval,
+	}
+	c.netflow.missingTemplate = func() { c.stats.MissingTemplate.Add(1) }
+	c.ipfix.missingTemplate = c.netflow.missingTemplate
+
+	sflow, err := newSFlowDecoder()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sflow decoder: %w", err)
+	}
+	c.sflow, c.counters = sflow, newCounterTable()
+	c.sflow.counters = func(counters InterfaceCounters) {
+		c.stats.CounterSamples.Add(1)
+		c.counters.update(counters)
+	}
+	return c, nil
+}
+
+// SetCounterSink makes the collector hand sFlow interface counters to sink
+// every counter_interval and once more on Stop. Call it before Start.
+func (c *Collector) SetCounterSink(sink CounterSink) {
+	c.counterSink = sink
+}
+
+// Start opens the configured UDP listeners and starts decoding.
+func (c *Collector) Start() error {
+	if c.cfg.NetFlowAddr != "" {
+		conn, err := c.listen(c.cfg.NetFlowAddr)
+		if err != nil {
+			return err
+		}
+		c.netflowConn = conn
+		c.wg.Add(1)
+		go c.serve(conn, "netflow", c.netflow.Decode, c.netflow.templates)
+		log.Printf("Collector listening for NetFlow v5/v9 on %s", conn.LocalAddr())
+	}
+	if c.cfg.IPFIXAddr != "" {
+		conn, err := c.listen(c.cfg.IPFIXAddr)
+		if err != nil {
+			c.closeListeners()
+			return err
+		}
+		c.ipfixConn = conn
+		c.wg.Add(1)
+		go c.serve(conn, "ipfix", c.ipfix.Decode, c.ipfix.templates)
+		log.Printf("Collector listening for IPFIX on %s", conn.LocalAddr())
+	}
+	if c.cfg.SFlowAddr != "" {
+		conn, err := c.listen(c.cfg.SFlowAddr)
+		if err != nil {
+			c.closeListeners()
+			return err
+		}
+		c.sflowConn = conn
+		c.wg.Add(1)
+		go c.serve(conn, "sflow", c.sflow.Decode, nil)
+		log.Printf("Collector listening for sFlow v5 on %s", conn.LocalAddr())
+	}
+	if c.counterSink != nil {
+		c.stopCounters, c.countersDone = make(chan struct{}), make(chan struct{})
+		go c.flushCounters()
+	}
+	return nil
+}
+
+func (c *Collector) listen(addr string) (*net.UDPConn, error) {
+	udpAddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collector address %q: %w", addr, err)
+	}
+	conn, err := net.ListenUDP("udp", udpAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	if c.cfg.ReadBuffer > 0 {
+		if err := conn.SetReadBuffer(c.cfg.ReadBuffer); err != nil {
+			log.Printf("Collector failed to set read buffer on %s: %v", addr, err)
+		}
+	}
+	return conn, nil
+}
+
+// NetFlowAddr returns the address the NetFlow listener is bound to, or nil
+// before Start or when NetFlow is not configured.
+func (c *Collector) NetFlowAddr() net.Addr {
+	if c.netflowConn == nil {
+		return nil
+	}
+	return c.netflowConn.LocalAddr()
+}
+
+// IPFIXAddr returns the address the IPFIX listener is bound to, or nil before
+// Start or when IPFIX is not configured.
+func (c *Collector) IPFIXAddr() net.Addr {
+	if c.ipfixConn == nil {
+		return nil
+	}
+	return c.ipfixConn.LocalAddr()
+}
+
+// SFlowAddr returns the address the sFlow listener is bound to, or nil before
+// Start or when sFlow is not configured.
+func (c *Collector) SFlowAddr() net.Addr {
+	if c.sflowConn == nil {
+		return nil
+	}
+	return c.sflowConn.LocalAddr()
+}
+
+// DrainCounters returns the newest interface counter sample of every sFlow
+// agent interface heard from since the previous call.
+func (c *Collector) DrainCounters() []InterfaceCounters {
+	return c.counters.drain()
+}
+
+// Stats returns the collector's counters.
+func (c *Collector) Stats() *Stats {
+	return &c.stats
+}
+
+// serve reads datagrams until the connection is closed. templates is nil for
+// protocols without templates.
+func (c *Collector) serve(conn *net.UDPConn, protocol string, decode decodeFunc, templates *templateCache) {
+	defer c.wg.Done()
+	buf := make([]byte, maxDatagramSize)
+	var lastPrune, lastErrLog time.Time
+	for {
+		n, addr, err := conn.ReadFromUDPAddrPort(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			log.Printf("Collector %s read error: %v", protocol, err)
+			continue
+		}
+		now := time.Now()
+		c.stats.Datagrams.Add(1)
+
+		exporterAddr := addr.Addr().As16()
+		infos, err := decode(buf[:n], net.IP(exporterAddr[:]), now)
+		if err != nil {
+			c.stats.DecodeErrors.Add(1)
+			if now.Sub(lastErrLog) >= errorLogInterval {
+				log.Printf("Collector failed to decode %s packet from %s: %v", protocol, addr, err)
+				lastErrLog = now
+			}
+		}
+		// Records decoded before an error are still good. Those the sink
+		// drops are counted by the sink.
+		for i := range infos {
+			if c.out.Enqueue(&infos[i]) {
+				c.stats.Records.Add(1)
+			}
+		}
+
+		if templates != nil && now.Sub(lastPrune) >= pruneInterval {
+			templates.prune(now)
+			lastPrune = now
+		}
+	}
+}
+
+// flushCounters hands the drained counters to the sink until Stop, then
+// flushes what arrived since the last tick.
+func (c *Collector) flushCounters() {
+	defer close(c.countersDone)
+	ticker := time.NewTicker(c.counterInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-c.stopCounters:
+			c.writeCounters()
+			return
+		}
+		c.writeCounters()
+	}
+}
+
+func (c *Collector) writeCounters() {
+	if err := c.counterSink.WriteCounters(c.DrainCounters()); err != nil {
+		log.Printf("Collector failed to write interface counters: %v", err)
+	}
+}
+
+// Stop closes the listeners and waits for in-flight records to be handed over.
+func (c *Collector) Stop() {
+	c.closeListeners()
+	c.wg.Wait()
+	if c.stopCounters != nil {
+		close(c.stopCounters)
+		<-c.countersDone
+	}
+	log.Printf("Collector stopped after %d datagrams, %d records, %d counter samples, %d decode errors, %d sets without template",
+		c.stats.Datagrams.Load(), c.stats.Records.Load(), c.stats.CounterSamples.Load(), c.stats.DecodeErrors.Load(), c.stats.MissingTemplate.Load())
+}
+
+func (c *Collector) closeListeners() {
+	for _, conn := range []*net.UDPConn{c.netflowConn, c.ipfixConn, c.sflowConn} {
+		if conn == nil {
+			continue
+		}
+		if err := conn.Close(); err != nil {
+			log.Printf("Collector failed to close listener: %v", err)
+		}
+	}
+}
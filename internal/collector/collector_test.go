@@ -0,0 +1,148 @@
+package collector
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+// channelSink accepts every record onto its channel, or refuses them all
+// when it is nil, as a full Input dropping records would.
+type channelSink chan *model.PacketInfo
+
+func (s channelSink) Enqueue(packet *model.PacketInfo) bool {
+	if s == nil {
+		return false
+	}
+	s <- packet
+	return true
+}
+
+func TestCollectorForwardsNetflowRecords(t *testing.T) {
+	out := make(channelSink, 4)
+	c, err := New(config.CollectorConfig{NetFlowAddr: "127.0.0.1:0"}, out)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+	defer c.Stop()
+
+	conn, err := net.Dial("udp", c.NetFlowAddr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() unexpected error: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write([]byte("garbage")); err != nil {
+		t.Fatalf("Write(garbage) unexpected error: %v", err)
+	}
+	if _, err := conn.Write(netflowV5Packet(0, netflowV5Record("10.0.0.1", "10.0.0.2", 1234, 80, 3, 180, 0, 1000))); err != nil {
+		t.Fatalf("Write(v5) unexpected error: %v", err)
+	}
+
+	select {
+	case got := <-out:
+		if got.Packets != 3 || got.Length != 180 || !got.ExporterIP.Equal(net.ParseIP("127.0.0.1")) {
+			t.Fatalf("forwarded packets/bytes/exporter = %d/%d/%v, want 3/180/127.0.0.1", got.Packets, got.Length, got.ExporterIP)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("collector did not forward the v5 record")
+	}
+	if got := c.Stats().DecodeErrors.Load(); got != 1 {
+		t.Fatalf("Stats().DecodeErrors = %d, want 1", got)
+	}
+}
+
+func TestCollectorCountsOnlyAcceptedRecords(t *testing.T) {
+	c, err := New(config.CollectorConfig{NetFlowAddr: "127.0.0.1:0"}, channelSink(nil))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+
+	conn, err := net.Dial("udp", c.NetFlowAddr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() unexpected error: %v", err)
+	}
+	defer conn.Close()
+	if _, err := conn.Write(netflowV5Packet(0, netflowV5Record("10.0.0.1", "10.0.0.2", 1234, 80, 3, 180, 0, 1000))); err != nil {
+		t.Fatalf("Write(v5) unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Stats().Datagrams.Load() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Stop()
+
+	if got := c.Stats().Datagrams.Load(); got != 1 {
+		t.Fatalf("Stats().Datagrams = %d, want 1", got)
+	}
+	if got := c.Stats().Records.Load(); got != 0 {
+		t.Fatalf("Stats().Records = %d, want 0 with every record refused", got)
+	}
+}
+
+func TestNewRequiresListenAddress(t *testing.T) {
+	if _, err := New(config.CollectorConfig{}, nil); err == nil {
+		t.Fatal("New(no address) error = nil, want non-nil")
+	}
+	if _, err := New(config.CollectorConfig{NetFlowAddr: ":2055", TemplateTimeout: "soon"}, nil); err == nil {
+		t.Fatal("New(template_timeout soon) error = nil, want non-nil")
+	}
+	if _, err := New(config.CollectorConfig{SFlowAddr: ":6343", CounterInterval: "0s"}, nil); err == nil {
+		t.Fatal("New(counter_interval 0s) error = nil, want non-nil")
+	}
+}
+
+type recordingSink struct {
+	written chan []InterfaceCounters
+}
+
+func (s *recordingSink) WriteCounters(counters []InterfaceCounters) error {
+	s.written <- counters
+	return nil
+}
+
+func TestCollectorFlushesSFlowCountersOnStop(t *testing.T) {
+	c, err := New(config.CollectorConfig{SFlowAddr: "127.0.0.1:0", CounterInterval: "1h"}, make(channelSink))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	sink := &recordingSink{written: make(chan []InterfaceCounters, 1)}
+	c.SetCounterSink(sink)
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+
+	conn, err := net.Dial("udp", c.SFlowAddr().String())
+	if err != nil {
+		t.Fatalf("net.Dial() unexpected error: %v", err)
+	}
+	defer conn.Close()
+	// Two samples of the same interface keep only the newer one.
+	for range 2 {
+		if _, err := conn.Write(sflowDatagram(sflowOpaque(sflowCounterSample, sflowCounterSampleData(12)))); err != nil {
+			t.Fatalf("Write(sflow) unexpected error: %v", err)
+		}
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Stats().CounterSamples.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Stop()
+
+	select {
+	case counters := <-sink.written:
+		if len(counters) != 1 || counters[0].IfIndex != 12 || !counters[0].Agent.Equal(testAgent) {
+			t.Fatalf("flushed counters = %+v, want interface 12 of %v", counters, testAgent)
+		}
+	default:
+		t.Fatal("Stop() did not flush the interface counters")
+	}
+}
@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"net"
+	"sync"
+	"time"
+)
+
+// InterfaceCounters is the latest generic interface counter sample an sFlow
+// agent reported for one of its interfaces. Counters are the agent's running
+// totals, not deltas.
+type InterfaceCounters struct {
+	Agent       net.IP
+	IfIndex     uint32
+	Timestamp   time.Time // when the sample was received
+	Speed       uint64    // bits per second
+	Status      uint32    // bit 0 ifAdminStatus up, bit 1 ifOperStatus up
+	InOctets    uint64
+	InPackets   uint64 // unicast, multicast and broadcast
+	InErrors    uint64
+	InDiscards  uint64
+	OutOctets   uint64
+	OutPackets  uint64
+	OutErrors   uint64
+	OutDiscards uint64
+}
+
+type counterKey struct {
+	agent   string
+	ifIndex uint32
+}
+
+// counterTable keeps the newest sample of every interface until it is drained.
+type counterTable struct {
+	mu     sync.Mutex
+	latest map[counterKey]InterfaceCounters
+}
+
+func newCounterTable() *counterTable {
+	return &counterTable{latest: make(map[counterKey]InterfaceCounters)}
+}
+
+func (t *counterTable) update(c InterfaceCounters) {
+	t.mu.Lock()
+	t.latest[counterKey{agent: c.Agent.String(), ifIndex: c.IfIndex}] = c
+	t.mu.Unlock()
+}
+
+func (t *counterTable) drain() []InterfaceCounters {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counters := make([]InterfaceCounters, 0, len(t.latest))
+	for _, c := range t.latest {
+		counters = append(counters, c)
+	}
+	clear(t.latest)
+	return counters
+}
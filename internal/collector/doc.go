@@ -0,0 +1,5 @@
+// Package collector receives NetFlow v5/v9, IPFIX and sFlow v5 exports from
+// routers and switches over UDP and turns their records and samples into
+// weighted packet updates for the manager. sFlow interface counters are kept
+// apart and written to ClickHouse by CounterWriter.
+package collector
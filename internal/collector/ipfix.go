@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+)
+
+const (
+	ipfixVersion   = 10
+	ipfixHeaderLen = 16
+
+	ipfixTemplateSetID = 2
+	ipfixOptionsSetID  = 3
+	ipfixMinDataSetID  = 256
+
+	// enterpriseBit marks an information element followed by its enterprise number.
+	enterpriseBit = 0x8000
+)
+
+// ipfixDecoder decodes IPFIX (RFC 7011) messages.
+type ipfixDecoder struct {
+	templates *templateCache
+	// missingTemplate counts the data sets skipped for want of a template.
+	missingTemplate func()
+}
+
+func newIPFIXDecoder(templateTimeout time.Duration) *ipfixDecoder {
+	return &ipfixDecoder{templates: newTemplateCache(templateTimeout), missingTemplate: func() {}}
+}
+
+// Decode turns the IPFIX messages in data from exporter into one update per
+// flow record. A datagram carries one message, a TCP stream several.
+func (d *ipfixDecoder) Decode(data []byte, exporter net.IP, now time.Time) ([]model.PacketInfo, error) {
+	var infos []model.PacketInfo
+	for len(data) > 0 {
+		if len(data) < ipfixHeaderLen {
+			return infos, fmt.Errorf("ipfix header too short: %d bytes", len(data))
+		}
+		if version := binary.BigEndian.Uint16(data[0:2]); version != ipfixVersion {
+			return infos, fmt.Errorf("unsupported ipfix version %d", version)
+		}
+		length := int(binary.BigEndian.Uint16(data[2:4]))
+		if length < ipfixHeaderLen || length > len(data) {
+			return infos, fmt.Errorf("ipfix message has invalid length %d", length)
+		}
+		var err error
+		infos, err = d.decodeMessage(infos, data[:length], exporter, now)
+		if err != nil {
+			return infos, err
+		}
+		data = data[length:]
+	}
+	return infos, nil
+}
+
+func (d *ipfixDecoder) decodeMessage(infos []model.PacketInfo, msg []byte, exporter net.IP, now time.Time) ([]model.PacketInfo, error) {
+	// The IPFIX header has no uptime; exporters use the absolute flowStart/End fields.
+	clock := exportClock{export: time.Unix(int64(binary.BigEndian.Uint32(msg[4:8])), 0), noUptime: true}
+	domain := domainKey{exporter: exporter.String(), domain: binary.BigEndian.Uint32(msg[12:16])}
+
+	for rest := msg[ipfixHeaderLen:]; len(rest) >= 4; {
+		setID := binary.BigEndian.Uint16(rest[0:2])
+		setLen := int(binary.BigEndian.Uint16(rest[2:4]))
+		if setLen < 4 || setLen > len(rest) {
+			return infos, fmt.Errorf("ipfix set %d has invalid length %d", setID, setLen)
+		}
+		body := rest[4:setLen]
+		rest = rest[setLen:]
+
+		var err error
+		switch {
+		case setID == ipfixTemplateSetID:
+			err = d.readTemplates(body, domain, false, now)
+		case setID == ipfixOptionsSetID:
+			err = d.readTemplates(body, domain, true, now)
+		case setID >= ipfixMinDataSetID:
+			key := templateKey{domainKey: domain, id: setID}
+			tmpl := d.templates.get(key, now)
+			if tmpl == nil {
+				d.missingTemplate()
+				continue
+			}
+			infos, err = decodeRecords(infos, body, tmpl, d.templates, domain, exporter, clock)
+		}
+		if err != nil {
+			return infos, err
+		}
+	}
+	return infos, nil
+}
+
+// readTemplates reads the template records of a template or options template
+// set. A record without fields withdraws its template.
+func (d *ipfixDecoder) readTemplates(body []byte, domain domainKey, options bool, now time.Time) error {
+	for len(body) >= 4 {
+		id := binary.BigEndian.Uint16(body[0:2])
+		fieldCount := int(binary.BigEndian.Uint16(body[2:4]))
+		body = body[4:]
+		if fieldCount == 0 {
+			d.templates.withdraw(templateKey{domainKey: domain, id: id})
+			continue
+		}
+
+		scopeCount := 0
+		if options {
+			if len(body) < 2 {
+				return fmt.Errorf("ipfix options template %d truncated", id)
+			}
+			scopeCount = int(binary.BigEndian.Uint16(body[0:2]))
+			body = body[2:]
+			if scopeCount == 0 || scopeCount > fieldCount {
+				return fmt.Errorf("ipfix options template %d has %d scope fields of %d", id, scopeCount, fieldCount)
+			}
+		}
+
+		fields := make([]templateField, fieldCount)
+		for i := range fields {
+			if len(body) < 4 {
+				return fmt.Errorf("ipfix template %d truncated", id)
+			}
+			ie := binary.BigEndian.Uint16(body[0:2])
+			fields[i] = templateField{Type: ie &^ enterpriseBit, Length: binary.BigEndian.Uint16(body[2:4])}
+			body = body[4:]
+			if ie&enterpriseBit != 0 {
+				if len(body) < 4 {
+					return fmt.Errorf("ipfix template %d truncated", id)
+				}
+				fields[i].Enterprise = binary.BigEndian.Uint32(body[0:4])
+				body = body[4:]
+			}
+		}
+		if id < ipfixMinDataSetID {
+			return errors.New("ipfix template id below 256")
+		}
+		tmpl := newTemplate(fields, scopeCount, options, now)
+		if tmpl.minLen == 0 {
+			return fmt.Errorf("ipfix template %d has zero-length records", id)
+		}
+		d.templates.put(templateKey{domainKey: domain, id: id}, tmpl)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package collector
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// ipfixBuilder assembles an IPFIX message.
+type ipfixBuilder struct {
+	sets [][]byte
+}
+
+func (b *ipfixBuilder) set(id uint16, body []byte) *ipfixBuilder {
+	set := binary.BigEndian.AppendUint16(nil, id)
+	set = binary.BigEndian.AppendUint16(set, uint16(len(body)+4))
+	b.sets = append(b.sets, append(set, body...))
+	return b
+}
+
+func (b *ipfixBuilder) template(id uint16, fields ...templateField) *ipfixBuilder {
+	body := binary.BigEndian.AppendUint16(nil, id)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(fields)))
+	for _, f := range fields {
+		if f.Enterprise != 0 {
+			body = binary.BigEndian.AppendUint16(body, f.Type|enterpriseBit)
+			body = binary.BigEndian.AppendUint16(body, f.Length)
+			body = binary.BigEndian.AppendUint32(body, f.Enterprise)
+			continue
+		}
+		body = binary.BigEndian.AppendUint16(body, f.Type)
+		body = binary.BigEndian.AppendUint16(body, f.Length)
+	}
+	return b.set(ipfixTemplateSetID, body)
+}
+
+func (b *ipfixBuilder) message(domain uint32) []byte {
+	msg := make([]byte, ipfixHeaderLen)
+	binary.BigEndian.PutUint16(msg[0:], ipfixVersion)
+	binary.BigEndian.PutUint32(msg[4:], 1700000000)
+	binary.BigEndian.PutUint32(msg[12:], domain)
+	for _, set := range b.sets {
+		msg = append(msg, set...)
+	}
+	binary.BigEndian.PutUint16(msg[2:], uint16(len(msg)))
+	return msg
+}
+
+// ipfixTestTemplate has an enterprise-specific fixed-length field and an
+// enterprise-specific variable-length field among the standard ones.
+var ipfixTestTemplate = []templateField{
+	{Type: fieldIPv4SrcAddr, Length: 4}, {Type: fieldIPv4DstAddr, Length: 4},
+	{Type: fieldL4SrcPort, Length: 2, Enterprise: 9}, // vendor field that reuses a standard number
+	{Type: fieldL4DstPort, Length: 2}, {Type: fieldProtocol, Length: 1},
+	{Type: 1000, Length: variableLength, Enterprise: 29305},
+	{Type: fieldPacketTotalCount, Length: 8}, {Type: fieldOctetTotalCount, Length: 8},
+	{Type: fieldFlowStartMilliseconds, Length: 8}, {Type: fieldFlowEndMilliseconds, Length: 8},
+}
+
+func ipfixTestRecord(vendor []byte, packets, bytes uint64) []byte {
+	rec := append([]byte(nil), net.ParseIP("10.1.0.1").To4()...)
+	rec = append(rec, net.ParseIP("10.1.0.2").To4()...)
+	rec = binary.BigEndian.AppendUint16(rec, 9999)
+	rec = binary.BigEndian.AppendUint16(rec, 443)
+	rec = append(rec, 6)
+	if len(vendor) < 255 {
+		rec = append(rec, byte(len(vendor)))
+	} else {
+		rec = binary.BigEndian.AppendUint16(append(rec, 255), uint16(len(vendor)))
+	}
+	rec = append(rec, vendor...)
+	rec = binary.BigEndian.AppendUint64(rec, packets)
+	rec = binary.BigEndian.AppendUint64(rec, bytes)
+	rec = binary.BigEndian.AppendUint64(rec, 1700000000000-20000)
+	return binary.BigEndian.AppendUint64(rec, 1700000000000-5000)
+}
+
+func TestDecodeIPFIXSkipsEnterpriseFields(t *testing.T) {
+	decoder := newIPFIXDecoder(0)
+	records := append(ipfixTestRecord([]byte("vendor"), 5, 500), ipfixTestRecord(make([]byte, 300), 1, 40)...)
+	msg := (&ipfixBuilder{}).template(300, ipfixTestTemplate...).set(300, records).message(1)
+
+	infos, err := decoder.Decode(msg, testExporter, time.Now())
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Decode() records = %d, want 2", len(infos))
+	}
+	got := infos[0]
+	if !got.FiveTuple.SrcIP.Equal(net.ParseIP("10.1.0.1")) || got.FiveTuple.SrcPort != 0 || got.FiveTuple.DstPort != 443 {
+		t.Fatalf("decoded tuple = %+v, want 10.1.0.1:0 -> :443 (enterprise port skipped)", got.FiveTuple)
+	}
+	if got.Packets != 5 || got.Length != 500 {
+		t.Fatalf("decoded packets/bytes = %d/%d, want 5/500", got.Packets, got.Length)
+	}
+	wantEnd := time.UnixMilli(1700000000000 - 5000)
+	if !got.Timestamp.Equal(wantEnd) || got.Duration != 15*time.Second {
+		t.Fatalf("decoded timestamp/duration = %v/%v, want %v/15s", got.Timestamp, got.Duration, wantEnd)
+	}
+	if infos[1].Packets != 1 || infos[1].Length != 40 {
+		t.Fatalf("decoded long-vendor record packets/bytes = %d/%d, want 1/40", infos[1].Packets, infos[1].Length)
+	}
+}
+
+func TestDecodeIPFIXWithdrawsTemplates(t *testing.T) {
+	decoder := newIPFIXDecoder(0)
+	missing := 0
+	decoder.missingTemplate = func() { missing++ }
+	now := time.Now()
+
+	data := (&ipfixBuilder{}).set(300, ipfixTestRecord(nil, 1, 60)).message(1)
+	learn := (&ipfixBuilder{}).template(300, ipfixTestTemplate...).message(1)
+	if _, err := decoder.Decode(append(learn, data...), testExporter, now); err != nil {
+		t.Fatalf("Decode(template, data) unexpected error: %v", err)
+	}
+	if infos, _ := decoder.Decode(data, testExporter, now); len(infos) != 1 {
+		t.Fatalf("Decode(data) records = %d, want 1", len(infos))
+	}
+
+	withdraw := (&ipfixBuilder{}).template(300).message(1)
+	if infos, err := decoder.Decode(append(withdraw, data...), testExporter, now); err != nil || len(infos) != 0 {
+		t.Fatalf("Decode(after withdrawal) = %d records, %v, want 0, nil", len(infos), err)
+	}
+	if missing != 1 {
+		t.Fatalf("missing template count = %d, want 1", missing)
+	}
+}
+
+func TestDecodeIPFIXAppliesOptionsSamplingRate(t *testing.T) {
+	decoder := newIPFIXDecoder(0)
+
+	// Options template 400: scope observationDomainId, then samplingPacketInterval/Space.
+	options := []byte{0x01, 0x90, 0, 3, 0, 1, 0, 149, 0, 4, 0x01, 0x31, 0, 4, 0x01, 0x32, 0, 4}
+	optionsRecord := []byte{0, 0, 0, 1, 0, 0, 0, 1, 0, 0, 0, 99}
+	msg := (&ipfixBuilder{}).set(ipfixOptionsSetID, options).set(400, optionsRecord).
+		template(300, ipfixTestTemplate...).set(300, ipfixTestRecord(nil, 2, 120)).message(1)
+
+	infos, err := decoder.Decode(msg, testExporter, time.Now())
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(infos) != 1 || infos[0].SampleRate != 100 {
+		t.Fatalf("Decode() = %d records, want 1 with sample rate 100", len(infos))
+	}
+}
+
+func TestDecodeIPFIXRejectsBadMessages(t *testing.T) {
+	decoder := newIPFIXDecoder(0)
+	msg := (&ipfixBuilder{}).template(300, ipfixTestTemplate...).message(1)
+	if _, err := decoder.Decode(msg[:len(msg)-3], testExporter, time.Now()); err == nil {
+		t.Fatal("Decode(truncated) error = nil, want non-nil")
+	}
+	binary.BigEndian.PutUint16(msg, 9)
+	if _, err := decoder.Decode(msg, testExporter, time.Now()); err == nil {
+		t.Fatal("Decode(version 9) error = nil, want non-nil")
+	}
+}
+
+func TestDecodeIPFIXRejectsZeroLengthTemplate(t *testing.T) {
+	decoder := newIPFIXDecoder(0)
+	msg := (&ipfixBuilder{}).template(256, templateField{Type: 1, Length: 0}).set(256, nil).message(1)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := decoder.Decode(msg, testExporter, time.Now())
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("Decode(zero-length template) error = nil, want non-nil")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Decode(zero-length template) did not return")
+	}
+}
@@ -0,0 +1,237 @@
+package collector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+)
+
+const (
+	netflowV5HeaderLen = 24
+	netflowV5RecordLen = 48
+	netflowV9HeaderLen = 20
+
+	// Flowset IDs below 256 carry templates rather than records.
+	netflowV9TemplateSetID = 0
+	netflowV9OptionsSetID  = 1
+	netflowV9MinDataSetID  = 256
+)
+
+// errMissingTemplate marks data records that arrived before their template.
+var errMissingTemplate = errors.New("template not received yet")
+
+// netflowDecoder decodes NetFlow v5 and v9 export packets.
+type netflowDecoder struct {
+	templates *templateCache
+	// missingTemplate counts the data flowsets skipped for want of a template.
+	missingTemplate func()
+}
+
+func newNetflowDecoder(templateTimeout time.Duration) *netflowDecoder {
+	return &netflowDecoder{templates: newTemplateCache(templateTimeout), missingTemplate: func() {}}
+}
+
+// Decode turns one export packet from exporter into one update per flow record.
+func (d *netflowDecoder) Decode(data []byte, exporter net.IP, now time.Time) ([]model.PacketInfo, error) {
+	if len(data) < 2 {
+		return nil, fmt.Errorf("netflow packet too short: %d bytes", len(data))
+	}
+	switch version := binary.BigEndian.Uint16(data); version {
+	case 5:
+		return decodeNetflowV5(data, exporter)
+	case 9:
+		return d.decodeV9(data, exporter, now)
+	default:
+		return nil, fmt.Errorf("unsupported netflow version %d", version)
+	}
+}
+
+// decodeNetflowV5 decodes a v5 packet, whose fixed record format needs no templates.
+func decodeNetflowV5(data []byte, exporter net.IP) ([]model.PacketInfo, error) {
+	if len(data) < netflowV5HeaderLen {
+		return nil, fmt.Errorf("netflow v5 header too short: %d bytes", len(data))
+	}
+	count := int(binary.BigEndian.Uint16(data[2:4]))
+	if len(data) < netflowV5HeaderLen+count*netflowV5RecordLen {
+		return nil, fmt.Errorf("netflow v5 packet truncated: %d records in %d bytes", count, len(data))
+	}
+	clock := exportClock{
+		uptime: binary.BigEndian.Uint32(data[4:8]),
+		export: time.Unix(int64(binary.BigEndian.Uint32(data[8:12])), int64(binary.BigEndian.Uint32(data[12:16]))),
+	}
+	// The top two bits hold the sampling mode, the rest the interval.
+	sampleRate := uint32(binary.BigEndian.Uint16(data[22:24]) & 0x3fff)
+
+	infos := make([]model.PacketInfo, 0, count)
+	for i := 0; i < count; i++ {
+		rec := data[netflowV5HeaderLen+i*netflowV5RecordLen:][:netflowV5RecordLen]
+		packets := uint64(binary.BigEndian.Uint32(rec[16:20]))
+		bytes := uint64(binary.BigEndian.Uint32(rec[20:24]))
+		if packets == 0 && bytes == 0 {
+			continue
+		}
+		first := clock.at(binary.BigEndian.Uint32(rec[24:28]))
+		last := clock.at(binary.BigEndian.Uint32(rec[28:32]))
+		infos = append(infos, model.PacketInfo{
+			Timestamp: last,
+			Duration:  max(last.Sub(first), 0),
+			FiveTuple: model.FiveTuple{
+				SrcIP:    ipFromBytes(rec[0:4]),
+				DstIP:    ipFromBytes(rec[4:8]),
+				SrcPort:  binary.BigEndian.Uint16(rec[32:34]),
+				DstPort:  binary.BigEndian.Uint16(rec[34:36]),
+				Protocol: rec[38],
+			},
+			Length:      int(bytes),
+			Packets:     packets,
+			TCPFlags:    rec[37],
+			InterfaceID: uint32(binary.BigEndian.Uint16(rec[12:14])),
+			SampleRate:  sampleRate,
+			ExporterIP:  exporter,
+		})
+	}
+	return infos, nil
+}
+
+// decodeV9 decodes a v9 packet, learning the templates it carries and
+// decoding the data flowsets whose templates are known.
+func (d *netflowDecoder) decodeV9(data []byte, exporter net.IP, now time.Time) ([]model.PacketInfo, error) {
+	if len(data) < netflowV9HeaderLen {
+		return nil, fmt.Errorf("netflow v9 header too short: %d bytes", len(data))
+	}
+	clock := exportClock{
+		uptime: binary.BigEndian.Uint32(data[4:8]),
+		export: time.Unix(int64(binary.BigEndian.Uint32(data[8:12])), 0),
+	}
+	domain := domainKey{exporter: exporter.String(), domain: binary.BigEndian.Uint32(data[16:20])}
+
+	var infos []model.PacketInfo
+	for rest := data[netflowV9HeaderLen:]; len(rest) >= 4; {
+		setID := binary.BigEndian.Uint16(rest[0:2])
+		setLen := int(binary.BigEndian.Uint16(rest[2:4]))
+		if setLen < 4 || setLen > len(rest) {
+			return infos, fmt.Errorf("netflow v9 flowset %d has invalid length %d", setID, setLen)
+		}
+		body := rest[4:setLen]
+		rest = rest[setLen:]
+
+		var err error
+		switch {
+		case setID == netflowV9TemplateSetID:
+			err = d.readTemplates(body, domain, now)
+		case setID == netflowV9OptionsSetID:
+			err = d.readOptionsTemplates(body, domain, now)
+		case setID >= netflowV9MinDataSetID:
+			infos, err = d.readRecords(infos, body, templateKey{domainKey: domain, id: setID}, exporter, clock, now)
+			if errors.Is(err, errMissingTemplate) {
+				d.missingTemplate()
+				err = nil
+			}
+		}
+		if err != nil {
+			return infos, err
+		}
+	}
+	return infos, nil
+}
+
+func (d *netflowDecoder) readTemplates(body []byte, domain domainKey, now time.Time) error {
+	for len(body) >= 4 {
+		id := binary.BigEndian.Uint16(body[0:2])
+		fieldCount := int(binary.BigEndian.Uint16(body[2:4]))
+		body = body[4:]
+		if len(body) < fieldCount*4 {
+			return fmt.Errorf("netflow v9 template %d truncated", id)
+		}
+		tmpl := newTemplate(readV9Fields(body, fieldCount), 0, false, now)
+		body = body[fieldCount*4:]
+		if id < netflowV9MinDataSetID || tmpl.minLen == 0 {
+			return fmt.Errorf("netflow v9 template %d is invalid", id)
+		}
+		d.templates.put(templateKey{domainKey: domain, id: id}, tmpl)
+	}
+	return nil
+}
+
+func (d *netflowDecoder) readOptionsTemplates(body []byte, domain domainKey, now time.Time) error {
+	for len(body) >= 6 {
+		id := binary.BigEndian.Uint16(body[0:2])
+		scopeLen := int(binary.BigEndian.Uint16(body[2:4]))
+		optionLen := int(binary.BigEndian.Uint16(body[4:6]))
+		body = body[6:]
+		if scopeLen%4 != 0 || optionLen%4 != 0 || len(body) < scopeLen+optionLen {
+			return fmt.Errorf("netflow v9 options template %d truncated", id)
+		}
+		tmpl := newTemplate(readV9Fields(body, (scopeLen+optionLen)/4), scopeLen/4, true, now)
+		body = body[scopeLen+optionLen:]
+		if id < netflowV9MinDataSetID || tmpl.minLen == 0 {
+			return fmt.Errorf("netflow v9 options template %d is invalid", id)
+		}
+		d.templates.put(templateKey{domainKey: domain, id: id}, tmpl)
+	}
+	return nil
+}
+
+// readV9Fields reads count type/length pairs.
+func readV9Fields(body []byte, count int) []templateField {
+	fields := make([]templateField, count)
+	for i := range fields {
+		fields[i] = templateField{Type: binary.BigEndian.Uint16(body[i*4:]), Length: binary.BigEndian.Uint16(body[i*4+2:])}
+	}
+	return fields
+}
+
+// readRecords appends the flow records of a data flowset to infos. Options
+// records only update the domain's sampling rate.
+func (d *netflowDecoder) readRecords(infos []model.PacketInfo, body []byte, key templateKey, exporter net.IP, clock exportClock, now time.Time) ([]model.PacketInfo, error) {
+	tmpl := d.templates.get(key, now)
+	if tmpl == nil {
+		return infos, errMissingTemplate
+	}
+	return decodeRecords(infos, body, tmpl, d.templates, key.domainKey, exporter, clock)
+}
+
+// decodeRecords decodes the records of a NetFlow v9 or IPFIX data set.
+// Anything shorter than a record after the last one is padding.
+func decodeRecords(infos []model.PacketInfo, body []byte, tmpl *template, templates *templateCache, domain domainKey, exporter net.IP, clock exportClock) ([]model.PacketInfo, error) {
+	for len(body) >= tmpl.minLen {
+		builder := recordBuilder{clock: clock}
+		i := 0
+		n, err := tmpl.walk(body, func(field templateField, value []byte) {
+			// Scope fields only say what an options record describes.
+			if i >= tmpl.scopeLen {
+				builder.set(field, value)
+			}
+			i++
+		})
+		if err != nil {
+			return infos, err
+		}
+		if n == 0 {
+			// A record that consumes nothing would never advance.
+			return infos, errors.New("flow record has zero length")
+		}
+		body = body[n:]
+
+		if tmpl.options {
+			if rate := builder.sampleRate(); rate > 0 {
+				templates.setSamplingRate(domain, rate)
+			}
+			continue
+		}
+		info, ok := builder.finish()
+		if !ok {
+			continue
+		}
+		info.ExporterIP = exporter
+		if info.SampleRate == 0 {
+			info.SampleRate = templates.samplingRate(domain)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
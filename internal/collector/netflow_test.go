@@ -0,0 +1,221 @@
+package collector
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+var testExporter = net.ParseIP("192.0.2.254")
+
+func netflowV5Packet(sampling uint16, records ...[]byte) []byte {
+	packet := make([]byte, netflowV5HeaderLen)
+	binary.BigEndian.PutUint16(packet[0:], 5)
+	binary.BigEndian.PutUint16(packet[2:], uint16(len(records)))
+	binary.BigEndian.PutUint32(packet[4:], 100000)     // uptime 100s
+	binary.BigEndian.PutUint32(packet[8:], 1700000000) // export time
+	binary.BigEndian.PutUint16(packet[22:], sampling)
+	for _, rec := range records {
+		packet = append(packet, rec...)
+	}
+	return packet
+}
+
+func netflowV5Record(src, dst string, srcPort, dstPort uint16, packets, bytes, firstMillis, lastMillis uint32) []byte {
+	rec := make([]byte, netflowV5RecordLen)
+	copy(rec[0:], net.ParseIP(src).To4())
+	copy(rec[4:], net.ParseIP(dst).To4())
+	binary.BigEndian.PutUint16(rec[12:], 3) // input interface
+	binary.BigEndian.PutUint32(rec[16:], packets)
+	binary.BigEndian.PutUint32(rec[20:], bytes)
+	binary.BigEndian.PutUint32(rec[24:], firstMillis)
+	binary.BigEndian.PutUint32(rec[28:], lastMillis)
+	binary.BigEndian.PutUint16(rec[32:], srcPort)
+	binary.BigEndian.PutUint16(rec[34:], dstPort)
+	rec[37] = 0x12 // SYN|ACK
+	rec[38] = 6
+	return rec
+}
+
+func TestDecodeNetflowV5(t *testing.T) {
+	packet := netflowV5Packet(0x4000|64,
+		netflowV5Record("10.0.0.1", "10.0.0.2", 40000, 443, 12, 9000, 40000, 95000),
+		netflowV5Record("10.0.0.3", "10.0.0.4", 1, 2, 0, 0, 0, 0),
+	)
+
+	infos, err := newNetflowDecoder(0).Decode(packet, testExporter, time.Now())
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("Decode() records = %d, want 1 (empty records skipped)", len(infos))
+	}
+
+	got := infos[0]
+	if !got.FiveTuple.SrcIP.Equal(net.ParseIP("10.0.0.1")) || got.FiveTuple.DstPort != 443 || got.FiveTuple.Protocol != 6 {
+		t.Fatalf("decoded tuple = %+v, want 10.0.0.1 -> :443/6", got.FiveTuple)
+	}
+	if got.Packets != 12 || got.Length != 9000 || got.SampleRate != 64 {
+		t.Fatalf("decoded packets/bytes/rate = %d/%d/%d, want 12/9000/64", got.Packets, got.Length, got.SampleRate)
+	}
+	if got.PacketCount() != 768 || got.ByteCount() != 576000 {
+		t.Fatalf("PacketCount()/ByteCount() = %d/%d, want 768/576000", got.PacketCount(), got.ByteCount())
+	}
+	wantLast := time.Unix(1700000000, 0).Add(-5 * time.Second)
+	if !got.Timestamp.Equal(wantLast) || got.Duration != 55*time.Second {
+		t.Fatalf("decoded timestamp/duration = %v/%v, want %v/55s", got.Timestamp, got.Duration, wantLast)
+	}
+	if got.TCPFlags != 0x12 || got.InterfaceID != 3 || !got.ExporterIP.Equal(testExporter) {
+		t.Fatalf("decoded flags/interface/exporter = %#x/%d/%v, want 0x12/3/%v", got.TCPFlags, got.InterfaceID, got.ExporterIP, testExporter)
+	}
+}
+
+func TestDecodeNetflowV5RejectsTruncatedPacket(t *testing.T) {
+	packet := netflowV5Packet(0, netflowV5Record("10.0.0.1", "10.0.0.2", 1, 2, 1, 60, 0, 0))
+	if _, err := newNetflowDecoder(0).Decode(packet[:len(packet)-1], testExporter, time.Now()); err == nil {
+		t.Fatal("Decode(truncated) error = nil, want non-nil")
+	}
+}
+
+// v9Builder assembles a NetFlow v9 export packet.
+type v9Builder struct {
+	sets [][]byte
+}
+
+func (b *v9Builder) flowset(id uint16, body []byte) *v9Builder {
+	// Pad to a 32-bit boundary like exporters do.
+	for len(body)%4 != 0 {
+		body = append(body, 0)
+	}
+	set := binary.BigEndian.AppendUint16(nil, id)
+	set = binary.BigEndian.AppendUint16(set, uint16(len(body)+4))
+	b.sets = append(b.sets, append(set, body...))
+	return b
+}
+
+func (b *v9Builder) template(id uint16, fields ...templateField) *v9Builder {
+	body := binary.BigEndian.AppendUint16(nil, id)
+	body = binary.BigEndian.AppendUint16(body, uint16(len(fields)))
+	for _, f := range fields {
+		body = binary.BigEndian.AppendUint16(body, f.Type)
+		body = binary.BigEndian.AppendUint16(body, f.Length)
+	}
+	return b.flowset(netflowV9TemplateSetID, body)
+}
+
+func (b *v9Builder) packet(sourceID uint32) []byte {
+	packet := make([]byte, netflowV9HeaderLen)
+	binary.BigEndian.PutUint16(packet[0:], 9)
+	binary.BigEndian.PutUint16(packet[2:], uint16(len(b.sets)))
+	binary.BigEndian.PutUint32(packet[4:], 100000)
+	binary.BigEndian.PutUint32(packet[8:], 1700000000)
+	binary.BigEndian.PutUint32(packet[16:], sourceID)
+	for _, set := range b.sets {
+		packet = append(packet, set...)
+	}
+	return packet
+}
+
+var v9TestTemplate = []templateField{
+	{Type: fieldIPv6SrcAddr, Length: 16}, {Type: fieldIPv6DstAddr, Length: 16}, {Type: fieldL4SrcPort, Length: 2}, {Type: fieldL4DstPort, Length: 2},
+	{Type: fieldProtocol, Length: 1}, {Type: fieldInPkts, Length: 4}, {Type: fieldInBytes, Length: 8}, {Type: fieldFirstSwitched, Length: 4}, {Type: fieldLastSwitched, Length: 4},
+}
+
+func v9TestRecord(packets uint32, bytes uint64) []byte {
+	rec := append([]byte(nil), net.ParseIP("2001:db8::1")...)
+	rec = append(rec, net.ParseIP("2001:db8::2")...)
+	rec = binary.BigEndian.AppendUint16(rec, 5353)
+	rec = binary.BigEndian.AppendUint16(rec, 53)
+	rec = append(rec, 17)
+	rec = binary.BigEndian.AppendUint32(rec, packets)
+	rec = binary.BigEndian.AppendUint64(rec, bytes)
+	rec = binary.BigEndian.AppendUint32(rec, 90000)
+	return binary.BigEndian.AppendUint32(rec, 99000)
+}
+
+func TestDecodeNetflowV9WithTemplate(t *testing.T) {
+	decoder := newNetflowDecoder(0)
+	now := time.Now()
+
+	// Data that arrives before its template is dropped and counted.
+	missing := 0
+	decoder.missingTemplate = func() { missing++ }
+	early := (&v9Builder{}).flowset(300, v9TestRecord(1, 100)).packet(7)
+	if infos, err := decoder.Decode(early, testExporter, now); err != nil || len(infos) != 0 {
+		t.Fatalf("Decode(before template) = %d records, %v, want 0, nil", len(infos), err)
+	}
+	if missing != 1 {
+		t.Fatalf("missing template count = %d, want 1", missing)
+	}
+
+	packet := (&v9Builder{}).template(300, v9TestTemplate...).
+		flowset(300, append(v9TestRecord(4, 1<<33), v9TestRecord(1, 80)...)).packet(7)
+	infos, err := decoder.Decode(packet, testExporter, now)
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("Decode() records = %d, want 2", len(infos))
+	}
+	got := infos[0]
+	if !got.FiveTuple.DstIP.Equal(net.ParseIP("2001:db8::2")) || got.FiveTuple.SrcPort != 5353 || got.FiveTuple.Protocol != 17 {
+		t.Fatalf("decoded tuple = %+v, want 2001:db8::2 <- :5353/17", got.FiveTuple)
+	}
+	if got.Packets != 4 || uint64(got.Length) != 1<<33 {
+		t.Fatalf("decoded packets/bytes = %d/%d, want 4/%d", got.Packets, got.Length, uint64(1<<33))
+	}
+	if got.Duration != 9*time.Second || !got.ExporterIP.Equal(testExporter) {
+		t.Fatalf("decoded duration/exporter = %v/%v, want 9s/%v", got.Duration, got.ExporterIP, testExporter)
+	}
+
+	// Templates belong to one exporter and source ID.
+	other := (&v9Builder{}).flowset(300, v9TestRecord(1, 100)).packet(8)
+	if infos, _ := decoder.Decode(other, testExporter, now); len(infos) != 0 {
+		t.Fatalf("Decode(other source id) records = %d, want 0", len(infos))
+	}
+}
+
+func TestDecodeNetflowV9ExpiresTemplates(t *testing.T) {
+	decoder := newNetflowDecoder(time.Minute)
+	start := time.Now()
+	if _, err := decoder.Decode((&v9Builder{}).template(300, v9TestTemplate...).packet(1), testExporter, start); err != nil {
+		t.Fatalf("Decode(template) unexpected error: %v", err)
+	}
+
+	data := (&v9Builder{}).flowset(300, v9TestRecord(1, 100)).packet(1)
+	if infos, _ := decoder.Decode(data, testExporter, start.Add(30*time.Second)); len(infos) != 1 {
+		t.Fatalf("Decode(fresh template) records = %d, want 1", len(infos))
+	}
+	if infos, _ := decoder.Decode(data, testExporter, start.Add(2*time.Minute)); len(infos) != 0 {
+		t.Fatalf("Decode(expired template) records = %d, want 0", len(infos))
+	}
+}
+
+func TestDecodeNetflowV9AppliesOptionsSamplingRate(t *testing.T) {
+	decoder := newNetflowDecoder(0)
+	now := time.Now()
+
+	// Options template 400: scope system (4 bytes), option SAMPLING_INTERVAL (4 bytes).
+	options := []byte{0x01, 0x90, 0, 4, 0, 4, 0, 1, 0, 4, 0, fieldSamplingInterval, 0, 4}
+	optionsRecord := []byte{0, 0, 0, 0, 0, 0, 0, 128}
+	packet := (&v9Builder{}).flowset(netflowV9OptionsSetID, options).flowset(400, optionsRecord).
+		template(300, v9TestTemplate...).flowset(300, v9TestRecord(2, 200)).packet(1)
+
+	infos, err := decoder.Decode(packet, testExporter, now)
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(infos) != 1 || infos[0].SampleRate != 128 {
+		t.Fatalf("Decode() = %d records, want 1 with sample rate 128", len(infos))
+	}
+	if got := infos[0].PacketCount(); got != 256 {
+		t.Fatalf("PacketCount() = %d, want 256", got)
+	}
+}
+
+func TestDecodeRejectsUnknownVersion(t *testing.T) {
+	if _, err := newNetflowDecoder(0).Decode([]byte{0, 7, 0, 0}, testExporter, time.Now()); err == nil {
+		t.Fatal("Decode(version 7) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,227 @@
+package collector
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// Field types understood in NetFlow v9 and IPFIX data records. IPFIX
+// information elements 1-127 keep the NetFlow v9 numbering.
+const (
+	fieldInBytes                   = 1
+	fieldInPkts                    = 2
+	fieldProtocol                  = 4
+	fieldTCPFlags                  = 6
+	fieldL4SrcPort                 = 7
+	fieldIPv4SrcAddr               = 8
+	fieldInputSNMP                 = 10
+	fieldL4DstPort                 = 11
+	fieldIPv4DstAddr               = 12
+	fieldLastSwitched              = 21
+	fieldFirstSwitched             = 22
+	fieldIPv6SrcAddr               = 27
+	fieldIPv6DstAddr               = 28
+	fieldSamplingInterval          = 34
+	fieldFlowSamplerRandomInterval = 50
+	fieldSrcVLAN                   = 58
+	fieldMPLSLabel1                = 70
+	fieldOctetTotalCount           = 85
+	fieldPacketTotalCount          = 86
+	fieldFlowStartSeconds          = 150
+	fieldFlowEndSeconds            = 151
+	fieldFlowStartMilliseconds     = 152
+	fieldFlowEndMilliseconds       = 153
+	fieldDot1qVLANID               = 243
+	fieldSamplingPacketInterval    = 305
+	fieldSamplingPacketSpace       = 306
+)
+
+// variableLength marks an IPFIX field whose length precedes its value.
+const variableLength = 0xffff
+
+// templateField is one field of a NetFlow v9 or IPFIX template.
+type templateField struct {
+	Type       uint16
+	Length     uint16
+	Enterprise uint32 // IPFIX private enterprise number; zero for IANA elements
+}
+
+// template describes the records of a data set.
+type template struct {
+	fields   []templateField
+	scopeLen int // leading scope fields of an options template
+	minLen   int // record length, counting variable-length fields as one byte
+	options  bool
+	updated  time.Time
+}
+
+func newTemplate(fields []templateField, scopeLen int, options bool, now time.Time) *template {
+	tmpl := &template{fields: fields, scopeLen: scopeLen, options: options, updated: now}
+	for _, field := range fields {
+		if field.Length == variableLength {
+			tmpl.minLen++
+		} else {
+			tmpl.minLen += int(field.Length)
+		}
+	}
+	return tmpl
+}
+
+// walk calls fn with the value of every field of the record at the start of
+// data, and returns the record's length.
+func (t *template) walk(data []byte, fn func(field templateField, value []byte)) (int, error) {
+	n := 0
+	for _, field := range t.fields {
+		length := int(field.Length)
+		if field.Length == variableLength {
+			if n >= len(data) {
+				return 0, fmt.Errorf("record truncated")
+			}
+			length = int(data[n])
+			n++
+			if length == 255 {
+				if n+2 > len(data) {
+					return 0, fmt.Errorf("record truncated")
+				}
+				length = int(data[n])<<8 | int(data[n+1])
+				n += 2
+			}
+		}
+		if n+length > len(data) {
+			return 0, fmt.Errorf("record truncated")
+		}
+		fn(field, data[n:n+length])
+		n += length
+	}
+	return n, nil
+}
+
+// recordBuilder turns the fields of one data record into a weighted update.
+// Enterprise-specific fields are skipped.
+type recordBuilder struct {
+	info              model.PacketInfo
+	clock             exportClock
+	bytes             uint64
+	first, last       time.Time
+	hasFirst, hasLast bool
+	samplingInterval  uint64 // 1-in-N rate of NetFlow v9 style sampling fields
+	packetInterval    uint64 // IPFIX systematic sampling: packetInterval selected ...
+	packetSpace       uint64 // ... then packetSpace skipped
+}
+
+func (b *recordBuilder) set(field templateField, value []byte) {
+	if field.Enterprise != 0 {
+		return
+	}
+	info := &b.info
+	switch field.Type {
+	case fieldInBytes, fieldOctetTotalCount:
+		b.bytes = readUint(value)
+	case fieldInPkts, fieldPacketTotalCount:
+		info.Packets = readUint(value)
+	case fieldProtocol:
+		info.FiveTuple.Protocol = uint8(readUint(value))
+	case fieldTCPFlags:
+		info.TCPFlags = uint8(readUint(value))
+	case fieldL4SrcPort:
+		info.FiveTuple.SrcPort = uint16(readUint(value))
+	case fieldL4DstPort:
+		info.FiveTuple.DstPort = uint16(readUint(value))
+	case fieldIPv4SrcAddr, fieldIPv6SrcAddr:
+		info.FiveTuple.SrcIP = ipFromBytes(value)
+	case fieldIPv4DstAddr, fieldIPv6DstAddr:
+		info.FiveTuple.DstIP = ipFromBytes(value)
+	case fieldInputSNMP:
+		info.InterfaceID = uint32(readUint(value))
+	case fieldFirstSwitched:
+		if !b.clock.noUptime {
+			b.first, b.hasFirst = b.clock.at(uint32(readUint(value))), true
+		}
+	case fieldLastSwitched:
+		if !b.clock.noUptime {
+			b.last, b.hasLast = b.clock.at(uint32(readUint(value))), true
+		}
+	case fieldFlowStartSeconds:
+		b.first, b.hasFirst = time.Unix(int64(readUint(value)), 0), true
+	case fieldFlowEndSeconds:
+		b.last, b.hasLast = time.Unix(int64(readUint(value)), 0), true
+	case fieldFlowStartMilliseconds:
+		b.first, b.hasFirst = time.UnixMilli(int64(readUint(value))), true
+	case fieldFlowEndMilliseconds:
+		b.last, b.hasLast = time.UnixMilli(int64(readUint(value))), true
+	case fieldSamplingInterval, fieldFlowSamplerRandomInterval:
+		b.samplingInterval = readUint(value)
+	case fieldSamplingPacketInterval:
+		b.packetInterval = readUint(value)
+	case fieldSamplingPacketSpace:
+		b.packetSpace = readUint(value)
+	case fieldSrcVLAN, fieldDot1qVLANID:
+		info.OuterVLAN = uint16(readUint(value)) & 0x0fff
+	case fieldMPLSLabel1:
+		// Label, experimental bits and bottom-of-stack bit.
+		info.MPLSLabel = uint32(readUint(value) >> 4)
+	}
+}
+
+// sampleRate returns the 1-in-N rate announced by the record, or zero.
+func (b *recordBuilder) sampleRate() uint32 {
+	if b.packetInterval > 0 && b.packetSpace > 0 {
+		return uint32((b.packetInterval + b.packetSpace) / b.packetInterval)
+	}
+	return uint32(b.samplingInterval)
+}
+
+// finish returns the update for the record. ok is false for records that
+// carry no traffic.
+func (b *recordBuilder) finish() (info model.PacketInfo, ok bool) {
+	info = b.info
+	if info.Packets == 0 && b.bytes == 0 {
+		return info, false
+	}
+	info.Length = int(b.bytes)
+	info.SampleRate = b.sampleRate()
+	info.Timestamp = b.clock.export
+	if b.hasLast {
+		info.Timestamp = b.last
+	}
+	if b.hasFirst && !b.first.After(info.Timestamp) {
+		info.Duration = info.Timestamp.Sub(b.first)
+	}
+	return info, true
+}
+
+// exportClock converts the exporter's uptime timestamps to wall-clock time.
+type exportClock struct {
+	uptime   uint32 // exporter uptime in milliseconds when the packet was sent
+	export   time.Time
+	noUptime bool // the header carries no uptime, so uptime fields are ignored
+}
+
+func (c exportClock) at(uptimeMillis uint32) time.Time {
+	// Unsigned subtraction stays correct across an uptime wrap.
+	return c.export.Add(-time.Duration(c.uptime-uptimeMillis) * time.Millisecond)
+}
+
+// readUint reads a big-endian unsigned integer of up to eight bytes.
+func readUint(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// ipFromBytes copies a 4 or 16 byte address into the 16 byte form the engine expects.
+func ipFromBytes(b []byte) net.IP {
+	switch len(b) {
+	case net.IPv4len:
+		return net.IPv4(b[0], b[1], b[2], b[3])
+	case net.IPv6len:
+		return append(net.IP(nil), b...)
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,254 @@
+package collector
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+	"Go2NetSpectra/internal/protocol"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+const (
+	sflowVersion = 5
+
+	// Sample formats, enterprise 0.
+	sflowFlowSample            = 1
+	sflowCounterSample         = 2
+	sflowExpandedFlowSample    = 3
+	sflowExpandedCounterSample = 4
+
+	// Flow and counter record formats, enterprise 0.
+	sflowRawPacketHeader          = 1
+	sflowGenericInterfaceCounters = 1
+
+	// Header protocols of a raw packet header record.
+	sflowHeaderEthernet = 1
+	sflowHeaderIPv4     = 11
+	sflowHeaderIPv6     = 12
+
+	sflowGenericInterfaceCountersLen = 88
+)
+
+// errSFlowTruncated reports a datagram that ends inside a structure.
+var errSFlowTruncated = errors.New("sflow datagram truncated")
+
+// sflowDecoder decodes sFlow v5 datagrams. Flow samples become updates
+// weighted by the sampling rate; counter samples go to counters.
+type sflowDecoder struct {
+	ethernet *protocol.Decoder
+	raw      *protocol.Decoder
+	counters func(InterfaceCounters)
+}
+
+func newSFlowDecoder() (*sflowDecoder, error) {
+	ethernet, err := protocol.NewDecoder(layers.LinkTypeEthernet)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := protocol.NewDecoder(layers.LinkTypeRaw)
+	if err != nil {
+		return nil, err
+	}
+	return &sflowDecoder{ethernet: ethernet, raw: raw, counters: func(InterfaceCounters) {}}, nil
+}
+
+// xdrReader reads the big-endian, 4-byte aligned fields of an sFlow datagram.
+// After the first short read every method returns zero values and err is set.
+type xdrReader struct {
+	data []byte
+	err  error
+}
+
+func (r *xdrReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	padded := (n + 3) &^ 3
+	if n < 0 || padded > len(r.data) {
+		r.err = errSFlowTruncated
+		r.data = nil
+		return nil
+	}
+	b := r.data[:n]
+	r.data = r.data[padded:]
+	return b
+}
+
+func (r *xdrReader) uint32() uint32 {
+	if b := r.next(4); b != nil {
+		return binary.BigEndian.Uint32(b)
+	}
+	return 0
+}
+
+func (r *xdrReader) uint64() uint64 {
+	if b := r.next(8); b != nil {
+		return binary.BigEndian.Uint64(b)
+	}
+	return 0
+}
+
+// opaque reads a length-prefixed block.
+func (r *xdrReader) opaque() []byte {
+	return r.next(int(r.uint32()))
+}
+
+// Decode turns the flow samples of one datagram into updates. The agent
+// address in the datagram, not its UDP source, identifies the exporter.
+// Datagrams are not timestamped, so samples are stamped with now.
+func (d *sflowDecoder) Decode(data []byte, exporter net.IP, now time.Time) ([]model.PacketInfo, error) {
+	r := &xdrReader{data: data}
+	if version := r.uint32(); r.err == nil && version != sflowVersion {
+		return nil, fmt.Errorf("unsupported sflow version %d", version)
+	}
+	switch addrType := r.uint32(); addrType {
+	case 1:
+		if b := r.next(net.IPv4len); b != nil {
+			exporter = ipFromBytes(b)
+		}
+	case 2:
+		if b := r.next(net.IPv6len); b != nil {
+			exporter = ipFromBytes(b)
+		}
+	}
+	r.next(12) // sub-agent ID, sequence number and uptime
+	count := r.uint32()
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	var infos []model.PacketInfo
+	for i := uint32(0); i < count; i++ {
+		format := r.uint32()
+		sample := &xdrReader{data: r.opaque()}
+		if r.err != nil {
+			return infos, r.err
+		}
+		// Samples of other enterprises keep the standard framing and are skipped.
+		switch format {
+		case sflowFlowSample, sflowExpandedFlowSample:
+			infos = d.readFlowSample(infos, sample, format == sflowExpandedFlowSample, exporter, now)
+		case sflowCounterSample, sflowExpandedCounterSample:
+			d.readCounterSample(sample, format == sflowExpandedCounterSample, exporter, now)
+		}
+		if sample.err != nil {
+			return infos, fmt.Errorf("sflow sample %d: %w", i, sample.err)
+		}
+	}
+	return infos, nil
+}
+
+func (d *sflowDecoder) readFlowSample(infos []model.PacketInfo, r *xdrReader, expanded bool, exporter net.IP, now time.Time) []model.PacketInfo {
+	r.next(4) // sequence number
+	if expanded {
+		r.next(8) // source ID type and index
+	} else {
+		r.next(4)
+	}
+	rate := r.uint32()
+	r.next(8) // sample pool and drops
+	var input uint32
+	if expanded {
+		r.next(4) // input interface format
+		input = r.uint32()
+		r.next(8) // output interface format and value
+	} else {
+		// The top two bits hold the interface format.
+		input = r.uint32() & 0x3fffffff
+		r.next(4)
+	}
+
+	records := r.uint32()
+	for i := uint32(0); i < records && r.err == nil; i++ {
+		format := r.uint32()
+		record := r.opaque()
+		if r.err != nil || format != sflowRawPacketHeader {
+			continue
+		}
+		info, ok := d.decodeRawHeader(record, now)
+		if !ok {
+			continue
+		}
+		info.InterfaceID = input
+		info.SampleRate = rate
+		info.ExporterIP = exporter
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// decodeRawHeader decodes the packet header captured by a raw packet header
+// record. The update counts the original frame length, not the captured bytes.
+func (d *sflowDecoder) decodeRawHeader(record []byte, now time.Time) (model.PacketInfo, bool) {
+	r := &xdrReader{data: record}
+	headerProtocol := r.uint32()
+	frameLength := r.uint32()
+	stripped := r.uint32()
+	header := r.opaque()
+	if r.err != nil {
+		return model.PacketInfo{}, false
+	}
+
+	var decoder *protocol.Decoder
+	switch headerProtocol {
+	case sflowHeaderEthernet:
+		decoder = d.ethernet
+	case sflowHeaderIPv4, sflowHeaderIPv6:
+		decoder = d.raw
+	default:
+		return model.PacketInfo{}, false
+	}
+
+	var info model.PacketInfo
+	ci := gopacket.CaptureInfo{Timestamp: now, CaptureLength: len(header), Length: int(frameLength - min(stripped, frameLength))}
+	if err := decoder.DecodeInto(header, ci, &info); err != nil {
+		return model.PacketInfo{}, false
+	}
+	return info, true
+}
+
+func (d *sflowDecoder) readCounterSample(r *xdrReader, expanded bool, exporter net.IP, now time.Time) {
+	r.next(4) // sequence number
+	if expanded {
+		r.next(8)
+	} else {
+		r.next(4)
+	}
+
+	records := r.uint32()
+	for i := uint32(0); i < records && r.err == nil; i++ {
+		format := r.uint32()
+		record := r.opaque()
+		if r.err != nil || format != sflowGenericInterfaceCounters || len(record) < sflowGenericInterfaceCountersLen {
+			continue
+		}
+		d.counters(readGenericInterfaceCounters(record, exporter, now))
+	}
+}
+
+// readGenericInterfaceCounters reads an if_counters record (RFC 2233 counters).
+func readGenericInterfaceCounters(record []byte, agent net.IP, now time.Time) InterfaceCounters {
+	r := &xdrReader{data: record}
+	c := InterfaceCounters{Agent: agent, Timestamp: now}
+	c.IfIndex = r.uint32()
+	r.next(4) // ifType
+	c.Speed = r.uint64()
+	r.next(4) // ifDirection
+	c.Status = r.uint32()
+	c.InOctets = r.uint64()
+	c.InPackets = uint64(r.uint32()) + uint64(r.uint32()) + uint64(r.uint32()) // unicast, multicast, broadcast
+	c.InDiscards = uint64(r.uint32())
+	c.InErrors = uint64(r.uint32())
+	r.next(4) // ifInUnknownProtos
+	c.OutOctets = r.uint64()
+	c.OutPackets = uint64(r.uint32()) + uint64(r.uint32()) + uint64(r.uint32())
+	c.OutDiscards = uint64(r.uint32())
+	c.OutErrors = uint64(r.uint32())
+	return c
+}
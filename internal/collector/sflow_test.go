@@ -0,0 +1,171 @@
+package collector
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+var testAgent = net.ParseIP("198.51.100.7")
+
+// sflowDatagram assembles an sFlow v5 datagram from IPv4 agent testAgent.
+func sflowDatagram(samples ...[]byte) []byte {
+	d := binary.BigEndian.AppendUint32(nil, sflowVersion)
+	d = binary.BigEndian.AppendUint32(d, 1)
+	d = append(d, testAgent.To4()...)
+	d = binary.BigEndian.AppendUint32(d, 0)      // sub-agent
+	d = binary.BigEndian.AppendUint32(d, 1)      // sequence
+	d = binary.BigEndian.AppendUint32(d, 100000) // uptime
+	d = binary.BigEndian.AppendUint32(d, uint32(len(samples)))
+	for _, s := range samples {
+		d = append(d, s...)
+	}
+	return d
+}
+
+// sflowOpaque frames data with its format and padded length.
+func sflowOpaque(format uint32, data []byte) []byte {
+	for len(data)%4 != 0 {
+		data = append(data, 0)
+	}
+	b := binary.BigEndian.AppendUint32(nil, format)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+func sflowFlowSampleData(rate, input uint32, records ...[]byte) []byte {
+	s := binary.BigEndian.AppendUint32(nil, 1) // sequence
+	s = binary.BigEndian.AppendUint32(s, 3)    // source ID
+	s = binary.BigEndian.AppendUint32(s, rate)
+	s = binary.BigEndian.AppendUint32(s, 5000) // sample pool
+	s = binary.BigEndian.AppendUint32(s, 0)    // drops
+	s = binary.BigEndian.AppendUint32(s, input)
+	s = binary.BigEndian.AppendUint32(s, 9) // output
+	s = binary.BigEndian.AppendUint32(s, uint32(len(records)))
+	for _, r := range records {
+		s = append(s, r...)
+	}
+	return s
+}
+
+// sflowRawHeader captures the first 64 bytes of a TCP segment of frameLength bytes.
+func sflowRawHeader(t *testing.T, frameLength uint32) []byte {
+	t.Helper()
+	eth := &layers.Ethernet{SrcMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5}, DstMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6}, EthernetType: layers.EthernetTypeIPv4}
+	ip := &layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolTCP, SrcIP: net.ParseIP("10.2.0.1").To4(), DstIP: net.ParseIP("10.2.0.2").To4()}
+	tcp := &layers.TCP{SrcPort: 33000, DstPort: 443, SYN: true}
+	if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+		t.Fatalf("SetNetworkLayerForChecksum() unexpected error: %v", err)
+	}
+	buf := gopacket.NewSerializeBuffer()
+	payload := gopacket.Payload(make([]byte, frameLength-14-20-20))
+	if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}, eth, ip, tcp, payload); err != nil {
+		t.Fatalf("SerializeLayers() unexpected error: %v", err)
+	}
+	header := buf.Bytes()[:64]
+
+	r := binary.BigEndian.AppendUint32(nil, sflowHeaderEthernet)
+	r = binary.BigEndian.AppendUint32(r, frameLength+4) // with FCS
+	r = binary.BigEndian.AppendUint32(r, 4)             // FCS stripped
+	r = binary.BigEndian.AppendUint32(r, uint32(len(header)))
+	return sflowOpaque(sflowRawPacketHeader, append(r, header...))
+}
+
+func sflowCounterSampleData(ifIndex uint32) []byte {
+	c := binary.BigEndian.AppendUint32(nil, ifIndex)
+	c = binary.BigEndian.AppendUint32(c, 6)              // ethernetCsmacd
+	c = binary.BigEndian.AppendUint64(c, 10_000_000_000) // speed
+	c = binary.BigEndian.AppendUint32(c, 1)              // full duplex
+	c = binary.BigEndian.AppendUint32(c, 3)              // admin and oper up
+	c = binary.BigEndian.AppendUint64(c, 1<<40)          // in octets
+	for _, v := range []uint32{100, 20, 3, 4, 5, 6} {    // in ucast, mcast, bcast, discards, errors, unknown
+		c = binary.BigEndian.AppendUint32(c, v)
+	}
+	c = binary.BigEndian.AppendUint64(c, 1<<20) // out octets
+	for _, v := range []uint32{50, 0, 0, 7, 8, 0} {
+		c = binary.BigEndian.AppendUint32(c, v)
+	}
+	record := sflowOpaque(sflowGenericInterfaceCounters, c)
+
+	s := binary.BigEndian.AppendUint32(nil, 1) // sequence
+	s = binary.BigEndian.AppendUint32(s, ifIndex)
+	s = binary.BigEndian.AppendUint32(s, 1)
+	return append(s, record...)
+}
+
+func TestDecodeSFlowFlowSample(t *testing.T) {
+	decoder, err := newSFlowDecoder()
+	if err != nil {
+		t.Fatalf("newSFlowDecoder() unexpected error: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	unknownRecord := sflowOpaque(1001, []byte{1, 2, 3, 4}) // extended switch data
+	datagram := sflowDatagram(
+		sflowOpaque(sflowFlowSample, sflowFlowSampleData(512, 3, unknownRecord, sflowRawHeader(t, 1000))),
+		sflowOpaque(4<<12|1, []byte{0, 0, 0, 0}), // sample of another enterprise
+	)
+
+	infos, err := decoder.Decode(datagram, testExporter, now)
+	if err != nil {
+		t.Fatalf("Decode() unexpected error: %v", err)
+	}
+	if len(infos) != 1 {
+		t.Fatalf("Decode() records = %d, want 1", len(infos))
+	}
+	got := infos[0]
+	if !got.FiveTuple.SrcIP.Equal(net.ParseIP("10.2.0.1")) || got.FiveTuple.DstPort != 443 || got.FiveTuple.Protocol != 6 {
+		t.Fatalf("decoded tuple = %+v, want 10.2.0.1 -> :443/6", got.FiveTuple)
+	}
+	if got.Length != 1000 || got.SampleRate != 512 || got.PacketCount() != 512 || got.ByteCount() != 512000 {
+		t.Fatalf("decoded length/rate/packets/bytes = %d/%d/%d/%d, want 1000/512/512/512000", got.Length, got.SampleRate, got.PacketCount(), got.ByteCount())
+	}
+	if got.TCPFlags == 0 || got.InterfaceID != 3 || !got.ExporterIP.Equal(testAgent) || !got.Timestamp.Equal(now) {
+		t.Fatalf("decoded flags/interface/exporter/time = %#x/%d/%v/%v, want SYN/3/%v/%v", got.TCPFlags, got.InterfaceID, got.ExporterIP, got.Timestamp, testAgent, now)
+	}
+}
+
+func TestDecodeSFlowCounterSample(t *testing.T) {
+	decoder, err := newSFlowDecoder()
+	if err != nil {
+		t.Fatalf("newSFlowDecoder() unexpected error: %v", err)
+	}
+	var counters []InterfaceCounters
+	decoder.counters = func(c InterfaceCounters) { counters = append(counters, c) }
+
+	infos, err := decoder.Decode(sflowDatagram(sflowOpaque(sflowCounterSample, sflowCounterSampleData(12))), testExporter, time.Now())
+	if err != nil || len(infos) != 0 {
+		t.Fatalf("Decode() = %d records, %v, want 0, nil", len(infos), err)
+	}
+	if len(counters) != 1 {
+		t.Fatalf("counter samples = %d, want 1", len(counters))
+	}
+	got := counters[0]
+	if !got.Agent.Equal(testAgent) || got.IfIndex != 12 || got.Speed != 10_000_000_000 || got.Status != 3 {
+		t.Fatalf("counters agent/ifIndex/speed/status = %v/%d/%d/%d, want %v/12/1e10/3", got.Agent, got.IfIndex, got.Speed, got.Status, testAgent)
+	}
+	if got.InOctets != 1<<40 || got.InPackets != 123 || got.InDiscards != 4 || got.InErrors != 5 {
+		t.Fatalf("in octets/packets/discards/errors = %d/%d/%d/%d, want %d/123/4/5", got.InOctets, got.InPackets, got.InDiscards, got.InErrors, uint64(1<<40))
+	}
+	if got.OutOctets != 1<<20 || got.OutPackets != 50 || got.OutDiscards != 7 || got.OutErrors != 8 {
+		t.Fatalf("out octets/packets/discards/errors = %d/%d/%d/%d, want %d/50/7/8", got.OutOctets, got.OutPackets, got.OutDiscards, got.OutErrors, 1<<20)
+	}
+}
+
+func TestDecodeSFlowRejectsBadDatagrams(t *testing.T) {
+	decoder, err := newSFlowDecoder()
+	if err != nil {
+		t.Fatalf("newSFlowDecoder() unexpected error: %v", err)
+	}
+	datagram := sflowDatagram(sflowOpaque(sflowCounterSample, sflowCounterSampleData(1)))
+	if _, err := decoder.Decode(datagram[:len(datagram)-8], testExporter, time.Now()); err == nil {
+		t.Fatal("Decode(truncated) error = nil, want non-nil")
+	}
+	binary.BigEndian.PutUint32(datagram, 4)
+	if _, err := decoder.Decode(datagram, testExporter, time.Now()); err == nil {
+		t.Fatal("Decode(version 4) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,102 @@
+package collector
+
+import (
+	"sync"
+	"time"
+)
+
+// defaultTemplateTimeout is how long a template is kept without a refresh.
+// Exporters resend their templates every few minutes.
+const defaultTemplateTimeout = 30 * time.Minute
+
+// domainKey identifies one exporter's observation domain (the v9 source ID).
+type domainKey struct {
+	exporter string
+	domain   uint32
+}
+
+type templateKey struct {
+	domainKey
+	id uint16
+}
+
+// templateCache holds the templates, and the sampling rates announced in
+// options records, of every exporter. Templates are scoped to the exporter
+// address and its observation domain, as exporters number them independently.
+type templateCache struct {
+	mu            sync.Mutex
+	timeout       time.Duration
+	templates     map[templateKey]*template
+	samplingRates map[domainKey]uint32
+}
+
+func newTemplateCache(timeout time.Duration) *templateCache {
+	if timeout <= 0 {
+		timeout = defaultTemplateTimeout
+	}
+	return &templateCache{
+		timeout:       timeout,
+		templates:     make(map[templateKey]*template),
+		samplingRates: make(map[domainKey]uint32),
+	}
+}
+
+func (c *templateCache) put(key templateKey, tmpl *template) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.templates[key] = tmpl
+}
+
+// withdraw forgets a template the exporter withdrew.
+func (c *templateCache) withdraw(key templateKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.templates, key)
+}
+
+// get returns the template for key, dropping it if it was not refreshed in time.
+func (c *templateCache) get(key templateKey, now time.Time) *template {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tmpl, ok := c.templates[key]
+	if !ok {
+		return nil
+	}
+	if now.Sub(tmpl.updated) > c.timeout {
+		delete(c.templates, key)
+		return nil
+	}
+	return tmpl
+}
+
+func (c *templateCache) setSamplingRate(key domainKey, rate uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.samplingRates[key] = rate
+}
+
+func (c *templateCache) samplingRate(key domainKey) uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.samplingRates[key]
+}
+
+// prune drops every template that timed out, along with the sampling rates of
+// domains left without templates.
+func (c *templateCache) prune(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	live := make(map[domainKey]bool)
+	for key, tmpl := range c.templates {
+		if now.Sub(tmpl.updated) > c.timeout {
+			delete(c.templates, key)
+			continue
+		}
+		live[key.domainKey] = true
+	}
+	for key := range c.samplingRates {
+		if !live[key] {
+			delete(c.samplingRates, key)
+		}
+	}
+}
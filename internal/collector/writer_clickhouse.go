@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+
+	"Go2NetSpectra/internal/config"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
+)
+
+const createCountersTableStatement = `
+CREATE TABLE IF NOT EXISTS interface_counters (
+    Timestamp   DateTime,
+    AgentIP     String,
+    IfIndex     UInt32,
+    IfSpeed     UInt64,
+    IfStatus    UInt32,
+    InOctets    UInt64,
+    InPackets   UInt64,
+    InErrors    UInt64,
+    InDiscards  UInt64,
+    OutOctets   UInt64,
+    OutPackets  UInt64,
+    OutErrors   UInt64,
+    OutDiscards UInt64,
+    EngineID    LowCardinality(String)
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(Timestamp)
+ORDER BY (AgentIP, IfIndex, Timestamp);
+`
+
+// CounterSink stores the interface counter samples drained from a collector.
+type CounterSink interface {
+	WriteCounters(counters []InterfaceCounters) error
+}
+
+// CounterWriter writes interface counter samples to the ClickHouse
+// interface_counters table. It implements CounterSink.
+type CounterWriter struct {
+	conn     driver.Conn
+	engineID string
+}
+
+// NewCounterWriter connects to ClickHouse and creates the counter table.
+// Rows are tagged with engineID like the flow snapshot rows.
+func NewCounterWriter(cfg config.ClickHouseConfig, engineID string) (*CounterWriter, error) {
+	opts := &clickhouse.Options{
+		Addr: []string{fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)},
+		Auth: clickhouse.Auth{
+			Database: cfg.Database,
+			Username: cfg.Username,
+			Password: cfg.Password,
+		},
+	}
+	if cfg.Cloud {
+		opts.Protocol = clickhouse.HTTP
+		opts.TLS = &tls.Config{
+			InsecureSkipVerify: true,
+		}
+	}
+	conn, err := clickhouse.Open(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clickhouse: %w", err)
+	}
+	if err := conn.Ping(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to ping clickhouse: %w", err)
+	}
+	if err := conn.Exec(context.Background(), createCountersTableStatement); err != nil {
+		return nil, fmt.Errorf("failed to create interface_counters table: %w", err)
+	}
+	return &CounterWriter{conn: conn, engineID: engineID}, nil
+}
+
+// WriteCounters inserts one row per sample.
+func (w *CounterWriter) WriteCounters(counters []InterfaceCounters) error {
+	if len(counters) == 0 {
+		return nil
+	}
+	batch, err := w.conn.PrepareBatch(context.Background(), "INSERT INTO interface_counters")
+	if err != nil {
+		return fmt.Errorf("failed to prepare batch: %w", err)
+	}
+	for _, c := range counters {
+		err := batch.Append(
+			c.Timestamp,
+			c.Agent.String(),
+			c.IfIndex,
+			c.Speed,
+			c.Status,
+			c.InOctets,
+			c.InPackets,
+			c.InErrors,
+			c.InDiscards,
+			c.OutOctets,
+			c.OutPackets,
+			c.OutErrors,
+			c.OutDiscards,
+			w.engineID,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append counters to batch: %w", err)
+		}
+	}
+	if err := batch.Send(); err != nil {
+		return fmt.Errorf("failed to send batch: %w", err)
+	}
+	log.Printf("Wrote %d interface counter samples to ClickHouse", len(counters))
+	return nil
+}
+
+// Close closes the ClickHouse connection.
+func (w *CounterWriter) Close() error {
+	return w.conn.Close()
+}
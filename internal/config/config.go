@@ -29,21 +29,85 @@ type TextConfig struct {
 	RootPath string `yaml:"root_path"`
 }
 
+// IPFIXConfig holds the configuration for the IPFIX export writer.
+type IPFIXConfig struct {
+	Address           string `yaml:"address"`            // collector address, e.g. "collector:4739"
+	Transport         string `yaml:"transport"`          // "udp" (default) or "tcp"
+	ObservationDomain uint32 `yaml:"observation_domain"` // observation domain ID carried in every message
+}
+
 // WriterDef defines a writer configuration.
 type WriterDef struct {
+	// Name labels the writer's metrics. It defaults to the aggregator type,
+	// writer type and position, e.g. "exact/clickhouse/0".
+	Name             string           `yaml:"name"`
 	Type             string           `yaml:"type"`
 	Enabled          bool             `yaml:"enabled"`
 	SnapshotInterval string           `yaml:"snapshot_interval"`
 	Gob              GobConfig        `yaml:"gob"`
 	Text             TextConfig       `yaml:"text"`
 	ClickHouse       ClickHouseConfig `yaml:"clickhouse"`
+	IPFIX            IPFIXConfig      `yaml:"ipfix"`
 }
 
 // ExactTaskDef defines a single task's parameters within the exact aggregator group.
 type ExactTaskDef struct {
-	Name      string   `yaml:"name"`
-	NumShards uint32   `yaml:"num_shards"`
-	KeyFields []string `yaml:"key_fields"`
+	Name      string       `yaml:"name"`
+	NumShards uint32       `yaml:"num_shards"`
+	KeyFields []string     `yaml:"key_fields"`
+	Window    WindowConfig `yaml:"window"`
+	// Filter limits the task to matching packets, e.g.
+	// "tcp and dst port 80-443 and not src net 10.0.0.0/8". Empty matches all.
+	Filter string `yaml:"filter"`
+	// IdleTimeout and ActiveTimeout end flows NetFlow-style, e.g. "15s" and
+	// "30m", instead of at period resets. Setting either enables both.
+	IdleTimeout   string `yaml:"idle_timeout"`
+	ActiveTimeout string `yaml:"active_timeout"`
+	// MaxFlows caps the flows the task holds; 0 leaves it unbounded. Overflow
+	// picks what happens to new flows past it: "evict" (the default) writes
+	// out the least recently seen flow, "refuse" drops their packets and
+	// "other" counts them in a single catch-all flow.
+	MaxFlows int64  `yaml:"max_flows"`
+	Overflow string `yaml:"overflow"`
+}
+
+// EventTimeConfig makes packet timestamps, instead of the wall clock, drive
+// window closes, writer snapshots and period resets, so a replayed capture is
+// measured on its own timeline.
+type EventTimeConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// AllowedLateness is how far, in packet time, a packet may trail the
+	// latest one seen and still be counted. Later packets are dropped.
+	AllowedLateness string `yaml:"allowed_lateness"`
+}
+
+// OverloadConfig decides what happens to packets from NATS that arrive while
+// the engine's packet channel is full.
+type OverloadConfig struct {
+	// Policy is "block" (the default) to wait for room, "drop_newest" to drop
+	// the packet, or "sample" to keep 1 in SampleRate of the packets that find
+	// the channel full, weighted up like probe sampling, and drop the rest.
+	Policy     string `yaml:"policy"`
+	SampleRate uint32 `yaml:"sample_rate"`
+}
+
+// CheckpointConfig saves the state of every task to Dir, periodically and when
+// the engine stops, so that a restarted engine carries on with the windows and
+// period that were open. State is only restored into a task whose definition
+// has not changed.
+type CheckpointConfig struct {
+	Dir      string `yaml:"dir"`      // empty disables checkpoints
+	Interval string `yaml:"interval"` // e.g. "1m"; empty only checkpoints on stop
+}
+
+// WindowConfig gives a task its own measurement windows. Windows start at
+// multiples of Hop since the Unix epoch, shifted by Offset, so engines and
+// restarts agree on them. Tasks without a Size are reset every
+// aggregator.period, counted from engine start.
+type WindowConfig struct {
+	Size   string `yaml:"size"`   // e.g. "1m"; empty disables the window
+	Hop    string `yaml:"hop"`    // sliding step dividing size; empty or equal to size is tumbling
+	Offset string `yaml:"offset"` // shifts every boundary, e.g. "30s"
 }
 
 // ExactAggregatorConfig holds all configuration for the "exact" aggregator type.
@@ -67,6 +131,9 @@ type SketchTaskDef struct {
 	Size uint32  `yaml:"size"`
 	Base float64 `yaml:"base"`
 	B    float64 `yaml:"b"`
+
+	Window WindowConfig `yaml:"window"`
+	Filter string       `yaml:"filter"` // same syntax as ExactTaskDef.Filter
 }
 
 // SketchAggregatorConfig holds all configuration for the sketch aggregator type.
@@ -75,12 +142,37 @@ type SketchAggregatorConfig struct {
 	Tasks   []SketchTaskDef `yaml:"tasks"`
 }
 
+// ClusterConfig controls how several ns-engine instances share the packet partitions.
+type ClusterConfig struct {
+	EngineID   string `yaml:"engine_id"`   // written with every snapshot row; empty uses the host name
+	QueueGroup string `yaml:"queue_group"` // NATS queue group joined on each partition; empty uses "ns-engine"
+	Partitions []int  `yaml:"partitions"`  // partitions this engine consumes; empty consumes all of probe.partitions
+}
+
+// ID returns the configured engine ID, falling back to the host name.
+func (c ClusterConfig) ID() string {
+	if c.EngineID != "" {
+		return c.EngineID
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "ns-engine"
+}
+
 // AggregatorConfig holds the top-level aggregator settings.
 type AggregatorConfig struct {
 	Types               []string               `yaml:"types"`
 	Period              string                 `yaml:"period"`
 	NumWorkers          int                    `yaml:"num_workers"`
+	Dispatch            string                 `yaml:"dispatch"` // "shared" (default) or "flow"
 	SizeOfPacketChannel int                    `yaml:"size_of_packet_channel"`
+	Cluster             ClusterConfig          `yaml:"cluster"`
+	AdminListenAddr     string                 `yaml:"admin_listen_addr"`   // ns-engine admin RPC, empty disables it
+	MetricsListenAddr   string                 `yaml:"metrics_listen_addr"` // ns-engine /metrics, empty disables it
+	EventTime           EventTimeConfig        `yaml:"event_time"`
+	Overload            OverloadConfig         `yaml:"overload"`
+	Checkpoint          CheckpointConfig       `yaml:"checkpoint"`
 	Exact               ExactAggregatorConfig  `yaml:"exact"`
 	Sketch              SketchAggregatorConfig `yaml:"sketch"`
 }
@@ -94,17 +186,94 @@ type PersistenceConfig struct {
 	ChannelBufferSize int    `yaml:"channel_buffer_size"`
 }
 
+// DecapConfig controls how the packet parser treats tunnel-encapsulated traffic.
+type DecapConfig struct {
+	Mode    string   `yaml:"mode"`    // "outer" (default) keeps the outer headers, "inner" uses the innermost ones
+	Tunnels []string `yaml:"tunnels"` // any of "vxlan", "geneve", "gre", "ipip"; empty enables all
+}
+
+// CaptureConfig controls how packets are captured and which of them are kept.
+type CaptureConfig struct {
+	BPFFilter   string `yaml:"bpf_filter"`  // tcpdump-style expression; empty keeps every packet
+	SnapLen     int32  `yaml:"snaplen"`     // bytes captured per packet; 0 uses 1600
+	Promiscuous *bool  `yaml:"promiscuous"` // unset enables promiscuous mode
+	BufferSize  int    `yaml:"buffer_size"` // kernel capture buffer in bytes; 0 keeps the libpcap default
+}
+
+// PromiscuousEnabled reports whether the interface should be put in promiscuous mode.
+func (c CaptureConfig) PromiscuousEnabled() bool {
+	return c.Promiscuous == nil || *c.Promiscuous
+}
+
+// InterfaceConfig describes one interface captured by the probe.
+type InterfaceConfig struct {
+	Name      string `yaml:"name"`
+	ID        uint32 `yaml:"id"`         // InterfaceID stamped on its packets; 0 uses the OS interface index
+	BPFFilter string `yaml:"bpf_filter"` // replaces capture.bpf_filter for this interface when set
+	LinkType  string `yaml:"link_type"`  // "ethernet", "linux_sll", "raw", ...; empty keeps the interface default
+}
+
+// BatchConfig controls how the probe groups packets into NATS messages.
+type BatchConfig struct {
+	MaxPackets    int    `yaml:"max_packets"`    // packets per message; 0 sends every packet on its own
+	FlushInterval string `yaml:"flush_interval"` // longest a partial batch waits, e.g. "100ms"
+	Compression   string `yaml:"compression"`    // "none" (default), "zstd" or "lz4"
+}
+
+// SamplingConfig controls how many packets the probe forwards to the engine.
+type SamplingConfig struct {
+	Mode string `yaml:"mode"` // "" or "none" forwards every packet, "packet" keeps 1 in rate at random, "flow" keeps 1 in rate flows
+	Rate uint32 `yaml:"rate"` // N of the 1-in-N sampling; 0 or 1 disables sampling
+}
+
+// JetStreamConfig moves packet messages from core NATS onto a JetStream stream,
+// so engine restarts and slow consumers no longer lose traffic.
+type JetStreamConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	Stream     string `yaml:"stream"`      // stream name; empty uses "NETSPECTRA_PACKETS"
+	MaxAge     string `yaml:"max_age"`     // how long messages are kept, e.g. "1h"; empty keeps them until another limit is hit
+	MaxBytes   int64  `yaml:"max_bytes"`   // stream size limit; 0 means unlimited
+	MaxMsgs    int64  `yaml:"max_msgs"`    // stream message limit; 0 means unlimited
+	Replicas   int    `yaml:"replicas"`    // 0 uses 1
+	Consumer   string `yaml:"consumer"`    // durable consumer name; empty uses "ns-engine"
+	AckWait    string `yaml:"ack_wait"`    // redelivery timeout for unacked messages; empty uses "30s"
+	MaxDeliver int    `yaml:"max_deliver"` // delivery attempts per message; 0 uses 5
+	FetchBatch int    `yaml:"fetch_batch"` // messages pulled per request; 0 uses 256
+	MaxPending int    `yaml:"max_pending"` // unacknowledged probe publishes before publishing stalls; 0 uses 4096
+	ReplayFrom string `yaml:"replay_from"` // RFC3339 time or stream sequence to reprocess from; empty resumes the durable consumer
+}
+
 // ProbeConfig holds the configuration for the probe component.
 type ProbeConfig struct {
-	NATSURL     string            `yaml:"nats_url"`
-	Subject     string            `yaml:"subject"`
-	Persistence PersistenceConfig `yaml:"persistence"`
+	NATSURL           string            `yaml:"nats_url"`
+	Subject           string            `yaml:"subject"`
+	Partitions        int               `yaml:"partitions"` // subjects "<subject>.<n>" packets are spread over by flow hash; 0 or 1 publishes to subject
+	Persistence       PersistenceConfig `yaml:"persistence"`
+	Decap             DecapConfig       `yaml:"decap"`
+	Capture           CaptureConfig     `yaml:"capture"`
+	Interfaces        []InterfaceConfig `yaml:"interfaces"`
+	Batch             BatchConfig       `yaml:"batch"`
+	Sampling          SamplingConfig    `yaml:"sampling"`
+	JetStream         JetStreamConfig   `yaml:"jetstream"`
+	MetricsListenAddr string            `yaml:"metrics_listen_addr"` // ns-probe /metrics, empty disables it
+}
+
+// CollectorConfig controls the flow-export collector that ns-engine runs in
+// collector mode, for sites that export flow records instead of running ns-probe.
+type CollectorConfig struct {
+	NetFlowAddr     string `yaml:"netflow_addr"`     // UDP address for NetFlow v5/v9, e.g. ":2055"; empty disables it
+	IPFIXAddr       string `yaml:"ipfix_addr"`       // UDP address for IPFIX, e.g. ":4739"; empty disables it
+	SFlowAddr       string `yaml:"sflow_addr"`       // UDP address for sFlow v5, e.g. ":6343"; empty disables it
+	CounterInterval string `yaml:"counter_interval"` // how often sFlow interface counters are written to ClickHouse; empty uses "60s"
+	ReadBuffer      int    `yaml:"read_buffer"`      // socket receive buffer in bytes; 0 keeps the OS default
+	TemplateTimeout string `yaml:"template_timeout"` // how long an exporter's template is kept without a refresh; empty uses "30m"
 }
 
 // APIConfig holds the configuration for the API server.
 type APIConfig struct {
-	RPCListenAddr  string `yaml:"rpc_listen_addr"`
-	HTTPListenAddr string `yaml:"http_listen_addr"`
+	RPCListenAddr     string `yaml:"rpc_listen_addr"`
+	HTTPListenAddr    string `yaml:"http_listen_addr"`
+	MetricsListenAddr string `yaml:"metrics_listen_addr"` // ns-api /metrics, empty disables it
 }
 
 // AlerterRule defines a single condition for triggering an alert.
@@ -142,17 +311,19 @@ type SMTPConfig struct {
 
 // AIConfig defines the configuration for the AI analyzer service.
 type AIConfig struct {
-	Provider      string `yaml:"provider"`
-	APIKey        string `yaml:"api_key"`
-	Model         string `yaml:"model"`
-	BaseURL       string `yaml:"base_url"`
-	RPCListenAddr string `yaml:"rpc_listen_addr"`
+	Provider          string `yaml:"provider"`
+	APIKey            string `yaml:"api_key"`
+	Model             string `yaml:"model"`
+	BaseURL           string `yaml:"base_url"`
+	RPCListenAddr     string `yaml:"rpc_listen_addr"`
+	MetricsListenAddr string `yaml:"metrics_listen_addr"` // ns-ai /metrics, empty disables it
 }
 
 // Config is the top-level configuration for the application.
 type Config struct {
 	Aggregator AggregatorConfig `yaml:"aggregator"`
 	Probe      ProbeConfig      `yaml:"probe"`
+	Collector  CollectorConfig  `yaml:"collector"`
 	API        APIConfig        `yaml:"api"`
 	Alerter    AlerterConfig    `yaml:"alerter"`
 	SMTP       SMTPConfig       `yaml:"smtp"`
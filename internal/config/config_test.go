@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -48,3 +49,63 @@ func TestLoadConfigReturnsYAMLError(t *testing.T) {
 		t.Fatalf("LoadConfig(%q) error = %q, want substring %q", configPath, err.Error(), "failed to unmarshal config yaml")
 	}
 }
+
+func TestLoadConfigReadsProbeCaptureSettings(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+probe:
+  capture:
+    bpf_filter: "not port 873"
+    snaplen: 128
+    promiscuous: false
+    buffer_size: 8388608
+`)
+	if err := os.WriteFile(configPath, content, 0o644); err != nil {
+		t.Fatalf("WriteFile(%q) error: %v", configPath, err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig(%q) unexpected error: %v", configPath, err)
+	}
+
+	capture := cfg.Probe.Capture
+	if capture.BPFFilter != "not port 873" || capture.SnapLen != 128 || capture.BufferSize != 8388608 {
+		t.Fatalf("LoadConfig(%q) capture = %+v, want filter/snaplen/buffer from file", configPath, capture)
+	}
+	if capture.PromiscuousEnabled() {
+		t.Fatalf("LoadConfig(%q) PromiscuousEnabled() = true, want false", configPath)
+	}
+	if !(CaptureConfig{}).PromiscuousEnabled() {
+		t.Fatal("CaptureConfig{}.PromiscuousEnabled() = false, want true by default")
+	}
+}
+
+func TestLoadConfigReadsProbeInterfaces(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	content := []byte(`
+probe:
+  interfaces:
+    - name: "eth0"
+    - name: "eth1"
+      id: 7
+      bpf_filter: "not port 2049"
+      link_type: "linux_sll"
+`)
+	if err := os.WriteFile(configPath, content, 0o644); err != nil {
+		t.Fatalf("WriteFile(%q) error: %v", configPath, err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig(%q) unexpected error: %v", configPath, err)
+	}
+
+	want := []InterfaceConfig{
+		{Name: "eth0"},
+		{Name: "eth1", ID: 7, BPFFilter: "not port 2049", LinkType: "linux_sll"},
+	}
+	if !reflect.DeepEqual(cfg.Probe.Interfaces, want) {
+		t.Fatalf("LoadConfig(%q) interfaces = %+v, want %+v", configPath, cfg.Probe.Interfaces, want)
+	}
+}
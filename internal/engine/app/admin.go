@@ -0,0 +1,114 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	v1 "Go2NetSpectra/api/gen/thrift/v1"
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/manager"
+	"Go2NetSpectra/internal/metrics"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+const adminRPCBufferSize = 32 * 1024
+
+// reloadFunc applies a new config to a running engine.
+type reloadFunc func(cfg *config.Config) (*manager.ReloadResult, error)
+
+// reloader re-reads the config file and applies it to the running engine.
+// It implements v1.AdminService.
+type reloader struct {
+	configPath string
+	apply      reloadFunc
+}
+
+func (r *reloader) reload() (*manager.ReloadResult, error) {
+	cfg, err := config.LoadConfig(r.configPath)
+	if err != nil {
+		return nil, err
+	}
+	return r.apply(cfg)
+}
+
+// ReloadConfig reloads the config file. A rejected config is reported in the
+// response rather than as an RPC error, and the running one is kept.
+func (r *reloader) ReloadConfig(ctx context.Context, req *v1.ReloadConfigRequest) (*v1.ReloadConfigResponse, error) {
+	log.Println("Received ReloadConfig request")
+	result, err := r.reload()
+	if err != nil {
+		log.Printf("Config reload rejected: %v", err)
+		errorText := err.Error()
+		return &v1.ReloadConfigResponse{ErrorText: &errorText}, nil
+	}
+	return &v1.ReloadConfigResponse{
+		Applied:        true,
+		TasksAdded:     result.TasksAdded,
+		TasksRemoved:   result.TasksRemoved,
+		TasksKept:      result.TasksKept,
+		WritersStarted: int32(result.WritersStarted),
+		WritersStopped: int32(result.WritersStopped),
+		WritersKept:    int32(result.WritersKept),
+		AlertRules:     int32(result.AlertRules),
+		Warnings:       result.Warnings,
+	}, nil
+}
+
+// startReloader reloads configPath through apply on SIGHUP and, when
+// adminAddr is set, on AdminService.ReloadConfig calls. The returned function
+// stops both.
+func startReloader(configPath, adminAddr string, apply reloadFunc) (func(), error) {
+	r := &reloader{configPath: configPath, apply: apply}
+
+	var server *thrift.TSimpleServer
+	if adminAddr != "" {
+		serverTransport, err := thrift.NewTServerSocket(adminAddr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", adminAddr, err)
+		}
+		transportFactory := thrift.NewTBufferedTransportFactory(adminRPCBufferSize)
+		protocolFactory := thrift.NewTBinaryProtocolFactoryConf(&thrift.TConfiguration{})
+		server = thrift.NewTSimpleServer4(thrift.WrapProcessor(v1.NewAdminServiceProcessor(r), metrics.ThriftMiddleware("admin")), serverTransport, transportFactory, protocolFactory)
+		if err := server.Listen(); err != nil {
+			return nil, fmt.Errorf("failed to listen on %s: %w", adminAddr, err)
+		}
+		go func() {
+			log.Printf("Admin RPC server starting on %s", adminAddr)
+			if err := server.AcceptLoop(); err != nil {
+				log.Printf("Admin RPC server stopped with error: %v", err)
+			}
+		}()
+	}
+
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-hangup:
+				log.Printf("SIGHUP received, reloading %s", configPath)
+				if _, err := r.reload(); err != nil {
+					log.Printf("Config reload rejected: %v", err)
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(hangup)
+		close(done)
+		if server != nil {
+			if err := server.Stop(); err != nil {
+				log.Printf("Failed to stop admin rpc server: %v", err)
+			}
+		}
+	}, nil
+}
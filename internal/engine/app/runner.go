@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"log"
 
+	"Go2NetSpectra/internal/collector"
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/manager"
 	"Go2NetSpectra/internal/engine/streamaggregator"
+	"Go2NetSpectra/internal/metrics"
 )
 
 // RunStreamEngine starts the stream aggregator and blocks until shutdown.
-func RunStreamEngine(ctx context.Context, cfg *config.Config) error {
+// configPath is re-read on SIGHUP and admin reload requests.
+func RunStreamEngine(ctx context.Context, cfg *config.Config, configPath string) error {
 	log.Println("Starting ns-engine...")
 
 	streamAgg, err := streamaggregator.NewStreamAggregator(cfg)
@@ -21,10 +25,83 @@ func RunStreamEngine(ctx context.Context, cfg *config.Config) error {
 	if err := streamAgg.Start(); err != nil {
 		return fmt.Errorf("failed to start stream aggregator: %w", err)
 	}
+	stopReloader, err := startReloader(configPath, cfg.Aggregator.AdminListenAddr, streamAgg.Reload)
+	if err != nil {
+		streamAgg.Stop()
+		return fmt.Errorf("failed to start admin rpc server: %w", err)
+	}
+	metrics.Start(ctx, cfg.Aggregator.MetricsListenAddr)
 	<-ctx.Done()
 
 	log.Println("Shutdown signal received, stopping aggregator...")
+	stopReloader()
 	streamAgg.Stop()
 	log.Println("Shutdown complete.")
 	return nil
 }
+
+// RunCollectorEngine aggregates flow records exported to the collector instead
+// of packets from NATS, and blocks until shutdown. configPath is re-read on
+// SIGHUP and admin reload requests.
+func RunCollectorEngine(ctx context.Context, cfg *config.Config, configPath string) error {
+	log.Println("Starting ns-engine in collector mode...")
+
+	mgr, err := manager.NewManager(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create manager: %w", err)
+	}
+	coll, err := collector.New(cfg.Collector, mgr.Input())
+	if err != nil {
+		return fmt.Errorf("failed to create collector: %w", err)
+	}
+	if cfg.Collector.SFlowAddr != "" {
+		counterWriter := newCounterWriter(cfg)
+		if counterWriter != nil {
+			defer counterWriter.Close()
+			coll.SetCounterSink(counterWriter)
+		}
+	}
+
+	mgr.Start()
+	if err := coll.Start(); err != nil {
+		mgr.Stop()
+		return fmt.Errorf("failed to start collector: %w", err)
+	}
+	stopReloader, err := startReloader(configPath, cfg.Aggregator.AdminListenAddr, mgr.Reload)
+	if err != nil {
+		coll.Stop()
+		mgr.Stop()
+		return fmt.Errorf("failed to start admin rpc server: %w", err)
+	}
+	metrics.Start(ctx, cfg.Aggregator.MetricsListenAddr)
+	<-ctx.Done()
+
+	log.Println("Shutdown signal received, stopping collector...")
+	stopReloader()
+	// The collector must stop sending before the manager closes its input.
+	coll.Stop()
+	mgr.Stop()
+	log.Println("Shutdown complete.")
+	return nil
+}
+
+// newCounterWriter stores sFlow interface counters in the ClickHouse database
+// of the first enabled clickhouse writer, where the query API looks for them.
+// It returns nil, and counters are dropped, when there is none.
+func newCounterWriter(cfg *config.Config) *collector.CounterWriter {
+	for _, writers := range [][]config.WriterDef{cfg.Aggregator.Exact.Writers, cfg.Aggregator.Sketch.Writers} {
+		for _, writerDef := range writers {
+			if !writerDef.Enabled || writerDef.Type != "clickhouse" {
+				continue
+			}
+			counterWriter, err := collector.NewCounterWriter(writerDef.ClickHouse, cfg.Aggregator.Cluster.ID())
+			if err != nil {
+				log.Printf("Warning: failed to create interface counter writer: %v, counters will not be stored.", err)
+				return nil
+			}
+			return counterWriter
+		}
+	}
+	log.Println("Warning: no clickhouse writer is enabled, sFlow interface counters will not be stored.")
+	return nil
+}
@@ -0,0 +1,149 @@
+package checkpoint
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// Version is the file format version. Bump it whenever the layout of a file,
+// or of the state any task writes into one, changes; older files are then
+// rejected instead of misread.
+const Version = 1
+
+// magic starts every checkpoint file.
+const magic = "NSCK"
+
+// A file is magic, the version and the body length, the body, and the CRC-32
+// of the body. The body is the length of the gob-encoded Header, the Header,
+// and the task's own state.
+const prefixSize = len(magic) + 2 + 8
+
+// ErrVersion is returned for files written in another format version.
+var ErrVersion = errors.New("unsupported checkpoint version")
+
+// Header describes the task state that follows it.
+type Header struct {
+	Task       string // "<aggregator type>/<task name>"
+	Definition string // Fingerprint of the task definition
+	// PeriodStart and Period are the global measurement period the state
+	// belongs to. They only matter for tasks without their own windows.
+	PeriodStart time.Time
+	Period      time.Duration
+	SavedAt     time.Time
+}
+
+// File is a checkpoint file that passed the integrity checks.
+type File struct {
+	Header Header
+	state  []byte
+}
+
+// Fingerprint identifies a task definition, so that state is only restored
+// into a task configured the same way as the one that saved it.
+func Fingerprint(def any) (string, error) {
+	data, err := json.Marshal(def)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode task definition: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Write saves the state of task to path. The file is written next to path
+// and renamed over it, so a crash leaves the previous checkpoint intact.
+func Write(path string, header Header, task model.Checkpointer) error {
+	var headerBuf bytes.Buffer
+	if err := gob.NewEncoder(&headerBuf).Encode(header); err != nil {
+		return fmt.Errorf("failed to encode checkpoint header: %w", err)
+	}
+	var body bytes.Buffer
+	body.Write(binary.BigEndian.AppendUint32(nil, uint32(headerBuf.Len())))
+	body.Write(headerBuf.Bytes())
+	if err := task.Checkpoint(&body); err != nil {
+		return fmt.Errorf("failed to save task state: %w", err)
+	}
+
+	data := make([]byte, 0, prefixSize+body.Len()+4)
+	data = append(data, magic...)
+	data = binary.BigEndian.AppendUint16(data, Version)
+	data = binary.BigEndian.AppendUint64(data, uint64(body.Len()))
+	data = append(data, body.Bytes()...)
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(body.Bytes()))
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to create checkpoint file: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to sync checkpoint file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to close checkpoint file: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to replace checkpoint file: %w", err)
+	}
+	return nil
+}
+
+// Load reads the checkpoint at path and checks its version and checksum.
+// A missing file returns an error satisfying errors.Is(err, os.ErrNotExist).
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < prefixSize+4 || string(data[:len(magic)]) != magic {
+		return nil, fmt.Errorf("%s is not a checkpoint file", path)
+	}
+	if version := binary.BigEndian.Uint16(data[len(magic):]); version != Version {
+		return nil, fmt.Errorf("%s: %w %d, want %d", path, ErrVersion, version, Version)
+	}
+	size := binary.BigEndian.Uint64(data[len(magic)+2:])
+	if size != uint64(len(data)-prefixSize-4) {
+		return nil, fmt.Errorf("%s is truncated or has trailing data", path)
+	}
+	body := data[prefixSize : prefixSize+int(size)]
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(data[prefixSize+int(size):]) {
+		return nil, fmt.Errorf("%s failed its checksum", path)
+	}
+
+	if len(body) < 4 || uint64(binary.BigEndian.Uint32(body)) > uint64(len(body)-4) {
+		return nil, fmt.Errorf("%s has a malformed header", path)
+	}
+	headerSize := 4 + int(binary.BigEndian.Uint32(body))
+	file := &File{state: body[headerSize:]}
+	if err := gob.NewDecoder(bytes.NewReader(body[4:headerSize])).Decode(&file.Header); err != nil {
+		return nil, fmt.Errorf("failed to decode header of %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// Restore loads the saved state into task.
+func (f *File) Restore(task model.Checkpointer) error {
+	if err := task.Restore(bytes.NewReader(f.state)); err != nil {
+		return fmt.Errorf("failed to restore task %s: %w", f.Header.Task, err)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package checkpoint
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// bytesTask saves and restores a byte string.
+type bytesTask struct {
+	state []byte
+}
+
+func (b *bytesTask) Checkpoint(w io.Writer) error {
+	_, err := w.Write(b.state)
+	return err
+}
+
+func (b *bytesTask) Restore(r io.Reader) error {
+	state, err := io.ReadAll(r)
+	b.state = state
+	return err
+}
+
+func TestWriteLoadRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "exact", "per_src.ckpt")
+	header := Header{
+		Task:        "exact/per_src",
+		Definition:  "abc",
+		PeriodStart: time.Unix(1700000000, 0),
+		Period:      time.Minute,
+		SavedAt:     time.Unix(1700000030, 0),
+	}
+	if err := Write(path, header, &bytesTask{state: []byte("flows")}); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() unexpected error: %v", err)
+	}
+	if file.Header.Task != header.Task || file.Header.Definition != header.Definition || file.Header.Period != header.Period ||
+		!file.Header.PeriodStart.Equal(header.PeriodStart) || !file.Header.SavedAt.Equal(header.SavedAt) {
+		t.Fatalf("Load() header = %+v, want %+v", file.Header, header)
+	}
+	restored := &bytesTask{}
+	if err := file.Restore(restored); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	if string(restored.state) != "flows" {
+		t.Fatalf("restored state = %q, want %q", restored.state, "flows")
+	}
+	if _, err := os.Stat(path + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("temporary file left behind: %v", err)
+	}
+}
+
+func TestLoadRejectsDamagedFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "task.ckpt")
+	if err := Write(path, Header{Task: "exact/task"}, &bytesTask{state: []byte("flows")}); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() unexpected error: %v", err)
+	}
+
+	damage := func(name string, change func([]byte) []byte) {
+		damaged := change(append([]byte(nil), data...))
+		if err := os.WriteFile(path, damaged, 0644); err != nil {
+			t.Fatalf("WriteFile() unexpected error: %v", err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Fatalf("Load(%s) error = nil, want non-nil", name)
+		}
+	}
+	damage("flipped byte", func(b []byte) []byte { b[len(b)-6] ^= 0xff; return b })
+	damage("truncated", func(b []byte) []byte { return b[:len(b)-1] })
+	damage("bad magic", func(b []byte) []byte { b[0] = 'X'; return b })
+	damage("empty", func(b []byte) []byte { return nil })
+
+	data[len(magic)+1]++ // next format version
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+	if _, err := Load(path); !errors.Is(err, ErrVersion) {
+		t.Fatalf("Load(newer version) error = %v, want ErrVersion", err)
+	}
+
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.ckpt")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("Load(missing) error = %v, want os.ErrNotExist", err)
+	}
+}
+
+func TestFingerprintFollowsDefinition(t *testing.T) {
+	type def struct {
+		Name   string
+		Fields []string
+	}
+	a, err := Fingerprint(def{Name: "t", Fields: []string{"SrcIP"}})
+	if err != nil {
+		t.Fatalf("Fingerprint() unexpected error: %v", err)
+	}
+	same, _ := Fingerprint(def{Name: "t", Fields: []string{"SrcIP"}})
+	changed, _ := Fingerprint(def{Name: "t", Fields: []string{"DstIP"}})
+	if a != same || a == changed {
+		t.Fatalf("Fingerprint() = %s, same %s, changed %s; want equal for equal definitions only", a, same, changed)
+	}
+}
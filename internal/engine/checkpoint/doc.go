@@ -0,0 +1,2 @@
+// Package checkpoint stores task state in versioned, checksummed files so that a restarted engine can resume its current windows.
+package checkpoint
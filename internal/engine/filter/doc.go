@@ -0,0 +1,2 @@
+// Package filter compiles per-task packet filter expressions and restricts tasks to the packets they match.
+package filter
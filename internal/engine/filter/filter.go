@@ -0,0 +1,249 @@
+package filter
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// Filter reports whether a packet matches a compiled expression.
+type Filter func(packet *model.PacketInfo) bool
+
+// Compile parses a filter expression. It returns nil, which callers treat as
+// matching everything, for an empty expression.
+//
+// The grammar follows tcpdump's where they overlap:
+//
+//	expr      = and { ("or" | "||") and }
+//	and       = unary { ("and" | "&&") unary }
+//	unary     = ("not" | "!") unary | "(" expr ")" | primitive
+//	primitive = ["src" | "dst"] "port" port ["-" port]
+//	          | ["src" | "dst"] "net" cidr
+//	          | ["src" | "dst"] "host" ip
+//	          | ["proto"] ("tcp" | "udp" | "icmp" | "icmp6" | number)
+//
+// Without "src" or "dst", port, net and host match either end of the packet.
+func Compile(expr string) (Filter, error) {
+	tokens := tokenize(expr)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	p := &parser{tokens: tokens}
+	f, err := p.parseOr()
+	if err == nil && p.pos < len(p.tokens) {
+		err = fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter %q: %w", expr, err)
+	}
+	return f, nil
+}
+
+// tokenize splits expr on whitespace and around parentheses and "!".
+func tokenize(expr string) []string {
+	var b strings.Builder
+	for _, r := range expr {
+		switch r {
+		case '(', ')', '!':
+			b.WriteString(" " + string(r) + " ")
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Fields(strings.ToLower(b.String()))
+}
+
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+// next returns the current token and moves past it, or "" at the end.
+func (p *parser) next() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	p.pos++
+	return p.tokens[p.pos-1]
+}
+
+// accept moves past the current token if it is one of words.
+func (p *parser) accept(words ...string) bool {
+	if p.pos < len(p.tokens) {
+		for _, w := range words {
+			if p.tokens[p.pos] == w {
+				p.pos++
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (p *parser) parseOr() (Filter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("or", "||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(packet *model.PacketInfo) bool { return l(packet) || right(packet) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Filter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("and", "&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		left = func(packet *model.PacketInfo) bool { return l(packet) && right(packet) }
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Filter, error) {
+	if p.accept("not", "!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(packet *model.PacketInfo) bool { return !inner(packet) }, nil
+	}
+	if p.accept("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.accept(")") {
+			return nil, fmt.Errorf("missing )")
+		}
+		return inner, nil
+	}
+	return p.parsePrimitive()
+}
+
+// direction selects which end of the packet a primitive looks at.
+type direction int
+
+const (
+	either direction = iota
+	src
+	dst
+)
+
+func (p *parser) parsePrimitive() (Filter, error) {
+	dir := either
+	if p.accept("src") {
+		dir = src
+	} else if p.accept("dst") {
+		dir = dst
+	}
+
+	switch word := p.next(); word {
+	case "port":
+		return portFilter(dir, p.next())
+	case "net":
+		return netFilter(dir, p.next())
+	case "host":
+		return hostFilter(dir, p.next())
+	case "proto":
+		if dir != either {
+			return nil, fmt.Errorf("%q cannot follow src or dst", word)
+		}
+		return protoFilter(p.next())
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	default:
+		if dir != either {
+			return nil, fmt.Errorf("want port, net or host after src or dst, got %q", word)
+		}
+		return protoFilter(word)
+	}
+}
+
+var protocolNumbers = map[string]uint8{
+	"icmp":  1,
+	"tcp":   6,
+	"udp":   17,
+	"icmp6": 58,
+}
+
+func protoFilter(word string) (Filter, error) {
+	proto, ok := protocolNumbers[word]
+	if !ok {
+		n, err := strconv.ParseUint(word, 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("unknown protocol %q", word)
+		}
+		proto = uint8(n)
+	}
+	return func(packet *model.PacketInfo) bool { return packet.FiveTuple.Protocol == proto }, nil
+}
+
+func portFilter(dir direction, word string) (Filter, error) {
+	lowText, highText, isRange := strings.Cut(word, "-")
+	low, err := strconv.ParseUint(lowText, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port %q", word)
+	}
+	high := low
+	if isRange {
+		if high, err = strconv.ParseUint(highText, 10, 16); err != nil || high < low {
+			return nil, fmt.Errorf("invalid port range %q", word)
+		}
+	}
+	in := func(port uint16) bool { return uint64(port) >= low && uint64(port) <= high }
+	switch dir {
+	case src:
+		return func(packet *model.PacketInfo) bool { return in(packet.FiveTuple.SrcPort) }, nil
+	case dst:
+		return func(packet *model.PacketInfo) bool { return in(packet.FiveTuple.DstPort) }, nil
+	default:
+		return func(packet *model.PacketInfo) bool {
+			return in(packet.FiveTuple.SrcPort) || in(packet.FiveTuple.DstPort)
+		}, nil
+	}
+}
+
+func netFilter(dir direction, word string) (Filter, error) {
+	_, ipNet, err := net.ParseCIDR(word)
+	if err != nil {
+		return nil, fmt.Errorf("invalid net %q", word)
+	}
+	return addrFilter(dir, ipNet.Contains), nil
+}
+
+func hostFilter(dir direction, word string) (Filter, error) {
+	host := net.ParseIP(word)
+	if host == nil {
+		return nil, fmt.Errorf("invalid host %q", word)
+	}
+	return addrFilter(dir, host.Equal), nil
+}
+
+func addrFilter(dir direction, match func(net.IP) bool) Filter {
+	switch dir {
+	case src:
+		return func(packet *model.PacketInfo) bool { return match(packet.FiveTuple.SrcIP) }
+	case dst:
+		return func(packet *model.PacketInfo) bool { return match(packet.FiveTuple.DstIP) }
+	default:
+		return func(packet *model.PacketInfo) bool {
+			return match(packet.FiveTuple.SrcIP) || match(packet.FiveTuple.DstIP)
+		}
+	}
+}
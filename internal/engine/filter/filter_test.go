@@ -0,0 +1,140 @@
+package filter
+
+import (
+	"net"
+	"testing"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+func packet(srcIP, dstIP string, srcPort, dstPort uint16, proto uint8) *model.PacketInfo {
+	return &model.PacketInfo{FiveTuple: model.FiveTuple{
+		SrcIP:    net.ParseIP(srcIP),
+		DstIP:    net.ParseIP(dstIP),
+		SrcPort:  srcPort,
+		DstPort:  dstPort,
+		Protocol: proto,
+	}}
+}
+
+func TestCompileMatches(t *testing.T) {
+	web := packet("10.1.2.3", "192.0.2.10", 51000, 443, 6)
+	dns := packet("2001:db8::1", "2001:db8:1::53", 40000, 53, 17)
+
+	tests := []struct {
+		expr     string
+		web, dns bool
+	}{
+		{"tcp", true, false},
+		{"proto 17", false, true},
+		{"proto udp", false, true},
+		{"port 53", false, true},
+		{"dst port 80-443", true, false},
+		{"src port 80-443", false, false},
+		{"port 50000-52000", true, false},
+		{"src net 10.0.0.0/8", true, false},
+		{"dst net 10.0.0.0/8", false, false},
+		{"net 2001:db8::/32", false, true},
+		{"host 192.0.2.10", true, false},
+		{"dst host 2001:db8:1::53", false, true},
+		{"tcp and dst port 443", true, false},
+		{"tcp && dst port 53", false, false},
+		{"tcp or udp", true, true},
+		{"udp || port 443", true, true},
+		{"not tcp", false, true},
+		{"!tcp and !udp", false, false},
+		{"not (tcp or udp)", false, false},
+		{"tcp or udp and port 53", true, true},
+		{"(tcp or udp) and port 53", false, true},
+		{"TCP AND NOT SRC NET 192.168.0.0/16", true, false},
+	}
+	for _, tt := range tests {
+		match, err := Compile(tt.expr)
+		if err != nil {
+			t.Fatalf("Compile(%q) unexpected error: %v", tt.expr, err)
+		}
+		if got := match(web); got != tt.web {
+			t.Fatalf("Compile(%q)(web) = %v, want %v", tt.expr, got, tt.web)
+		}
+		if got := match(dns); got != tt.dns {
+			t.Fatalf("Compile(%q)(dns) = %v, want %v", tt.expr, got, tt.dns)
+		}
+	}
+}
+
+func TestCompileEmptyMatchesEverything(t *testing.T) {
+	match, err := Compile("  ")
+	if err != nil || match != nil {
+		t.Fatalf("Compile(blank) = %v, %v, want nil, nil", match != nil, err)
+	}
+}
+
+func TestCompileRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"gre",
+		"port",
+		"port http",
+		"port 443-80",
+		"port 70000",
+		"net 10.0.0.0",
+		"host example.com",
+		"src tcp",
+		"src proto tcp",
+		"tcp and",
+		"(tcp or udp",
+		"tcp udp",
+		"tcp )",
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Fatalf("Compile(%q) error = nil, want non-nil", expr)
+		}
+	}
+}
+
+// countTask counts the packets it is given, per worker once partitioned.
+type countTask struct {
+	packets   int
+	perWorker []int
+}
+
+func (c *countTask) ProcessPacket(packet *model.PacketInfo) { c.packets++ }
+func (c *countTask) Snapshot() interface{}                  { return c.packets }
+func (c *countTask) Reset()                                 { c.packets = 0 }
+func (c *countTask) Name() string                           { return "count" }
+func (c *countTask) Query(flow []byte) uint64               { return 0 }
+func (c *countTask) Fields() []string                       { return nil }
+func (c *countTask) DecodeFlowFunc() func(flow []byte, fields []string) string {
+	return func(flow []byte, fields []string) string { return "" }
+}
+func (c *countTask) AlerterMsg(rules []config.AlerterRule) string { return "" }
+func (c *countTask) Partition(workers int)                        { c.perWorker = make([]int, workers) }
+func (c *countTask) ProcessPacketOn(worker int, packet *model.PacketInfo) {
+	c.perWorker[worker]++
+}
+
+func TestWrapSkipsUnmatchedPackets(t *testing.T) {
+	inner := &countTask{}
+	if got := Wrap(nil, inner); got != model.Task(inner) {
+		t.Fatalf("Wrap(nil) = %v, want the task itself", got)
+	}
+
+	match, err := Compile("udp")
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+	task := Wrap(match, inner)
+	task.ProcessPacket(packet("10.0.0.1", "10.0.0.2", 1, 2, 6))
+	task.ProcessPacket(packet("10.0.0.1", "10.0.0.2", 1, 2, 17))
+	if got := task.Snapshot(); got != 1 {
+		t.Fatalf("Snapshot() = %v, want 1", got)
+	}
+
+	partitioned := task.(model.PartitionedTask)
+	partitioned.Partition(2)
+	partitioned.ProcessPacketOn(1, packet("10.0.0.1", "10.0.0.2", 1, 2, 17))
+	partitioned.ProcessPacketOn(1, packet("10.0.0.1", "10.0.0.2", 1, 2, 6))
+	if inner.perWorker[1] != 1 || inner.perWorker[0] != 0 {
+		t.Fatalf("per-worker packets = %v, want [0 1]", inner.perWorker)
+	}
+}
@@ -0,0 +1,84 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// Task passes a task only the packets its filter matches. Everything else is
+// the wrapped task's. It implements model.PartitionedTask,
+// model.Checkpointer and model.ExpiringTask.
+type Task struct {
+	model.Task
+	match Filter
+}
+
+// Wrap restricts task to the packets match accepts. A nil match returns task
+// unchanged.
+func Wrap(match Filter, task model.Task) model.Task {
+	if match == nil {
+		return task
+	}
+	return &Task{Task: task, match: match}
+}
+
+// ProcessPacket hands the packet to the wrapped task if it matches.
+func (t *Task) ProcessPacket(packet *model.PacketInfo) {
+	if t.match(packet) {
+		t.Task.ProcessPacket(packet)
+	}
+}
+
+// Partition partitions the wrapped task if it keeps state per worker.
+func (t *Task) Partition(workers int) {
+	if partitioned, ok := t.Task.(model.PartitionedTask); ok {
+		partitioned.Partition(workers)
+	}
+}
+
+// ProcessPacketOn hands a matching packet to the wrapped task from worker.
+func (t *Task) ProcessPacketOn(worker int, packet *model.PacketInfo) {
+	if !t.match(packet) {
+		return
+	}
+	if partitioned, ok := t.Task.(model.PartitionedTask); ok {
+		partitioned.ProcessPacketOn(worker, packet)
+		return
+	}
+	t.Task.ProcessPacket(packet)
+}
+
+// Checkpoint saves the wrapped task.
+func (t *Task) Checkpoint(w io.Writer) error {
+	checkpointer, ok := t.Task.(model.Checkpointer)
+	if !ok {
+		return fmt.Errorf("task %s does not support checkpoints", t.Name())
+	}
+	return checkpointer.Checkpoint(w)
+}
+
+// Restore loads the wrapped task.
+func (t *Task) Restore(r io.Reader) error {
+	checkpointer, ok := t.Task.(model.Checkpointer)
+	if !ok {
+		return fmt.Errorf("task %s does not support checkpoints", t.Name())
+	}
+	return checkpointer.Restore(r)
+}
+
+// ExpiresFlows reports whether the wrapped task expires its own flows.
+func (t *Task) ExpiresFlows() bool {
+	expiring, ok := t.Task.(model.ExpiringTask)
+	return ok && expiring.ExpiresFlows()
+}
+
+// ExpireFlows expires the flows of the wrapped task.
+func (t *Task) ExpireFlows(now time.Time) interface{} {
+	if expiring, ok := t.Task.(model.ExpiringTask); ok {
+		return expiring.ExpireFlows(now)
+	}
+	return nil
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"net"
+	"runtime"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"Go2NetSpectra/internal/config"
@@ -16,6 +19,7 @@ import (
 	"Go2NetSpectra/pkg/pcap"
 
 	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
 	gopcap "github.com/google/gopacket/pcap"
 )
 
@@ -24,6 +28,7 @@ var (
 	benchPacketOnce sync.Once
 	benchPacketInfo *model.PacketInfo
 	benchRawPacket  gopacket.Packet
+	benchLinkType   layers.LinkType
 	benchPacketErr  error
 )
 
@@ -38,6 +43,7 @@ func loadBenchmarkPacket(b *testing.B) (*model.PacketInfo, gopacket.Packet) {
 		}
 		defer handle.Close()
 
+		benchLinkType = handle.LinkType()
 		packetSource := gopacket.NewPacketSource(handle, handle.LinkType())
 		packet, ok := <-packetSource.Packets()
 		if !ok {
@@ -77,6 +83,59 @@ func BenchmarkProtocolParsePacketInto(b *testing.B) {
 	}
 }
 
+func BenchmarkProtocolDecoderDecodeInto(b *testing.B) {
+	_, rawPacket := loadBenchmarkPacket(b)
+	decoder, err := protocol.NewDecoder(benchLinkType)
+	if err != nil {
+		b.Fatalf("NewDecoder() unexpected error: %v", err)
+	}
+	data := rawPacket.Data()
+	ci := rawPacket.Metadata().CaptureInfo
+	var info model.PacketInfo
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := decoder.DecodeInto(data, ci, &info); err != nil {
+			b.Fatalf("DecodeInto() unexpected error: %v", err)
+		}
+	}
+}
+
+// BenchmarkProtocolDecodeFromBytes compares both parsers starting from the
+// raw frame, including the cost of building the gopacket.Packet.
+func BenchmarkProtocolDecodeFromBytes(b *testing.B) {
+	_, rawPacket := loadBenchmarkPacket(b)
+	data := rawPacket.Data()
+	ci := rawPacket.Metadata().CaptureInfo
+
+	b.Run("ParsePacketInto", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			packet := gopacket.NewPacket(data, benchLinkType, gopacket.Default)
+			packet.Metadata().CaptureInfo = ci
+			var info model.PacketInfo
+			if err := protocol.ParsePacketInto(packet, &info); err != nil {
+				b.Fatalf("ParsePacketInto() unexpected error: %v", err)
+			}
+		}
+	})
+
+	b.Run("DecoderDecodeInto", func(b *testing.B) {
+		decoder, err := protocol.NewDecoder(benchLinkType)
+		if err != nil {
+			b.Fatalf("NewDecoder() unexpected error: %v", err)
+		}
+		var info model.PacketInfo
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := decoder.DecodeInto(data, ci, &info); err != nil {
+				b.Fatalf("DecodeInto() unexpected error: %v", err)
+			}
+		}
+	})
+}
+
 func BenchmarkPacketCodecRoundTrip(b *testing.B) {
 	packetInfo, _ := loadBenchmarkPacket(b)
 	b.ReportAllocs()
@@ -93,11 +152,55 @@ func BenchmarkPacketCodecRoundTrip(b *testing.B) {
 	}
 }
 
+func BenchmarkPacketBatchCodecRoundTrip(b *testing.B) {
+	packetInfo, _ := loadBenchmarkPacket(b)
+	batch := make([]*model.PacketInfo, 256)
+	for i := range batch {
+		batch[i] = packetInfo
+	}
+
+	for _, encoding := range []probe.Encoding{probe.EncodingNone, probe.EncodingZstd, probe.EncodingLZ4} {
+		b.Run(encoding.String(), func(b *testing.B) {
+			b.ReportAllocs()
+			var buffer []byte
+			for i := 0; i < b.N; i++ {
+				data, err := probe.MarshalPacketBatch(buffer, batch, encoding)
+				if err != nil {
+					b.Fatalf("MarshalPacketBatch() unexpected error: %v", err)
+				}
+				buffer = data[:0]
+				if _, err := probe.UnmarshalPackets(data); err != nil {
+					b.Fatalf("UnmarshalPackets() unexpected error: %v", err)
+				}
+			}
+			b.ReportMetric(float64(len(batch)*b.N)/b.Elapsed().Seconds(), "packets/s")
+		})
+	}
+}
+
+func mustNewExact(b *testing.B, name string, keyFields []string, numShards uint32) model.Task {
+	b.Helper()
+	task, err := exact.New(name, keyFields, numShards)
+	if err != nil {
+		b.Fatalf("exact.New() unexpected error: %v", err)
+	}
+	return task
+}
+
+func mustNewSketch(b *testing.B, cfg config.SketchTaskDef) model.Task {
+	b.Helper()
+	task, err := sketch.New(cfg)
+	if err != nil {
+		b.Fatalf("sketch.New() unexpected error: %v", err)
+	}
+	return task
+}
+
 func BenchmarkExactTaskProcessPacket(b *testing.B) {
 	packetInfo, _ := loadBenchmarkPacket(b)
 	restoreLogs := muteBenchmarkLogs()
 	defer restoreLogs()
-	task := exact.New("exact-srcip", []string{"SrcIP"}, 64)
+	task := mustNewExact(b, "exact-srcip", []string{"SrcIP"}, 64)
 	b.ReportAllocs()
 	b.ResetTimer()
 
@@ -113,7 +216,7 @@ func BenchmarkCountMinTaskProcessPacket(b *testing.B) {
 	packetInfo, _ := loadBenchmarkPacket(b)
 	restoreLogs := muteBenchmarkLogs()
 	defer restoreLogs()
-	task := sketch.New(config.SketchTaskDef{
+	task := mustNewSketch(b, config.SketchTaskDef{
 		Name:           "bench-count-min",
 		SketchType:     0,
 		FlowFields:     []string{"SrcIP"},
@@ -138,7 +241,7 @@ func BenchmarkSuperSpreadTaskProcessPacket(b *testing.B) {
 	packetInfo, _ := loadBenchmarkPacket(b)
 	restoreLogs := muteBenchmarkLogs()
 	defer restoreLogs()
-	task := sketch.New(config.SketchTaskDef{
+	task := mustNewSketch(b, config.SketchTaskDef{
 		Name:           "bench-super-spread",
 		SketchType:     1,
 		FlowFields:     []string{"DstIP"},
@@ -207,7 +310,7 @@ func runSSParallel(b *testing.B) {
 		B:              1.08,
 	}
 
-	task := sketch.New(cfg)
+	task := mustNewSketch(b, cfg)
 
 	b.Run("Insert_SS_Parallel", func(b *testing.B) {
 		b.ResetTimer()
@@ -244,7 +347,7 @@ func runCmParallel(b *testing.B) {
 		CountThreshold: 4096,
 	}
 
-	task := sketch.New(cfg)
+	task := mustNewSketch(b, cfg)
 
 	b.Run("Insert_Sketch_Parallel", func(b *testing.B) {
 		b.ResetTimer()
@@ -270,7 +373,7 @@ func runCmParallel(b *testing.B) {
 }
 
 func runExactParallel(b *testing.B) {
-	task := exact.New("exact_per_src", []string{"SrcIP"}, 64)
+	task := mustNewExact(b, "exact_per_src", []string{"SrcIP"}, 64)
 
 	b.Run("Insert_Exact_Parallel", func(b *testing.B) {
 		b.ResetTimer()
@@ -342,7 +445,7 @@ func runCm(b *testing.B) {
 		CountThreshold: 4096,
 	}
 
-	task := sketch.New(cfg)
+	task := mustNewSketch(b, cfg)
 
 	b.Run("Insert_Sketch", func(b *testing.B) {
 		b.ResetTimer()
@@ -379,7 +482,7 @@ func runSs(b *testing.B) {
 		B:              1.08,
 	}
 
-	task := sketch.New(cfg)
+	task := mustNewSketch(b, cfg)
 
 	b.Run("Insert_SS", func(b *testing.B) {
 		b.ResetTimer()
@@ -401,7 +504,7 @@ func runSs(b *testing.B) {
 }
 
 func runExact(b *testing.B) {
-	task := exact.New("exact_per_src", []string{"SrcIP"}, 64)
+	task := mustNewExact(b, "exact_per_src", []string{"SrcIP"}, 64)
 
 	b.Run("Insert_Exact", func(b *testing.B) {
 		b.ResetTimer()
@@ -421,3 +524,63 @@ func runExact(b *testing.B) {
 		}
 	})
 }
+
+// BenchmarkExactTaskDispatch compares every worker updating the shared,
+// locked shards with flow dispatch, where each worker updates its own
+// partition. Run with -cpu to see how each scales.
+func BenchmarkExactTaskDispatch(b *testing.B) {
+	restoreLogs := muteBenchmarkLogs()
+	defer restoreLogs()
+
+	flows := make([]*model.PacketInfo, 4096)
+	for i := range flows {
+		flows[i] = &model.PacketInfo{
+			Length: 512,
+			FiveTuple: model.FiveTuple{
+				SrcIP:    net.IPv4(10, 0, byte(i>>8), byte(i)),
+				DstIP:    net.IPv4(192, 0, 2, byte(i%16)),
+				SrcPort:  uint16(1024 + i),
+				DstPort:  443,
+				Protocol: 6,
+			},
+		}
+	}
+	keyFields := []string{"SrcIP", "DstIP", "SrcPort", "DstPort", "Protocol"}
+
+	b.Run("Shared", func(b *testing.B) {
+		task := mustNewExact(b, "dispatch-shared", keyFields, 64)
+		var next atomic.Uint64
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			i := next.Add(1) * 7919
+			for pb.Next() {
+				task.ProcessPacket(flows[i%uint64(len(flows))])
+				i++
+			}
+		})
+	})
+
+	b.Run("FlowAffine", func(b *testing.B) {
+		workers := runtime.GOMAXPROCS(0)
+		task := mustNewExact(b, "dispatch-flow", keyFields, 64).(model.PartitionedTask)
+		task.Partition(workers)
+		owned := make([][]*model.PacketInfo, workers)
+		for _, packet := range flows {
+			w := packet.FiveTuple.SymmetricHash(0) % uint64(workers)
+			owned[w] = append(owned[w], packet)
+		}
+		var next atomic.Int64
+		b.ReportAllocs()
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			worker := int(next.Add(1)-1) % workers
+			packets := owned[worker]
+			i := 0
+			for pb.Next() {
+				task.ProcessPacketOn(worker, packets[i%len(packets)])
+				i++
+			}
+		})
+	})
+}
@@ -0,0 +1,271 @@
+package exact
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/metrics"
+	"Go2NetSpectra/internal/model"
+)
+
+// Policies for the new flows of a task past its max_flows.
+const (
+	overflowEvict  = "evict"
+	overflowRefuse = "refuse"
+	overflowOther  = "other"
+)
+
+// otherFlowKey keys the catch-all flow of the "other" policy. No packet
+// generates it, as keys are made of addresses and numbers.
+const otherFlowKey = "other"
+
+// evictionSamples is how many flows of a shard are compared to pick the one
+// to evict. Taking the least recently seen of a few flows approximates LRU
+// without keeping every shard in recency order.
+const evictionSamples = 8
+
+var (
+	overflowPackets = metrics.NewCounter(metrics.Namespace+"_engine_exact_overflow_packets_total",
+		"Packets of exact tasks not counted in a flow of their own because of the flow budget, by task and policy.", "task", "policy")
+	overflowBytes = metrics.NewCounter(metrics.Namespace+"_engine_exact_overflow_bytes_total",
+		"Bytes of exact tasks not counted in a flow of their own because of the flow budget, by task and policy.", "task", "policy")
+	evictedFlows = metrics.NewCounter(metrics.Namespace+"_engine_exact_evicted_flows_total",
+		"Flows of exact tasks evicted to stay within the flow budget, by task.", "task")
+)
+
+// flowLimit is the flow budget of a task definition.
+type flowLimit struct {
+	maxFlows int64
+	policy   string
+}
+
+// parseFlowLimit returns the flow budget of def; a zero maxFlows means none.
+func parseFlowLimit(def config.ExactTaskDef) (flowLimit, error) {
+	if def.MaxFlows < 0 {
+		return flowLimit{}, fmt.Errorf("max_flows must not be negative")
+	}
+	if def.MaxFlows == 0 {
+		if def.Overflow != "" {
+			return flowLimit{}, fmt.Errorf("overflow requires max_flows")
+		}
+		return flowLimit{}, nil
+	}
+	limit := flowLimit{maxFlows: def.MaxFlows, policy: def.Overflow}
+	switch limit.policy {
+	case "":
+		limit.policy = overflowEvict
+	case overflowEvict, overflowRefuse, overflowOther:
+	default:
+		return flowLimit{}, fmt.Errorf("unknown overflow policy %q", limit.policy)
+	}
+	// Evicted flows are written by the sweeper, which does not reach the
+	// panes of a window.
+	if limit.policy == overflowEvict && def.Window.Size != "" {
+		return flowLimit{}, fmt.Errorf("overflow %q cannot be combined with a window", overflowEvict)
+	}
+	return limit, nil
+}
+
+// budget keeps a task within its flow limit and accounts for what that costs.
+type budget struct {
+	flowLimit
+	flows atomic.Int64
+
+	// keys counts the partitions holding each key when a key can be split
+	// over several workers, so that it takes one flow of the budget however
+	// many hold it; see countKeys. It is nil otherwise, and every flow is
+	// counted on flows alone. keysMu is taken after the lock of a shard,
+	// never before.
+	keysMu sync.Mutex
+	keys   map[string]int
+
+	evicted atomic.Uint64
+	packets atomic.Uint64
+	bytes   atomic.Uint64
+
+	// pending holds evicted flows until ExpireFlows hands them to the
+	// writers. It is capped at maxFlows so eviction cannot grow memory either.
+	mu      sync.Mutex
+	pending []*statistic.Flow
+
+	packetsTotal *metrics.Series
+	bytesTotal   *metrics.Series
+	evictedTotal *metrics.Series
+}
+
+func newBudget(task string, limit flowLimit) *budget {
+	return &budget{
+		flowLimit:    limit,
+		packetsTotal: overflowPackets.With(task, limit.policy),
+		bytesTotal:   overflowBytes.With(task, limit.policy),
+		evictedTotal: evictedFlows.With(task),
+	}
+}
+
+// countKeys makes the budget count flows by key across partitions. It must
+// be called before packets are processed.
+func (b *budget) countKeys() {
+	b.keys = make(map[string]int)
+}
+
+// admit reports whether a new flow for key may be added to shard, evicting
+// one of its flows to make room under the "evict" policy. The caller holds
+// shard.Mu.
+func (b *budget) admit(shard *statistic.Shard, key string) bool {
+	if b.keys != nil {
+		return b.admitKey(shard, key)
+	}
+	if b.flows.Add(1) <= b.maxFlows {
+		return true
+	}
+	if b.policy == overflowEvict {
+		// A shard with nothing to evict takes the flow over budget; it
+		// has one to give up next time.
+		b.evict(shard)
+		return true
+	}
+	b.flows.Add(-1)
+	return false
+}
+
+// admitKey is admit for a budget that counts keys. A key another partition
+// holds is always admitted.
+func (b *budget) admitKey(shard *statistic.Shard, key string) bool {
+	b.keysMu.Lock()
+	defer b.keysMu.Unlock()
+	if b.keys[key] == 0 && b.flows.Load() >= b.maxFlows {
+		if b.policy != overflowEvict {
+			return false
+		}
+		// The victim may still be held by other partitions, in which case
+		// the flow goes over budget like one of a shard with nothing to evict.
+		b.evict(shard)
+	}
+	b.holdLocked(key)
+	return true
+}
+
+// hold counts a flow for key added to a partition outside admit.
+func (b *budget) hold(key string) {
+	if b.keys == nil {
+		b.flows.Add(1)
+		return
+	}
+	b.keysMu.Lock()
+	b.holdLocked(key)
+	b.keysMu.Unlock()
+}
+
+func (b *budget) holdLocked(key string) {
+	if b.keys[key] == 0 {
+		b.flows.Add(1)
+	}
+	b.keys[key]++
+}
+
+// evict removes the least recently seen of a sample of shard's flows and
+// queues it for the writers. The caller holds shard.Mu, and b.keysMu when
+// the budget counts keys.
+func (b *budget) evict(shard *statistic.Shard) {
+	var victim *statistic.Flow
+	sampled := 0
+	for _, flow := range shard.Flows {
+		if victim == nil || flow.EndTime.Before(victim.EndTime) {
+			victim = flow
+		}
+		if sampled++; sampled == evictionSamples {
+			break
+		}
+	}
+	if victim == nil {
+		return
+	}
+	delete(shard.Flows, victim.Key)
+	if b.keys != nil {
+		b.releaseLocked(victim.Key)
+	} else {
+		b.flows.Add(-1)
+	}
+	b.evicted.Add(1)
+	b.evictedTotal.Inc()
+
+	victim.EndReason = statistic.EndReasonEvicted
+	b.mu.Lock()
+	if int64(len(b.pending)) < b.maxFlows {
+		b.pending = append(b.pending, victim)
+		victim = nil
+	}
+	b.mu.Unlock()
+	if victim != nil {
+		b.count(victim.PacketCount, victim.ByteCount)
+	}
+}
+
+// release accounts for the flow for key removed from a partition. It does
+// nothing on a task without a budget.
+func (b *budget) release(key string) {
+	if b == nil {
+		return
+	}
+	if b.keys == nil {
+		b.flows.Add(-1)
+		return
+	}
+	b.keysMu.Lock()
+	b.releaseLocked(key)
+	b.keysMu.Unlock()
+}
+
+func (b *budget) releaseLocked(key string) {
+	if b.keys[key]--; b.keys[key] <= 0 {
+		delete(b.keys, key)
+		b.flows.Add(-1)
+	}
+}
+
+// refuse accounts for a packet turned away from a new flow.
+func (b *budget) refuse(packetInfo *model.PacketInfo) {
+	b.count(packetInfo.PacketCount(), packetInfo.ByteCount())
+}
+
+func (b *budget) count(packets, bytes uint64) {
+	b.packets.Add(packets)
+	b.bytes.Add(bytes)
+	b.packetsTotal.Add(float64(packets))
+	b.bytesTotal.Add(float64(bytes))
+}
+
+// drain returns the evicted flows waiting to be written.
+func (b *budget) drain() []*statistic.Flow {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending := b.pending
+	b.pending = nil
+	return pending
+}
+
+// overflow reports the budget's counters since the last reset.
+func (b *budget) overflow() *statistic.Overflow {
+	return &statistic.Overflow{
+		Policy:       b.policy,
+		MaxFlows:     b.maxFlows,
+		Flows:        b.flows.Load(),
+		EvictedFlows: b.evicted.Load(),
+		Packets:      b.packets.Load(),
+		Bytes:        b.bytes.Load(),
+	}
+}
+
+// reset zeroes the budget for a task whose flows were all cleared.
+func (b *budget) reset() {
+	b.keysMu.Lock()
+	clear(b.keys)
+	b.flows.Store(0)
+	b.keysMu.Unlock()
+	b.evicted.Store(0)
+	b.packets.Store(0)
+	b.bytes.Store(0)
+}
@@ -0,0 +1,62 @@
+package exact
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"slices"
+
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+)
+
+// taskCheckpoint is the state an exact task saves. Shards are not saved:
+// their hash seed is per process, so restored flows are hashed again.
+type taskCheckpoint struct {
+	KeyFields []string
+	Flows     []*statistic.Flow
+}
+
+// Checkpoint writes every flow of the task, with worker partitions merged.
+func (t *Task) Checkpoint(w io.Writer) error {
+	snapshot, ok := t.Snapshot().(statistic.SnapshotData)
+	if !ok {
+		return fmt.Errorf("unexpected snapshot type %T", t.Snapshot())
+	}
+	state := taskCheckpoint{KeyFields: t.keyFields}
+	for _, shard := range snapshot.Shards {
+		for _, flow := range shard.Flows {
+			state.Flows = append(state.Flows, flow)
+		}
+	}
+	return gob.NewEncoder(w).Encode(state)
+}
+
+// Restore adds the flows of a checkpoint to the shared shards, merging them
+// with any flow already counted under the same key.
+func (t *Task) Restore(r io.Reader) error {
+	var state taskCheckpoint
+	if err := gob.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode exact task state: %w", err)
+	}
+	if !slices.Equal(state.KeyFields, t.keyFields) {
+		return fmt.Errorf("checkpoint is keyed on %v, task on %v", state.KeyFields, t.keyFields)
+	}
+	for _, flow := range state.Flows {
+		// Checkpoints of older releases carry no record IDs.
+		if flow.RecordID == 0 {
+			flow.RecordID = statistic.NewRecordID()
+		}
+		shard := t.getShard(flow.Key)
+		shard.Mu.Lock()
+		if existing, ok := shard.Flows[flow.Key]; ok {
+			existing.Merge(flow)
+		} else {
+			shard.Flows[flow.Key] = flow
+			if t.budget != nil {
+				t.budget.hold(flow.Key)
+			}
+		}
+		shard.Mu.Unlock()
+	}
+	return nil
+}
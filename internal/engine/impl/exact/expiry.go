@@ -0,0 +1,198 @@
+package exact
+
+import (
+	"fmt"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/model"
+)
+
+// Defaults for the timeout a task leaves unset, as on most NetFlow exporters.
+const (
+	defaultIdleTimeout   = 15 * time.Second
+	defaultActiveTimeout = 30 * time.Minute
+	// closedFlowLinger keeps a TCP flow closed by FIN or RST open a little
+	// longer, for the last packets of the close.
+	closedFlowLinger = time.Second
+)
+
+// parseTimeouts returns the flow timeouts of def, and false when it sets
+// neither, in which case its flows last until the period resets them.
+func parseTimeouts(def config.ExactTaskDef) (statistic.Timeouts, bool, error) {
+	if def.IdleTimeout == "" && def.ActiveTimeout == "" {
+		return statistic.Timeouts{}, false, nil
+	}
+	if def.Window.Size != "" {
+		return statistic.Timeouts{}, false, fmt.Errorf("idle_timeout and active_timeout cannot be combined with a window")
+	}
+	timeouts := statistic.Timeouts{Idle: defaultIdleTimeout, Active: defaultActiveTimeout}
+	for _, timeout := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{{"idle_timeout", def.IdleTimeout, &timeouts.Idle}, {"active_timeout", def.ActiveTimeout, &timeouts.Active}} {
+		if timeout.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(timeout.value)
+		if err != nil {
+			return statistic.Timeouts{}, false, fmt.Errorf("invalid %s: %w", timeout.name, err)
+		}
+		if d <= 0 {
+			return statistic.Timeouts{}, false, fmt.Errorf("%s must be a positive duration", timeout.name)
+		}
+		*timeout.dst = d
+	}
+	timeouts.Closed = min(closedFlowLinger, timeouts.Idle)
+	return timeouts, true, nil
+}
+
+// NewExpiring creates an exact task whose flows end on the given timeouts
+// instead of at period resets.
+func NewExpiring(name string, keyFields []string, numShards uint32, timeouts statistic.Timeouts) (model.Task, error) {
+	task, err := New(name, keyFields, numShards)
+	if err != nil {
+		return nil, err
+	}
+	task.(*Task).expireOn(timeouts)
+	return task, nil
+}
+
+// expireOn makes the flows of the task end on timeouts.
+func (t *Task) expireOn(timeouts statistic.Timeouts) {
+	t.timeouts = timeouts
+	t.expires = true
+}
+
+// ExpiresFlows reports whether the task was created with flow timeouts.
+func (t *Task) ExpiresFlows() bool {
+	return t.expires
+}
+
+// ExpireFlows removes the flows that have ended by now and returns them, with
+// the flows evicted by the budget since the last call, each with its
+// EndReason, as an expired statistic.SnapshotData. It returns nil when there
+// are none.
+func (t *Task) ExpireFlows(now time.Time) interface{} {
+	var shards []*statistic.Shard
+	if t.expires {
+		if len(t.local) == 0 {
+			shards = t.expireShared(now)
+		} else {
+			shards = t.expirePartitioned(now)
+		}
+	}
+	if t.budget != nil {
+		shards = t.addEvicted(shards, t.budget.drain())
+	}
+	if shards == nil {
+		return nil
+	}
+	return statistic.SnapshotData{TaskName: t.name, Shards: shards, Expired: true}
+}
+
+// expireShared removes the ended flows from the shared shards in one pass.
+func (t *Task) expireShared(now time.Time) []*statistic.Shard {
+	var out []*statistic.Shard
+	for i, shard := range t.shards {
+		shard.Mu.Lock()
+		for key, flow := range shard.Flows {
+			reason := t.timeouts.Expiry(flow, now)
+			if reason == statistic.EndReasonNone {
+				continue
+			}
+			delete(shard.Flows, key)
+			t.budget.release(key)
+			flow.EndReason = reason
+			if out == nil {
+				out = t.emptyShards()
+			}
+			out[i].Flows[key] = flow
+		}
+		shard.Mu.Unlock()
+	}
+	return out
+}
+
+// expirePartitioned removes the ended flows under flow dispatch, where a key
+// can be split over the shared shards and several workers. A key ends when
+// its merged flow does, and is then removed from every partition.
+func (t *Task) expirePartitioned(now time.Time) []*statistic.Shard {
+	partitions := append([]*statistic.Shard(nil), t.shards...)
+	for _, local := range t.local {
+		partitions = append(partitions, &local.Shard)
+	}
+
+	merged := make(map[string]*statistic.Flow)
+	for _, p := range partitions {
+		p.Mu.RLock()
+		for key, flow := range p.Flows {
+			if m, ok := merged[key]; ok {
+				m.Merge(flow)
+				continue
+			}
+			flowCopy := *flow
+			merged[key] = &flowCopy
+		}
+		p.Mu.RUnlock()
+	}
+	ended := make(map[string]statistic.EndReason)
+	for key, flow := range merged {
+		if reason := t.timeouts.Expiry(flow, now); reason != statistic.EndReasonNone {
+			ended[key] = reason
+		}
+	}
+	if len(ended) == 0 {
+		return nil
+	}
+
+	// Whatever arrived since the scan is removed and exported with the rest.
+	out := t.emptyShards()
+	for _, p := range partitions {
+		p.Mu.Lock()
+		for key, reason := range ended {
+			flow, ok := p.Flows[key]
+			if !ok {
+				continue
+			}
+			delete(p.Flows, key)
+			t.budget.release(key)
+			target := out[t.shardIndex(key)].Flows
+			if m, ok := target[key]; ok {
+				m.Merge(flow)
+				continue
+			}
+			flow.EndReason = reason
+			target[key] = flow
+		}
+		p.Mu.Unlock()
+	}
+	return out
+}
+
+// addEvicted adds evicted flows to the expired shards. A key evicted again,
+// or expired too, since the last sweep is written as one merged record.
+func (t *Task) addEvicted(shards []*statistic.Shard, evicted []*statistic.Flow) []*statistic.Shard {
+	for _, flow := range evicted {
+		if shards == nil {
+			shards = t.emptyShards()
+		}
+		target := shards[t.shardIndex(flow.Key)].Flows
+		if m, ok := target[flow.Key]; ok {
+			m.Merge(flow)
+			continue
+		}
+		target[flow.Key] = flow
+	}
+	return shards
+}
+
+func (t *Task) emptyShards() []*statistic.Shard {
+	shards := make([]*statistic.Shard, t.shardCount)
+	for i := range shards {
+		shards[i] = &statistic.Shard{Flows: make(map[string]*statistic.Flow)}
+	}
+	return shards
+}
@@ -0,0 +1,61 @@
+package statistic
+
+import "time"
+
+// EndReason records why a flow's record was closed, as the IPFIX
+// flowEndReason element does.
+type EndReason uint8
+
+const (
+	// EndReasonNone marks a flow still in progress.
+	EndReasonNone EndReason = iota
+	// EndReasonIdleTimeout means no packet arrived for the idle timeout.
+	EndReasonIdleTimeout
+	// EndReasonActiveTimeout means the flow lasted the active timeout and
+	// its later packets start a new record.
+	EndReasonActiveTimeout
+	// EndReasonEndOfFlow means the TCP connection was closed by FIN or RST.
+	EndReasonEndOfFlow
+	// EndReasonEvicted means the flow was evicted to keep its task within
+	// its flow budget; later packets start a new record.
+	EndReasonEvicted
+)
+
+var endReasonNames = [...]string{
+	EndReasonNone:          "",
+	EndReasonIdleTimeout:   "idle_timeout",
+	EndReasonActiveTimeout: "active_timeout",
+	EndReasonEndOfFlow:     "end_of_flow",
+	EndReasonEvicted:       "evicted",
+}
+
+// String returns the name stored in ClickHouse; flows in progress have none.
+func (r EndReason) String() string {
+	if int(r) < len(endReasonNames) {
+		return endReasonNames[r]
+	}
+	return "unknown"
+}
+
+// Timeouts are the NetFlow-style expiry limits of a flow.
+type Timeouts struct {
+	Idle   time.Duration // since the last packet
+	Active time.Duration // since the first packet
+	// Closed is how long a TCP connection closed by FIN or RST stays open,
+	// so the final ACKs of the close join its record.
+	Closed time.Duration
+}
+
+// Expiry returns why f has ended by now, or EndReasonNone while it has not.
+func (t Timeouts) Expiry(f *Flow, now time.Time) EndReason {
+	idle := now.Sub(f.EndTime)
+	switch {
+	case (f.ConnState == ConnStateClosedFIN || f.ConnState == ConnStateReset) && idle >= t.Closed:
+		return EndReasonEndOfFlow
+	case idle >= t.Idle:
+		return EndReasonIdleTimeout
+	case now.Sub(f.StartTime) >= t.Active:
+		return EndReasonActiveTimeout
+	}
+	return EndReasonNone
+}
@@ -2,17 +2,71 @@ package statistic
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// recordIDs hands out flow record IDs. It starts from the process start time
+// in nanoseconds, so a restarted engine does not reuse the IDs of its last run.
+var recordIDs atomic.Uint64
+
+func init() {
+	recordIDs.Store(uint64(time.Now().UnixNano()))
+}
+
+// NewRecordID returns the ID of a new flow record, unique within the engine
+// and larger than those of the records started before it.
+func NewRecordID() uint64 {
+	return recordIDs.Add(1)
+}
+
 // Flow represents an aggregated flow of traffic with exact metrics.
 type Flow struct {
+	// RecordID tells the records of one key apart. It is assigned when the
+	// flow is created and stays the same in every snapshot of the record.
+	RecordID    uint64
 	Key         string
 	Fields      map[string]interface{} // Holds the actual values for the fields that make up the key.
 	StartTime   time.Time
 	EndTime     time.Time
 	ByteCount   uint64
 	PacketCount uint64
+	SampleRate  uint32 // Largest probe sampling rate among the packets counted; 1 when exact.
+
+	// TCP accounting; left at zero for flows that never carried TCP.
+	TCPFlags  uint8 // Union of all flags seen on the flow.
+	SYNCount  uint64
+	FINCount  uint64
+	RSTCount  uint64
+	ACKCount  uint64
+	ConnState ConnState
+
+	// EndReason is set on flows that expired, in the snapshots that export them.
+	EndReason EndReason
+}
+
+// Merge adds the counts of other, the same key seen by another worker, to f.
+// The connection state of whichever flow saw the later packet wins, and the
+// record keeps the ID of whichever was created first.
+func (f *Flow) Merge(other *Flow) {
+	if other.RecordID != 0 && (f.RecordID == 0 || other.RecordID < f.RecordID) {
+		f.RecordID = other.RecordID
+	}
+	if other.StartTime.Before(f.StartTime) {
+		f.StartTime = other.StartTime
+	}
+	if other.EndTime.After(f.EndTime) {
+		f.EndTime = other.EndTime
+		f.ConnState = other.ConnState
+	}
+	f.ByteCount += other.ByteCount
+	f.PacketCount += other.PacketCount
+	f.SampleRate = max(f.SampleRate, other.SampleRate)
+	f.TCPFlags |= other.TCPFlags
+	f.SYNCount += other.SYNCount
+	f.FINCount += other.FINCount
+	f.RSTCount += other.RSTCount
+	f.ACKCount += other.ACKCount
 }
 
 // Shard is a part of a sharded map, containing its own map and a mutex.
@@ -26,4 +80,23 @@ type Shard struct {
 type SnapshotData struct {
 	TaskName string
 	Shards   []*Shard
+	// Expired is set when the shards hold flows that ended, each with its
+	// EndReason, instead of the flows in progress.
+	Expired bool
+	// Overflow accounts for the traffic past the task's flow budget since the
+	// last reset; nil when the task has no budget or the flows expired.
+	Overflow *Overflow
+}
+
+// Overflow tells how far a task with a flow budget is from exact counts.
+type Overflow struct {
+	Policy   string `json:"policy"`
+	MaxFlows int64  `json:"max_flows"`
+	Flows    int64  `json:"flows"` // held when the snapshot was taken
+	// EvictedFlows were written early to make room for new ones.
+	EvictedFlows uint64 `json:"evicted_flows"`
+	// Packets and Bytes were not counted in a flow of their own: refused,
+	// folded into the "other" flow, or in evicted flows dropped unwritten.
+	Packets uint64 `json:"packets"`
+	Bytes   uint64 `json:"bytes"`
 }
@@ -0,0 +1,87 @@
+package statistic
+
+import "Go2NetSpectra/internal/model"
+
+// ConnState is the connection state inferred from the TCP flags observed on a flow.
+type ConnState uint8
+
+const (
+	// ConnStateNone means no TCP packet has been observed.
+	ConnStateNone ConnState = iota
+	// ConnStateSynSent means a SYN was seen without any reply or follow-up yet (half-open).
+	ConnStateSynSent
+	// ConnStateHandshake means a SYN-ACK was seen.
+	ConnStateHandshake
+	// ConnStateEstablished means ACK traffic was seen after the opening or mid-stream.
+	ConnStateEstablished
+	// ConnStateClosedFIN means the connection was closed with a FIN.
+	ConnStateClosedFIN
+	// ConnStateReset means the connection was torn down with a RST.
+	ConnStateReset
+)
+
+var connStateNames = [...]string{
+	ConnStateNone:        "none",
+	ConnStateSynSent:     "syn_sent",
+	ConnStateHandshake:   "handshake",
+	ConnStateEstablished: "established",
+	ConnStateClosedFIN:   "closed_fin",
+	ConnStateReset:       "reset",
+}
+
+// String returns the name stored in ClickHouse and accepted by query filters.
+func (s ConnState) String() string {
+	if int(s) < len(connStateNames) {
+		return connStateNames[s]
+	}
+	return "unknown"
+}
+
+// ObserveTCPFlags updates the flag counters and the inferred connection state
+// with one TCP packet that stands for weight packets of sampled traffic.
+func (f *Flow) ObserveTCPFlags(flags uint8, weight uint64) {
+	f.TCPFlags |= flags
+	if flags&model.TCPFlagSYN != 0 {
+		f.SYNCount += weight
+	}
+	if flags&model.TCPFlagFIN != 0 {
+		f.FINCount += weight
+	}
+	if flags&model.TCPFlagRST != 0 {
+		f.RSTCount += weight
+	}
+	if flags&model.TCPFlagACK != 0 {
+		f.ACKCount += weight
+	}
+	f.ConnState = nextConnState(f.ConnState, flags)
+}
+
+// nextConnState applies a single packet's flags to the current state.
+// RST and FIN are terminal until a fresh SYN reopens the flow.
+func nextConnState(state ConnState, flags uint8) ConnState {
+	syn := flags&model.TCPFlagSYN != 0
+	ack := flags&model.TCPFlagACK != 0
+
+	switch {
+	case flags&model.TCPFlagRST != 0:
+		return ConnStateReset
+	case flags&model.TCPFlagFIN != 0:
+		if state == ConnStateReset {
+			return state
+		}
+		return ConnStateClosedFIN
+	case syn && ack:
+		if state == ConnStateNone || state == ConnStateSynSent {
+			return ConnStateHandshake
+		}
+	case syn:
+		if state != ConnStateHandshake && state != ConnStateEstablished {
+			return ConnStateSynSent
+		}
+	case ack:
+		if state != ConnStateClosedFIN && state != ConnStateReset {
+			return ConnStateEstablished
+		}
+	}
+	return state
+}
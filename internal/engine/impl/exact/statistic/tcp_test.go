@@ -0,0 +1,74 @@
+package statistic
+
+import (
+	"testing"
+
+	"Go2NetSpectra/internal/model"
+)
+
+func TestObserveTCPFlagsInfersConnState(t *testing.T) {
+	const (
+		syn    = model.TCPFlagSYN
+		synAck = model.TCPFlagSYN | model.TCPFlagACK
+		ack    = model.TCPFlagACK
+		finAck = model.TCPFlagFIN | model.TCPFlagACK
+		rst    = model.TCPFlagRST
+	)
+
+	tests := []struct {
+		name  string
+		flags []uint8
+		want  ConnState
+	}{
+		{name: "half open scan", flags: []uint8{syn, syn}, want: ConnStateSynSent},
+		{name: "syn ack only", flags: []uint8{synAck}, want: ConnStateHandshake},
+		{name: "full handshake", flags: []uint8{syn, synAck, ack}, want: ConnStateEstablished},
+		{name: "mid stream", flags: []uint8{ack, ack}, want: ConnStateEstablished},
+		{name: "graceful close", flags: []uint8{syn, synAck, ack, finAck, ack}, want: ConnStateClosedFIN},
+		{name: "reset after fin", flags: []uint8{syn, ack, finAck, rst}, want: ConnStateReset},
+		{name: "scan answered by reset", flags: []uint8{syn, rst}, want: ConnStateReset},
+		{name: "reopened after close", flags: []uint8{syn, ack, finAck, syn}, want: ConnStateSynSent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var flow Flow
+			for _, flags := range tt.flags {
+				flow.ObserveTCPFlags(flags, 1)
+			}
+			if flow.ConnState != tt.want {
+				t.Fatalf("ConnState = %v, want %v", flow.ConnState, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserveTCPFlagsCountsFlags(t *testing.T) {
+	var flow Flow
+	flow.ObserveTCPFlags(model.TCPFlagSYN, 1)
+	flow.ObserveTCPFlags(model.TCPFlagSYN|model.TCPFlagACK, 1)
+	flow.ObserveTCPFlags(model.TCPFlagFIN|model.TCPFlagACK, 1)
+	flow.ObserveTCPFlags(model.TCPFlagRST, 1)
+
+	if flow.SYNCount != 2 || flow.ACKCount != 2 || flow.FINCount != 1 || flow.RSTCount != 1 {
+		t.Fatalf("counts = syn:%d ack:%d fin:%d rst:%d, want syn:2 ack:2 fin:1 rst:1",
+			flow.SYNCount, flow.ACKCount, flow.FINCount, flow.RSTCount)
+	}
+	wantFlags := model.TCPFlagSYN | model.TCPFlagACK | model.TCPFlagFIN | model.TCPFlagRST
+	if flow.TCPFlags != wantFlags {
+		t.Fatalf("TCPFlags = %#x, want %#x", flow.TCPFlags, wantFlags)
+	}
+}
+
+func TestObserveTCPFlagsScalesCountsByWeight(t *testing.T) {
+	var flow Flow
+	flow.ObserveTCPFlags(model.TCPFlagSYN, 100)
+	flow.ObserveTCPFlags(model.TCPFlagACK, 100)
+
+	if flow.SYNCount != 100 || flow.ACKCount != 100 {
+		t.Fatalf("counts = syn:%d ack:%d, want syn:100 ack:100", flow.SYNCount, flow.ACKCount)
+	}
+	if flow.ConnState != ConnStateEstablished {
+		t.Fatalf("ConnState = %v, want %v", flow.ConnState, ConnStateEstablished)
+	}
+}
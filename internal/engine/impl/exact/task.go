@@ -4,15 +4,17 @@ import (
 	"fmt"
 	"hash/maphash"
 	"log"
-	"net"
-	"strconv"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/filter"
 	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/engine/window"
 	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/keyfield"
 	"Go2NetSpectra/internal/model"
 )
 
@@ -24,7 +26,8 @@ func init() {
 
 		// Create all enabled writers for this aggregator group
 		writers := make([]model.Writer, 0, len(exactCfg.Writers))
-		for _, writerDef := range exactCfg.Writers {
+		writerDefs := make([]config.WriterDef, 0, len(exactCfg.Writers))
+		for i, writerDef := range exactCfg.Writers {
 			if !writerDef.Enabled {
 				continue
 			}
@@ -40,7 +43,13 @@ func init() {
 			case "gob":
 				writer = NewGobWriter(writerDef.Gob.RootPath, interval)
 			case "clickhouse":
-				writer, err = NewClickHouseWriter(writerDef.ClickHouse, interval)
+				writer, err = NewClickHouseWriter(writerDef.ClickHouse, interval, cfg.Aggregator.Cluster.ID())
+				if err != nil {
+					log.Printf("Warning: failed to create writer type '%s': %v, skipping.", writerDef.Type, err)
+					continue
+				}
+			case "ipfix":
+				writer, err = NewIPFIXWriter(writerDef.IPFIX, interval)
 				if err != nil {
 					log.Printf("Warning: failed to create writer type '%s': %v, skipping.", writerDef.Type, err)
 					continue
@@ -49,41 +58,98 @@ func init() {
 				log.Printf("Warning: unknown writer type '%s' in config, skipping.", writerDef.Type)
 				continue
 			}
+			writerDef.Name = factory.WriterName("exact", i, writerDef)
 			writers = append(writers, writer)
+			writerDefs = append(writerDefs, writerDef)
 		}
 
 		// Create all tasks for this aggregator group
 		tasks := make([]model.Task, len(exactCfg.Tasks))
 		for i, taskCfg := range exactCfg.Tasks {
-			tasks[i] = New(taskCfg.Name, taskCfg.KeyFields, taskCfg.NumShards)
+			match, err := filter.Compile(taskCfg.Filter)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			fields, err := keyfield.Resolve(taskCfg.KeyFields)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			timeouts, expires, err := parseTimeouts(taskCfg)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			limit, err := parseFlowLimit(taskCfg)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			task, err := window.NewTask(taskCfg.Window, func() model.Task {
+				task := newTask(taskCfg.Name, taskCfg.KeyFields, fields, taskCfg.NumShards)
+				if expires {
+					task.expireOn(timeouts)
+				}
+				if limit.maxFlows > 0 {
+					task.budget = newBudget(taskCfg.Name, limit)
+				}
+				return filter.Wrap(match, task)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			tasks[i] = task
 		}
 
-		return &factory.TaskGroup{Tasks: tasks, Writers: writers}, nil
+		return &factory.TaskGroup{Tasks: tasks, Writers: writers, WriterDefs: writerDefs}, nil
 	})
 }
 
 // --- Task Implementation ---
 
-const (
-	ipv6ByteSize  = 16
-	portByteSize  = 2
-	protoByteSize = 1
-)
-
 const defaultShardCount = 256
 
+const protocolTCP = 6
+
 // Task performs exact aggregation for a specific set of key fields using a sharded map.
-// It implements the model.Task interface.
+// It implements the model.PartitionedTask, model.Checkpointer and
+// model.ExpiringTask interfaces.
 type Task struct {
 	name       string
 	keyFields  []string
+	fields     []*keyfield.Field
 	shards     []*statistic.Shard
 	shardCount uint32
 	shardSeed  maphash.Seed
+
+	// timeouts end flows when expires is set; see ExpireFlows.
+	timeouts statistic.Timeouts
+	expires  bool
+
+	// budget bounds the flows of the task when it has a max_flows.
+	budget *budget
+
+	// local holds each worker's flows under flow dispatch. Only Snapshot,
+	// Reset, Query and ExpireFlows take a worker's lock besides the worker itself.
+	local []*localShard
+}
+
+// localShard keeps a worker's shard on cache lines of its own, so workers
+// taking their locks do not invalidate each other's.
+type localShard struct {
+	statistic.Shard
+	_ [64]byte
+}
+
+// New creates a new exact aggregation task. It returns an error if the key
+// fields are not registered in keyfield.
+func New(name string, keyFields []string, numShards uint32) (model.Task, error) {
+	fields, err := keyfield.Resolve(keyFields)
+	if err != nil {
+		return nil, fmt.Errorf("exact task '%s': %w", name, err)
+	}
+	return newTask(name, keyFields, fields, numShards), nil
 }
 
-// New creates a new exact aggregation task.
-func New(name string, keyFields []string, numShards uint32) model.Task {
+// newTask creates an exact task keyed on fields, resolved from keyFields.
+func newTask(name string, keyFields []string, fields []*keyfield.Field, numShards uint32) *Task {
 	if numShards == 0 || numShards >= 32768 {
 		numShards = defaultShardCount
 	}
@@ -91,6 +157,7 @@ func New(name string, keyFields []string, numShards uint32) model.Task {
 	task := &Task{
 		name:       name,
 		keyFields:  keyFields,
+		fields:     fields,
 		shards:     make([]*statistic.Shard, numShards),
 		shardCount: numShards,
 		shardSeed:  maphash.MakeSeed(),
@@ -122,29 +189,121 @@ func (t *Task) DecodeFlowFunc() func(flow []byte, fields []string) string {
 
 // ProcessPacket processes a single packet, creating or updating a flow in the correct shard.
 func (t *Task) ProcessPacket(packetInfo *model.PacketInfo) {
-	fields, key, err := t.generateKeyAndFields(packetInfo.FiveTuple)
-	if err != nil {
-		log.Printf("Error generating key for task '%s': %v", t.name, err)
+	fields, key := t.generateKeyAndFields(packetInfo)
+
+	shard := t.getShard(key)
+	shard.Mu.Lock()
+	added := t.addPacket(shard, key, fields, packetInfo)
+	shard.Mu.Unlock()
+	if !added {
+		t.overflow(t.getShard(otherFlowKey), packetInfo)
+	}
+}
+
+// Partition gives each of workers its own flow map. It must be called before
+// packets are processed.
+func (t *Task) Partition(workers int) {
+	t.local = make([]*localShard, workers)
+	for i := range t.local {
+		t.local[i] = &localShard{Shard: statistic.Shard{Flows: make(map[string]*statistic.Flow)}}
+	}
+	// Workers are picked by five-tuple, so only a coarser key can be held
+	// by several of them and needs to be counted once across partitions.
+	if t.budget != nil && workers > 1 && !t.keyedByFiveTuple() {
+		t.budget.countKeys()
+	}
+}
+
+// fiveTupleFields are the fields flow dispatch hashes packets to workers by.
+var fiveTupleFields = []string{"SrcIP", "DstIP", "SrcPort", "DstPort", "Protocol"}
+
+// keyedByFiveTuple reports whether the task's key includes the whole
+// five-tuple, so that each key is only ever seen by one worker.
+func (t *Task) keyedByFiveTuple() bool {
+	for _, name := range fiveTupleFields {
+		if !slices.Contains(t.keyFields, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProcessPacketOn processes a packet into the flow map of worker. Without a
+// partition for worker it falls back to ProcessPacket.
+func (t *Task) ProcessPacketOn(worker int, packetInfo *model.PacketInfo) {
+	if worker >= len(t.local) {
+		t.ProcessPacket(packetInfo)
 		return
 	}
+	fields, key := t.generateKeyAndFields(packetInfo)
 
-	shard := t.getShard(key)
+	shard := &t.local[worker].Shard
+	shard.Mu.Lock()
+	added := t.addPacket(shard, key, fields, packetInfo)
+	shard.Mu.Unlock()
+	if !added {
+		t.overflow(shard, packetInfo)
+	}
+}
+
+// addPacket counts packetInfo in the flow for key, unless the flow is new and
+// the budget turns it away. The caller holds shard.Mu.
+func (t *Task) addPacket(shard *statistic.Shard, key string, fields map[string]interface{}, packetInfo *model.PacketInfo) bool {
+	if t.budget != nil {
+		if _, ok := shard.Flows[key]; !ok && !t.budget.admit(shard, key) {
+			return false
+		}
+	}
+	countPacket(shard, key, fields, packetInfo)
+	return true
+}
+
+// overflow accounts for a packet the budget refused a flow, counting it in
+// the catch-all flow of shard under the "other" policy.
+func (t *Task) overflow(shard *statistic.Shard, packetInfo *model.PacketInfo) {
+	t.budget.refuse(packetInfo)
+	if t.budget.policy != overflowOther {
+		return
+	}
 	shard.Mu.Lock()
-	defer shard.Mu.Unlock()
+	if _, ok := shard.Flows[otherFlowKey]; !ok {
+		t.budget.hold(otherFlowKey)
+	}
+	countPacket(shard, otherFlowKey, map[string]interface{}{}, packetInfo)
+	shard.Mu.Unlock()
+}
 
-	if flow, ok := shard.Flows[key]; ok {
-		flow.EndTime = packetInfo.Timestamp
-		flow.PacketCount++
-		flow.ByteCount += uint64(packetInfo.Length)
+// countPacket counts packetInfo in the flow for key. The caller holds shard.Mu.
+func countPacket(shard *statistic.Shard, key string, fields map[string]interface{}, packetInfo *model.PacketInfo) {
+	// Sampled packets stand for Weight() packets of the original traffic, and
+	// flow records for all the packets they summarise.
+	weight := packetInfo.Weight()
+	flow, ok := shard.Flows[key]
+	if ok {
+		if firstSeen := packetInfo.FirstSeen(); firstSeen.Before(flow.StartTime) {
+			flow.StartTime = firstSeen
+		}
+		if packetInfo.Timestamp.After(flow.EndTime) {
+			flow.EndTime = packetInfo.Timestamp
+		}
+		flow.PacketCount += packetInfo.PacketCount()
+		flow.ByteCount += packetInfo.ByteCount()
 	} else {
-		shard.Flows[key] = &statistic.Flow{
+		flow = &statistic.Flow{
+			RecordID:    statistic.NewRecordID(),
 			Key:         key,
 			Fields:      fields,
-			StartTime:   packetInfo.Timestamp,
+			StartTime:   packetInfo.FirstSeen(),
 			EndTime:     packetInfo.Timestamp,
-			PacketCount: 1,
-			ByteCount:   uint64(packetInfo.Length),
+			PacketCount: packetInfo.PacketCount(),
+			ByteCount:   packetInfo.ByteCount(),
 		}
+		shard.Flows[key] = flow
+	}
+	flow.SampleRate = max(flow.SampleRate, uint32(weight))
+
+	if packetInfo.FiveTuple.Protocol == protocolTCP {
+		flow.ObserveTCPFlags(packetInfo.TCPFlags, weight)
 	}
 }
 
@@ -186,11 +345,29 @@ func (t *Task) Snapshot() interface{} {
 
 	wg.Wait() // Wait until all shard snapshots are complete
 
-	// Return the full snapshot
-	return statistic.SnapshotData{
+	// Fold each worker's flows into the shard the key hashes to.
+	for _, local := range t.local {
+		local.Mu.RLock()
+		for k, v := range local.Flows {
+			target := snapshotShards[t.shardIndex(k)].Flows
+			if flow, ok := target[k]; ok {
+				flow.Merge(v)
+				continue
+			}
+			flowCopy := *v
+			target[k] = &flowCopy
+		}
+		local.Mu.RUnlock()
+	}
+
+	snapshot := statistic.SnapshotData{
 		TaskName: t.name,
 		Shards:   snapshotShards,
 	}
+	if t.budget != nil {
+		snapshot.Overflow = t.budget.overflow()
+	}
+	return snapshot
 }
 
 // Reset clears the internal state of the task, preparing for a new measurement period.
@@ -209,6 +386,15 @@ func (t *Task) Reset() {
 	}
 
 	wait.Wait() // Wait until all shards are reset
+
+	for _, local := range t.local {
+		local.Mu.Lock()
+		local.Flows = make(map[string]*statistic.Flow)
+		local.Mu.Unlock()
+	}
+	if t.budget != nil {
+		t.budget.reset()
+	}
 }
 
 // AlerterMsg evaluates rules against the task's aggregated data and returns a markdown string if triggered.
@@ -301,71 +487,49 @@ func check(value, threshold float64, operator string) bool {
 
 // Query looks up the aggregated counters for the provided encoded flow key.
 func (t *Task) Query(flow []byte) uint64 {
-	parts := make([]string, len(t.keyFields))
+	if len(flow) < keyfield.Size(t.fields) {
+		return 0
+	}
+	parts := make([]string, len(t.fields))
 	index := 0
-	for i, fieldName := range t.keyFields {
-		switch fieldName {
-		case "SrcIP", "DstIP":
-			val := net.IP(flow[index : index+ipv6ByteSize]).String()
-			parts[i] = val
-			index += ipv6ByteSize
-		case "SrcPort", "DstPort":
-			val := uint16(flow[index])<<8 | uint16(flow[index+1])
-			parts[i] = strconv.Itoa(int(val))
-			index += portByteSize
-		case "Protocol":
-			val := uint8(flow[index])
-			parts[i] = strconv.Itoa(int(val))
-			index += protoByteSize
-		default:
-			return 0
-		}
+	for i, field := range t.fields {
+		parts[i] = field.Format(field.Decode(flow[index:]))
+		index += field.Size
 	}
 	key := strings.Join(parts, "-")
-	shard := t.getShard(key)
-	shard.Mu.RLock()
-	defer shard.Mu.RUnlock()
-	if flow, ok := shard.Flows[key]; ok {
-		return flow.PacketCount<<32 | flow.ByteCount
+	shards := []*statistic.Shard{t.getShard(key)}
+	for _, local := range t.local {
+		shards = append(shards, &local.Shard)
 	}
-	return 0
+	var packets, bytes uint64
+	for _, shard := range shards {
+		shard.Mu.RLock()
+		if flow, ok := shard.Flows[key]; ok {
+			packets += flow.PacketCount
+			bytes += flow.ByteCount
+		}
+		shard.Mu.RUnlock()
+	}
+	return packets<<32 | bytes
 }
 
 // getShard returns the appropriate shard for a given key.
 func (t *Task) getShard(key string) *statistic.Shard {
-	return t.shards[uint32(maphash.String(t.shardSeed, key))%t.shardCount]
+	return t.shards[t.shardIndex(key)]
+}
+
+func (t *Task) shardIndex(key string) uint32 {
+	return uint32(maphash.String(t.shardSeed, key)) % t.shardCount
 }
 
 // generateKeyAndFields creates a unique string key and a field map for a packet.
-func (t *Task) generateKeyAndFields(ft model.FiveTuple) (map[string]interface{}, string, error) {
-	parts := make([]string, len(t.keyFields))
-	fields := make(map[string]interface{}, len(t.keyFields))
-
-	for i, fieldName := range t.keyFields {
-		switch fieldName {
-		case "SrcIP":
-			val := ft.SrcIP.String()
-			parts[i] = val
-			fields[fieldName] = val
-		case "DstIP":
-			val := ft.DstIP.String()
-			parts[i] = val
-			fields[fieldName] = val
-		case "SrcPort":
-			val := ft.SrcPort
-			parts[i] = strconv.Itoa(int(val))
-			fields[fieldName] = val
-		case "DstPort":
-			val := ft.DstPort
-			parts[i] = strconv.Itoa(int(val))
-			fields[fieldName] = val
-		case "Protocol":
-			val := ft.Protocol
-			parts[i] = strconv.Itoa(int(val))
-			fields[fieldName] = val
-		default:
-			return nil, "", fmt.Errorf("unknown key field: %s", fieldName)
-		}
+func (t *Task) generateKeyAndFields(packetInfo *model.PacketInfo) (map[string]interface{}, string) {
+	parts := make([]string, len(t.fields))
+	fields := make(map[string]interface{}, len(t.fields))
+	for i, field := range t.fields {
+		val := field.Value(packetInfo)
+		parts[i] = field.Format(val)
+		fields[field.ColumnName()] = val
 	}
-	return fields, strings.Join(parts, "-"), nil
+	return fields, strings.Join(parts, "-")
 }
@@ -0,0 +1,508 @@
+package exact
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/keyfield"
+	"Go2NetSpectra/internal/model"
+)
+
+func mustNew(t *testing.T, name string, keyFields []string, numShards uint32) *Task {
+	t.Helper()
+	task, err := New(name, keyFields, numShards)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	return task.(*Task)
+}
+
+func mustNewExpiring(t *testing.T, name string, keyFields []string, numShards uint32, timeouts statistic.Timeouts) *Task {
+	t.Helper()
+	task, err := NewExpiring(name, keyFields, numShards, timeouts)
+	if err != nil {
+		t.Fatalf("NewExpiring() unexpected error: %v", err)
+	}
+	return task.(*Task)
+}
+
+func TestNewRejectsUnknownKeyFields(t *testing.T) {
+	if _, err := New("typo", []string{"SrcIP", "DstPrt"}, 4); err == nil {
+		t.Fatal("New(DstPrt) error = nil, want non-nil")
+	}
+	if _, err := NewExpiring("typo", []string{"SrcIP/33/"}, 4, statistic.Timeouts{Idle: time.Second}); err == nil {
+		t.Fatal("NewExpiring(SrcIP/33/) error = nil, want non-nil")
+	}
+}
+
+func TestProcessPacketScalesSampledPackets(t *testing.T) {
+	task := mustNew(t, "sampled", []string{"SrcIP", "DstIP"}, 4)
+	tuple := model.FiveTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"), Protocol: 17}
+
+	task.ProcessPacket(&model.PacketInfo{Timestamp: time.Unix(1, 0), FiveTuple: tuple, Length: 100})
+	task.ProcessPacket(&model.PacketInfo{Timestamp: time.Unix(2, 0), FiveTuple: tuple, Length: 200, SampleRate: 10})
+
+	snapshot := task.Snapshot().(statistic.SnapshotData)
+	var flows []*statistic.Flow
+	for _, shard := range snapshot.Shards {
+		for _, flow := range shard.Flows {
+			flows = append(flows, flow)
+		}
+	}
+	if len(flows) != 1 {
+		t.Fatalf("Snapshot() flows = %d, want 1", len(flows))
+	}
+
+	flow := flows[0]
+	if flow.PacketCount != 11 || flow.ByteCount != 2100 {
+		t.Fatalf("flow counts = %d packets/%d bytes, want 11/2100", flow.PacketCount, flow.ByteCount)
+	}
+	if flow.SampleRate != 10 {
+		t.Fatalf("flow sample rate = %d, want 10", flow.SampleRate)
+	}
+}
+
+func TestProcessPacketCountsFlowRecords(t *testing.T) {
+	task := mustNew(t, "records", []string{"ExporterIP", "SrcIP"}, 4)
+	tuple := model.FiveTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"), Protocol: 17}
+	exporter := net.ParseIP("192.0.2.254")
+
+	task.ProcessPacket(&model.PacketInfo{Timestamp: time.Unix(100, 0), Duration: 30 * time.Second, FiveTuple: tuple, Length: 15000, Packets: 10, ExporterIP: exporter})
+	task.ProcessPacket(&model.PacketInfo{Timestamp: time.Unix(90, 0), Duration: 40 * time.Second, FiveTuple: tuple, Length: 3000, Packets: 2, SampleRate: 100, ExporterIP: exporter})
+
+	snapshot := task.Snapshot().(statistic.SnapshotData)
+	var flows []*statistic.Flow
+	for _, shard := range snapshot.Shards {
+		for _, flow := range shard.Flows {
+			flows = append(flows, flow)
+		}
+	}
+	if len(flows) != 1 {
+		t.Fatalf("Snapshot() flows = %d, want 1", len(flows))
+	}
+
+	flow := flows[0]
+	if flow.PacketCount != 210 || flow.ByteCount != 315000 {
+		t.Fatalf("flow counts = %d packets/%d bytes, want 210/315000", flow.PacketCount, flow.ByteCount)
+	}
+	if !flow.StartTime.Equal(time.Unix(50, 0)) || !flow.EndTime.Equal(time.Unix(100, 0)) {
+		t.Fatalf("flow span = %v..%v, want %v..%v", flow.StartTime, flow.EndTime, time.Unix(50, 0), time.Unix(100, 0))
+	}
+	if got := flow.Fields["ExporterIP"]; got != "192.0.2.254" {
+		t.Fatalf("flow ExporterIP = %v, want 192.0.2.254", got)
+	}
+}
+
+func TestPartitionedSnapshotMergesWorkers(t *testing.T) {
+	task := mustNew(t, "partitioned", []string{"SrcIP"}, 4)
+	task.Partition(2)
+	src := net.ParseIP("10.0.0.1")
+
+	task.ProcessPacketOn(0, &model.PacketInfo{Timestamp: time.Unix(10, 0), FiveTuple: model.FiveTuple{SrcIP: src, DstIP: net.ParseIP("10.0.0.2"), Protocol: 17}, Length: 100})
+	task.ProcessPacketOn(1, &model.PacketInfo{Timestamp: time.Unix(5, 0), FiveTuple: model.FiveTuple{SrcIP: src, DstIP: net.ParseIP("10.0.0.3"), Protocol: 17}, Length: 50})
+	task.ProcessPacketOn(1, &model.PacketInfo{Timestamp: time.Unix(20, 0), FiveTuple: model.FiveTuple{SrcIP: src, DstIP: net.ParseIP("10.0.0.3"), Protocol: 17}, Length: 50})
+
+	snapshot := task.Snapshot().(statistic.SnapshotData)
+	var flows []*statistic.Flow
+	for _, shard := range snapshot.Shards {
+		for _, flow := range shard.Flows {
+			flows = append(flows, flow)
+		}
+	}
+	if len(flows) != 1 {
+		t.Fatalf("Snapshot() flows = %d, want 1", len(flows))
+	}
+	flow := flows[0]
+	if flow.PacketCount != 3 || flow.ByteCount != 200 {
+		t.Fatalf("merged counts = %d packets/%d bytes, want 3/200", flow.PacketCount, flow.ByteCount)
+	}
+	if !flow.StartTime.Equal(time.Unix(5, 0)) || !flow.EndTime.Equal(time.Unix(20, 0)) {
+		t.Fatalf("merged span = %v..%v, want 5s..20s", flow.StartTime, flow.EndTime)
+	}
+	if got := task.Query(src.To16()); got != 3<<32|200 {
+		t.Fatalf("Query() = %d, want %d", got, uint64(3<<32|200))
+	}
+
+	task.Reset()
+	if got := task.Query(src.To16()); got != 0 {
+		t.Fatalf("Query() after Reset = %d, want 0", got)
+	}
+}
+
+func TestCheckpointRestoresFlows(t *testing.T) {
+	keys := []string{"SrcIP", "DstPort", "Protocol"}
+	task := mustNew(t, "saved", keys, 4)
+	task.Partition(2)
+	tuple := model.FiveTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"), SrcPort: 40000, DstPort: 443, Protocol: 6}
+	task.ProcessPacket(&model.PacketInfo{Timestamp: time.Unix(1, 0), FiveTuple: tuple, Length: 100, TCPFlags: model.TCPFlagSYN})
+	task.ProcessPacketOn(1, &model.PacketInfo{Timestamp: time.Unix(2, 0), FiveTuple: tuple, Length: 200})
+
+	var buf bytes.Buffer
+	if err := task.Checkpoint(&buf); err != nil {
+		t.Fatalf("Checkpoint() unexpected error: %v", err)
+	}
+	restored := mustNew(t, "saved", keys, 8)
+	if err := restored.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+
+	var flows []*statistic.Flow
+	for _, shard := range restored.Snapshot().(statistic.SnapshotData).Shards {
+		for _, flow := range shard.Flows {
+			flows = append(flows, flow)
+		}
+	}
+	if len(flows) != 1 {
+		t.Fatalf("restored flows = %d, want 1", len(flows))
+	}
+	flow := flows[0]
+	if flow.PacketCount != 2 || flow.ByteCount != 300 || flow.SYNCount != 1 || flow.Fields["DstPort"] != uint16(443) {
+		t.Fatalf("restored flow = %+v, want 2 packets, 300 bytes, 1 SYN and DstPort 443", flow)
+	}
+
+	other := mustNew(t, "saved", []string{"SrcIP"}, 4)
+	if err := other.Restore(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatalf("Restore() into other key fields error = nil, want non-nil")
+	}
+}
+
+func expiredFlows(t *testing.T, payload interface{}) map[string]*statistic.Flow {
+	t.Helper()
+	if payload == nil {
+		return nil
+	}
+	snapshot, ok := payload.(statistic.SnapshotData)
+	if !ok || !snapshot.Expired {
+		t.Fatalf("ExpireFlows() = %#v, want an expired statistic.SnapshotData", payload)
+	}
+	flows := make(map[string]*statistic.Flow)
+	for _, shard := range snapshot.Shards {
+		for key, flow := range shard.Flows {
+			flows[key] = flow
+		}
+	}
+	return flows
+}
+
+func TestExpireFlowsEndsFlowsOnTimeouts(t *testing.T) {
+	timeouts := statistic.Timeouts{Idle: 15 * time.Second, Active: time.Minute, Closed: time.Second}
+	task := mustNewExpiring(t, "expiring", []string{"SrcIP", "DstPort"}, 4, timeouts)
+	src := net.ParseIP("10.0.0.1")
+	packet := func(at int64, port uint16, flags uint8) *model.PacketInfo {
+		return &model.PacketInfo{
+			Timestamp: time.Unix(at, 0),
+			FiveTuple: model.FiveTuple{SrcIP: src, DstIP: net.ParseIP("10.0.0.2"), DstPort: port, Protocol: protocolTCP},
+			Length:    100,
+			TCPFlags:  flags,
+		}
+	}
+
+	task.ProcessPacket(packet(0, 53, model.TCPFlagACK))  // goes idle
+	task.ProcessPacket(packet(0, 443, model.TCPFlagACK)) // stays busy past the active timeout
+	for at := int64(10); at <= 60; at += 10 {
+		task.ProcessPacket(packet(at, 443, model.TCPFlagACK))
+	}
+	task.ProcessPacket(packet(58, 80, model.TCPFlagSYN))
+	task.ProcessPacket(packet(58, 80, model.TCPFlagFIN|model.TCPFlagACK)) // closed
+
+	flows := expiredFlows(t, task.ExpireFlows(time.Unix(60, 0)))
+	want := map[string]statistic.EndReason{
+		"10.0.0.1-53":  statistic.EndReasonIdleTimeout,
+		"10.0.0.1-443": statistic.EndReasonActiveTimeout,
+		"10.0.0.1-80":  statistic.EndReasonEndOfFlow,
+	}
+	if len(flows) != len(want) {
+		t.Fatalf("ExpireFlows() flows = %d, want %d", len(flows), len(want))
+	}
+	for key, reason := range want {
+		if flow := flows[key]; flow == nil || flow.EndReason != reason {
+			t.Fatalf("expired flow %s = %+v, want end reason %s", key, flow, reason)
+		}
+	}
+	if got := flows["10.0.0.1-443"].PacketCount; got != 7 {
+		t.Fatalf("active flow packets = %d, want 7", got)
+	}
+	if got := task.ExpireFlows(time.Unix(60, 0)); got != nil {
+		t.Fatalf("second ExpireFlows() = %#v, want nil", got)
+	}
+
+	// Later packets of the flow cut by the active timeout start a new record.
+	task.ProcessPacket(packet(61, 443, model.TCPFlagACK))
+	if got := flowCount(task); got != 1 {
+		t.Fatalf("flows after expiry = %d, want 1", got)
+	}
+}
+
+func TestExpireFlowsMergesWorkerPartitions(t *testing.T) {
+	timeouts := statistic.Timeouts{Idle: 15 * time.Second, Active: time.Hour, Closed: time.Second}
+	task := mustNewExpiring(t, "partitioned", []string{"SrcIP"}, 4, timeouts)
+	task.Partition(2)
+	src := net.ParseIP("10.0.0.1")
+	packet := func(at int64) *model.PacketInfo {
+		return &model.PacketInfo{Timestamp: time.Unix(at, 0), FiveTuple: model.FiveTuple{SrcIP: src, Protocol: 17}, Length: 100}
+	}
+
+	task.ProcessPacketOn(0, packet(0))
+	task.ProcessPacketOn(1, packet(10))
+	if got := task.ExpireFlows(time.Unix(20, 0)); got != nil {
+		t.Fatalf("ExpireFlows() before the merged flow is idle = %#v, want nil", got)
+	}
+
+	flows := expiredFlows(t, task.ExpireFlows(time.Unix(25, 0)))
+	flow := flows["10.0.0.1"]
+	if len(flows) != 1 || flow == nil || flow.PacketCount != 2 || flow.EndReason != statistic.EndReasonIdleTimeout {
+		t.Fatalf("expired flows = %+v, want one idle flow of 2 packets", flows)
+	}
+	if got := flowCount(task); got != 0 {
+		t.Fatalf("flows after expiry = %d, want 0", got)
+	}
+}
+
+func TestRecordIDFollowsTheRecord(t *testing.T) {
+	timeouts := statistic.Timeouts{Idle: 15 * time.Second, Active: time.Hour, Closed: time.Second}
+	task := mustNewExpiring(t, "records", []string{"SrcIP"}, 4, timeouts)
+	task.Partition(2)
+	src := net.ParseIP("10.0.0.1")
+	packet := func(at int64, dst string) *model.PacketInfo {
+		return &model.PacketInfo{Timestamp: time.Unix(at, 0), FiveTuple: model.FiveTuple{SrcIP: src, DstIP: net.ParseIP(dst), Protocol: 17}, Length: 100}
+	}
+	snapshotFlow := func() *statistic.Flow {
+		t.Helper()
+		flow := task.Snapshot().(statistic.SnapshotData).Shards[task.shardIndex("10.0.0.1")].Flows["10.0.0.1"]
+		if flow == nil || flow.RecordID == 0 {
+			t.Fatalf("Snapshot() flow = %+v, want one with a record ID", flow)
+		}
+		return flow
+	}
+
+	task.ProcessPacketOn(0, packet(10, "10.0.0.2"))
+	first := snapshotFlow().RecordID
+
+	// Another worker's earlier packet moves the start time back, but the
+	// record stays the same.
+	task.ProcessPacketOn(1, packet(5, "10.0.0.3"))
+	if flow := snapshotFlow(); flow.RecordID != first || !flow.StartTime.Equal(time.Unix(5, 0)) {
+		t.Fatalf("Snapshot() flow record %d from %v, want record %d from 5s", flow.RecordID, flow.StartTime, first)
+	}
+
+	flows := expiredFlows(t, task.ExpireFlows(time.Unix(30, 0)))
+	if got := flows["10.0.0.1"].RecordID; got != first {
+		t.Fatalf("expired record = %d, want %d", got, first)
+	}
+
+	task.ProcessPacketOn(0, packet(40, "10.0.0.2"))
+	if got := snapshotFlow().RecordID; got <= first {
+		t.Fatalf("record after expiry = %d, want a new one after %d", got, first)
+	}
+}
+
+func TestParseTimeouts(t *testing.T) {
+	if _, expires, err := parseTimeouts(config.ExactTaskDef{}); expires || err != nil {
+		t.Fatalf("parseTimeouts(none) = %v, %v, want false, nil", expires, err)
+	}
+	timeouts, expires, err := parseTimeouts(config.ExactTaskDef{IdleTimeout: "30s"})
+	if err != nil || !expires {
+		t.Fatalf("parseTimeouts(idle) = %v, %v, want true, nil", expires, err)
+	}
+	if timeouts.Idle != 30*time.Second || timeouts.Active != defaultActiveTimeout {
+		t.Fatalf("parseTimeouts(idle) = %+v, want idle 30s and the default active timeout", timeouts)
+	}
+	for _, def := range []config.ExactTaskDef{
+		{ActiveTimeout: "soon"},
+		{IdleTimeout: "0s"},
+		{IdleTimeout: "15s", Window: config.WindowConfig{Size: "1m"}},
+	} {
+		if _, _, err := parseTimeouts(def); err == nil {
+			t.Fatalf("parseTimeouts(%+v) error = nil, want non-nil", def)
+		}
+	}
+}
+
+func flowCount(task *Task) int {
+	count := 0
+	for _, shard := range task.Snapshot().(statistic.SnapshotData).Shards {
+		count += len(shard.Flows)
+	}
+	return count
+}
+
+func TestFlowBudgetPolicies(t *testing.T) {
+	packet := func(at int64, src string) *model.PacketInfo {
+		return &model.PacketInfo{Timestamp: time.Unix(at, 0), FiveTuple: model.FiveTuple{SrcIP: net.ParseIP(src), Protocol: 17}, Length: 100}
+	}
+	for _, tc := range []struct {
+		policy   string
+		flows    int
+		evicted  uint64
+		overflow uint64 // packets
+	}{
+		{overflowEvict, 2, 2, 0},
+		{overflowRefuse, 2, 0, 3},
+		{overflowOther, 3, 0, 3},
+	} {
+		// One shard, so eviction always finds the oldest flow.
+		task := mustNew(t, "bounded", []string{"SrcIP"}, 1)
+		task.budget = newBudget("bounded", flowLimit{maxFlows: 2, policy: tc.policy})
+		for i, src := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3", "10.0.0.4"} {
+			task.ProcessPacket(packet(int64(i), src))
+		}
+		task.ProcessPacket(packet(10, "10.0.0.4")) // counted wherever its flow went
+
+		snapshot := task.Snapshot().(statistic.SnapshotData)
+		if got := flowCount(task); got != tc.flows {
+			t.Fatalf("%s: flows = %d, want %d", tc.policy, got, tc.flows)
+		}
+		o := snapshot.Overflow
+		if o == nil || o.Policy != tc.policy || o.EvictedFlows != tc.evicted || o.Packets != tc.overflow || o.Flows != int64(tc.flows) {
+			t.Fatalf("%s: overflow = %+v, want %d evicted, %d packets, %d flows", tc.policy, o, tc.evicted, tc.overflow, tc.flows)
+		}
+
+		expired := expiredFlows(t, task.ExpireFlows(time.Unix(10, 0)))
+		if len(expired) != int(tc.evicted) {
+			t.Fatalf("%s: ExpireFlows() flows = %d, want %d", tc.policy, len(expired), tc.evicted)
+		}
+		for key, flow := range expired {
+			if flow.EndReason != statistic.EndReasonEvicted || (key != "10.0.0.1" && key != "10.0.0.2") {
+				t.Fatalf("%s: evicted flow %s = %+v, want one of the two oldest, evicted", tc.policy, key, flow)
+			}
+		}
+		if tc.policy == overflowOther {
+			other := snapshot.Shards[0].Flows[otherFlowKey]
+			if other == nil || other.PacketCount != 3 {
+				t.Fatalf("other flow = %+v, want 3 packets", other)
+			}
+		}
+
+		task.Reset()
+		if o := task.Snapshot().(statistic.SnapshotData).Overflow; o.Flows != 0 || o.Packets != 0 || o.EvictedFlows != 0 {
+			t.Fatalf("%s: overflow after Reset() = %+v, want zero", tc.policy, o)
+		}
+	}
+}
+
+func TestFlowBudgetCountsKeysOnceAcrossWorkers(t *testing.T) {
+	timeouts := statistic.Timeouts{Idle: 15 * time.Second, Active: time.Hour, Closed: time.Second}
+	task := mustNewExpiring(t, "partitioned", []string{"SrcIP"}, 4, timeouts)
+	task.budget = newBudget("partitioned", flowLimit{maxFlows: 2, policy: overflowRefuse})
+	task.Partition(2)
+	packet := func(src string) *model.PacketInfo {
+		return &model.PacketInfo{Timestamp: time.Unix(0, 0), FiveTuple: model.FiveTuple{SrcIP: net.ParseIP(src), Protocol: 17}, Length: 100}
+	}
+
+	// 10.0.0.1 reaches both workers and is one flow of the budget.
+	task.ProcessPacketOn(0, packet("10.0.0.1"))
+	task.ProcessPacketOn(1, packet("10.0.0.1"))
+	task.ProcessPacketOn(1, packet("10.0.0.2"))
+	task.ProcessPacketOn(0, packet("10.0.0.3"))
+
+	o := task.Snapshot().(statistic.SnapshotData).Overflow
+	if o.Flows != 2 || o.Packets != 1 {
+		t.Fatalf("overflow = %+v, want 2 flows and 1 refused packet", o)
+	}
+
+	if flows := expiredFlows(t, task.ExpireFlows(time.Unix(20, 0))); len(flows) != 2 {
+		t.Fatalf("expired flows = %d, want 2", len(flows))
+	}
+	if o := task.Snapshot().(statistic.SnapshotData).Overflow; o.Flows != 0 {
+		t.Fatalf("overflow flows after expiry = %d, want 0", o.Flows)
+	}
+}
+
+func TestFlowBudgetCountsKeysOnlyWhenSplitOverWorkers(t *testing.T) {
+	fiveTuple := []string{"SrcIP", "DstIP", "SrcPort", "DstPort", "Protocol"}
+	for _, tc := range []struct {
+		keyFields []string
+		workers   int
+		want      bool
+	}{
+		{[]string{"SrcIP"}, 0, false},
+		{[]string{"SrcIP"}, 1, false},
+		{[]string{"SrcIP"}, 4, true},
+		{fiveTuple, 4, false},
+		{append([]string{"InterfaceID"}, fiveTuple...), 4, false},
+		{[]string{"SrcIP/24", "DstIP", "SrcPort", "DstPort", "Protocol"}, 4, true},
+	} {
+		task := mustNew(t, "bounded", tc.keyFields, 4)
+		task.budget = newBudget("bounded", flowLimit{maxFlows: 10, policy: overflowRefuse})
+		if tc.workers > 0 {
+			task.Partition(tc.workers)
+		}
+		if got := task.budget.keys != nil; got != tc.want {
+			t.Fatalf("%v on %d workers: counts keys = %v, want %v", tc.keyFields, tc.workers, got, tc.want)
+		}
+	}
+}
+
+func TestParseFlowLimit(t *testing.T) {
+	if limit, err := parseFlowLimit(config.ExactTaskDef{}); err != nil || limit.maxFlows != 0 {
+		t.Fatalf("parseFlowLimit(none) = %+v, %v, want no limit", limit, err)
+	}
+	limit, err := parseFlowLimit(config.ExactTaskDef{MaxFlows: 1000})
+	if err != nil || limit.policy != overflowEvict {
+		t.Fatalf("parseFlowLimit(max) = %+v, %v, want the evict policy", limit, err)
+	}
+	if _, err := parseFlowLimit(config.ExactTaskDef{MaxFlows: 1000, Overflow: overflowOther, Window: config.WindowConfig{Size: "1m"}}); err != nil {
+		t.Fatalf("parseFlowLimit(other, window) unexpected error: %v", err)
+	}
+	for _, def := range []config.ExactTaskDef{
+		{MaxFlows: -1},
+		{Overflow: overflowRefuse},
+		{MaxFlows: 10, Overflow: "drop"},
+		{MaxFlows: 10, Window: config.WindowConfig{Size: "1m"}},
+	} {
+		if _, err := parseFlowLimit(def); err == nil {
+			t.Fatalf("parseFlowLimit(%+v) error = nil, want non-nil", def)
+		}
+	}
+}
+
+func TestQueryFindsFlowByEncodedKey(t *testing.T) {
+	keyFields := []string{"SrcIP", "DstPort", "Protocol"}
+	task := mustNew(t, "query", keyFields, 4)
+	packet := &model.PacketInfo{
+		Timestamp: time.Unix(1, 0),
+		FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("10.0.0.1"), DstPort: 443, Protocol: protocolTCP},
+		Length:    100,
+	}
+	task.ProcessPacket(packet)
+	task.ProcessPacket(packet)
+
+	fields, err := keyfield.Resolve(keyFields)
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	key := make([]byte, keyfield.Size(fields))
+	offset := 0
+	for _, field := range fields {
+		field.Encode(key[offset:], packet)
+		offset += field.Size
+	}
+	if got, want := task.Query(key), uint64(2)<<32|200; got != want {
+		t.Fatalf("Query() = %#x, want %#x", got, want)
+	}
+}
+
+func TestProcessPacketAggregatesPrefixes(t *testing.T) {
+	task := mustNew(t, "per-subnet", []string{"SrcIP/24", "Protocol"}, 4)
+	for _, src := range []string{"10.0.0.1", "10.0.0.2", "10.0.1.1"} {
+		task.ProcessPacket(&model.PacketInfo{Timestamp: time.Unix(1, 0), FiveTuple: model.FiveTuple{SrcIP: net.ParseIP(src), Protocol: 17}, Length: 100})
+	}
+
+	flows := make(map[string]*statistic.Flow)
+	for _, shard := range task.Snapshot().(statistic.SnapshotData).Shards {
+		for key, flow := range shard.Flows {
+			flows[key] = flow
+		}
+	}
+	flow := flows["10.0.0.0/24-17"]
+	if len(flows) != 2 || flow == nil || flow.PacketCount != 2 {
+		t.Fatalf("flows = %v, want 10.0.0.0/24 with 2 packets and 10.0.1.0/24", flows)
+	}
+	if got := flow.Fields["SrcIP"]; got != "10.0.0.0/24" {
+		t.Fatalf("flow SrcIP = %v, want 10.0.0.0/24", got)
+	}
+}
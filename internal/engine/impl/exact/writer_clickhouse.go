@@ -5,53 +5,155 @@ import (
 	"crypto/tls"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
 	"Go2NetSpectra/internal/config"
 	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/keyfield"
 	"Go2NetSpectra/internal/model"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/ClickHouse/clickhouse-go/v2/lib/driver"
 )
 
+// createTableStatement holds a Nullable column for every registered key field
+// between TaskName and StartTime; see keyFieldColumns.
 const createTableStatement = `
 CREATE TABLE IF NOT EXISTS flow_metrics (
     Timestamp   DateTime,
     TaskName    String,
-    SrcIP       Nullable(String),
-    DstIP       Nullable(String),
-    SrcPort     Nullable(UInt16),
-    DstPort     Nullable(UInt16),
-    Protocol    Nullable(UInt8),
+%s
     StartTime   DateTime,
     EndTime     DateTime,
     ByteCount   UInt64,
-    PacketCount UInt64
+    PacketCount UInt64,
+    TCPFlags    UInt8,
+    SYNCount    UInt64,
+    FINCount    UInt64,
+    RSTCount    UInt64,
+    ACKCount    UInt64,
+    ConnState   LowCardinality(String),
+    SampleRate  UInt32 DEFAULT 1,
+    EngineID    LowCardinality(String),
+    WindowStart DateTime,
+    WindowEnd   DateTime,
+    EndReason   LowCardinality(String),
+    RecordID    UInt64
 ) ENGINE = MergeTree()
 PARTITION BY toYYYYMM(Timestamp)
 ORDER BY (TaskName, Timestamp);
 `
 
+// createInputStatsTableStatement holds the engine input counters written with
+// each snapshot. The exact and sketch writers both write them, so rows for the
+// same engine and second are collapsed.
+const createInputStatsTableStatement = `
+CREATE TABLE IF NOT EXISTS engine_input_stats (
+    Timestamp     DateTime,
+    EngineID      LowCardinality(String),
+    Enqueued      UInt64,
+    Dropped       UInt64,
+    Processed     UInt64,
+    QueueDepth    UInt32,
+    QueueCapacity UInt32
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(Timestamp)
+ORDER BY (EngineID, Timestamp);
+`
+
+// createOverflowTableStatement holds the flow budget counters of exact tasks
+// with a max_flows, one row per task snapshot. Rows with traffic show the
+// task's flows for the period are no longer exact.
+const createOverflowTableStatement = `
+CREATE TABLE IF NOT EXISTS exact_task_overflow (
+    Timestamp    DateTime,
+    TaskName     String,
+    EngineID     LowCardinality(String),
+    WindowStart  DateTime,
+    WindowEnd    DateTime,
+    Policy       LowCardinality(String),
+    MaxFlows     UInt64,
+    Flows        UInt64,
+    EvictedFlows UInt64,
+    Packets      UInt64,
+    Bytes        UInt64
+) ENGINE = MergeTree()
+PARTITION BY toYYYYMM(Timestamp)
+ORDER BY (TaskName, Timestamp);
+`
+
+// keyFieldColumns returns the flow_metrics column definitions of the key
+// fields, in registry order.
+func keyFieldColumns() string {
+	columns := make([]string, 0, len(keyfield.All()))
+	for _, field := range keyfield.All() {
+		columns = append(columns, fmt.Sprintf("    %-11s Nullable(%s),", field.Name, field.Column))
+	}
+	return strings.Join(columns, "\n")
+}
+
+// migrateKeyFieldStatements add the columns of key fields registered after
+// a table was created, each after the field registered before it.
+func migrateKeyFieldStatements() []string {
+	statements := make([]string, 0, len(keyfield.All()))
+	after := "TaskName"
+	for _, field := range keyfield.All() {
+		statements = append(statements, fmt.Sprintf("ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS %s Nullable(%s) AFTER %s", field.Name, field.Column, after))
+		after = field.Name
+	}
+	return statements
+}
+
+// migrateTableStatements bring tables created by older releases up to the
+// current column set, after migrateKeyFieldStatements.
+var migrateTableStatements = []string{
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS TCPFlags UInt8 AFTER PacketCount",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS SYNCount UInt64 AFTER TCPFlags",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS FINCount UInt64 AFTER SYNCount",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS RSTCount UInt64 AFTER FINCount",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS ACKCount UInt64 AFTER RSTCount",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS ConnState LowCardinality(String) AFTER ACKCount",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS SampleRate UInt32 DEFAULT 1 AFTER ConnState",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS EngineID LowCardinality(String) AFTER SampleRate",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS WindowStart DateTime AFTER EngineID",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS WindowEnd DateTime AFTER WindowStart",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS EndReason LowCardinality(String) AFTER WindowEnd",
+	"ALTER TABLE flow_metrics ADD COLUMN IF NOT EXISTS RecordID UInt64 AFTER EndReason",
+}
+
 // ClickHouseWriter implements the model.Writer interface for ClickHouse.
 type ClickHouseWriter struct {
 	conn     driver.Conn
 	interval time.Duration
+	engineID string
 }
 
-// NewClickHouseWriter creates a new ClickHouse writer.
-func NewClickHouseWriter(cfg config.ClickHouseConfig, interval time.Duration) (model.Writer, error) {
+// NewClickHouseWriter creates a new ClickHouse writer. Every row it writes is
+// tagged with engineID, so flows split across engines can be told apart and summed.
+func NewClickHouseWriter(cfg config.ClickHouseConfig, interval time.Duration, engineID string) (model.Writer, error) {
 	conn, err := connect(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to clickhouse: %w", err)
 	}
 
-	if err := conn.Exec(context.Background(), createTableStatement); err != nil {
+	if err := conn.Exec(context.Background(), fmt.Sprintf(createTableStatement, keyFieldColumns())); err != nil {
 		return nil, fmt.Errorf("failed to create table: %w", err)
 	}
+	for _, stmt := range append(migrateKeyFieldStatements(), migrateTableStatements...) {
+		if err := conn.Exec(context.Background(), stmt); err != nil {
+			return nil, fmt.Errorf("failed to migrate table: %w", err)
+		}
+	}
+	if err := conn.Exec(context.Background(), createInputStatsTableStatement); err != nil {
+		return nil, fmt.Errorf("failed to create engine_input_stats table: %w", err)
+	}
+	if err := conn.Exec(context.Background(), createOverflowTableStatement); err != nil {
+		return nil, fmt.Errorf("failed to create exact_task_overflow table: %w", err)
+	}
 	log.Println("Successfully connected to ClickHouse and ensured table exists.")
 
-	return &ClickHouseWriter{conn: conn, interval: interval}, nil
+	return &ClickHouseWriter{conn: conn, interval: interval, engineID: engineID}, nil
 }
 
 // Interval returns the configured snapshot interval for this writer.
@@ -59,6 +161,11 @@ func (w *ClickHouseWriter) Interval() time.Duration {
 	return w.interval
 }
 
+// Close closes the ClickHouse connection.
+func (w *ClickHouseWriter) Close() error {
+	return w.conn.Close()
+}
+
 func connect(cfg config.ClickHouseConfig) (driver.Conn, error) {
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
@@ -88,12 +195,25 @@ func connect(cfg config.ClickHouseConfig) (driver.Conn, error) {
 	return conn, nil
 }
 
-// Write inserts flow data into the ClickHouse flow_metrics table.
-func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+// Write inserts flow data into the ClickHouse flow_metrics table. Flows that
+// expired carry their EndReason; rows of flows in progress leave it empty.
+// Every row of a flow record carries its RecordID.
+// The flow budget counters of the snapshot go to exact_task_overflow.
+func (w *ClickHouseWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
 	snapshot, ok := payload.(statistic.SnapshotData)
 	if !ok {
 		return fmt.Errorf("invalid payload type for clickhouse writer: expected statistic.SnapshotData, got %T", payload)
 	}
+	snapshotTime, _ := time.Parse("2006-01-02_15-04-05", timestamp)
+
+	if o := snapshot.Overflow; o != nil {
+		err := w.conn.Exec(context.Background(), "INSERT INTO exact_task_overflow VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			snapshotTime, snapshot.TaskName, w.engineID, window.Start, window.End, o.Policy,
+			uint64(o.MaxFlows), uint64(max(o.Flows, 0)), o.EvictedFlows, o.Packets, o.Bytes)
+		if err != nil {
+			return fmt.Errorf("failed to insert overflow stats: %w", err)
+		}
+	}
 
 	flowCount := 0
 	for _, shard := range snapshot.Shards {
@@ -108,24 +228,33 @@ func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fi
 		return fmt.Errorf("failed to prepare batch: %w", err)
 	}
 
-	snapshotTime, _ := time.Parse("2006-01-02_15-04-05", timestamp)
-
+	keyFields := keyfield.All()
+	row := make([]interface{}, 0, 2+len(keyFields)+16)
 	for _, shard := range snapshot.Shards {
 		for _, flow := range shard.Flows {
-			err = batch.Append(
-				snapshotTime,
-				snapshot.TaskName,
-				getNullableField(flow.Fields, "SrcIP"),
-				getNullableField(flow.Fields, "DstIP"),
-				getNullableField(flow.Fields, "SrcPort"),
-				getNullableField(flow.Fields, "DstPort"),
-				getNullableField(flow.Fields, "Protocol"),
+			row = append(row[:0], snapshotTime, snapshot.TaskName)
+			for _, field := range keyFields {
+				row = append(row, getNullableField(flow.Fields, field.Name))
+			}
+			row = append(row,
 				flow.StartTime,
 				flow.EndTime,
 				flow.ByteCount,
 				flow.PacketCount,
+				flow.TCPFlags,
+				flow.SYNCount,
+				flow.FINCount,
+				flow.RSTCount,
+				flow.ACKCount,
+				flow.ConnState.String(),
+				max(flow.SampleRate, 1),
+				w.engineID,
+				window.Start,
+				window.End,
+				flow.EndReason.String(),
+				flow.RecordID,
 			)
-			if err != nil {
+			if err = batch.Append(row...); err != nil {
 				return fmt.Errorf("failed to append flow to batch: %w", err)
 			}
 		}
@@ -135,10 +264,25 @@ func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fi
 		return fmt.Errorf("failed to send batch: %w", err)
 	}
 
+	if snapshot.Expired {
+		log.Printf("Wrote %d expired flows to ClickHouse for task '%s'", flowCount, snapshot.TaskName)
+		return nil
+	}
 	log.Printf("Wrote %d flows to ClickHouse for task '%s'", flowCount, snapshot.TaskName)
 	return nil
 }
 
+// WriteStats records the engine's input counters in engine_input_stats.
+func (w *ClickHouseWriter) WriteStats(stats model.InputStats, timestamp string) error {
+	snapshotTime, _ := time.Parse("2006-01-02_15-04-05", timestamp)
+	err := w.conn.Exec(context.Background(), "INSERT INTO engine_input_stats VALUES (?, ?, ?, ?, ?, ?, ?)",
+		snapshotTime, w.engineID, stats.Enqueued, stats.Dropped, stats.Processed, uint32(stats.QueueDepth), uint32(stats.QueueCapacity))
+	if err != nil {
+		return fmt.Errorf("failed to insert input stats: %w", err)
+	}
+	return nil
+}
+
 // getNullableField safely gets a value from the map for insertion.
 func getNullableField(fields map[string]interface{}, key string) interface{} {
 	if val, ok := fields[key]; ok {
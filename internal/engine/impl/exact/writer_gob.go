@@ -25,6 +25,8 @@ type summaryData struct {
 	TotalPackets uint64 `json:"total_packets"`
 	Shards       int    `json:"shards"`
 	Timestamp    string `json:"timestamp"`
+
+	Overflow *statistic.Overflow `json:"overflow,omitempty"`
 }
 
 // GobWriter handles writing aggregation task snapshot data to disk in gob format.
@@ -46,7 +48,7 @@ func (w *GobWriter) Interval() time.Duration {
 
 // Write serializes and writes the data from a single aggregation task snapshot to disk.
 // It expects the payload to be of type exact.SnapshotData.
-func (w *GobWriter) Write(payload interface{}, timestamp, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+func (w *GobWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
 	snapshot, ok := payload.(statistic.SnapshotData)
 	if !ok {
 		return fmt.Errorf("invalid payload type for gob writer: expected statistic.SnapshotData, got %T", payload)
@@ -56,6 +58,10 @@ func (w *GobWriter) Write(payload interface{}, timestamp, name string, fields []
 	snapshotDir := filepath.Join(w.rootPath, timestamp)
 	// Let's make a subdirectory for the task to avoid file name collisions
 	taskDir := filepath.Join(snapshotDir, snapshot.TaskName)
+	if snapshot.Expired {
+		// Flows that ended go beside the snapshot of the same second.
+		taskDir = filepath.Join(taskDir, "expired")
+	}
 	if err := os.MkdirAll(taskDir, 0755); err != nil {
 		return fmt.Errorf("failed to create snapshot directory: %w", err)
 	}
@@ -88,8 +94,8 @@ func (w *GobWriter) Write(payload interface{}, timestamp, name string, fields []
 		}
 	}
 
-	// 3. Write summary file if there were any flows
-	if totalFlows > 0 {
+	// 3. Write summary file if there were any flows, or traffic the flow budget kept out of them
+	if totalFlows > 0 || snapshot.Overflow != nil {
 		summary := summaryData{
 			TaskName:     snapshot.TaskName,
 			TotalFlows:   totalFlows,
@@ -97,6 +103,7 @@ func (w *GobWriter) Write(payload interface{}, timestamp, name string, fields []
 			TotalPackets: totalPackets,
 			Shards:       len(snapshot.Shards),
 			Timestamp:    time.Now().UTC().Format(time.RFC3339),
+			Overflow:     snapshot.Overflow,
 		}
 		summaryFilePath := filepath.Join(taskDir, "summary.json")
 		summaryFile, err := os.Create(summaryFilePath)
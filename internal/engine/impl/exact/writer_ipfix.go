@@ -0,0 +1,302 @@
+package exact
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log"
+	"net"
+	"sync"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/model"
+)
+
+const (
+	ipfixVersion        = 10
+	ipfixHeaderLen      = 16
+	ipfixSetHeaderLen   = 4
+	ipfixTemplateSetID  = 2
+	ipfixIPv4TemplateID = 256
+	ipfixIPv6TemplateID = 257
+	// ipfixMaxMessageSize keeps UDP messages below a typical path MTU.
+	ipfixMaxMessageSize = 1400
+	ipfixDialTimeout    = 5 * time.Second
+)
+
+// ipfixElement is an IANA information element of the exported templates.
+type ipfixElement struct {
+	id     uint16
+	length uint16
+}
+
+// ipfixCommonElements follow the addresses in both templates.
+var ipfixCommonElements = []ipfixElement{
+	{7, 2},   // sourceTransportPort
+	{11, 2},  // destinationTransportPort
+	{4, 1},   // protocolIdentifier
+	{6, 2},   // tcpControlBits
+	{10, 4},  // ingressInterface
+	{58, 2},  // vlanId
+	{70, 3},  // mplsTopLabelStackSection
+	{152, 8}, // flowStartMilliseconds
+	{153, 8}, // flowEndMilliseconds
+	{85, 8},  // octetTotalCount
+	{86, 8},  // packetTotalCount
+	{136, 1}, // flowEndReason
+}
+
+var (
+	ipfixIPv4Template = append([]ipfixElement{{8, 4}, {12, 4}}, ipfixCommonElements...)    // sourceIPv4Address, destinationIPv4Address
+	ipfixIPv6Template = append([]ipfixElement{{27, 16}, {28, 16}}, ipfixCommonElements...) // sourceIPv6Address, destinationIPv6Address
+)
+
+// IPFIXWriter exports the flows of each snapshot as IPFIX (RFC 7011) data
+// records to a collector. Key fields a task does not aggregate on are sent as
+// zero. It implements the model.Writer interface.
+type IPFIXWriter struct {
+	cfg      config.IPFIXConfig
+	interval time.Duration
+
+	mu            sync.Mutex
+	conn          net.Conn
+	templatesSent bool   // templates went out on the current TCP connection
+	sequence      uint32 // data records sent so far, modulo 2^32
+}
+
+// NewIPFIXWriter creates a writer that exports to cfg.Address. The connection
+// is opened on the first snapshot and reopened after a failed write.
+func NewIPFIXWriter(cfg config.IPFIXConfig, interval time.Duration) (model.Writer, error) {
+	if cfg.Address == "" {
+		return nil, fmt.Errorf("ipfix writer has no address")
+	}
+	switch cfg.Transport {
+	case "":
+		cfg.Transport = "udp"
+	case "udp", "tcp":
+	default:
+		return nil, fmt.Errorf("unsupported ipfix transport %q, want udp or tcp", cfg.Transport)
+	}
+	return &IPFIXWriter{cfg: cfg, interval: interval}, nil
+}
+
+// Interval returns the configured snapshot interval for this writer.
+func (w *IPFIXWriter) Interval() time.Duration {
+	return w.interval
+}
+
+// Close closes the connection to the collector, if one is open.
+func (w *IPFIXWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// Write sends every flow of the snapshot as one data record.
+func (w *IPFIXWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+	snapshot, ok := payload.(statistic.SnapshotData)
+	if !ok {
+		return fmt.Errorf("invalid payload type for ipfix writer: expected statistic.SnapshotData, got %T", payload)
+	}
+
+	exportTime, err := time.ParseInLocation("2006-01-02_15-04-05", timestamp, time.Local)
+	if err != nil {
+		exportTime = time.Now()
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		conn, err := net.DialTimeout(w.cfg.Transport, w.cfg.Address, ipfixDialTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to connect to ipfix collector %s: %w", w.cfg.Address, err)
+		}
+		w.conn, w.templatesSent = conn, false
+	}
+
+	msg := w.newMessage(exportTime)
+	flowCount := 0
+	for _, shard := range snapshot.Shards {
+		for _, flow := range shard.Flows {
+			templateID, record := encodeIPFIXRecord(flow)
+			if !msg.fits(templateID, len(record)) {
+				if err := w.send(msg); err != nil {
+					return err
+				}
+				msg = w.newMessage(exportTime)
+			}
+			msg.add(templateID, record)
+			flowCount++
+		}
+	}
+	if msg.records > 0 {
+		if err := w.send(msg); err != nil {
+			return err
+		}
+	}
+
+	if flowCount > 0 {
+		log.Printf("Exported %d flows over IPFIX for task '%s'", flowCount, snapshot.TaskName)
+	}
+	return nil
+}
+
+// newMessage starts a message, opening with the templates unless the TCP
+// collector already has them.
+func (w *IPFIXWriter) newMessage(exportTime time.Time) *ipfixMessage {
+	msg := &ipfixMessage{buf: make([]byte, ipfixHeaderLen, ipfixMaxMessageSize)}
+	binary.BigEndian.PutUint16(msg.buf[0:], ipfixVersion)
+	binary.BigEndian.PutUint32(msg.buf[4:], uint32(exportTime.Unix()))
+	binary.BigEndian.PutUint32(msg.buf[8:], w.sequence)
+	binary.BigEndian.PutUint32(msg.buf[12:], w.cfg.ObservationDomain)
+	if w.cfg.Transport == "udp" || !w.templatesSent {
+		msg.addTemplates()
+	}
+	return msg
+}
+
+// send writes msg, dropping the connection on failure so the next snapshot reconnects.
+func (w *IPFIXWriter) send(msg *ipfixMessage) error {
+	binary.BigEndian.PutUint16(msg.buf[2:], uint16(len(msg.buf)))
+	if _, err := w.conn.Write(msg.buf); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return fmt.Errorf("failed to send ipfix message to %s: %w", w.cfg.Address, err)
+	}
+	w.templatesSent = true
+	w.sequence += uint32(msg.records)
+	return nil
+}
+
+// ipfixMessage is an IPFIX message under construction.
+type ipfixMessage struct {
+	buf     []byte
+	setID   uint16 // template of the open data set; zero when none is open
+	setAt   int    // offset of the open data set's header
+	records int
+}
+
+func (m *ipfixMessage) addTemplates() {
+	start := len(m.buf)
+	m.buf = binary.BigEndian.AppendUint16(m.buf, ipfixTemplateSetID)
+	m.buf = binary.BigEndian.AppendUint16(m.buf, 0)
+	for _, tmpl := range []struct {
+		id       uint16
+		elements []ipfixElement
+	}{{ipfixIPv4TemplateID, ipfixIPv4Template}, {ipfixIPv6TemplateID, ipfixIPv6Template}} {
+		m.buf = binary.BigEndian.AppendUint16(m.buf, tmpl.id)
+		m.buf = binary.BigEndian.AppendUint16(m.buf, uint16(len(tmpl.elements)))
+		for _, e := range tmpl.elements {
+			m.buf = binary.BigEndian.AppendUint16(m.buf, e.id)
+			m.buf = binary.BigEndian.AppendUint16(m.buf, e.length)
+		}
+	}
+	binary.BigEndian.PutUint16(m.buf[start+2:], uint16(len(m.buf)-start))
+}
+
+// fits reports whether a record of the given template and size still fits.
+func (m *ipfixMessage) fits(templateID uint16, size int) bool {
+	if templateID != m.setID {
+		size += ipfixSetHeaderLen
+	}
+	return m.records == 0 || len(m.buf)+size <= ipfixMaxMessageSize
+}
+
+func (m *ipfixMessage) add(templateID uint16, record []byte) {
+	if templateID != m.setID {
+		m.setID, m.setAt = templateID, len(m.buf)
+		m.buf = binary.BigEndian.AppendUint16(m.buf, templateID)
+		m.buf = binary.BigEndian.AppendUint16(m.buf, 0)
+	}
+	m.buf = append(m.buf, record...)
+	binary.BigEndian.PutUint16(m.buf[m.setAt+2:], uint16(len(m.buf)-m.setAt))
+	m.records++
+}
+
+// encodeIPFIXRecord encodes flow with the IPv6 template when either address
+// is IPv6 and with the IPv4 template otherwise.
+func encodeIPFIXRecord(flow *statistic.Flow) (uint16, []byte) {
+	src, dst := ipfixAddr(flow.Fields, "SrcIP"), ipfixAddr(flow.Fields, "DstIP")
+	var templateID uint16
+	var record []byte
+	if src.To4() != nil && dst.To4() != nil {
+		templateID = ipfixIPv4TemplateID
+		record = append(append(make([]byte, 0, 57), src.To4()...), dst.To4()...)
+	} else {
+		templateID = ipfixIPv6TemplateID
+		record = append(append(make([]byte, 0, 81), src.To16()...), dst.To16()...)
+	}
+
+	record = binary.BigEndian.AppendUint16(record, uint16(ipfixUint(flow.Fields, "SrcPort")))
+	record = binary.BigEndian.AppendUint16(record, uint16(ipfixUint(flow.Fields, "DstPort")))
+	record = append(record, uint8(ipfixUint(flow.Fields, "Protocol")))
+	record = binary.BigEndian.AppendUint16(record, uint16(flow.TCPFlags))
+	record = binary.BigEndian.AppendUint32(record, uint32(ipfixUint(flow.Fields, "InterfaceID")))
+	record = binary.BigEndian.AppendUint16(record, uint16(ipfixUint(flow.Fields, "OuterVLAN")))
+	// Label in the top 20 bits, then the experimental bits and the bottom-of-stack bit.
+	var mpls uint32
+	if label := uint32(ipfixUint(flow.Fields, "MPLSLabel")); label != 0 {
+		mpls = label<<4 | 1
+	}
+	record = append(record, byte(mpls>>16), byte(mpls>>8), byte(mpls))
+	record = binary.BigEndian.AppendUint64(record, uint64(flow.StartTime.UnixMilli()))
+	record = binary.BigEndian.AppendUint64(record, uint64(flow.EndTime.UnixMilli()))
+	record = binary.BigEndian.AppendUint64(record, flow.ByteCount)
+	record = binary.BigEndian.AppendUint64(record, flow.PacketCount)
+	return templateID, append(record, ipfixEndReason(flow.EndReason))
+}
+
+// ipfixEndReason maps an end reason to its flowEndReason code. Flows still in
+// progress are sent as 0, which RFC 5102 leaves unassigned.
+func ipfixEndReason(reason statistic.EndReason) uint8 {
+	switch reason {
+	case statistic.EndReasonIdleTimeout:
+		return 0x01
+	case statistic.EndReasonActiveTimeout:
+		return 0x02
+	case statistic.EndReasonEndOfFlow:
+		return 0x03
+	case statistic.EndReasonEvicted:
+		return 0x05 // lack of resources
+	default:
+		return 0
+	}
+}
+
+// ipfixAddr returns the address stored under key, or the unspecified IPv4
+// address when the task does not aggregate on it.
+func ipfixAddr(fields map[string]interface{}, key string) net.IP {
+	if s, ok := fields[key].(string); ok {
+		if ip := net.ParseIP(s); ip != nil {
+			return ip
+		}
+		// Tasks keyed on a prefix such as SrcIP/24 export the network.
+		if ip, _, err := net.ParseCIDR(s); err == nil {
+			return ip
+		}
+	}
+	return net.IPv4zero
+}
+
+// ipfixUint returns the unsigned field stored under key, or zero.
+func ipfixUint(fields map[string]interface{}, key string) uint64 {
+	switch v := fields[key].(type) {
+	case uint8:
+		return uint64(v)
+	case uint16:
+		return uint64(v)
+	case uint32:
+		return uint64(v)
+	case uint64:
+		return v
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,150 @@
+package exact
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/collector"
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/model"
+)
+
+func ipfixTestSnapshot(flows ...*statistic.Flow) statistic.SnapshotData {
+	shard := &statistic.Shard{Flows: make(map[string]*statistic.Flow)}
+	for _, flow := range flows {
+		shard.Flows[flow.Key] = flow
+	}
+	return statistic.SnapshotData{TaskName: "ipfix", Shards: []*statistic.Shard{shard}}
+}
+
+// channelSink hands the records a collector decodes to the test.
+type channelSink chan *model.PacketInfo
+
+func (s channelSink) Enqueue(packet *model.PacketInfo) bool {
+	s <- packet
+	return true
+}
+
+func TestIPFIXWriterExportsToCollector(t *testing.T) {
+	out := make(channelSink, 64)
+	c, err := collector.New(config.CollectorConfig{IPFIXAddr: "127.0.0.1:0"}, out)
+	if err != nil {
+		t.Fatalf("collector.New() unexpected error: %v", err)
+	}
+	if err := c.Start(); err != nil {
+		t.Fatalf("Start() unexpected error: %v", err)
+	}
+	defer c.Stop()
+
+	writer, err := NewIPFIXWriter(config.IPFIXConfig{Address: c.IPFIXAddr().String()}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewIPFIXWriter() unexpected error: %v", err)
+	}
+
+	start := time.UnixMilli(1700000000000)
+	// Enough flows to need several UDP messages.
+	var flows []*statistic.Flow
+	for i := range 40 {
+		flows = append(flows, &statistic.Flow{
+			Key:         string(rune('a' + i)),
+			Fields:      map[string]interface{}{"SrcIP": "10.0.0.1", "DstIP": "10.0.0.2", "DstPort": uint16(1000 + i), "Protocol": uint8(6)},
+			StartTime:   start,
+			EndTime:     start.Add(3 * time.Second),
+			ByteCount:   1500,
+			PacketCount: 3,
+			TCPFlags:    0x12,
+		})
+	}
+	flows = append(flows, &statistic.Flow{
+		Key:         "v6",
+		Fields:      map[string]interface{}{"SrcIP": "2001:db8::1", "MPLSLabel": uint32(16), "OuterVLAN": uint16(20)},
+		StartTime:   start,
+		EndTime:     start,
+		ByteCount:   64,
+		PacketCount: 1,
+	})
+	if err := writer.Write(ipfixTestSnapshot(flows...), "2023-11-14_22-13-20", model.Window{}, "ipfix", nil, nil); err != nil {
+		t.Fatalf("Write() unexpected error: %v", err)
+	}
+
+	var ports uint64
+	for range flows {
+		select {
+		case got := <-out:
+			if got.FiveTuple.SrcIP.Equal(net.ParseIP("2001:db8::1")) {
+				if got.MPLSLabel != 16 || got.OuterVLAN != 20 || got.Packets != 1 {
+					t.Fatalf("v6 record label/vlan/packets = %d/%d/%d, want 16/20/1", got.MPLSLabel, got.OuterVLAN, got.Packets)
+				}
+				continue
+			}
+			if got.Packets != 3 || got.Length != 1500 || got.TCPFlags != 0x12 || got.Duration != 3*time.Second {
+				t.Fatalf("record packets/bytes/flags/duration = %d/%d/%#x/%v, want 3/1500/0x12/3s", got.Packets, got.Length, got.TCPFlags, got.Duration)
+			}
+			ports += uint64(got.FiveTuple.DstPort)
+		case <-time.After(5 * time.Second):
+			t.Fatal("collector did not receive every exported flow")
+		}
+	}
+	if want := uint64(40*1000 + 40*39/2); ports != want {
+		t.Fatalf("sum of exported ports = %d, want %d", ports, want)
+	}
+	if got := c.Stats().Datagrams.Load(); got < 2 {
+		t.Fatalf("Stats().Datagrams = %d, want several messages", got)
+	}
+}
+
+func TestIPFIXWriterSendsTemplatesOncePerTCPConnection(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen() unexpected error: %v", err)
+	}
+	defer ln.Close()
+
+	writer, err := NewIPFIXWriter(config.IPFIXConfig{Address: ln.Addr().String(), Transport: "tcp", ObservationDomain: 7}, time.Minute)
+	if err != nil {
+		t.Fatalf("NewIPFIXWriter() unexpected error: %v", err)
+	}
+	flow := &statistic.Flow{Key: "f", Fields: map[string]interface{}{"SrcIP": "10.0.0.1"}, PacketCount: 1, ByteCount: 60}
+	for range 2 {
+		if err := writer.Write(ipfixTestSnapshot(flow), "2023-11-14_22-13-20", model.Window{}, "ipfix", nil, nil); err != nil {
+			t.Fatalf("Write() unexpected error: %v", err)
+		}
+	}
+
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() unexpected error: %v", err)
+	}
+	defer conn.Close()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	for i, wantTemplates := range []bool{true, false} {
+		header := make([]byte, ipfixHeaderLen)
+		if _, err := io.ReadFull(conn, header); err != nil {
+			t.Fatalf("read message %d header: %v", i, err)
+		}
+		body := make([]byte, int(binary.BigEndian.Uint16(header[2:]))-ipfixHeaderLen)
+		if _, err := io.ReadFull(conn, body); err != nil {
+			t.Fatalf("read message %d body: %v", i, err)
+		}
+		if seq, domain := binary.BigEndian.Uint32(header[8:]), binary.BigEndian.Uint32(header[12:]); seq != uint32(i) || domain != 7 {
+			t.Fatalf("message %d sequence/domain = %d/%d, want %d/7", i, seq, domain, i)
+		}
+		if hasTemplates := binary.BigEndian.Uint16(body) == ipfixTemplateSetID; hasTemplates != wantTemplates {
+			t.Fatalf("message %d carries templates = %v, want %v", i, hasTemplates, wantTemplates)
+		}
+	}
+}
+
+func TestNewIPFIXWriterValidatesConfig(t *testing.T) {
+	if _, err := NewIPFIXWriter(config.IPFIXConfig{}, time.Minute); err == nil {
+		t.Fatal("NewIPFIXWriter(no address) error = nil, want non-nil")
+	}
+	if _, err := NewIPFIXWriter(config.IPFIXConfig{Address: "127.0.0.1:4739", Transport: "sctp"}, time.Minute); err == nil {
+		t.Fatal("NewIPFIXWriter(sctp) error = nil, want non-nil")
+	}
+}
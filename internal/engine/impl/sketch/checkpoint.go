@@ -0,0 +1,28 @@
+package sketch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// Checkpoint writes the largest sampling rate seen, then the sketch.
+func (t *Task) Checkpoint(w io.Writer) error {
+	if _, err := w.Write(binary.BigEndian.AppendUint32(nil, t.sampleRate.Load())); err != nil {
+		return err
+	}
+	return t.sketch.Checkpoint(w)
+}
+
+// Restore loads what Checkpoint wrote, replacing the sketch's seeds and table.
+func (t *Task) Restore(r io.Reader) error {
+	var sampleRate [4]byte
+	if _, err := io.ReadFull(r, sampleRate[:]); err != nil {
+		return fmt.Errorf("failed to read sketch task state: %w", err)
+	}
+	if err := t.sketch.Restore(r); err != nil {
+		return err
+	}
+	t.sampleRate.Store(binary.BigEndian.Uint32(sampleRate[:]))
+	return nil
+}
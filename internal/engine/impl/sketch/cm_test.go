@@ -16,6 +16,15 @@ import (
 	"Go2NetSpectra/pkg/pcap"
 )
 
+func mustNew(t testing.TB, cfg config.SketchTaskDef) model.Task {
+	t.Helper()
+	task, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+	return task
+}
+
 func TestCountMin(t *testing.T) {
 	pcapFilePath := "../../../../test/data/caida.pcap"
 	pcapReader, err := pcap.NewReader(pcapFilePath)
@@ -41,7 +50,7 @@ func TestCountMin(t *testing.T) {
 		CountThreshold: Counthreshold,
 	}
 
-	task := New(cfg)
+	task := mustNew(t, cfg)
 
 	// Ground truth (map-based)
 	countMap := make(map[string]int)
@@ -176,7 +185,7 @@ func TestCountMinFixtureProducesHeavyHitterSnapshot(t *testing.T) {
 		CountThreshold: 1,
 	}
 
-	task := New(cfg)
+	task := mustNew(t, cfg)
 	feedFixturePackets(t, "../../../../test/data/test.pcap", task)
 
 	snapshot, ok := task.Snapshot().(statistic.HeavyRecord)
@@ -258,3 +267,82 @@ func evaluateHeavyHitters(detected map[string]uint32, truth map[string]int) (mre
 
 	return
 }
+
+func TestCountMinScalesSampledPackets(t *testing.T) {
+	task := mustNew(t, config.SketchTaskDef{
+		Name:           "sampled-heavy-hitter",
+		SketchType:     0,
+		FlowFields:     []string{"SrcIP"},
+		Width:          64,
+		Depth:          2,
+		SizeThreshold:  1,
+		CountThreshold: 1,
+	})
+
+	packet := &model.PacketInfo{
+		FiveTuple:  model.FiveTuple{SrcIP: net.ParseIP("192.0.2.1").To16(), DstIP: net.ParseIP("198.51.100.10").To16()},
+		Length:     100,
+		SampleRate: 50,
+	}
+	task.ProcessPacket(packet)
+	task.ProcessPacket(packet)
+
+	snapshot := task.Snapshot().(statistic.HeavyRecord)
+	if len(snapshot.Count) != 1 || snapshot.Count[0].Count != 100 {
+		t.Fatalf("Snapshot() count hitters = %+v, want one flow with count 100", snapshot.Count)
+	}
+	if len(snapshot.Size) != 1 || snapshot.Size[0].Size != 10000 {
+		t.Fatalf("Snapshot() size hitters = %+v, want one flow with size 10000", snapshot.Size)
+	}
+	if snapshot.SampleRate != 50 {
+		t.Fatalf("Snapshot() sample rate = %d, want 50", snapshot.SampleRate)
+	}
+
+	task.Reset()
+	if rate := task.Snapshot().(statistic.HeavyRecord).SampleRate; rate != 1 {
+		t.Fatalf("Snapshot() sample rate after Reset() = %d, want 1", rate)
+	}
+}
+
+func TestNewRejectsInvalidTaskDefs(t *testing.T) {
+	for _, cfg := range []config.SketchTaskDef{
+		{Name: "type", SketchType: 2, FlowFields: []string{"SrcIP"}},
+		{Name: "flow", FlowFields: []string{"SrcMAC"}},
+		{Name: "element", SketchType: 1, FlowFields: []string{"SrcIP"}, ElementFields: []string{"DstIP", "DstIP/24"}},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Fatalf("New(%s) error = nil, want non-nil", cfg.Name)
+		}
+	}
+}
+
+func TestCountMinAggregatesPrefixes(t *testing.T) {
+	task := mustNew(t, config.SketchTaskDef{
+		Name:           "per-subnet",
+		SketchType:     0,
+		FlowFields:     []string{"SrcIP/24/64"},
+		Width:          64,
+		Depth:          2,
+		SizeThreshold:  1,
+		CountThreshold: 1,
+	}).(*Task)
+
+	for _, src := range []string{"192.0.2.1", "192.0.2.200", "2001:db8:0:1::1", "2001:db8:0:1::2"} {
+		task.ProcessPacket(&model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: net.ParseIP(src)}, Length: 100})
+	}
+
+	snapshot := task.Snapshot().(statistic.HeavyRecord)
+	got := make(map[string]uint32)
+	for _, hitter := range snapshot.Count {
+		got[task.DecodeFlow(hitter.Flow, task.Fields())] = hitter.Count
+	}
+	want := map[string]uint32{"192.0.2.0/24": 2, "2001:db8:0:1::/64": 2}
+	if len(got) != len(want) {
+		t.Fatalf("Snapshot() count hitters = %v, want %v", got, want)
+	}
+	for flow, count := range want {
+		if got[flow] != count {
+			t.Fatalf("Snapshot() count hitters = %v, want %v", got, want)
+		}
+	}
+}
@@ -30,7 +30,7 @@ func TestMultiProcess(t *testing.T) {
 	CountThreshold := uint32(4096)
 	SizeThreshold := uint32(4096 * 1024)
 
-	task := New(config.SketchTaskDef{
+	task := mustNew(t, config.SketchTaskDef{
 		Name:           "per_src_flow",
 		SketchType:     0,
 		FlowFields:     []string{"SrcIP"},
@@ -210,7 +210,7 @@ func TestMultiProcessSS(t *testing.T) {
 		B:              1.08,
 	}
 
-	task := New(cfg)
+	task := mustNew(t, cfg)
 
 	// Ground truth (map-based)
 	spreadMap := make(map[string]map[string]bool)
@@ -44,7 +44,7 @@ func TestSuperSpread(t *testing.T) {
 		B:              1.08,
 	}
 
-	task := New(cfg)
+	task := mustNew(t, cfg)
 
 	// Ground truth (map-based)
 	spreadMap := make(map[string]map[string]bool)
@@ -137,7 +137,7 @@ func TestSuperSpread(t *testing.T) {
 }
 
 func TestSuperSpreadResetKeepsTaskUsable(t *testing.T) {
-	task := New(config.SketchTaskDef{
+	task := mustNew(t, config.SketchTaskDef{
 		Name:           "fixture-super-spread",
 		SketchType:     1,
 		FlowFields:     []string{"DstIP"},
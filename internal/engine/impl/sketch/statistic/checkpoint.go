@@ -0,0 +1,149 @@
+package statistic
+
+import (
+	"encoding/gob"
+	"fmt"
+	"io"
+	"math"
+	"sync/atomic"
+)
+
+// countMinState is a CountMin table flattened row by row. Fingerprints are
+// FlowSize bytes each.
+type countMinState struct {
+	Width, Depth, FlowSize uint32
+	Seed                   []uint32
+	Size, Count            []uint32
+	SizeFP, CountFP        []byte
+}
+
+// Checkpoint writes the seeds and buckets of the sketch.
+func (t *CountMin) Checkpoint(w io.Writer) error {
+	state := countMinState{Width: t.w, Depth: t.d, FlowSize: t.flowSize(), Seed: t.seed}
+	n := int(t.w * t.d)
+	state.Size = make([]uint32, 0, n)
+	state.Count = make([]uint32, 0, n)
+	state.SizeFP = make([]byte, 0, n*int(state.FlowSize))
+	state.CountFP = make([]byte, 0, n*int(state.FlowSize))
+	for i := range t.table {
+		for j := range t.table[i] {
+			bucket := &t.table[i][j]
+			state.Size = append(state.Size, atomic.LoadUint32(&bucket.Size.S))
+			state.Count = append(state.Count, atomic.LoadUint32(&bucket.Count.C))
+			state.SizeFP = append(state.SizeFP, bucket.Size.FP...)
+			state.CountFP = append(state.CountFP, bucket.Count.FP...)
+		}
+	}
+	return gob.NewEncoder(w).Encode(state)
+}
+
+// Restore replaces the seeds and buckets of the sketch with a checkpoint of
+// one with the same dimensions.
+func (t *CountMin) Restore(r io.Reader) error {
+	var state countMinState
+	if err := gob.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode CountMin state: %w", err)
+	}
+	if state.Width != t.w || state.Depth != t.d || state.FlowSize != t.flowSize() {
+		return fmt.Errorf("checkpoint is a %dx%d CountMin of %d-byte flows, sketch is %dx%d of %d-byte flows",
+			state.Depth, state.Width, state.FlowSize, t.d, t.w, t.flowSize())
+	}
+	n := int(t.w * t.d)
+	fs := int(state.FlowSize)
+	if len(state.Seed) != int(t.d) || len(state.Size) != n || len(state.Count) != n || len(state.SizeFP) != n*fs || len(state.CountFP) != n*fs {
+		return fmt.Errorf("CountMin state does not match its dimensions")
+	}
+	copy(t.seed, state.Seed)
+	for i := range t.table {
+		for j := range t.table[i] {
+			k := i*int(t.w) + j
+			bucket := &t.table[i][j]
+			bucket.Size.S = state.Size[k]
+			bucket.Count.C = state.Count[k]
+			copy(bucket.Size.FP, state.SizeFP[k*fs:])
+			copy(bucket.Count.FP, state.CountFP[k*fs:])
+		}
+	}
+	return nil
+}
+
+// flowSize returns the fingerprint length the sketch was created with.
+func (t *CountMin) flowSize() uint32 {
+	return uint32(len(t.table[0][0].Count.FP))
+}
+
+// superSpreadState is a SuperSpread table flattened row by row, with the
+// registers, seeds and sampling probability of each bucket's HLL.
+type superSpreadState struct {
+	Width, Depth, FlowSize, M uint32
+	Seeds                     []uint32
+	Keys                      []byte
+	Values                    []uint32
+	Registers                 []uint32 // M per bucket
+	HLLSeeds                  []uint32 // M+1 per bucket
+	P                         []float64
+}
+
+// Checkpoint writes the seeds, keys, values and HLLs of the sketch.
+func (ss *SuperSpread) Checkpoint(w io.Writer) error {
+	m := ss.m()
+	state := superSpreadState{Width: ss.w, Depth: ss.d, FlowSize: ss.flowSize(), M: m, Seeds: ss.seeds}
+	n := int(ss.w * ss.d)
+	state.Keys = make([]byte, 0, n*int(state.FlowSize))
+	state.Values = make([]uint32, 0, n)
+	state.Registers = make([]uint32, 0, n*int(m))
+	state.HLLSeeds = make([]uint32, 0, n*int(m+1))
+	state.P = make([]float64, 0, n)
+	for i := range ss.cm {
+		for j, hll := range ss.cm[i] {
+			state.Keys = append(state.Keys, ss.keys[i][j]...)
+			state.Values = append(state.Values, atomic.LoadUint32(&ss.values[i][j]))
+			for k := range hll.hll {
+				state.Registers = append(state.Registers, atomic.LoadUint32(&hll.hll[k]))
+			}
+			state.HLLSeeds = append(state.HLLSeeds, hll.seeds...)
+			state.P = append(state.P, math.Float64frombits(atomic.LoadUint64(&hll.pbits)))
+		}
+	}
+	return gob.NewEncoder(w).Encode(state)
+}
+
+// Restore replaces the state of the sketch with a checkpoint of one with the
+// same dimensions.
+func (ss *SuperSpread) Restore(r io.Reader) error {
+	var state superSpreadState
+	if err := gob.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode SuperSpread state: %w", err)
+	}
+	m := ss.m()
+	if state.Width != ss.w || state.Depth != ss.d || state.FlowSize != ss.flowSize() || state.M != m {
+		return fmt.Errorf("checkpoint is a %dx%d SuperSpread of %d-byte flows and m %d, sketch is %dx%d of %d-byte flows and m %d",
+			state.Depth, state.Width, state.FlowSize, state.M, ss.d, ss.w, ss.flowSize(), m)
+	}
+	n := int(ss.w * ss.d)
+	fs := int(state.FlowSize)
+	if len(state.Seeds) != int(ss.d) || len(state.Keys) != n*fs || len(state.Values) != n ||
+		len(state.Registers) != n*int(m) || len(state.HLLSeeds) != n*int(m+1) || len(state.P) != n {
+		return fmt.Errorf("SuperSpread state does not match its dimensions")
+	}
+	copy(ss.seeds, state.Seeds)
+	for i := range ss.cm {
+		for j, hll := range ss.cm[i] {
+			k := i*int(ss.w) + j
+			copy(ss.keys[i][j], state.Keys[k*fs:])
+			ss.values[i][j] = state.Values[k]
+			copy(hll.hll, state.Registers[k*int(m):])
+			copy(hll.seeds, state.HLLSeeds[k*int(m+1):])
+			hll.pbits = math.Float64bits(state.P[k])
+		}
+	}
+	return nil
+}
+
+func (ss *SuperSpread) m() uint32 {
+	return ss.cm[0][0].m
+}
+
+func (ss *SuperSpread) flowSize() uint32 {
+	return uint32(len(ss.keys[0][0]))
+}
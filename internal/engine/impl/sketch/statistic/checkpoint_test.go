@@ -0,0 +1,70 @@
+package statistic
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCountMinCheckpointRoundTrip(t *testing.T) {
+	cm := NewCountMin(64, 3, 1, 1, 4)
+	heavy, light := []byte{1, 2, 3, 4}, []byte{5, 6, 7, 8}
+	for i := 0; i < 10; i++ {
+		cm.Insert(heavy, nil, 100, 1)
+	}
+	cm.Insert(light, nil, 40, 1)
+
+	var buf bytes.Buffer
+	if err := cm.Checkpoint(&buf); err != nil {
+		t.Fatalf("Checkpoint() unexpected error: %v", err)
+	}
+	restored := NewCountMin(64, 3, 1, 1, 4)
+	if err := restored.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	for _, flow := range [][]byte{heavy, light} {
+		if got, want := restored.Query(flow), cm.Query(flow); got != want {
+			t.Fatalf("restored Query(%v) = %d, want %d", flow, got, want)
+		}
+	}
+	// Restored seeds send further inserts to the same buckets.
+	cm.Insert(heavy, nil, 100, 1)
+	restored.Insert(heavy, nil, 100, 1)
+	if got, want := restored.Query(heavy), cm.Query(heavy); got != want {
+		t.Fatalf("Query() after insert = %d, want %d", got, want)
+	}
+
+	if err := NewCountMin(32, 3, 1, 1, 4).Restore(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatalf("Restore() into a narrower sketch error = nil, want non-nil")
+	}
+}
+
+func TestSuperSpreadCheckpointRoundTrip(t *testing.T) {
+	ss := NewSuperSpread(16, 2, 1, 16, 5, 0.5, 1.08, 2)
+	for i := 0; i < 200; i++ {
+		ss.Insert([]byte{0, 1}, []byte{byte(i), byte(i >> 8)}, 0, 1)
+	}
+
+	var buf bytes.Buffer
+	if err := ss.Checkpoint(&buf); err != nil {
+		t.Fatalf("Checkpoint() unexpected error: %v", err)
+	}
+	restored := NewSuperSpread(16, 2, 1, 16, 5, 0.5, 1.08, 2)
+	if err := restored.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	if got, want := restored.Query([]byte{0, 1}), ss.Query([]byte{0, 1}); got != want {
+		t.Fatalf("restored Query() = %d, want %d", got, want)
+	}
+	// Elements already counted do not raise the restored estimate again.
+	before := restored.Query([]byte{0, 1})
+	for i := 0; i < 200; i++ {
+		restored.Insert([]byte{0, 1}, []byte{byte(i), byte(i >> 8)}, 0, 1)
+	}
+	if got := restored.Query([]byte{0, 1}); got != before {
+		t.Fatalf("Query() after repeated elements = %d, want %d", got, before)
+	}
+
+	if err := NewSuperSpread(16, 2, 1, 32, 5, 0.5, 1.08, 2).Restore(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatalf("Restore() into a sketch with other m error = nil, want non-nil")
+	}
+}
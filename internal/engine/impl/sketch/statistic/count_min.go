@@ -91,7 +91,7 @@ func NewCountMin(width, depth, st, ct uint32, FS uint32) *CountMin {
 }
 
 // Insert updates the sketch with one flow observation.
-func (t *CountMin) Insert(flow, elem []byte, size uint32) {
+func (t *CountMin) Insert(flow, elem []byte, size, count uint32) {
 	for i := 0; i < int(t.d); i++ {
 		index := MurmurHash3(flow, t.seed[i]) % t.w
 		bucket := &t.table[i][index]
@@ -131,23 +131,30 @@ func (t *CountMin) Insert(flow, elem []byte, size uint32) {
 			bcount := &bucket.Count
 			currentC := atomic.LoadUint32(&bcount.C)
 			if currentC == 0 {
-				if atomic.CompareAndSwapUint32(&bcount.C, 0, 1) {
+				if atomic.CompareAndSwapUint32(&bcount.C, 0, count) {
 					copy(bcount.FP, flow)
 					break
 				}
 			} else {
 				if bytes.Equal(bcount.FP, flow) {
-					newC := currentC + 1
+					newC := currentC + count
 					if atomic.CompareAndSwapUint32(&bcount.C, currentC, newC) {
 						break
 					}
 				} else {
-					newC := currentC - 1
-					if atomic.CompareAndSwapUint32(&bcount.C, currentC, newC) {
-						if newC == 0 {
+					if count > currentC {
+						if atomic.CompareAndSwapUint32(&bcount.C, currentC, count) {
 							copy(bcount.FP, flow)
+							break
+						}
+					} else {
+						newC := currentC - count
+						if atomic.CompareAndSwapUint32(&bcount.C, currentC, newC) {
+							if newC == 0 {
+								copy(bcount.FP, flow)
+							}
+							break
 						}
-						break
 					}
 				}
 			}
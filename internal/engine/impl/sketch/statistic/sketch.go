@@ -1,12 +1,19 @@
 package statistic
 
+import "io"
+
 // Sketch defines the interface for a sketch data structure.
 // It supports insertion of elements, querying flow metrics, and retrieving top-k elements.
+// Insert adds size bytes and count packets to flow; count is above one when the
+// observation stands for several sampled packets. Checkpoint saves the table
+// and hash seeds, which Restore loads into a sketch of the same dimensions.
 type Sketch interface {
-	Insert(flow, elem []byte, size uint32)
+	Insert(flow, elem []byte, size, count uint32)
 	Query(flow []byte) uint64
 	HeavyHitters() HeavyRecord
 	Reset()
+	Checkpoint(w io.Writer) error
+	Restore(r io.Reader) error
 }
 
 // HeavySize stores a heavy-hitter flow and its estimated byte size.
@@ -25,4 +32,7 @@ type HeavyCount struct {
 type HeavyRecord struct {
 	Size  []HeavySize
 	Count []HeavyCount
+	// SampleRate is the largest probe sampling rate inserted since the last
+	// reset; above one the values are estimates scaled up from sampled traffic.
+	SampleRate uint32
 }
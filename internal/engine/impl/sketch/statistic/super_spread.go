@@ -178,8 +178,9 @@ func NewSuperSpread(width, depth, threshold, m, size uint32, base, b float64, FS
 	return ss
 }
 
-// Insert records one flow-element observation in the sketch.
-func (ss *SuperSpread) Insert(flow, elem []byte, size uint32) {
+// Insert records one flow-element observation in the sketch. Spread counts
+// distinct elements, so size and count are not used.
+func (ss *SuperSpread) Insert(flow, elem []byte, size, count uint32) {
 	mergedLen := len(flow) + len(elem)
 	var mergedBuf [maxMergedFieldSize]byte
 	merged := mergedBuf[:0]
@@ -1,18 +1,20 @@
 package sketch
 
 import (
-	"encoding/binary"
 	"fmt"
 	"log"
-	"net"
-	"strconv"
+	"math"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/filter"
 	"Go2NetSpectra/internal/engine/impl/sketch/statistic"
+	"Go2NetSpectra/internal/engine/window"
 	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/keyfield"
 	"Go2NetSpectra/internal/model"
 )
 
@@ -24,7 +26,8 @@ func init() {
 
 		// Create all enabled writers for this aggregator group
 		writers := make([]model.Writer, 0, len(sketchCfg.Writers))
-		for _, writerDef := range sketchCfg.Writers {
+		writerDefs := make([]config.WriterDef, 0, len(sketchCfg.Writers))
+		for i, writerDef := range sketchCfg.Writers {
 			if !writerDef.Enabled {
 				continue
 			}
@@ -41,7 +44,7 @@ func init() {
 				writer = NewTextWriter(writerDef.Text.RootPath, interval)
 				log.Printf("Text writer created at %s", writerDef.Text.RootPath)
 			case "clickhouse":
-				writer, err = NewClickHouseWriter(writerDef.ClickHouse, interval)
+				writer, err = NewClickHouseWriter(writerDef.ClickHouse, interval, cfg.Aggregator.Cluster.ID())
 				if err != nil {
 					log.Printf("Warning: failed to create writer type '%s': %v, skipping.", writerDef.Type, err)
 					continue
@@ -51,86 +54,134 @@ func init() {
 				log.Printf("Warning: unknown writer type '%s' in sketch aggregator config, skipping.", writerDef.Type)
 				continue
 			}
+			writerDef.Name = factory.WriterName("sketch", i, writerDef)
 			writers = append(writers, writer)
+			writerDefs = append(writerDefs, writerDef)
 		}
 
 		// Create all tasks for this aggregator group
 		tasks := make([]model.Task, len(sketchCfg.Tasks))
 		for i, taskCfg := range sketchCfg.Tasks {
-			tasks[i] = New(taskCfg)
+			match, err := filter.Compile(taskCfg.Filter)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			flowKeys, elemKeys, err := resolveTaskDef(taskCfg)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			task, err := window.NewTask(taskCfg.Window, func() model.Task {
+				return filter.Wrap(match, newTask(taskCfg, flowKeys, elemKeys))
+			})
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", taskCfg.Name, err)
+			}
+			tasks[i] = task
 		}
 
-		return &factory.TaskGroup{Tasks: tasks, Writers: writers}, nil
+		return &factory.TaskGroup{Tasks: tasks, Writers: writers, WriterDefs: writerDefs}, nil
 	})
 }
 
 // --- Task Implementation ---
 
-const (
-	ipv6ByteSize  = 16
-	portByteSize  = 2
-	protoByteSize = 1
-
-	maxFieldSize = 37 // IPv6(16) + IPv6(16) + Port(2) + Port(2) + Proto(1) = 37
-)
-
+// Pooled key buffers fit every registered field once; keyBuffer grows them
+// for wider keys.
 var (
 	flowPool = sync.Pool{
 		New: func() any {
-			return make([]byte, maxFieldSize)
+			return make([]byte, keyfield.Size(keyfield.All()))
 		},
 	}
 	elemPool = sync.Pool{
 		New: func() any {
-			return make([]byte, maxFieldSize)
+			return make([]byte, keyfield.Size(keyfield.All()))
 		},
 	}
 )
 
+// keyBuffer takes a buffer of size bytes from pool.
+func keyBuffer(pool *sync.Pool, size uint32) []byte {
+	buf := pool.Get().([]byte)
+	if uint32(cap(buf)) < size {
+		buf = make([]byte, size)
+	}
+	return buf[:size]
+}
+
 // Task performs sketch-based aggregation for a configured flow definition.
 type Task struct {
 	name string
 	// flow key fields
 	flowFields []string
+	flowKeys   []*keyfield.Field
 	// the byte size of flow key
 	flowSize uint32
 	// element key fields
 	elementFields []string
+	elemKeys      []*keyfield.Field
 	// the byte size of element key
 	elemSize uint32
 	// data
 	sketch statistic.Sketch
+	// largest probe sampling rate seen since the last reset
+	sampleRate atomic.Uint32
+}
+
+// Values of SketchTaskDef.SketchType.
+const (
+	sketchCountMin    = 0
+	sketchSuperSpread = 1
+)
+
+// New creates a new Sketch task based on the provided configuration. It
+// returns an error for an unknown sketch type or fields not registered in
+// keyfield.
+func New(cfg config.SketchTaskDef) (model.Task, error) {
+	flowKeys, elemKeys, err := resolveTaskDef(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("sketch task '%s': %w", cfg.Name, err)
+	}
+	return newTask(cfg, flowKeys, elemKeys), nil
 }
 
-// New creates a new Sketch task based on the provided configuration.
-func New(cfg config.SketchTaskDef) model.Task {
-	flowSize := uint32(0)
-	for _, f := range cfg.FlowFields {
-		flowSize += fieldByteSize(f)
+// resolveTaskDef checks the sketch type of cfg and resolves its fields.
+func resolveTaskDef(cfg config.SketchTaskDef) (flowKeys, elemKeys []*keyfield.Field, err error) {
+	if cfg.SketchType != sketchCountMin && cfg.SketchType != sketchSuperSpread {
+		return nil, nil, fmt.Errorf("unknown sketch type %d", cfg.SketchType)
 	}
-	elemSize := uint32(0)
-	for _, f := range cfg.ElementFields {
-		elemSize += fieldByteSize(f)
+	if flowKeys, err = keyfield.Resolve(cfg.FlowFields); err != nil {
+		return nil, nil, fmt.Errorf("flow fields: %w", err)
 	}
+	if elemKeys, err = keyfield.Resolve(cfg.ElementFields); err != nil {
+		return nil, nil, fmt.Errorf("element fields: %w", err)
+	}
+	return flowKeys, elemKeys, nil
+}
+
+// newTask creates a sketch task for a definition resolveTaskDef accepted.
+func newTask(cfg config.SketchTaskDef, flowKeys, elemKeys []*keyfield.Field) *Task {
+	flowSize := uint32(keyfield.Size(flowKeys))
+	elemSize := uint32(keyfield.Size(elemKeys))
 
 	var sketchImpl statistic.Sketch
 	switch cfg.SketchType {
-	case 0: // CountMin
+	case sketchCountMin:
 		log.Printf("Creating CountMin Sketch '%s' for:\n\tflow fields %v (bytes %d)\n\telement fields %v (bytes %d) with width %d, depth %d, size_thereshold %d, count_thereshold %d\n",
 			cfg.Name, cfg.FlowFields, flowSize, cfg.ElementFields, elemSize, cfg.Width, cfg.Depth, cfg.SizeThreshold, cfg.CountThreshold)
 		sketchImpl = statistic.NewCountMin(cfg.Width, cfg.Depth, cfg.SizeThreshold, cfg.CountThreshold, flowSize)
-	case 1: // SuperSpread
+	default: // sketchSuperSpread
 		log.Printf("Creating SuperSpread Sketch '%s' for:\n\tflow fields %v (bytes %d)\n\telement fields %v (bytes %d) with width %d, depth %d, threshold %d, m %d, size %d, base %.2f, b %.2f\n",
 			cfg.Name, cfg.FlowFields, flowSize, cfg.ElementFields, elemSize, cfg.Width, cfg.Depth, cfg.CountThreshold, cfg.M, cfg.Size, cfg.Base, cfg.B)
 		sketchImpl = statistic.NewSuperSpread(cfg.Width, cfg.Depth, cfg.CountThreshold, cfg.M, cfg.Size, cfg.Base, cfg.B, flowSize)
-	default:
-		log.Fatalf("Unknown sketch type: %d for task %s", cfg.SketchType, cfg.Name)
 	}
 
 	return &Task{
 		name:          cfg.Name,
 		flowFields:    cfg.FlowFields,
+		flowKeys:      flowKeys,
 		elementFields: cfg.ElementFields,
+		elemKeys:      elemKeys,
 		flowSize:      flowSize,
 		elemSize:      elemSize,
 		sketch:        sketchImpl,
@@ -154,18 +205,24 @@ func (t *Task) DecodeFlowFunc() func(flow []byte, fields []string) string {
 
 // ProcessPacket processes a single packet, creating or updating a flow in the correct shard.
 func (t *Task) ProcessPacket(packetInfo *model.PacketInfo) {
-	flow := flowPool.Get().([]byte)[:t.flowSize]
-	elem := elemPool.Get().([]byte)[:t.elemSize]
+	flow := keyBuffer(&flowPool, t.flowSize)
+	elem := keyBuffer(&elemPool, t.elemSize)
 	defer flowPool.Put(flow)
 	defer elemPool.Put(elem)
 
-	err := t.generateFlowAndElem(flow, elem, &packetInfo.FiveTuple)
-	if err != nil {
-		log.Printf("Error generating key for task '%s': %v", t.name, err)
-		return
-	}
+	encodeKey(flow, t.flowKeys, packetInfo)
+	encodeKey(elem, t.elemKeys, packetInfo)
 
-	t.sketch.Insert(flow, elem, uint32(packetInfo.Length))
+	// Sampled packets stand for Weight() packets of the original traffic, and
+	// flow records for all the packets they summarise.
+	weight := uint32(packetInfo.Weight())
+	for {
+		current := t.sampleRate.Load()
+		if weight <= current || t.sampleRate.CompareAndSwap(current, weight) {
+			break
+		}
+	}
+	t.sketch.Insert(flow, elem, saturateUint32(packetInfo.ByteCount()), saturateUint32(packetInfo.PacketCount()))
 }
 
 // Query returns the current sketch estimate for the provided encoded flow.
@@ -175,12 +232,15 @@ func (t *Task) Query(flow []byte) uint64 {
 
 // Snapshot returns the current heavy hitter snapshot from the underlying sketch.
 func (t *Task) Snapshot() any {
-	return t.sketch.HeavyHitters()
+	record := t.sketch.HeavyHitters()
+	record.SampleRate = max(t.sampleRate.Load(), 1)
+	return record
 }
 
 // Reset clears the internal state of the task, preparing for a new measurement period.
 func (t *Task) Reset() {
 	t.sketch.Reset()
+	t.sampleRate.Store(0)
 }
 
 // AlerterMsg formats the sketch snapshot into alert content for matching rules.
@@ -261,42 +321,13 @@ func check(value, threshold float64, operator string) bool {
 	}
 }
 
-// generateFlowAndElem creates Flow and Element keys based on the configured fields.
-func (t *Task) generateFlowAndElem(flow, elem []byte, ft *model.FiveTuple) error {
+// encodeKey writes the fields of a packet into buf, one after another.
+func encodeKey(buf []byte, fields []*keyfield.Field, packetInfo *model.PacketInfo) {
 	offset := 0
-	for _, f := range t.flowFields {
-		offset = t.EncodeFlow(flow, offset, f, ft)
-	}
-	offset = 0
-	for _, f := range t.elementFields {
-		offset = t.EncodeFlow(elem, offset, f, ft)
-	}
-
-	return nil
-}
-
-// EncodeFlow appends a configured five-tuple field into buf and returns the next offset.
-func (t *Task) EncodeFlow(buf []byte, offset int, field string, ft *model.FiveTuple) int {
-	switch field {
-	case "SrcIP":
-		copy(buf[offset:], ft.SrcIP)
-		offset += ipv6ByteSize
-	case "DstIP":
-		copy(buf[offset:], ft.DstIP)
-		offset += ipv6ByteSize
-	case "SrcPort":
-		buf[offset] = byte(ft.SrcPort >> 8)
-		buf[offset+1] = byte(ft.SrcPort & 0xFF)
-		offset += portByteSize
-	case "DstPort":
-		buf[offset] = byte(ft.DstPort >> 8)
-		buf[offset+1] = byte(ft.DstPort & 0xFF)
-		offset += portByteSize
-	case "Protocol":
-		buf[offset] = byte(ft.Protocol)
-		offset += protoByteSize
+	for _, field := range fields {
+		field.Encode(buf[offset:], packetInfo)
+		offset += field.Size
 	}
-	return offset
 }
 
 // DecodeFlow converts an encoded flow back into a human-readable string.
@@ -304,35 +335,22 @@ func (t *Task) DecodeFlow(flow []byte, fields []string) string {
 	var parts []string
 	offset := 0
 
-	for _, f := range fields {
-		switch f {
-		case "SrcIP", "DstIP":
-			ip := net.IP(flow[offset : offset+ipv6ByteSize])
-			parts = append(parts, ip.String())
-			offset += ipv6ByteSize
-		case "SrcPort", "DstPort":
-			port := binary.BigEndian.Uint16(flow[offset : offset+portByteSize])
-			parts = append(parts, strconv.Itoa(int(port)))
-			offset += portByteSize
-		case "Protocol":
-			proto := uint8(flow[offset])
-			parts = append(parts, strconv.Itoa(int(proto)))
-			offset += protoByteSize
+	for _, name := range fields {
+		field, ok := keyfield.Lookup(name)
+		if !ok || offset+field.Size > len(flow) {
+			break
 		}
+		parts = append(parts, field.Format(field.Decode(flow[offset:])))
+		offset += field.Size
 	}
 
 	return strings.Join(parts, " ")
 }
 
-func fieldByteSize(field string) uint32 {
-	switch field {
-	case "SrcIP", "DstIP":
-		return ipv6ByteSize
-	case "SrcPort", "DstPort":
-		return portByteSize
-	case "Protocol":
-		return protoByteSize
-	default:
-		return 0
+// saturateUint32 clamps a count to the sketch counter width.
+func saturateUint32(v uint64) uint32 {
+	if v > math.MaxUint32 {
+		return math.MaxUint32
 	}
+	return uint32(v)
 }
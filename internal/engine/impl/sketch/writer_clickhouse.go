@@ -21,20 +21,49 @@ CREATE TABLE IF NOT EXISTS heavy_hitters (
     TaskName    String,
     Flow        String,
     Value       UInt64,
-	Type		UInt8
+	Type		UInt8,
+    SampleRate  UInt32 DEFAULT 1,
+    EngineID    LowCardinality(String),
+    WindowStart DateTime,
+    WindowEnd   DateTime
 ) ENGINE = MergeTree()
 PARTITION BY toYYYYMM(Timestamp)
 ORDER BY (TaskName, Timestamp);
 `
 
+// createInputStatsTableStatement must match the exact writer's, since both
+// write the engine input counters to the same table.
+const createInputStatsTableStatement = `
+CREATE TABLE IF NOT EXISTS engine_input_stats (
+    Timestamp     DateTime,
+    EngineID      LowCardinality(String),
+    Enqueued      UInt64,
+    Dropped       UInt64,
+    Processed     UInt64,
+    QueueDepth    UInt32,
+    QueueCapacity UInt32
+) ENGINE = ReplacingMergeTree()
+PARTITION BY toYYYYMM(Timestamp)
+ORDER BY (EngineID, Timestamp);
+`
+
+// migrateHeavyHittersStatements bring tables created by older releases up to the current column set.
+var migrateHeavyHittersStatements = []string{
+	"ALTER TABLE heavy_hitters ADD COLUMN IF NOT EXISTS SampleRate UInt32 DEFAULT 1 AFTER Type",
+	"ALTER TABLE heavy_hitters ADD COLUMN IF NOT EXISTS EngineID LowCardinality(String) AFTER SampleRate",
+	"ALTER TABLE heavy_hitters ADD COLUMN IF NOT EXISTS WindowStart DateTime AFTER EngineID",
+	"ALTER TABLE heavy_hitters ADD COLUMN IF NOT EXISTS WindowEnd DateTime AFTER WindowStart",
+}
+
 // ClickHouseWriter implements the model.Writer interface for ClickHouse.
 type ClickHouseWriter struct {
 	conn     driver.Conn
 	interval time.Duration
+	engineID string
 }
 
-// NewClickHouseWriter creates a new ClickHouse writer for heavy hitters.
-func NewClickHouseWriter(cfg config.ClickHouseConfig, interval time.Duration) (model.Writer, error) {
+// NewClickHouseWriter creates a new ClickHouse writer for heavy hitters, tagging each row with engineID.
+func NewClickHouseWriter(cfg config.ClickHouseConfig, interval time.Duration, engineID string) (model.Writer, error) {
 	conn, err := connect(cfg)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to clickhouse: %w", err)
@@ -43,9 +72,17 @@ func NewClickHouseWriter(cfg config.ClickHouseConfig, interval time.Duration) (m
 	if err := conn.Exec(context.Background(), createHeavyHittersTableStatement); err != nil {
 		return nil, fmt.Errorf("failed to create heavy_hitters table: %w", err)
 	}
+	for _, stmt := range migrateHeavyHittersStatements {
+		if err := conn.Exec(context.Background(), stmt); err != nil {
+			return nil, fmt.Errorf("failed to migrate heavy_hitters table: %w", err)
+		}
+	}
+	if err := conn.Exec(context.Background(), createInputStatsTableStatement); err != nil {
+		return nil, fmt.Errorf("failed to create engine_input_stats table: %w", err)
+	}
 	log.Println("Successfully connected to ClickHouse and ensured heavy_hitters table exists.")
 
-	return &ClickHouseWriter{conn: conn, interval: interval}, nil
+	return &ClickHouseWriter{conn: conn, interval: interval, engineID: engineID}, nil
 }
 
 // Interval returns the configured snapshot interval for this writer.
@@ -53,6 +90,11 @@ func (w *ClickHouseWriter) Interval() time.Duration {
 	return w.interval
 }
 
+// Close closes the ClickHouse connection.
+func (w *ClickHouseWriter) Close() error {
+	return w.conn.Close()
+}
+
 func connect(cfg config.ClickHouseConfig) (driver.Conn, error) {
 	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
 
@@ -83,7 +125,7 @@ func connect(cfg config.ClickHouseConfig) (driver.Conn, error) {
 	return conn, nil
 }
 
-func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+func (w *ClickHouseWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
 	heavyHitters, ok := payload.(statistic.HeavyRecord)
 	if !ok {
 		return fmt.Errorf("invalid payload type for clickhouse writer: expected statistic.HeavyRecord, got %T", payload)
@@ -100,12 +142,13 @@ func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fi
 	}
 
 	snapshotTime, _ := time.Parse("2006-01-02_15-04-05", timestamp)
+	sampleRate := max(heavyHitters.SampleRate, 1)
 
 	if heavyHitters.Size != nil {
 		// size
 		for _, hitter := range heavyHitters.Size {
 			flow := decodeFlowFunc(hitter.Flow, fields)
-			err = batch.Append(snapshotTime, name, flow, hitter.Size, 1)
+			err = batch.Append(snapshotTime, name, flow, hitter.Size, 1, sampleRate, w.engineID, window.Start, window.End)
 			if err != nil {
 				return fmt.Errorf("failed to append heavy hitter to batch: %w", err)
 			}
@@ -113,7 +156,7 @@ func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fi
 		// count
 		for _, hitter := range heavyHitters.Count {
 			flow := decodeFlowFunc(hitter.Flow, fields)
-			err = batch.Append(snapshotTime, name, flow, hitter.Count, 0)
+			err = batch.Append(snapshotTime, name, flow, hitter.Count, 0, sampleRate, w.engineID, window.Start, window.End)
 			if err != nil {
 				return fmt.Errorf("failed to append heavy hitter to batch: %w", err)
 			}
@@ -122,7 +165,7 @@ func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fi
 		// count
 		for _, hitter := range heavyHitters.Count {
 			flow := decodeFlowFunc(hitter.Flow, fields)
-			err = batch.Append(snapshotTime, name, flow, hitter.Count, 2)
+			err = batch.Append(snapshotTime, name, flow, hitter.Count, 2, sampleRate, w.engineID, window.Start, window.End)
 			if err != nil {
 				return fmt.Errorf("failed to append heavy hitter to batch: %w", err)
 			}
@@ -136,3 +179,14 @@ func (w *ClickHouseWriter) Write(payload interface{}, timestamp, name string, fi
 	log.Printf("Wrote %d heavy hitters to ClickHouse", total)
 	return nil
 }
+
+// WriteStats records the engine's input counters in engine_input_stats.
+func (w *ClickHouseWriter) WriteStats(stats model.InputStats, timestamp string) error {
+	snapshotTime, _ := time.Parse("2006-01-02_15-04-05", timestamp)
+	err := w.conn.Exec(context.Background(), "INSERT INTO engine_input_stats VALUES (?, ?, ?, ?, ?, ?, ?)",
+		snapshotTime, w.engineID, stats.Enqueued, stats.Dropped, stats.Processed, uint32(stats.QueueDepth), uint32(stats.QueueCapacity))
+	if err != nil {
+		return fmt.Errorf("failed to insert input stats: %w", err)
+	}
+	return nil
+}
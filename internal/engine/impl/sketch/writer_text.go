@@ -28,7 +28,7 @@ func (w *TextWriter) Interval() time.Duration {
 }
 
 // Write writes heavy hitter results to text files under the configured snapshot directory.
-func (w *TextWriter) Write(payload interface{}, timestamp, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+func (w *TextWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
 	heavyHitters, ok := payload.(statistic.HeavyRecord)
 	if !ok {
 		return fmt.Errorf("invalid payload type for text writer: expected statistic.HeavyRecord, got %T", payload)
@@ -0,0 +1,165 @@
+package manager
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/checkpoint"
+	"Go2NetSpectra/internal/engine/window"
+	"Go2NetSpectra/internal/model"
+)
+
+// checkpoints says where and how often task state is saved.
+type checkpoints struct {
+	dir      string
+	interval time.Duration // 0 only checkpoints on stop
+}
+
+// newCheckpoints returns nil when checkpoints are disabled.
+func newCheckpoints(cfg config.CheckpointConfig) (*checkpoints, error) {
+	if cfg.Dir == "" {
+		return nil, nil
+	}
+	c := &checkpoints{dir: cfg.Dir}
+	if cfg.Interval != "" {
+		interval, err := time.ParseDuration(cfg.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint interval: %w", err)
+		}
+		if interval <= 0 {
+			return nil, fmt.Errorf("checkpoint interval must be a positive duration")
+		}
+		c.interval = interval
+	}
+	return c, nil
+}
+
+// path returns the checkpoint file of a task.
+func (c *checkpoints) path(aggType, name string) string {
+	return filepath.Join(c.dir, aggType, name+".ckpt")
+}
+
+// taskFingerprints fingerprints the definition of every configured task,
+// keyed by "<aggregator type>/<task name>".
+func taskFingerprints(cfg *config.Config) (map[string]string, error) {
+	fingerprints := make(map[string]string)
+	for _, aggType := range cfg.Aggregator.Types {
+		for _, def := range taskDefs(cfg, aggType) {
+			fingerprint, err := checkpoint.Fingerprint(def.def)
+			if err != nil {
+				return nil, fmt.Errorf("task '%s': %w", def.name, err)
+			}
+			fingerprints[aggType+"/"+def.name] = fingerprint
+		}
+	}
+	return fingerprints, nil
+}
+
+// restoreCheckpoints loads the saved state of every task whose definition is
+// unchanged. Tasks without their own windows are only restored if the period
+// they were saved in is still running at now, which then continues instead of
+// a new one starting. The caller starts no worker before this returns.
+func (m *Manager) restoreCheckpoints(now time.Time) {
+	restored := 0
+	var periodStart time.Time
+	for _, group := range m.currentGroups() {
+		for _, task := range group.Tasks {
+			name := group.Type + "/" + task.Name()
+			checkpointer, ok := task.(model.Checkpointer)
+			if !ok {
+				continue
+			}
+			file, err := checkpoint.Load(m.checkpoints.path(group.Type, task.Name()))
+			if errors.Is(err, os.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				log.Printf("Warning: not restoring task %s: %v", name, err)
+				continue
+			}
+			if file.Header.Definition != m.fingerprints[name] {
+				log.Printf("Not restoring task %s: its definition changed since the checkpoint.", name)
+				continue
+			}
+			if _, windowed := task.(*window.Task); !windowed {
+				saved := file.Header.PeriodStart
+				if file.Header.Period != m.period || !now.Before(saved.Add(m.period)) {
+					log.Printf("Not restoring task %s: the period it was saved in has ended.", name)
+					continue
+				}
+				if !periodStart.IsZero() && !saved.Equal(periodStart) {
+					log.Printf("Warning: not restoring task %s: it was saved in another period than the tasks already restored.", name)
+					continue
+				}
+				periodStart = saved
+			}
+			if err := file.Restore(checkpointer); err != nil {
+				log.Printf("Warning: %v", err)
+				task.Reset()
+				continue
+			}
+			restored++
+		}
+	}
+	if !periodStart.IsZero() {
+		m.periodStart.Store(periodStart.UnixNano())
+	}
+	log.Printf("Restored %d tasks from checkpoints in %s.", restored, m.checkpoints.dir)
+}
+
+// runCheckpointer saves every task each checkpoint interval until the
+// manager stops, which takes the last checkpoint itself.
+func (m *Manager) runCheckpointer() {
+	defer m.checkpointerWg.Done()
+	ticker := time.NewTicker(m.checkpoints.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.writeCheckpoints()
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// writeCheckpoints saves the state of every task that supports it.
+func (m *Manager) writeCheckpoints() {
+	// A checkpoint must not straddle a period reset, or the state would be
+	// saved under the wrong period.
+	m.resetMu.Lock()
+	defer m.resetMu.Unlock()
+
+	m.groupsMu.RLock()
+	groups, fingerprints := m.taskGroups, m.fingerprints
+	m.groupsMu.RUnlock()
+
+	header := checkpoint.Header{
+		PeriodStart: time.Unix(0, m.periodStart.Load()),
+		Period:      m.period,
+		SavedAt:     time.Now(),
+	}
+	written := 0
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			checkpointer, ok := task.(model.Checkpointer)
+			if !ok {
+				continue
+			}
+			header.Task = group.Type + "/" + task.Name()
+			header.Definition = fingerprints[header.Task]
+			if err := checkpoint.Write(m.checkpoints.path(group.Type, task.Name()), header, checkpointer); err != nil {
+				log.Printf("Error writing checkpoint for task %s: %v", header.Task, err)
+				continue
+			}
+			written++
+		}
+	}
+	log.Printf("Checkpointed %d tasks to %s.", written, m.checkpoints.dir)
+}
@@ -0,0 +1,69 @@
+package manager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+)
+
+func checkpointTestConfig(t *testing.T, dir string, tasks ...string) *config.Config {
+	t.Helper()
+	cfg := reloadTestConfig(t.TempDir(), tasks...)
+	cfg.Aggregator.Checkpoint = config.CheckpointConfig{Dir: dir}
+	return cfg
+}
+
+func TestManagerRestoresCheckpointAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	m := startReloadTestManager(t, checkpointTestConfig(t, dir, "kept", "changed"))
+	m.Stop()
+	if _, err := os.Stat(filepath.Join(dir, "exact", "kept.ckpt")); err != nil {
+		t.Fatalf("checkpoint of kept not written on stop: %v", err)
+	}
+
+	cfg := checkpointTestConfig(t, dir, "kept", "changed")
+	cfg.Aggregator.Exact.Tasks[1].KeyFields = []string{"DstIP"}
+	restarted, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager() unexpected error: %v", err)
+	}
+	restarted.Start()
+	defer restarted.Stop()
+
+	if got := flowCount(t, taskByName(restarted, "kept")); got != 1 {
+		t.Fatalf("kept flows after restart = %d, want 1", got)
+	}
+	if got := flowCount(t, taskByName(restarted, "changed")); got != 0 {
+		t.Fatalf("changed flows after restart = %d, want 0", got)
+	}
+}
+
+func TestManagerSkipsCheckpointOfEndedPeriod(t *testing.T) {
+	dir := t.TempDir()
+	m := startReloadTestManager(t, checkpointTestConfig(t, dir, "kept"))
+	m.Stop()
+
+	restarted, err := NewManager(checkpointTestConfig(t, dir, "kept"))
+	if err != nil {
+		t.Fatalf("NewManager() unexpected error: %v", err)
+	}
+	// The 1h period the checkpoint was saved in is over two hours later.
+	restarted.restoreCheckpoints(time.Now().Add(2 * time.Hour))
+	if got := flowCount(t, taskByName(restarted, "kept")); got != 0 {
+		t.Fatalf("kept flows = %d, want 0", got)
+	}
+}
+
+func TestNewCheckpointsRejectsInvalidInterval(t *testing.T) {
+	for _, interval := range []string{"soon", "-1m", "0s"} {
+		if _, err := newCheckpoints(config.CheckpointConfig{Dir: "x", Interval: interval}); err == nil {
+			t.Fatalf("newCheckpoints(%q) error = nil, want non-nil", interval)
+		}
+	}
+	if c, err := newCheckpoints(config.CheckpointConfig{}); c != nil || err != nil {
+		t.Fatalf("newCheckpoints(empty) = %v, %v, want nil, nil", c, err)
+	}
+}
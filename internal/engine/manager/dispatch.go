@@ -0,0 +1,89 @@
+package manager
+
+import (
+	"log"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// Values of aggregator.dispatch.
+const (
+	dispatchShared = "shared" // every worker takes the next packet from one channel
+	dispatchFlow   = "flow"   // packets are hashed to workers by flow
+)
+
+// dispatchHashSeed keeps worker choice independent of probe partitioning and
+// flow sampling, which hash the same tuples with their own seeds. An engine
+// fed one probe partition only gets tuples whose partition hash agrees modulo
+// the partition count, so sharing that seed would leave workers idle.
+const dispatchHashSeed = 0xc2b2ae3d27d4eb4f
+
+// partitionTasks gives the tasks that support it one partition per worker.
+func partitionTasks(tasks []model.Task, workers int) {
+	for _, task := range tasks {
+		if partitioned, ok := task.(model.PartitionedTask); ok {
+			partitioned.Partition(workers)
+		}
+	}
+}
+
+// newWorkerQueues splits the capacity of the input channel between workers.
+func newWorkerQueues(workers, capacity int) []chan *model.PacketInfo {
+	queues := make([]chan *model.PacketInfo, workers)
+	for i := range queues {
+		queues[i] = make(chan *model.PacketInfo, max(1, capacity/workers))
+	}
+	return queues
+}
+
+// startFlowWorkers starts a dispatcher that hashes each packet's bidirectional
+// five-tuple to a worker queue, and a worker per queue. Both directions of a
+// connection go to the same worker, so a task keyed by the whole tuple sees
+// each flow in one partition; other keys are merged across partitions by
+// Snapshot.
+func (m *Manager) startFlowWorkers() {
+	m.workerWg.Add(len(m.workerQueues) + 1)
+	go m.dispatchFlows(m.workerQueues)
+	for i, queue := range m.workerQueues {
+		go m.flowWorker(i, queue)
+	}
+}
+
+// dispatchFlows routes packets from the input channel until it is closed,
+// then closes the worker queues.
+func (m *Manager) dispatchFlows(queues []chan *model.PacketInfo) {
+	defer m.workerWg.Done()
+	defer func() {
+		for _, queue := range queues {
+			close(queue)
+		}
+	}()
+	n := uint64(len(queues))
+	for packet := range m.packetChannel {
+		if packet == nil {
+			log.Printf("Manager failed to process packet: nil packet")
+			continue
+		}
+		queues[packet.FiveTuple.SymmetricHash(dispatchHashSeed)%n] <- packet
+	}
+}
+
+// flowWorker applies the packets of its queue to its partition of each task.
+func (m *Manager) flowWorker(worker int, queue <-chan *model.PacketInfo) {
+	defer m.workerWg.Done()
+	for packet := range queue {
+		m.groupsMu.RLock()
+		for i, group := range m.taskGroups {
+			for j, task := range group.Tasks {
+				m.taskCounters[i][j].Inc()
+				if partitioned, ok := task.(model.PartitionedTask); ok {
+					partitioned.ProcessPacketOn(worker, packet)
+				} else {
+					task.ProcessPacket(packet)
+				}
+			}
+		}
+		m.groupsMu.RUnlock()
+		m.processed.Add(1)
+	}
+}
@@ -0,0 +1,117 @@
+package manager
+
+import (
+	"net"
+	"sync"
+	"testing"
+
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/model"
+	"Go2NetSpectra/internal/probe"
+)
+
+// workerTask records which worker saw each flow.
+type workerTask struct {
+	stubTask
+	partitions int
+	workers    map[uint64]map[int]bool
+}
+
+func (w *workerTask) Partition(workers int) {
+	w.partitions = workers
+}
+
+func (w *workerTask) ProcessPacketOn(worker int, packet *model.PacketInfo) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	flow := packet.FiveTuple.SymmetricHash(0)
+	if w.workers[flow] == nil {
+		w.workers[flow] = make(map[int]bool)
+	}
+	w.workers[flow][worker] = true
+}
+
+func TestFlowDispatchKeepsEachFlowOnOneWorker(t *testing.T) {
+	task := &workerTask{workers: make(map[uint64]map[int]bool)}
+	partitionTasks([]model.Task{task}, 4)
+	if task.partitions != 4 {
+		t.Fatalf("Partition() workers = %d, want 4", task.partitions)
+	}
+	groups := []factory.TaskGroup{{Tasks: []model.Task{task}}}
+	m := &Manager{
+		taskGroups:    groups,
+		taskCounters:  taskPacketCounters(groups),
+		packetChannel: make(chan *model.PacketInfo, 8),
+		done:          make(chan struct{}),
+		numWorkers:    4,
+		period:        1 << 62,
+		flowDispatch:  true,
+		workerQueues:  newWorkerQueues(4, 8),
+	}
+	m.Start()
+
+	a, b := net.ParseIP("192.0.2.1"), net.ParseIP("198.51.100.1")
+	var wg sync.WaitGroup
+	for sender := 0; sender < 4; sender++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for port := uint16(1); port <= 64; port++ {
+				m.InputChannel() <- &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: a, DstIP: b, SrcPort: port, DstPort: 443, Protocol: 6}}
+				m.InputChannel() <- &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: b, DstIP: a, SrcPort: 443, DstPort: port, Protocol: 6}}
+			}
+		}()
+	}
+	wg.Wait()
+	m.Stop()
+
+	if len(task.workers) != 64 {
+		t.Fatalf("flows seen = %d, want 64", len(task.workers))
+	}
+	for flow, workers := range task.workers {
+		if len(workers) != 1 {
+			t.Fatalf("flow %x was processed by workers %v, want one", flow, workers)
+		}
+	}
+	if got := m.InputStats().Processed; got != 512 {
+		t.Fatalf("processed packets = %d, want 512", got)
+	}
+}
+
+func TestFlowDispatchSpreadsOnePartitionOverAllWorkers(t *testing.T) {
+	const workers, partitions = 8, 4
+	task := &workerTask{workers: make(map[uint64]map[int]bool)}
+	groups := []factory.TaskGroup{{Tasks: []model.Task{task}}}
+	m := &Manager{
+		taskGroups:    groups,
+		taskCounters:  taskPacketCounters(groups),
+		packetChannel: make(chan *model.PacketInfo, 8),
+		done:          make(chan struct{}),
+		numWorkers:    workers,
+		period:        1 << 62,
+		flowDispatch:  true,
+		workerQueues:  newWorkerQueues(workers, 8),
+	}
+	partitionTasks([]model.Task{task}, workers)
+	m.Start()
+
+	// Only the flows of one probe partition reach this engine.
+	a, b := net.ParseIP("192.0.2.1"), net.ParseIP("198.51.100.1")
+	for port := uint16(1); port <= 1024; port++ {
+		packet := &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: a, DstIP: b, SrcPort: port, DstPort: 443, Protocol: 6}}
+		if probe.Partition(packet, partitions) == 1 {
+			m.InputChannel() <- packet
+		}
+	}
+	m.Stop()
+
+	busy := make(map[int]bool)
+	for _, seen := range task.workers {
+		for worker := range seen {
+			busy[worker] = true
+		}
+	}
+	if len(busy) != workers {
+		t.Fatalf("workers with traffic = %d, want all %d", len(busy), workers)
+	}
+}
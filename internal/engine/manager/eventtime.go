@@ -0,0 +1,212 @@
+package manager
+
+import (
+	"container/heap"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/window"
+	"Go2NetSpectra/internal/model"
+)
+
+// eventClock runs the manager on packet time. Packets wait in a buffer until
+// the watermark, the latest timestamp seen minus the allowed lateness, passes
+// them, and are then applied in timestamp order. Before a packet is applied,
+// every window close, writer snapshot and period reset due at or before its
+// timestamp is carried out, so results follow the capture's own timeline no
+// matter how fast it is replayed.
+type eventClock struct {
+	lateness time.Duration
+
+	mu        sync.Mutex // serializes packets, so they are applied one at a time
+	pending   packetHeap
+	maxSeen   time.Time
+	now       time.Time // timestamp of the last applied packet
+	started   bool
+	periodEnd time.Time
+	nextSweep time.Time                  // next check for flows that ended
+	snapshots map[model.Writer]time.Time // next snapshot of each writer
+	late      uint64
+}
+
+// newEventClock returns nil when event time is disabled.
+func newEventClock(cfg config.EventTimeConfig) (*eventClock, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	var lateness time.Duration
+	if cfg.AllowedLateness != "" {
+		var err error
+		if lateness, err = time.ParseDuration(cfg.AllowedLateness); err != nil {
+			return nil, fmt.Errorf("invalid event_time allowed_lateness: %w", err)
+		}
+		if lateness < 0 {
+			return nil, fmt.Errorf("event_time allowed_lateness must not be negative")
+		}
+	}
+	return &eventClock{lateness: lateness, snapshots: make(map[model.Writer]time.Time)}, nil
+}
+
+// packetHeap orders buffered packets by timestamp.
+type packetHeap []*model.PacketInfo
+
+func (h packetHeap) Len() int           { return len(h) }
+func (h packetHeap) Less(i, j int) bool { return h[i].Timestamp.Before(h[j].Timestamp) }
+func (h packetHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *packetHeap) Push(x any)        { *h = append(*h, x.(*model.PacketInfo)) }
+func (h *packetHeap) Pop() any {
+	old := *h
+	packet := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return packet
+}
+
+// processEventPacket buffers packet and applies every buffered packet the
+// watermark has passed. Packets older than one already applied are dropped.
+func (m *Manager) processEventPacket(packet *model.PacketInfo) {
+	c := m.eventTime
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.started && packet.Timestamp.Before(c.now) {
+		c.late++
+		return
+	}
+	heap.Push(&c.pending, packet)
+	if packet.Timestamp.After(c.maxSeen) {
+		c.maxSeen = packet.Timestamp
+	}
+	m.releaseEventPackets(c.maxSeen.Add(-c.lateness))
+}
+
+// releaseEventPackets applies the buffered packets up to watermark, oldest
+// first. The caller holds c.mu.
+func (m *Manager) releaseEventPackets(watermark time.Time) {
+	c := m.eventTime
+	for c.pending.Len() > 0 && !c.pending[0].Timestamp.After(watermark) {
+		packet := heap.Pop(&c.pending).(*model.PacketInfo)
+		m.advanceEventTime(packet.Timestamp)
+		m.applyPacket(packet)
+	}
+}
+
+// advanceEventTime moves packet time forward to now, carrying out everything
+// due on the way in time order. The first packet starts the clock. The caller
+// holds c.mu.
+func (m *Manager) advanceEventTime(now time.Time) {
+	c := m.eventTime
+	if !c.started {
+		m.startEventTime(now)
+	}
+	c.now = now
+
+	for {
+		next := c.periodEnd
+		if c.nextSweep.Before(next) {
+			next = c.nextSweep
+		}
+		for _, group := range m.currentGroups() {
+			for _, task := range group.Tasks {
+				if windowed, ok := task.(*window.Task); ok && windowed.NextClose().Before(next) {
+					next = windowed.NextClose()
+				}
+			}
+			for _, writer := range group.Writers {
+				if at, ok := c.snapshots[writer]; ok && at.Before(next) {
+					next = at
+				}
+			}
+		}
+		if next.After(now) {
+			return
+		}
+		m.runEventBoundary(next)
+	}
+}
+
+// startEventTime aligns the period, the writer snapshots and the windows to
+// the first packet. The caller holds c.mu.
+func (m *Manager) startEventTime(first time.Time) {
+	c := m.eventTime
+	c.started = true
+	start := floorTime(first, m.period)
+	m.periodStart.Store(start.UnixNano())
+	c.periodEnd = start.Add(m.period)
+	c.nextSweep = first.Add(flowSweepInterval)
+	for _, group := range m.currentGroups() {
+		for _, task := range group.Tasks {
+			if windowed, ok := task.(*window.Task); ok {
+				windowed.Align(first)
+			}
+		}
+		for _, writer := range group.Writers {
+			if interval := writer.Interval(); interval > 0 {
+				c.snapshots[writer] = floorTime(first, interval).Add(interval)
+			}
+		}
+	}
+	log.Printf("Event time started at %s.", first.Format("2006-01-02_15-04-05"))
+}
+
+// runEventBoundary closes the windows, sweeps the flows that ended, takes
+// the snapshots and resets the period due at at. Windows close and flows end
+// before snapshots, and snapshots are taken before the period they cover is
+// reset. The caller holds c.mu.
+func (m *Manager) runEventBoundary(at time.Time) {
+	c := m.eventTime
+	groups := m.currentGroups()
+	if !c.nextSweep.After(at) {
+		m.sweepFlows(at)
+		c.nextSweep = at.Add(flowSweepInterval)
+	}
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			if windowed, ok := task.(*window.Task); ok {
+				m.writeClosedWindows(group.Type, task, windowed.Advance(at))
+			}
+		}
+	}
+	for _, group := range groups {
+		for i, writer := range group.Writers {
+			if next, ok := c.snapshots[writer]; ok && !next.After(at) {
+				m.takeSnapshotAt(writer, groupWriterLabel(group, i), group.Tasks, at)
+				c.snapshots[writer] = at.Add(writer.Interval())
+			}
+		}
+	}
+	if !c.periodEnd.After(at) {
+		m.resetAllTasks(at)
+		c.periodEnd = at.Add(m.period)
+	}
+}
+
+// flushEventTime applies the packets still buffered and gives every writer a
+// final snapshot at the last packet's time, as a live engine does on stop.
+func (m *Manager) flushEventTime() {
+	c := m.eventTime
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pending.Len() > 0 {
+		m.releaseEventPackets(c.maxSeen)
+	}
+	if c.started {
+		for _, group := range m.currentGroups() {
+			for i, writer := range group.Writers {
+				if _, ok := c.snapshots[writer]; ok {
+					m.takeSnapshotAt(writer, groupWriterLabel(group, i), group.Tasks, c.now)
+				}
+			}
+		}
+	}
+	if c.late > 0 {
+		log.Printf("Dropped %d packets that arrived more than %s late.", c.late, c.lateness)
+	}
+}
+
+// floorTime returns the latest multiple of d since the Unix epoch at or
+// before t.
+func floorTime(t time.Time, d time.Duration) time.Time {
+	return window.Spec{Size: d, Hop: d}.Floor(t)
+}
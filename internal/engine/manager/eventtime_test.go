@@ -0,0 +1,143 @@
+package manager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/window"
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/model"
+)
+
+// countTask counts packets; its snapshot is the count.
+type countTask struct {
+	stubTask
+	count int
+}
+
+func (c *countTask) ProcessPacket(packet *model.PacketInfo) {
+	c.mu.Lock()
+	c.count++
+	c.mu.Unlock()
+}
+
+func (c *countTask) Snapshot() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.count
+}
+
+func (c *countTask) Reset() {
+	c.mu.Lock()
+	c.count = 0
+	c.mu.Unlock()
+}
+
+type row struct {
+	timestamp string
+	window    model.Window
+	payload   interface{}
+}
+
+// timelineWriter records every write.
+type timelineWriter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	rows     []row
+}
+
+func (w *timelineWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.rows = append(w.rows, row{timestamp: timestamp, window: window, payload: payload})
+	return nil
+}
+
+func (w *timelineWriter) Interval() time.Duration { return w.interval }
+
+func newEventManager(t *testing.T, lateness string, period time.Duration, group factory.TaskGroup) *Manager {
+	t.Helper()
+	clock, err := newEventClock(config.EventTimeConfig{Enabled: true, AllowedLateness: lateness})
+	if err != nil {
+		t.Fatalf("newEventClock() unexpected error: %v", err)
+	}
+	return &Manager{
+		taskGroups:    []factory.TaskGroup{group},
+		taskCounters:  taskPacketCounters([]factory.TaskGroup{group}),
+		packetChannel: make(chan *model.PacketInfo, 16),
+		done:          make(chan struct{}),
+		numWorkers:    1,
+		period:        period,
+		eventTime:     clock,
+	}
+}
+
+func stamp(t time.Time) string {
+	return t.Format("2006-01-02_15-04-05")
+}
+
+func TestEventTimeSnapshotsAndResetsFollowPackets(t *testing.T) {
+	base := time.Unix(1700000040, 0) // a multiple of 120s
+	writer := &timelineWriter{interval: time.Minute}
+	m := newEventManager(t, "", 2*time.Minute, factory.TaskGroup{
+		Type: "exact", Tasks: []model.Task{&countTask{}}, Writers: []model.Writer{writer},
+	})
+	m.Start()
+	for _, offset := range []time.Duration{10 * time.Second, 20 * time.Second, 70 * time.Second, 130 * time.Second} {
+		m.InputChannel() <- &model.PacketInfo{Timestamp: base.Add(offset)}
+	}
+	m.Stop()
+
+	period := model.Window{Start: base, End: base.Add(2 * time.Minute)}
+	next := model.Window{Start: base.Add(2 * time.Minute), End: base.Add(4 * time.Minute)}
+	want := []row{
+		{stamp(base.Add(time.Minute)), period, 2},
+		{stamp(base.Add(2 * time.Minute)), period, 3},
+		{stamp(base.Add(130 * time.Second)), next, 1},
+	}
+	if len(writer.rows) != len(want) {
+		t.Fatalf("rows = %+v, want %+v", writer.rows, want)
+	}
+	for i, got := range writer.rows {
+		if got.timestamp != want[i].timestamp || got.payload != want[i].payload ||
+			!got.window.Start.Equal(want[i].window.Start) || !got.window.End.Equal(want[i].window.End) {
+			t.Fatalf("row %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestEventTimeReordersWithinLatenessAndDropsLatePackets(t *testing.T) {
+	base := time.Unix(1700000040, 0)
+	writer := &timelineWriter{}
+	task := window.New(window.Spec{Size: time.Minute, Hop: time.Minute}, func() model.Task { return &countTask{} }, time.Now())
+	m := newEventManager(t, "30s", time.Hour, factory.TaskGroup{
+		Type: "exact", Tasks: []model.Task{task}, Writers: []model.Writer{writer},
+	})
+	m.Start()
+	for _, offset := range []time.Duration{10, 50, 40, 70, 100, 20, 65} {
+		m.InputChannel() <- &model.PacketInfo{Timestamp: base.Add(offset * time.Second)}
+	}
+	m.Stop()
+
+	if len(writer.rows) != 1 {
+		t.Fatalf("rows = %+v, want the first window only", writer.rows)
+	}
+	got := writer.rows[0]
+	if got.payload != 3 || !got.window.Start.Equal(base) || !got.window.End.Equal(base.Add(time.Minute)) {
+		t.Fatalf("closed window = %+v, want 3 packets in [%v, %v)", got, base, base.Add(time.Minute))
+	}
+	if m.eventTime.late != 2 {
+		t.Fatalf("late packets = %d, want 2", m.eventTime.late)
+	}
+}
+
+func TestNewEventClockRejectsNegativeLateness(t *testing.T) {
+	if _, err := newEventClock(config.EventTimeConfig{Enabled: true, AllowedLateness: "-1s"}); err == nil {
+		t.Fatal("newEventClock() error = nil, want non-nil")
+	}
+	if clock, err := newEventClock(config.EventTimeConfig{}); err != nil || clock != nil {
+		t.Fatalf("newEventClock(disabled) = %v, %v, want nil, nil", clock, err)
+	}
+}
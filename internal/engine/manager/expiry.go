@@ -0,0 +1,64 @@
+package manager
+
+import (
+	"log"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// flowSweepInterval is how often tasks are checked for flows that have ended
+// or were evicted.
+const flowSweepInterval = time.Second
+
+// expiresFlows reports whether task ends its own flows instead of being reset
+// with the period.
+func expiresFlows(task model.Task) bool {
+	expiring, ok := task.(model.ExpiringTask)
+	return ok && expiring.ExpiresFlows()
+}
+
+// runSweeper exports the flows that end, on the wall clock.
+func (m *Manager) runSweeper() {
+	defer m.sweeperWg.Done()
+	ticker := time.NewTicker(flowSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			m.sweepFlows(time.Now())
+		case <-m.done:
+			return
+		}
+	}
+}
+
+// sweepFlows writes the flows that have ended by now to the writers of their
+// aggregator, as finished records of the current period.
+func (m *Manager) sweepFlows(now time.Time) {
+	m.resetMu.Lock()
+	defer m.resetMu.Unlock()
+	start := time.Unix(0, m.periodStart.Load())
+	period := model.Window{Start: start, End: start.Add(m.period)}
+	timestamp := now.Format("2006-01-02_15-04-05")
+
+	for _, group := range m.currentGroups() {
+		for _, task := range group.Tasks {
+			expiring, ok := task.(model.ExpiringTask)
+			if !ok {
+				continue
+			}
+			expired := expiring.ExpireFlows(now)
+			if expired == nil {
+				continue
+			}
+			for i, writer := range group.Writers {
+				if err := writer.Write(expired, timestamp, period, task.Name(), task.Fields(), task.DecodeFlowFunc()); err != nil {
+					writerErrors.Inc(groupWriterLabel(group, i))
+					log.Printf("Error writing expired flows of task %s: %v", task.Name(), err)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package manager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/model"
+)
+
+// expiringTask hands out one batch of expired flows and counts resets.
+type expiringTask struct {
+	stubTask
+	mu      sync.Mutex
+	expired interface{}
+	resets  int
+}
+
+func (e *expiringTask) ExpiresFlows() bool { return true }
+
+func (e *expiringTask) ExpireFlows(now time.Time) interface{} {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	expired := e.expired
+	e.expired = nil
+	return expired
+}
+
+func (e *expiringTask) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.resets++
+}
+
+func TestSweepFlowsWritesExpiredFlowsOfThePeriod(t *testing.T) {
+	writer := &recordingWriter{}
+	task := &expiringTask{expired: "ended flows"}
+	groups := []factory.TaskGroup{{Type: "exact", Tasks: []model.Task{task}, Writers: []model.Writer{writer}}}
+	m := &Manager{taskGroups: groups, taskCounters: taskPacketCounters(groups), period: time.Hour}
+	start := time.Unix(3600, 0)
+	m.periodStart.Store(start.UnixNano())
+
+	m.sweepFlows(start.Add(time.Minute))
+	m.sweepFlows(start.Add(2 * time.Minute))
+	written := writer.written()
+	if len(written) != 1 {
+		t.Fatalf("writes = %d, want 1", len(written))
+	}
+	if want := (model.Window{Start: start, End: start.Add(time.Hour)}); written[0] != want {
+		t.Fatalf("expired flows window = %+v, want %+v", written[0], want)
+	}
+
+	m.resetAllTasks(start.Add(time.Hour))
+	if task.resets != 0 {
+		t.Fatalf("resets of a task with flow timeouts = %d, want 0", task.resets)
+	}
+}
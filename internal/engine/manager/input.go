@@ -0,0 +1,97 @@
+package manager
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+// Overload policies for packets that find the packet channel full.
+const (
+	OverloadBlock      = "block"
+	OverloadDropNewest = "drop_newest"
+	OverloadSample     = "sample"
+)
+
+// Input puts packets on a packet channel and applies the overload policy when
+// the channel is full, so a slow manager costs counted packets instead of
+// stalling the caller.
+type Input struct {
+	out        chan<- *model.PacketInfo
+	policy     string
+	sampleRate uint32
+
+	enqueued atomic.Uint64
+	dropped  atomic.Uint64
+	overflow atomic.Uint64 // packets that found the channel full, for sampling
+}
+
+// NewInput returns an Input feeding out. The zero config blocks.
+func NewInput(out chan<- *model.PacketInfo, cfg config.OverloadConfig) (*Input, error) {
+	in := &Input{out: out, policy: cfg.Policy, sampleRate: cfg.SampleRate}
+	switch cfg.Policy {
+	case "":
+		in.policy = OverloadBlock
+	case OverloadBlock, OverloadDropNewest:
+	case OverloadSample:
+		if cfg.SampleRate < 2 {
+			return nil, fmt.Errorf("overload sample_rate must be at least 2, got %d", cfg.SampleRate)
+		}
+	default:
+		return nil, fmt.Errorf("unknown overload policy %q, want %s, %s or %s", cfg.Policy, OverloadBlock, OverloadDropNewest, OverloadSample)
+	}
+	return in, nil
+}
+
+// Enqueue offers packet to the channel and reports whether it was accepted.
+// Under the sample policy an accepted packet may wait for room, and its
+// SampleRate is multiplied so that it stands for the packets dropped with it.
+func (in *Input) Enqueue(packet *model.PacketInfo) bool {
+	if in.policy == OverloadBlock {
+		in.out <- packet
+		in.enqueued.Add(1)
+		return true
+	}
+	select {
+	case in.out <- packet:
+		in.enqueued.Add(1)
+		return true
+	default:
+	}
+	if in.policy == OverloadSample && in.overflow.Add(1)%uint64(in.sampleRate) == 0 {
+		packet.SampleRate = max(packet.SampleRate, 1) * in.sampleRate
+		in.out <- packet
+		in.enqueued.Add(1)
+		return true
+	}
+	in.dropped.Add(1)
+	return false
+}
+
+// Policy returns the overload policy in effect.
+func (in *Input) Policy() string {
+	return in.policy
+}
+
+// InputStats returns the input counters and the current queue depth.
+// Live sources, the stream aggregator and the collector, enqueue through
+// Input; packets sent straight to InputChannel, as offline pcap replay does,
+// are only counted as processed.
+func (m *Manager) InputStats() model.InputStats {
+	stats := model.InputStats{
+		Processed:     m.processed.Load(),
+		QueueDepth:    len(m.packetChannel),
+		QueueCapacity: cap(m.packetChannel),
+	}
+	for _, queue := range m.workerQueues {
+		stats.QueueDepth += len(queue)
+		stats.QueueCapacity += cap(queue)
+	}
+	if m.input != nil {
+		stats.Enqueued = m.input.enqueued.Load()
+		stats.Dropped = m.input.dropped.Load()
+	}
+	return stats
+}
@@ -0,0 +1,77 @@
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+func TestInputDropNewestCountsDrops(t *testing.T) {
+	ch := make(chan *model.PacketInfo, 2)
+	m := &Manager{packetChannel: ch}
+	input, err := NewInput(ch, config.OverloadConfig{Policy: OverloadDropNewest})
+	if err != nil {
+		t.Fatalf("NewInput() unexpected error: %v", err)
+	}
+	m.input = input
+
+	for i := 0; i < 5; i++ {
+		input.Enqueue(&model.PacketInfo{})
+	}
+	want := model.InputStats{Enqueued: 2, Dropped: 3, QueueDepth: 2, QueueCapacity: 2}
+	if got := m.InputStats(); got != want {
+		t.Fatalf("InputStats() = %+v, want %+v", got, want)
+	}
+}
+
+func TestInputSampleWeightsKeptPackets(t *testing.T) {
+	ch := make(chan *model.PacketInfo, 1)
+	input, err := NewInput(ch, config.OverloadConfig{Policy: OverloadSample, SampleRate: 4})
+	if err != nil {
+		t.Fatalf("NewInput() unexpected error: %v", err)
+	}
+	if !input.Enqueue(&model.PacketInfo{}) {
+		t.Fatal("Enqueue() into an empty channel = false, want true")
+	}
+
+	// The channel is full: three overflowing packets are dropped and the
+	// fourth waits for room, standing for all four.
+	for i := 0; i < 3; i++ {
+		if input.Enqueue(&model.PacketInfo{SampleRate: 2}) {
+			t.Fatalf("Enqueue() overflow packet %d = true, want dropped", i)
+		}
+	}
+	kept := make(chan bool)
+	go func() { kept <- input.Enqueue(&model.PacketInfo{SampleRate: 2}) }()
+	for input.overflow.Load() < 4 {
+		time.Sleep(time.Millisecond)
+	}
+	<-ch
+	if !<-kept {
+		t.Fatal("Enqueue() sampled packet = false, want true")
+	}
+	if got := (<-ch).SampleRate; got != 8 {
+		t.Fatalf("sampled packet SampleRate = %d, want 8", got)
+	}
+	if input.dropped.Load() != 3 || input.enqueued.Load() != 2 {
+		t.Fatalf("dropped/enqueued = %d/%d, want 3/2", input.dropped.Load(), input.enqueued.Load())
+	}
+}
+
+func TestNewInputRejectsInvalidPolicy(t *testing.T) {
+	for _, cfg := range []config.OverloadConfig{
+		{Policy: "drop_oldest"},
+		{Policy: OverloadSample},
+		{Policy: OverloadSample, SampleRate: 1},
+	} {
+		if _, err := NewInput(nil, cfg); err == nil {
+			t.Fatalf("NewInput(%+v) error = nil, want non-nil", cfg)
+		}
+	}
+	input, err := NewInput(nil, config.OverloadConfig{})
+	if err != nil || input.Policy() != OverloadBlock {
+		t.Fatalf("NewInput(default) = %v, %v, want the block policy", input, err)
+	}
+}
@@ -2,35 +2,79 @@ package manager
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"Go2NetSpectra/internal/alerter"
 	"Go2NetSpectra/internal/config"
 	_ "Go2NetSpectra/internal/engine/impl/exact"  // Registers exact task aggregator
 	_ "Go2NetSpectra/internal/engine/impl/sketch" // Registers sketch task aggregator
+	"Go2NetSpectra/internal/engine/window"
 	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/metrics"
 	"Go2NetSpectra/internal/model"
 	"Go2NetSpectra/internal/notification"
 )
 
 // Manager orchestrates a set of aggregation tasks and their writers.
 type Manager struct {
-	taskGroups []factory.TaskGroup
-	alerter    *alerter.Alerter
+	// groupsMu guards taskGroups, which Reload replaces while workers read it,
+	// and taskCounters, the packet counter of each of their tasks.
+	groupsMu     sync.RWMutex
+	taskGroups   []factory.TaskGroup
+	taskCounters [][]*metrics.Series
+	alerter      *alerter.Alerter
+
+	// reloadMu serializes Reload and Stop and guards the fields below it.
+	reloadMu     sync.Mutex
+	cfg          *config.Config
+	snapshotters []*snapshotter
+	windowers    []*windower
+	stopped      bool
+
+	// eventTime is set when packet timestamps drive the manager instead of
+	// the wall clock.
+	eventTime *eventClock
+
+	// checkpoints is set when task state is saved across restarts.
+	// fingerprints identifies the definition of each running task and is
+	// guarded by groupsMu.
+	checkpoints    *checkpoints
+	fingerprints   map[string]string
+	checkpointerWg sync.WaitGroup
 
 	// Worker pool for concurrent packet processing
 	packetChannel chan *model.PacketInfo
+	input         *Input
+	processed     atomic.Uint64
 	numWorkers    int
 	workerWg      sync.WaitGroup
+	// flowDispatch sends every packet of a flow to the same worker, which
+	// updates its own partition of each task.
+	flowDispatch bool
+	workerQueues []chan *model.PacketInfo // one per worker with flow dispatch
 
 	// Snapshotting and Resetting resources
 	period        time.Duration // Global measurement period
+	periodStart   atomic.Int64  // Unix nanoseconds the current period started
+	resetMu       sync.Mutex    // serializes period resets, checkpoints and flow sweeps
 	done          chan struct{}
 	stopOnce      sync.Once
 	snapshotterWg sync.WaitGroup
 	resetterWg    sync.WaitGroup // New WaitGroup for the resetter
+	sweeperWg     sync.WaitGroup
+}
+
+// snapshotter periodically writes the tasks of one aggregator type to a writer.
+type snapshotter struct {
+	group  string
+	writer model.Writer
+	def    config.WriterDef
+	retire chan struct{} // closed by Reload, which takes the final snapshot itself
+	exited chan struct{}
 }
 
 // NewManager creates a new Manager.
@@ -47,6 +91,22 @@ func NewManager(cfg *config.Config) (*Manager, error) {
 	if period <= 0 {
 		return nil, fmt.Errorf("aggregator period must be a positive duration")
 	}
+	eventTime, err := newEventClock(cfg.Aggregator.EventTime)
+	if err != nil {
+		return nil, err
+	}
+	checkpoints, err := newCheckpoints(cfg.Aggregator.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoints != nil && eventTime != nil {
+		log.Println("Warning: checkpoints are disabled in event-time mode, where a capture is replayed from its start.")
+		checkpoints = nil
+	}
+	fingerprints, err := taskFingerprints(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	var alertr *alerter.Alerter
 	if cfg.Alerter.Enabled {
@@ -73,32 +133,65 @@ func NewManager(cfg *config.Config) (*Manager, error) {
 		}
 	}
 
+	packetChannel := make(chan *model.PacketInfo, cfg.Aggregator.SizeOfPacketChannel)
+	input, err := NewInput(packetChannel, cfg.Aggregator.Overload)
+	if err != nil {
+		return nil, err
+	}
+
+	numWorkers := max(1, cfg.Aggregator.NumWorkers)
+	var flowDispatch bool
+	var workerQueues []chan *model.PacketInfo
+	switch cfg.Aggregator.Dispatch {
+	case "", dispatchShared:
+	case dispatchFlow:
+		if eventTime != nil {
+			log.Println("Warning: flow dispatch does not apply in event-time mode, where packets are applied one at a time in timestamp order.")
+			break
+		}
+		flowDispatch = true
+		workerQueues = newWorkerQueues(numWorkers, cfg.Aggregator.SizeOfPacketChannel)
+		for _, group := range taskGroups {
+			partitionTasks(group.Tasks, numWorkers)
+		}
+	default:
+		return nil, fmt.Errorf("unknown aggregator dispatch %q, want %s or %s", cfg.Aggregator.Dispatch, dispatchShared, dispatchFlow)
+	}
+
 	return &Manager{
 		taskGroups:    taskGroups,
+		taskCounters:  taskPacketCounters(taskGroups),
+		cfg:           cfg,
 		alerter:       alertr,
 		period:        period,
+		eventTime:     eventTime,
+		checkpoints:   checkpoints,
+		fingerprints:  fingerprints,
 		done:          make(chan struct{}),
-		packetChannel: make(chan *model.PacketInfo, cfg.Aggregator.SizeOfPacketChannel),
-		numWorkers:    max(1, cfg.Aggregator.NumWorkers),
+		packetChannel: packetChannel,
+		input:         input,
+		numWorkers:    numWorkers,
+		flowDispatch:  flowDispatch,
+		workerQueues:  workerQueues,
 	}, nil
 }
 
 // Start begins the manager's packet processing workers, snapshotter, and resetter goroutines.
 func (m *Manager) Start() {
-	// For each group, start a dedicated snapshotter for each of its writers.
-	for _, group := range m.taskGroups {
-		for _, writer := range group.Writers {
-			m.snapshotterWg.Add(1)
-			// Pass the group-specific tasks to the snapshotter
-			go m.runSnapshotter(writer, group.Tasks)
-			log.Printf("Started snapshotter for a writer with interval %s, handling %d tasks.", writer.Interval(), len(group.Tasks))
+	m.periodStart.Store(time.Now().UnixNano())
+	m.registerInputMetrics()
+	if m.checkpoints != nil {
+		m.restoreCheckpoints(time.Now())
+		if m.checkpoints.interval > 0 {
+			m.checkpointerWg.Add(1)
+			go m.runCheckpointer()
 		}
 	}
-
-	// Start the global resetter for all tasks across all groups.
-	m.resetterWg.Add(1)
-	go m.runResetter()
-	log.Printf("Started global resetter with period %s", m.period)
+	if m.eventTime != nil {
+		log.Printf("Event-time mode: packets drive windows, snapshots and resets, with allowed lateness %s.", m.eventTime.lateness)
+	} else {
+		m.startClockLoops()
+	}
 
 	// Start the independent alerter goroutine if it's enabled.
 	if m.alerter != nil {
@@ -106,6 +199,11 @@ func (m *Manager) Start() {
 	}
 
 	// Start the packet processing worker pool.
+	if m.flowDispatch {
+		m.startFlowWorkers()
+		log.Printf("Manager started with %d flow-affine workers.", m.numWorkers)
+		return
+	}
 	m.workerWg.Add(m.numWorkers)
 	for i := 0; i < m.numWorkers; i++ {
 		go m.worker()
@@ -113,10 +211,50 @@ func (m *Manager) Start() {
 	log.Printf("Manager started with %d workers.", m.numWorkers)
 }
 
-// runSnapshotter runs a dedicated snapshot loop for a single writer and its associated tasks.
-func (m *Manager) runSnapshotter(writer model.Writer, tasks []model.Task) {
+// startClockLoops starts the windowers, snapshotters and resetter that run
+// the manager on the wall clock.
+func (m *Manager) startClockLoops() {
+	// For each group, start a dedicated snapshotter for each of its writers.
+	m.reloadMu.Lock()
+	for _, group := range m.taskGroups {
+		m.startWindowers(group)
+		for i, writer := range group.Writers {
+			s := &snapshotter{group: group.Type, writer: writer}
+			if i < len(group.WriterDefs) {
+				s.def = group.WriterDefs[i]
+			}
+			m.startSnapshotter(s)
+			log.Printf("Started snapshotter for a writer with interval %s, handling %d tasks.", writer.Interval(), len(group.Tasks))
+		}
+	}
+	m.reloadMu.Unlock()
+
+	// Start the global resetter for all tasks across all groups.
+	m.resetterWg.Add(1)
+	go m.runResetter()
+	log.Printf("Started global resetter with period %s", m.period)
+
+	// Flows that end on timeouts or are evicted, now or after a reload, are
+	// written by the sweeper.
+	m.sweeperWg.Add(1)
+	go m.runSweeper()
+}
+
+// startSnapshotter registers s and starts its loop. The caller holds reloadMu.
+func (m *Manager) startSnapshotter(s *snapshotter) {
+	s.retire = make(chan struct{})
+	s.exited = make(chan struct{})
+	m.snapshotters = append(m.snapshotters, s)
+	m.snapshotterWg.Add(1)
+	go m.runSnapshotter(s)
+}
+
+// runSnapshotter runs a dedicated snapshot loop for a single writer and the
+// current tasks of its aggregator type.
+func (m *Manager) runSnapshotter(s *snapshotter) {
 	defer m.snapshotterWg.Done()
-	interval := writer.Interval()
+	defer close(s.exited)
+	interval := s.writer.Interval()
 	if interval <= 0 {
 		log.Printf("Invalid interval %s for writer, snapshotter will not run.", interval)
 		return
@@ -127,18 +265,46 @@ func (m *Manager) runSnapshotter(writer model.Writer, tasks []model.Task) {
 	for {
 		select {
 		case <-ticker.C:
-			m.takeSnapshotForWriter(writer, tasks)
+			m.takeSnapshotForWriter(s, m.groupTasks(s.group))
+		case <-s.retire:
+			return
 		case <-m.done:
-			m.takeSnapshotForWriter(writer, tasks)
+			m.takeSnapshotForWriter(s, m.groupTasks(s.group))
 			return
 		}
 	}
 }
 
-// takeSnapshotForWriter orchestrates taking and writing a snapshot for a specific writer.
-func (m *Manager) takeSnapshotForWriter(writer model.Writer, tasks []model.Task) {
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	log.Printf("Taking snapshot for writer at %s for %d tasks.", timestamp, len(tasks))
+// groupTasks returns the running tasks of an aggregator type.
+func (m *Manager) groupTasks(aggType string) []model.Task {
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	for _, group := range m.taskGroups {
+		if group.Type == aggType {
+			return group.Tasks
+		}
+	}
+	return nil
+}
+
+// currentGroups returns the running task groups.
+func (m *Manager) currentGroups() []factory.TaskGroup {
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	return m.taskGroups
+}
+
+// takeSnapshotForWriter orchestrates taking and writing a snapshot for the writer of s.
+func (m *Manager) takeSnapshotForWriter(s *snapshotter, tasks []model.Task) {
+	m.takeSnapshotAt(s.writer, writerLabel(s.writer, s.def), tasks, time.Now())
+}
+
+// takeSnapshotAt writes a snapshot of tasks to writer, stamped with at. Its
+// metrics are labelled with label.
+func (m *Manager) takeSnapshotAt(writer model.Writer, label string, tasks []model.Task, at time.Time) {
+	timestamp := at.Format("2006-01-02_15-04-05")
+	log.Printf("Taking snapshot for writer %s at %s for %d tasks.", label, timestamp, len(tasks))
+	start := time.Now()
 
 	var wg sync.WaitGroup
 	wg.Add(len(tasks)) // Wait for all tasks in this group to finish snapshotting
@@ -146,28 +312,41 @@ func (m *Manager) takeSnapshotForWriter(writer model.Writer, tasks []model.Task)
 	for _, task := range tasks {
 		go func(t model.Task) {
 			defer wg.Done()
-			snapshotData := t.Snapshot()
-			if err := writer.Write(snapshotData, timestamp, t.Name(), t.Fields(), t.DecodeFlowFunc()); err != nil {
+			snapshotData, taskWindow := m.snapshotWindow(t)
+			if err := writer.Write(snapshotData, timestamp, taskWindow, t.Name(), t.Fields(), t.DecodeFlowFunc()); err != nil {
+				writerErrors.Inc(label)
 				log.Printf("Error writing snapshot for task %s: %v", t.Name(), err)
 			}
 		}(task)
 	}
 
 	wg.Wait() // Wait for all tasks in this group to complete
+	snapshotDuration.Observe(time.Since(start).Seconds(), label)
+
+	stats := m.InputStats()
+	log.Printf("Input: %d enqueued, %d dropped, %d processed, queue %d/%d.", stats.Enqueued, stats.Dropped, stats.Processed, stats.QueueDepth, stats.QueueCapacity)
+	if statsWriter, ok := writer.(model.StatsWriter); ok {
+		if err := statsWriter.WriteStats(stats, timestamp); err != nil {
+			writerErrors.Inc(label)
+			log.Printf("Error writing input stats: %v", err)
+		}
+	}
 
 	log.Printf("Completed snapshot for writer at %s.", time.Now().Format("2006-01-02_15-04-05"))
 }
 
-// runResetter runs a dedicated loop to reset all tasks periodically.
+// runResetter runs a dedicated loop to reset all tasks periodically. The
+// first reset ends the period that Start began or restored.
 func (m *Manager) runResetter() {
 	defer m.resetterWg.Done()
-	ticker := time.NewTicker(m.period)
-	defer ticker.Stop()
+	timer := time.NewTimer(time.Until(time.Unix(0, m.periodStart.Load()).Add(m.period)))
+	defer timer.Stop()
 
 	for {
 		select {
-		case <-ticker.C:
-			m.resetAllTasks()
+		case <-timer.C:
+			m.resetAllTasks(time.Now())
+			timer.Reset(m.period)
 		case <-m.done:
 			log.Println("Resetter shutting down.")
 			return
@@ -175,13 +354,21 @@ func (m *Manager) runResetter() {
 	}
 }
 
-// resetAllTasks iterates through all tasks across all groups and calls their Reset method.
-func (m *Manager) resetAllTasks() {
-	log.Printf("Resetting all tasks for new measurement period at %s", time.Now().Format("2006-01-02_15-04-05"))
+// resetAllTasks iterates through all tasks across all groups and calls their
+// Reset method, starting a new period at now. Tasks with their own windows
+// are reset when those close, and tasks with flow timeouts never are.
+func (m *Manager) resetAllTasks(now time.Time) {
+	m.resetMu.Lock()
+	defer m.resetMu.Unlock()
+	log.Printf("Resetting all tasks for new measurement period at %s", now.Format("2006-01-02_15-04-05"))
+	m.periodStart.Store(now.UnixNano())
 	var wg sync.WaitGroup
-	for _, group := range m.taskGroups {
-		wg.Add(len(group.Tasks))
+	for _, group := range m.currentGroups() {
 		for _, task := range group.Tasks {
+			if _, ok := task.(*window.Task); ok || expiresFlows(task) {
+				continue
+			}
+			wg.Add(1)
 			go func(t model.Task) {
 				defer wg.Done()
 				t.Reset()
@@ -196,16 +383,32 @@ func (m *Manager) resetAllTasks() {
 func (m *Manager) Stop() {
 	m.stopOnce.Do(func() {
 		log.Println("Manager stopping...")
+		m.reloadMu.Lock()
+		defer m.reloadMu.Unlock()
+		m.stopped = true
 		close(m.packetChannel)
 
 		log.Println("Waiting for workers to finish...")
 		m.workerWg.Wait()
+		if m.eventTime != nil {
+			m.flushEventTime()
+		}
 
 		close(m.done)
 		log.Println("Waiting for snapshotters and resetter to finish...")
 
 		m.snapshotterWg.Wait()
 		m.resetterWg.Wait()
+		m.sweeperWg.Wait()
+		m.checkpointerWg.Wait()
+		if m.checkpoints != nil {
+			m.writeCheckpoints()
+		}
+		for _, group := range m.currentGroups() {
+			for _, writer := range group.Writers {
+				closeWriter(writer)
+			}
+		}
 
 		if m.alerter != nil {
 			m.alerter.Stop()
@@ -221,24 +424,52 @@ func (m *Manager) worker() {
 		if err := m.processPacket(packet); err != nil {
 			log.Printf("Manager failed to process packet: %v", err)
 		}
+		m.processed.Add(1)
 	}
 }
 
 // InputChannel returns the packet input channel consumed by the worker pool.
+// Sends on it always wait for room; live sources should use Input instead.
 func (m *Manager) InputChannel() chan<- *model.PacketInfo {
 	return m.packetChannel
 }
 
+// Input returns the packet input that applies the configured overload policy.
+func (m *Manager) Input() *Input {
+	return m.input
+}
+
 func (m *Manager) processPacket(packet *model.PacketInfo) error {
 	if packet == nil {
 		return fmt.Errorf("nil packet")
 	}
+	if m.eventTime != nil {
+		m.processEventPacket(packet)
+		return nil
+	}
+	m.applyPacket(packet)
+	return nil
+}
 
-	for _, group := range m.taskGroups {
-		for _, task := range group.Tasks {
+// applyPacket feeds packet to every task.
+func (m *Manager) applyPacket(packet *model.PacketInfo) {
+	m.groupsMu.RLock()
+	defer m.groupsMu.RUnlock()
+	for i, group := range m.taskGroups {
+		for j, task := range group.Tasks {
 			task.ProcessPacket(packet)
+			m.taskCounters[i][j].Inc()
 		}
 	}
+}
 
-	return nil
+// closeWriter releases the connection held by writers that have one.
+func closeWriter(writer model.Writer) {
+	closer, ok := writer.(io.Closer)
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		log.Printf("Error closing writer: %v", err)
+	}
 }
@@ -57,11 +57,10 @@ func (s *stubTask) firstPacket() *model.PacketInfo {
 func TestManagerProcessPacketRoutesToAllTasks(t *testing.T) {
 	taskA := &stubTask{}
 	taskB := &stubTask{}
-	m := &Manager{
-		taskGroups: []factory.TaskGroup{
-			{Tasks: []model.Task{taskA, taskB}},
-		},
+	groups := []factory.TaskGroup{
+		{Tasks: []model.Task{taskA, taskB}},
 	}
+	m := &Manager{taskGroups: groups, taskCounters: taskPacketCounters(groups)}
 
 	packet := &model.PacketInfo{
 		Timestamp: time.Unix(1700000000, 0),
@@ -93,12 +92,32 @@ func TestManagerProcessPacketRoutesToAllTasks(t *testing.T) {
 	}
 }
 
+func TestManagerCountsPacketsPerTask(t *testing.T) {
+	groups := []factory.TaskGroup{
+		{Type: "counted", Tasks: []model.Task{&stubTask{}}},
+	}
+	m := &Manager{taskGroups: groups, taskCounters: taskPacketCounters(groups)}
+	counter := taskPackets.With("counted", "stub")
+	before := counter.Value()
+
+	for i := 0; i < 3; i++ {
+		if err := m.processPacket(&model.PacketInfo{Length: 64}); err != nil {
+			t.Fatalf("processPacket() unexpected error: %v", err)
+		}
+	}
+	if got := counter.Value() - before; got != 3 {
+		t.Fatalf("task packets counted = %v, want 3", got)
+	}
+}
+
 func TestManagerStopDrainsQueuedPackets(t *testing.T) {
 	task := &stubTask{}
+	groups := []factory.TaskGroup{
+		{Tasks: []model.Task{task}},
+	}
 	m := &Manager{
-		taskGroups: []factory.TaskGroup{
-			{Tasks: []model.Task{task}},
-		},
+		taskGroups:    groups,
+		taskCounters:  taskPacketCounters(groups),
 		packetChannel: make(chan *model.PacketInfo, 1),
 		done:          make(chan struct{}),
 		numWorkers:    1,
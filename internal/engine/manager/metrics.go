@@ -0,0 +1,74 @@
+package manager
+
+import (
+	"fmt"
+	"strings"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/metrics"
+	"Go2NetSpectra/internal/model"
+)
+
+var (
+	taskPackets = metrics.NewCounter(metrics.Namespace+"_engine_task_packets_total",
+		"Packets offered to a task, before its filter, by aggregator and task.", "aggregator", "task")
+	snapshotDuration = metrics.NewHistogram(metrics.Namespace+"_engine_snapshot_duration_seconds",
+		"Time to snapshot every task of an aggregator and write it, by writer.", nil, "writer")
+	writerErrors = metrics.NewCounter(metrics.Namespace+"_engine_writer_errors_total",
+		"Snapshot and input stats writes that failed, by writer.", "writer")
+)
+
+// taskPacketCounters returns the packet counter of every task in groups,
+// indexed like groups and their tasks.
+func taskPacketCounters(groups []factory.TaskGroup) [][]*metrics.Series {
+	counters := make([][]*metrics.Series, len(groups))
+	for i, group := range groups {
+		counters[i] = make([]*metrics.Series, len(group.Tasks))
+		for j, task := range group.Tasks {
+			counters[i][j] = taskPackets.With(group.Type, task.Name())
+		}
+	}
+	return counters
+}
+
+// writerLabel names a writer by the name of its definition, falling back to
+// its type, e.g. "exact.ClickHouseWriter", for one created without a name.
+func writerLabel(writer model.Writer, def config.WriterDef) string {
+	if def.Name != "" {
+		return def.Name
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", writer), "*")
+}
+
+// groupWriterLabel is writerLabel for the i-th writer of group.
+func groupWriterLabel(group factory.TaskGroup, i int) string {
+	var def config.WriterDef
+	if i < len(group.WriterDefs) {
+		def = group.WriterDefs[i]
+	}
+	return writerLabel(group.Writers[i], def)
+}
+
+// registerInputMetrics reports the input counters of m at scrape time.
+func (m *Manager) registerInputMetrics() {
+	metrics.NewGaugeFunc(metrics.Namespace+"_engine_queue_depth",
+		"Packets waiting in the engine input queue.", nil, func(emit func(float64, ...string)) {
+			emit(float64(m.InputStats().QueueDepth))
+		})
+	metrics.NewGaugeFunc(metrics.Namespace+"_engine_queue_capacity",
+		"Capacity of the engine input queue.", nil, func(emit func(float64, ...string)) {
+			emit(float64(m.InputStats().QueueCapacity))
+		})
+	metrics.NewCounterFunc(metrics.Namespace+"_engine_input_packets_total",
+		"Packets offered to the engine input, by whether they were enqueued or dropped.",
+		[]string{"outcome"}, func(emit func(float64, ...string)) {
+			stats := m.InputStats()
+			emit(float64(stats.Enqueued), "enqueued")
+			emit(float64(stats.Dropped), "dropped")
+		})
+	metrics.NewCounterFunc(metrics.Namespace+"_engine_packets_processed_total",
+		"Packets the engine workers applied to the tasks.", nil, func(emit func(float64, ...string)) {
+			emit(float64(m.InputStats().Processed))
+		})
+}
@@ -0,0 +1,405 @@
+package manager
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/model"
+)
+
+// ReloadResult describes what a Reload changed. Tasks are named
+// "<aggregator type>/<task name>"; a task whose definition changed is
+// reported as removed and added, since it restarts with empty counters.
+type ReloadResult struct {
+	TasksAdded     []string
+	TasksRemoved   []string
+	TasksKept      []string
+	WritersStarted int
+	WritersStopped int
+	WritersKept    int
+	AlertRules     int // rules in effect, 0 when the alerter is not running
+	// Warnings lists settings that changed but only take effect after a restart.
+	Warnings []string
+}
+
+// groupPlan is the new state of one aggregator type, built before anything
+// running is touched.
+type groupPlan struct {
+	group   factory.TaskGroup
+	added   []model.Task
+	removed []model.Task
+	started []*snapshotter
+}
+
+// Reload applies the tasks, writers and alert rules of cfg without stopping
+// packet processing. Tasks whose definition did not change keep their
+// counters. Removed tasks get a final snapshot through the writers that
+// remain, and removed writers get a final snapshot of their tasks before they
+// stop. An invalid cfg is rejected and the running configuration is kept.
+func (m *Manager) Reload(cfg *config.Config) (*ReloadResult, error) {
+	m.reloadMu.Lock()
+	defer m.reloadMu.Unlock()
+	if m.stopped {
+		return nil, fmt.Errorf("manager is stopped")
+	}
+	if m.eventTime != nil {
+		return nil, fmt.Errorf("reload is not supported in event-time mode")
+	}
+	if err := validateReload(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+	fingerprints, err := taskFingerprints(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	result := &ReloadResult{Warnings: restartOnlyChanges(m.cfg, cfg)}
+	oldGroups := m.currentGroups()
+	kept := make(map[*snapshotter]bool)
+
+	plans := make([]groupPlan, 0, len(cfg.Aggregator.Types))
+	for _, aggType := range cfg.Aggregator.Types {
+		plan, err := m.planGroup(cfg, aggType, oldGroups, kept, result)
+		if err != nil {
+			for _, p := range plans {
+				for _, s := range p.started {
+					closeWriter(s.writer)
+				}
+			}
+			return nil, err
+		}
+		if m.flowDispatch {
+			partitionTasks(plan.added, m.numWorkers)
+		}
+		plans = append(plans, plan)
+	}
+
+	// Every task of a type that is no longer configured is removed.
+	removed := make(map[string][]model.Task)
+	for _, old := range oldGroups {
+		if !slices.Contains(cfg.Aggregator.Types, old.Type) {
+			removed[old.Type] = old.Tasks
+			for _, task := range old.Tasks {
+				result.TasksRemoved = append(result.TasksRemoved, old.Type+"/"+task.Name())
+			}
+		}
+	}
+
+	newGroups := make([]factory.TaskGroup, len(plans))
+	var allTasks []model.Task
+	for i, plan := range plans {
+		newGroups[i] = plan.group
+		allTasks = append(allTasks, plan.group.Tasks...)
+		if len(plan.removed) > 0 {
+			removed[plan.group.Type] = plan.removed
+		}
+	}
+	counters := taskPacketCounters(newGroups)
+	m.groupsMu.Lock()
+	m.taskGroups = newGroups
+	m.taskCounters = counters
+	m.fingerprints = fingerprints
+	m.groupsMu.Unlock()
+	m.retireWindowers(newGroups)
+
+	// Packets no longer reach the removed tasks, so their final snapshots are
+	// complete.
+	running := make([]*snapshotter, 0, len(m.snapshotters))
+	for _, s := range m.snapshotters {
+		if kept[s] {
+			if tasks := removed[s.group]; len(tasks) > 0 {
+				m.takeSnapshotForWriter(s, tasks)
+			}
+			running = append(running, s)
+			continue
+		}
+		close(s.retire)
+		<-s.exited
+		m.takeSnapshotForWriter(s, tasksOfType(oldGroups, s.group))
+		closeWriter(s.writer)
+		result.WritersStopped++
+	}
+	m.snapshotters = running
+	for _, plan := range plans {
+		for _, s := range plan.started {
+			m.startSnapshotter(s)
+		}
+		m.startWindowers(factory.TaskGroup{Type: plan.group.Type, Tasks: plan.added})
+	}
+	result.WritersKept = len(kept)
+
+	if m.alerter != nil {
+		m.alerter.Update(cfg.Alerter.Rules, allTasks)
+		result.AlertRules = len(cfg.Alerter.Rules)
+	}
+	m.cfg = cfg
+
+	log.Printf("Configuration reloaded: %d tasks added, %d removed, %d kept; %d writers started, %d stopped, %d kept.",
+		len(result.TasksAdded), len(result.TasksRemoved), len(result.TasksKept), result.WritersStarted, result.WritersStopped, result.WritersKept)
+	for _, warning := range result.Warnings {
+		log.Printf("Warning: %s", warning)
+	}
+	return result, nil
+}
+
+// planGroup builds the tasks and writers of aggType that are new or changed
+// in cfg and reuses the running ones that are not. Reused snapshotters are
+// added to kept.
+func (m *Manager) planGroup(cfg *config.Config, aggType string, oldGroups []factory.TaskGroup, kept map[*snapshotter]bool, result *ReloadResult) (groupPlan, error) {
+	oldTasks := make(map[string]model.Task)
+	for _, task := range tasksOfType(oldGroups, aggType) {
+		oldTasks[task.Name()] = task
+	}
+	oldDefs := taskDefs(m.cfg, aggType)
+	newDefs := taskDefs(cfg, aggType)
+
+	var createTasks []string
+	keptTasks := make(map[string]bool)
+	for _, def := range newDefs {
+		if _, ok := oldTasks[def.name]; ok && reflect.DeepEqual(findTaskDef(oldDefs, def.name), def.def) {
+			keptTasks[def.name] = true
+			continue
+		}
+		createTasks = append(createTasks, def.name)
+	}
+
+	var createWriters []config.WriterDef
+	var keptWriters []*snapshotter
+	for _, def := range writerDefs(cfg, aggType) {
+		if !def.Enabled {
+			continue
+		}
+		if s := m.matchSnapshotter(aggType, def, kept); s != nil {
+			kept[s] = true
+			keptWriters = append(keptWriters, s)
+			continue
+		}
+		createWriters = append(createWriters, def)
+	}
+
+	built, err := factory.CreateGroup(subsetConfig(cfg, aggType, createTasks, createWriters), aggType)
+	if err != nil {
+		return groupPlan{}, err
+	}
+	builtTasks := make(map[string]model.Task, len(built.Tasks))
+	for _, task := range built.Tasks {
+		builtTasks[task.Name()] = task
+	}
+
+	plan := groupPlan{group: factory.TaskGroup{Type: aggType}}
+	for _, def := range newDefs {
+		name := aggType + "/" + def.name
+		if keptTasks[def.name] {
+			plan.group.Tasks = append(plan.group.Tasks, oldTasks[def.name])
+			result.TasksKept = append(result.TasksKept, name)
+			continue
+		}
+		plan.group.Tasks = append(plan.group.Tasks, builtTasks[def.name])
+		plan.added = append(plan.added, builtTasks[def.name])
+		result.TasksAdded = append(result.TasksAdded, name)
+	}
+	for _, task := range tasksOfType(oldGroups, aggType) {
+		if !keptTasks[task.Name()] {
+			plan.removed = append(plan.removed, task)
+			result.TasksRemoved = append(result.TasksRemoved, aggType+"/"+task.Name())
+		}
+	}
+
+	for _, s := range keptWriters {
+		plan.group.Writers = append(plan.group.Writers, s.writer)
+		plan.group.WriterDefs = append(plan.group.WriterDefs, s.def)
+	}
+	for i, writer := range built.Writers {
+		plan.group.Writers = append(plan.group.Writers, writer)
+		plan.group.WriterDefs = append(plan.group.WriterDefs, built.WriterDefs[i])
+		plan.started = append(plan.started, &snapshotter{group: aggType, writer: writer, def: built.WriterDefs[i]})
+	}
+	result.WritersStarted += len(built.Writers)
+	return plan, nil
+}
+
+// matchSnapshotter returns a running snapshotter of aggType whose writer was
+// created from def and has not been claimed yet.
+func (m *Manager) matchSnapshotter(aggType string, def config.WriterDef, kept map[*snapshotter]bool) *snapshotter {
+	for _, s := range m.snapshotters {
+		if s.group == aggType && !kept[s] && reflect.DeepEqual(s.def, def) {
+			return s
+		}
+	}
+	return nil
+}
+
+func tasksOfType(groups []factory.TaskGroup, aggType string) []model.Task {
+	for _, group := range groups {
+		if group.Type == aggType {
+			return group.Tasks
+		}
+	}
+	return nil
+}
+
+// namedTaskDef is a task definition of any aggregator type.
+type namedTaskDef struct {
+	name string
+	def  any
+}
+
+func findTaskDef(defs []namedTaskDef, name string) any {
+	for _, d := range defs {
+		if d.name == name {
+			return d.def
+		}
+	}
+	return nil
+}
+
+// taskDefs returns the task definitions of an aggregator type in config order.
+func taskDefs(cfg *config.Config, aggType string) []namedTaskDef {
+	var defs []namedTaskDef
+	switch aggType {
+	case "exact":
+		for _, def := range cfg.Aggregator.Exact.Tasks {
+			defs = append(defs, namedTaskDef{name: def.Name, def: def})
+		}
+	case "sketch":
+		for _, def := range cfg.Aggregator.Sketch.Tasks {
+			defs = append(defs, namedTaskDef{name: def.Name, def: def})
+		}
+	}
+	return defs
+}
+
+// writerDefs returns aggType's writer definitions, named as the factory
+// names them so they compare equal to the definitions of running writers.
+func writerDefs(cfg *config.Config, aggType string) []config.WriterDef {
+	var defs []config.WriterDef
+	switch aggType {
+	case "exact":
+		defs = cfg.Aggregator.Exact.Writers
+	case "sketch":
+		defs = cfg.Aggregator.Sketch.Writers
+	}
+	named := make([]config.WriterDef, len(defs))
+	for i, def := range defs {
+		def.Name = factory.WriterName(aggType, i, def)
+		named[i] = def
+	}
+	return named
+}
+
+// subsetConfig returns a copy of cfg whose aggType section holds only the
+// named tasks and the given writers, for building just those.
+func subsetConfig(cfg *config.Config, aggType string, tasks []string, writers []config.WriterDef) *config.Config {
+	subset := *cfg
+	switch aggType {
+	case "exact":
+		subset.Aggregator.Exact.Tasks = nil
+		for _, def := range cfg.Aggregator.Exact.Tasks {
+			if slices.Contains(tasks, def.Name) {
+				subset.Aggregator.Exact.Tasks = append(subset.Aggregator.Exact.Tasks, def)
+			}
+		}
+		subset.Aggregator.Exact.Writers = writers
+	case "sketch":
+		subset.Aggregator.Sketch.Tasks = nil
+		for _, def := range cfg.Aggregator.Sketch.Tasks {
+			if slices.Contains(tasks, def.Name) {
+				subset.Aggregator.Sketch.Tasks = append(subset.Aggregator.Sketch.Tasks, def)
+			}
+		}
+		subset.Aggregator.Sketch.Writers = writers
+	}
+	return &subset
+}
+
+// validateReload rejects configs the running manager cannot switch to.
+func validateReload(cfg *config.Config) error {
+	period, err := time.ParseDuration(cfg.Aggregator.Period)
+	if err != nil {
+		return fmt.Errorf("invalid aggregator period: %w", err)
+	}
+	if period <= 0 {
+		return fmt.Errorf("aggregator period must be a positive duration")
+	}
+
+	for i, aggType := range cfg.Aggregator.Types {
+		if !factory.Registered(aggType) {
+			return fmt.Errorf("unknown aggregator type: '%s'", aggType)
+		}
+		if aggType != "exact" && aggType != "sketch" {
+			return fmt.Errorf("aggregator type '%s' cannot be reloaded", aggType)
+		}
+		if slices.Contains(cfg.Aggregator.Types[:i], aggType) {
+			return fmt.Errorf("aggregator type '%s' is listed twice", aggType)
+		}
+
+		names := make(map[string]bool)
+		for _, def := range taskDefs(cfg, aggType) {
+			if def.name == "" {
+				return fmt.Errorf("%s task has no name", aggType)
+			}
+			if names[def.name] {
+				return fmt.Errorf("%s task '%s' is defined twice", aggType, def.name)
+			}
+			names[def.name] = true
+		}
+		for _, def := range writerDefs(cfg, aggType) {
+			if !def.Enabled {
+				continue
+			}
+			interval, err := time.ParseDuration(def.SnapshotInterval)
+			if err != nil {
+				return fmt.Errorf("invalid snapshot_interval for %s writer type '%s': %w", aggType, def.Type, err)
+			}
+			if interval <= 0 {
+				return fmt.Errorf("snapshot_interval for %s writer type '%s' must be positive", aggType, def.Type)
+			}
+		}
+	}
+
+	if cfg.Alerter.Enabled {
+		if _, err := time.ParseDuration(cfg.Alerter.CheckInterval); err != nil {
+			return fmt.Errorf("invalid check_interval for alerter: %w", err)
+		}
+		for _, rule := range cfg.Alerter.Rules {
+			switch rule.Operator {
+			case ">", "<", "=", ">=", "<=":
+			default:
+				return fmt.Errorf("alerter rule '%s' has unknown operator '%s'", rule.Name, rule.Operator)
+			}
+		}
+	}
+	return nil
+}
+
+// restartOnlyChanges lists the settings that differ between old and cfg but
+// are only read when the manager starts.
+func restartOnlyChanges(old, cfg *config.Config) []string {
+	var warnings []string
+	for _, setting := range []struct {
+		key     string
+		changed bool
+	}{
+		{"aggregator.period", old.Aggregator.Period != cfg.Aggregator.Period},
+		{"aggregator.num_workers", old.Aggregator.NumWorkers != cfg.Aggregator.NumWorkers},
+		{"aggregator.dispatch", old.Aggregator.Dispatch != cfg.Aggregator.Dispatch},
+		{"aggregator.size_of_packet_channel", old.Aggregator.SizeOfPacketChannel != cfg.Aggregator.SizeOfPacketChannel},
+		{"aggregator.cluster", !reflect.DeepEqual(old.Aggregator.Cluster, cfg.Aggregator.Cluster)},
+		{"aggregator.overload", old.Aggregator.Overload != cfg.Aggregator.Overload},
+		{"aggregator.checkpoint", old.Aggregator.Checkpoint != cfg.Aggregator.Checkpoint},
+		{"alerter.enabled", old.Alerter.Enabled != cfg.Alerter.Enabled},
+		{"alerter.check_interval", old.Alerter.CheckInterval != cfg.Alerter.CheckInterval},
+		{"alerter.ai_analysis", old.Alerter.AIAnalysis != cfg.Alerter.AIAnalysis},
+		{"smtp", old.SMTP != cfg.SMTP},
+	} {
+		if setting.changed {
+			warnings = append(warnings, fmt.Sprintf("%s changed; restart ns-engine to apply it", setting.key))
+		}
+	}
+	return warnings
+}
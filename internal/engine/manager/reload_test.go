@@ -0,0 +1,203 @@
+package manager
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/impl/exact/statistic"
+	"Go2NetSpectra/internal/model"
+)
+
+// reloadTestConfig configures exact tasks keyed on SrcIP and a gob writer
+// whose snapshots only happen on reload or stop.
+func reloadTestConfig(root string, tasks ...string) *config.Config {
+	cfg := &config.Config{
+		Aggregator: config.AggregatorConfig{
+			Types:  []string{"exact"},
+			Period: "1h",
+			Exact: config.ExactAggregatorConfig{
+				Writers: []config.WriterDef{
+					{Type: "gob", Enabled: true, SnapshotInterval: "1h", Gob: config.GobConfig{RootPath: root}},
+				},
+			},
+		},
+	}
+	for _, name := range tasks {
+		cfg.Aggregator.Exact.Tasks = append(cfg.Aggregator.Exact.Tasks, config.ExactTaskDef{Name: name, NumShards: 4, KeyFields: []string{"SrcIP"}})
+	}
+	return cfg
+}
+
+func startReloadTestManager(t *testing.T, cfg *config.Config) *Manager {
+	t.Helper()
+	m, err := NewManager(cfg)
+	if err != nil {
+		t.Fatalf("NewManager() unexpected error: %v", err)
+	}
+	m.Start()
+	t.Cleanup(m.Stop)
+
+	packet := &model.PacketInfo{
+		Timestamp: time.Unix(1700000000, 0),
+		FiveTuple: model.FiveTuple{SrcIP: net.IP{10, 0, 0, 1}, DstIP: net.IP{10, 0, 0, 2}, SrcPort: 1234, DstPort: 80, Protocol: 6},
+		Length:    100,
+	}
+	if err := m.processPacket(packet); err != nil {
+		t.Fatalf("processPacket() unexpected error: %v", err)
+	}
+	return m
+}
+
+func flowCount(t *testing.T, task model.Task) int {
+	t.Helper()
+	snapshot, ok := task.Snapshot().(statistic.SnapshotData)
+	if !ok {
+		t.Fatalf("Snapshot() = %T, want statistic.SnapshotData", task.Snapshot())
+	}
+	flows := 0
+	for _, shard := range snapshot.Shards {
+		flows += len(shard.Flows)
+	}
+	return flows
+}
+
+func taskByName(m *Manager, name string) model.Task {
+	for _, task := range m.groupTasks("exact") {
+		if task.Name() == name {
+			return task
+		}
+	}
+	return nil
+}
+
+// snapshotWritten reports whether the gob writer under root wrote a snapshot of task.
+func snapshotWritten(t *testing.T, root, task string) bool {
+	t.Helper()
+	dirs, err := filepath.Glob(filepath.Join(root, "*", task))
+	if err != nil {
+		t.Fatalf("filepath.Glob() unexpected error: %v", err)
+	}
+	return len(dirs) > 0
+}
+
+func TestManagerReloadKeepsUnchangedTasks(t *testing.T) {
+	root := t.TempDir()
+	m := startReloadTestManager(t, reloadTestConfig(root, "kept", "removed", "changed"))
+	kept := taskByName(m, "kept")
+
+	cfg := reloadTestConfig(root, "kept", "changed", "added")
+	cfg.Aggregator.Exact.Tasks[1].KeyFields = []string{"DstIP"}
+	result, err := m.Reload(cfg)
+	if err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+
+	if !slices.Equal(result.TasksKept, []string{"exact/kept"}) {
+		t.Fatalf("TasksKept = %v, want [exact/kept]", result.TasksKept)
+	}
+	if !slices.Equal(result.TasksAdded, []string{"exact/changed", "exact/added"}) {
+		t.Fatalf("TasksAdded = %v, want [exact/changed exact/added]", result.TasksAdded)
+	}
+	slices.Sort(result.TasksRemoved)
+	if !slices.Equal(result.TasksRemoved, []string{"exact/changed", "exact/removed"}) {
+		t.Fatalf("TasksRemoved = %v, want [exact/changed exact/removed]", result.TasksRemoved)
+	}
+	if result.WritersKept != 1 || result.WritersStarted != 0 || result.WritersStopped != 0 {
+		t.Fatalf("writers kept/started/stopped = %d/%d/%d, want 1/0/0", result.WritersKept, result.WritersStarted, result.WritersStopped)
+	}
+
+	if got := taskByName(m, "kept"); got != kept {
+		t.Fatal("unchanged task was replaced by Reload()")
+	}
+	if got := flowCount(t, kept); got != 1 {
+		t.Fatalf("flows of unchanged task = %d, want 1", got)
+	}
+	if got := flowCount(t, taskByName(m, "changed")); got != 0 {
+		t.Fatalf("flows of changed task = %d, want 0", got)
+	}
+	if taskByName(m, "removed") != nil {
+		t.Fatal("removed task still runs after Reload()")
+	}
+	if !snapshotWritten(t, root, "removed") {
+		t.Fatal("removed task got no final snapshot")
+	}
+	if snapshotWritten(t, root, "kept") {
+		t.Fatal("kept task was snapshotted by Reload()")
+	}
+}
+
+func TestManagerReloadStopsRemovedWriters(t *testing.T) {
+	oldRoot, newRoot := t.TempDir(), t.TempDir()
+	m := startReloadTestManager(t, reloadTestConfig(oldRoot, "flows"))
+
+	result, err := m.Reload(reloadTestConfig(newRoot, "flows"))
+	if err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if result.WritersKept != 0 || result.WritersStarted != 1 || result.WritersStopped != 1 {
+		t.Fatalf("writers kept/started/stopped = %d/%d/%d, want 0/1/1", result.WritersKept, result.WritersStarted, result.WritersStopped)
+	}
+	if !snapshotWritten(t, oldRoot, "flows") {
+		t.Fatal("removed writer got no final snapshot")
+	}
+
+	m.Stop()
+	if !snapshotWritten(t, newRoot, "flows") {
+		t.Fatal("new writer did not snapshot on Stop()")
+	}
+	if got := flowCount(t, taskByName(m, "flows")); got != 1 {
+		t.Fatalf("flows after writer change = %d, want 1", got)
+	}
+}
+
+func TestManagerReloadRejectsInvalidConfig(t *testing.T) {
+	root := t.TempDir()
+	m := startReloadTestManager(t, reloadTestConfig(root, "flows"))
+	task := taskByName(m, "flows")
+
+	duplicate := reloadTestConfig(root, "flows", "flows")
+	badInterval := reloadTestConfig(root, "other")
+	badInterval.Aggregator.Exact.Writers[0].SnapshotInterval = "soon"
+	unknownType := reloadTestConfig(root, "flows")
+	unknownType.Aggregator.Types = []string{"exact", "bogus"}
+	badRule := reloadTestConfig(root, "flows")
+	badRule.Alerter = config.AlerterConfig{Enabled: true, CheckInterval: "1m", Rules: []config.AlerterRule{{Name: "r", TaskName: "flows", Operator: "!="}}}
+
+	for name, cfg := range map[string]*config.Config{
+		"duplicate task":   duplicate,
+		"bad interval":     badInterval,
+		"unknown type":     unknownType,
+		"unknown operator": badRule,
+	} {
+		if _, err := m.Reload(cfg); err == nil {
+			t.Fatalf("Reload(%s) error = nil, want non-nil", name)
+		}
+	}
+
+	if got := m.groupTasks("exact"); len(got) != 1 || got[0] != task {
+		t.Fatalf("tasks after rejected reloads = %v, want the original task", got)
+	}
+	if entries, err := os.ReadDir(root); err != nil || len(entries) != 0 {
+		t.Fatalf("snapshots after rejected reloads = %d, %v, want none", len(entries), err)
+	}
+}
+
+func TestManagerReloadWarnsAboutRestartOnlySettings(t *testing.T) {
+	root := t.TempDir()
+	m := startReloadTestManager(t, reloadTestConfig(root, "flows"))
+
+	cfg := reloadTestConfig(root, "flows")
+	cfg.Aggregator.NumWorkers = 8
+	result, err := m.Reload(cfg)
+	if err != nil {
+		t.Fatalf("Reload() unexpected error: %v", err)
+	}
+	if len(result.Warnings) != 1 {
+		t.Fatalf("Warnings = %v, want one for aggregator.num_workers", result.Warnings)
+	}
+}
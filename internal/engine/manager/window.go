@@ -0,0 +1,107 @@
+package manager
+
+import (
+	"log"
+	"time"
+
+	"Go2NetSpectra/internal/engine/window"
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/model"
+)
+
+// windower closes the windows of one windowed task as their ends pass and
+// writes them to the writers of the task's aggregator type.
+type windower struct {
+	group  string
+	task   *window.Task
+	retire chan struct{} // closed by Reload when the task is removed
+	exited chan struct{}
+}
+
+// startWindowers starts a windower for every windowed task of group. The
+// caller holds reloadMu.
+func (m *Manager) startWindowers(group factory.TaskGroup) {
+	for _, task := range group.Tasks {
+		windowed, ok := task.(*window.Task)
+		if !ok {
+			continue
+		}
+		w := &windower{group: group.Type, task: windowed, retire: make(chan struct{}), exited: make(chan struct{})}
+		m.windowers = append(m.windowers, w)
+		m.snapshotterWg.Add(1)
+		go m.runWindower(w)
+		spec := windowed.Spec()
+		log.Printf("Started windows of %s (hop %s, offset %s) for task %s.", spec.Size, spec.Hop, spec.Offset, task.Name())
+	}
+}
+
+// retireWindowers stops the windowers whose task is not in groups. The
+// caller holds reloadMu.
+func (m *Manager) retireWindowers(groups []factory.TaskGroup) {
+	running := make(map[model.Task]bool)
+	for _, group := range groups {
+		for _, task := range group.Tasks {
+			running[task] = true
+		}
+	}
+	kept := m.windowers[:0]
+	for _, w := range m.windowers {
+		if running[w.task] {
+			kept = append(kept, w)
+			continue
+		}
+		close(w.retire)
+		<-w.exited
+	}
+	m.windowers = kept
+}
+
+// runWindower waits for each window end. Windows still open when the manager
+// stops get their final snapshot from the snapshotters instead.
+func (m *Manager) runWindower(w *windower) {
+	defer m.snapshotterWg.Done()
+	defer close(w.exited)
+
+	for {
+		timer := time.NewTimer(time.Until(w.task.NextClose()))
+		select {
+		case now := <-timer.C:
+			m.writeClosedWindows(w.group, w.task, w.task.Advance(now))
+		case <-w.retire:
+			timer.Stop()
+			return
+		case <-m.done:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// writeClosedWindows writes the final snapshots of closed windows to every
+// writer of the aggregator type, stamped with the window end.
+func (m *Manager) writeClosedWindows(aggType string, task model.Task, closed []window.Closed) {
+	var writers []model.Writer
+	for _, group := range m.currentGroups() {
+		if group.Type == aggType {
+			writers = group.Writers
+		}
+	}
+	for _, c := range closed {
+		timestamp := c.Window.End.Format("2006-01-02_15-04-05")
+		for _, writer := range writers {
+			if err := writer.Write(c.Snapshot, timestamp, c.Window, task.Name(), task.Fields(), task.DecodeFlowFunc()); err != nil {
+				log.Printf("Error writing window %s of task %s: %v", timestamp, task.Name(), err)
+			}
+		}
+	}
+}
+
+// snapshotWindow snapshots a task together with the window it is measuring:
+// its own for windowed tasks, the current aggregator period otherwise.
+func (m *Manager) snapshotWindow(task model.Task) (any, model.Window) {
+	if windowed, ok := task.(*window.Task); ok {
+		return windowed.SnapshotWindow()
+	}
+	start := time.Unix(0, m.periodStart.Load())
+	return task.Snapshot(), model.Window{Start: start, End: start.Add(m.period)}
+}
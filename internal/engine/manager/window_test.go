@@ -0,0 +1,68 @@
+package manager
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/engine/window"
+	"Go2NetSpectra/internal/factory"
+	"Go2NetSpectra/internal/model"
+)
+
+type recordingWriter struct {
+	mu      sync.Mutex
+	windows []model.Window
+}
+
+func (w *recordingWriter) Write(payload interface{}, timestamp string, window model.Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.windows = append(w.windows, window)
+	return nil
+}
+
+func (w *recordingWriter) Interval() time.Duration { return time.Hour }
+
+func (w *recordingWriter) written() []model.Window {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]model.Window(nil), w.windows...)
+}
+
+func TestManagerWritesClosedWindows(t *testing.T) {
+	const size = 50 * time.Millisecond
+	writer := &recordingWriter{}
+	task := window.New(window.Spec{Size: size, Hop: size}, func() model.Task { return &stubTask{} }, time.Now())
+	groups := []factory.TaskGroup{
+		{Type: "exact", Tasks: []model.Task{task}, Writers: []model.Writer{writer}},
+	}
+	m := &Manager{
+		taskGroups:    groups,
+		taskCounters:  taskPacketCounters(groups),
+		packetChannel: make(chan *model.PacketInfo, 1),
+		done:          make(chan struct{}),
+		numWorkers:    1,
+		period:        time.Hour,
+	}
+	m.Start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(writer.written()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	m.Stop()
+
+	windows := writer.written()
+	if len(windows) < 2 {
+		t.Fatalf("written windows = %d, want at least 2", len(windows))
+	}
+	for i, w := range windows[:2] {
+		if w.End.Sub(w.Start) != size || w.Start.UnixNano()%int64(size) != 0 {
+			t.Fatalf("window %d = [%v, %v), want %s long and aligned to %s", i, w.Start, w.End, size, size)
+		}
+	}
+	if !windows[1].Start.Equal(windows[0].End) {
+		t.Fatalf("second window starts at %v, want %v", windows[1].Start, windows[0].End)
+	}
+}
@@ -8,22 +8,36 @@ import (
 
 	"Go2NetSpectra/internal/config"
 	"Go2NetSpectra/internal/engine/manager"
+	"Go2NetSpectra/internal/protocol"
 	"Go2NetSpectra/pkg/pcap"
 )
 
 // RunAnalyzer runs the offline analyzer against a pcap file.
 func RunAnalyzer(cfg *config.Config, pcapFilePath string) error {
+	if err := pcap.ValidateCapture(cfg.Probe.Capture); err != nil {
+		return fmt.Errorf("invalid capture config: %w", err)
+	}
+
 	managerImpl, err := manager.NewManager(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create manager: %w", err)
 	}
 	log.Println("Manager initialized.")
 
+	parser, err := protocol.NewParser(cfg.Probe.Decap)
+	if err != nil {
+		return fmt.Errorf("invalid decap config: %w", err)
+	}
+
 	pcapReader, err := pcap.NewReader(pcapFilePath)
 	if err != nil {
 		return fmt.Errorf("failed to open pcap file: %w", err)
 	}
 	defer pcapReader.Close()
+	pcapReader.SetParser(parser)
+	if err := pcapReader.SetFilter(cfg.Probe.Capture.BPFFilter); err != nil {
+		return err
+	}
 	log.Printf("Reading packets from %q...", pcapFilePath)
 
 	managerImpl.Start()
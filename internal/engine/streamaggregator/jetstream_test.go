@@ -0,0 +1,207 @@
+package streamaggregator
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+	"Go2NetSpectra/internal/probe"
+
+	"github.com/google/gopacket"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func runJetStreamServer(t *testing.T) *server.Server {
+	t.Helper()
+	srv, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      -1,
+		JetStream: true,
+		StoreDir:  t.TempDir(),
+		NoLog:     true,
+		NoSigs:    true,
+	})
+	if err != nil {
+		t.Fatalf("server.NewServer() unexpected error: %v", err)
+	}
+	go srv.Start()
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("embedded nats-server not ready")
+	}
+	t.Cleanup(srv.Shutdown)
+	return srv
+}
+
+func jetStreamProbeConfig(srv *server.Server) config.ProbeConfig {
+	return config.ProbeConfig{
+		NATSURL: srv.ClientURL(),
+		Subject: "test.packets",
+		JetStream: config.JetStreamConfig{
+			Enabled:    true,
+			Stream:     "TEST_PACKETS",
+			MaxAge:     "1h",
+			AckWait:    "1s",
+			MaxDeliver: 3,
+		},
+	}
+}
+
+func publishPorts(t *testing.T, cfg config.ProbeConfig, ports ...uint16) {
+	t.Helper()
+	pub, err := probe.NewPublisher(cfg)
+	if err != nil {
+		t.Fatalf("NewPublisher() unexpected error: %v", err)
+	}
+	defer pub.Close()
+
+	for _, port := range ports {
+		info := &model.PacketInfo{
+			Timestamp: time.Unix(1700000000, 0),
+			Length:    64,
+			FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("192.0.2.1"), DstIP: net.ParseIP("192.0.2.2"), SrcPort: port, DstPort: 80, Protocol: 6},
+		}
+		if err := pub.Publish(gopacket.CaptureInfo{}, nil, info); err != nil {
+			t.Fatalf("Publish() unexpected error: %v", err)
+		}
+	}
+}
+
+// startJetStreamAggregator consumes cfg's stream into input, without a manager.
+func startJetStreamAggregator(t *testing.T, cfg config.ProbeConfig, input chan *model.PacketInfo) *StreamAggregator {
+	t.Helper()
+	nc, err := nats.Connect(cfg.NATSURL)
+	if err != nil {
+		t.Fatalf("nats.Connect() unexpected error: %v", err)
+	}
+	partitions, err := probe.AssignedPartitions(cfg.Partitions, nil)
+	if err != nil {
+		t.Fatalf("AssignedPartitions() unexpected error: %v", err)
+	}
+	sa := &StreamAggregator{nc: nc, input: newInput(t, input), probeCfg: cfg, partitions: partitions}
+	if err := sa.consumeJetStream(); err != nil {
+		nc.Close()
+		t.Fatalf("consumeJetStream() unexpected error: %v", err)
+	}
+	return sa
+}
+
+func stopJetStreamAggregator(t *testing.T, sa *StreamAggregator) {
+	t.Helper()
+	sa.stopConsuming()
+	if err := sa.nc.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	sa.nc.Close()
+}
+
+func receivePorts(t *testing.T, input chan *model.PacketInfo, n int) []uint16 {
+	t.Helper()
+	ports := make([]uint16, 0, n)
+	for len(ports) < n {
+		select {
+		case packet := <-input:
+			ports = append(ports, packet.FiveTuple.SrcPort)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("received ports %v, want %d packets", ports, n)
+		}
+	}
+	select {
+	case packet := <-input:
+		t.Fatalf("received unexpected extra packet from port %d", packet.FiveTuple.SrcPort)
+	case <-time.After(100 * time.Millisecond):
+	}
+	return ports
+}
+
+func TestJetStreamDurableConsumerResumesAfterRestart(t *testing.T) {
+	cfg := jetStreamProbeConfig(runJetStreamServer(t))
+	input := make(chan *model.PacketInfo, 16)
+
+	// Packets published while no engine is running are kept by the stream.
+	publishPorts(t, cfg, 1, 2, 3)
+	sa := startJetStreamAggregator(t, cfg, input)
+	if got := receivePorts(t, input, 3); got[0] != 1 || got[2] != 3 {
+		t.Fatalf("first run ports = %v, want [1 2 3]", got)
+	}
+	stopJetStreamAggregator(t, sa)
+
+	// A restarted engine only sees what it has not acknowledged yet.
+	publishPorts(t, cfg, 4, 5)
+	sa = startJetStreamAggregator(t, cfg, input)
+	if got := receivePorts(t, input, 2); got[0] != 4 || got[1] != 5 {
+		t.Fatalf("second run ports = %v, want [4 5]", got)
+	}
+	stopJetStreamAggregator(t, sa)
+}
+
+func TestJetStreamReplayFromSequence(t *testing.T) {
+	cfg := jetStreamProbeConfig(runJetStreamServer(t))
+	input := make(chan *model.PacketInfo, 16)
+
+	publishPorts(t, cfg, 1, 2, 3, 4)
+	sa := startJetStreamAggregator(t, cfg, input)
+	receivePorts(t, input, 4)
+	stopJetStreamAggregator(t, sa)
+
+	replayCfg := cfg
+	replayCfg.JetStream.ReplayFrom = "3"
+	sa = startJetStreamAggregator(t, replayCfg, input)
+	if got := receivePorts(t, input, 2); got[0] != 3 || got[1] != 4 {
+		t.Fatalf("replay ports = %v, want [3 4]", got)
+	}
+	stopJetStreamAggregator(t, sa)
+
+	// The replay must not have moved the durable consumer.
+	publishPorts(t, cfg, 5)
+	sa = startJetStreamAggregator(t, cfg, input)
+	if got := receivePorts(t, input, 1); got[0] != 5 {
+		t.Fatalf("durable ports after replay = %v, want [5]", got)
+	}
+	stopJetStreamAggregator(t, sa)
+}
+
+func TestJetStreamTerminatesUndecodableMessages(t *testing.T) {
+	cfg := jetStreamProbeConfig(runJetStreamServer(t))
+	input := make(chan *model.PacketInfo, 16)
+
+	sa := startJetStreamAggregator(t, cfg, input)
+	defer stopJetStreamAggregator(t, sa)
+
+	js, err := jetstream.New(sa.nc)
+	if err != nil {
+		t.Fatalf("jetstream.New() unexpected error: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := js.Publish(ctx, cfg.Subject, []byte("GN\x09garbage")); err != nil {
+		t.Fatalf("Publish(garbage) unexpected error: %v", err)
+	}
+	publishPorts(t, cfg, 7)
+	if got := receivePorts(t, input, 1); got[0] != 7 {
+		t.Fatalf("ports = %v, want [7]", got)
+	}
+
+	consumer, err := js.Consumer(ctx, "TEST_PACKETS", probe.DefaultConsumerName)
+	if err != nil {
+		t.Fatalf("Consumer() unexpected error: %v", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info, err := consumer.Info(ctx)
+		if err != nil {
+			t.Fatalf("Info() unexpected error: %v", err)
+		}
+		if info.NumAckPending == 0 && info.NumRedelivered == 0 && info.AckFloor.Stream == 2 {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("consumer ack pending/redelivered/ack floor = %d/%d/%d, want 0/0/2", info.NumAckPending, info.NumRedelivered, info.AckFloor.Stream)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
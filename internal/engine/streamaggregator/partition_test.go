@@ -0,0 +1,89 @@
+package streamaggregator
+
+import (
+	"net"
+	"testing"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+	"Go2NetSpectra/internal/probe"
+
+	"github.com/nats-io/nats.go"
+)
+
+// startPartitionAggregator subscribes to the assigned partitions in the default queue group, without a manager.
+func startPartitionAggregator(t *testing.T, cfg config.ProbeConfig, assigned []int, input chan *model.PacketInfo) *StreamAggregator {
+	t.Helper()
+	partitions, err := probe.AssignedPartitions(cfg.Partitions, assigned)
+	if err != nil {
+		t.Fatalf("AssignedPartitions() unexpected error: %v", err)
+	}
+	nc, err := nats.Connect(cfg.NATSURL)
+	if err != nil {
+		t.Fatalf("nats.Connect() unexpected error: %v", err)
+	}
+	sa := &StreamAggregator{nc: nc, input: newInput(t, input), probeCfg: cfg, queueGroup: probe.DefaultQueueGroup, partitions: partitions}
+	if err := sa.subscribe(); err != nil {
+		nc.Close()
+		t.Fatalf("subscribe() unexpected error: %v", err)
+	}
+	if err := nc.Flush(); err != nil {
+		t.Fatalf("Flush() unexpected error: %v", err)
+	}
+	t.Cleanup(func() {
+		sa.stopConsuming()
+		nc.Close()
+	})
+	return sa
+}
+
+// portPartition returns the partition of the flow publishPorts sends from port.
+func portPartition(port uint16, partitions int) int {
+	info := &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("192.0.2.1"), DstIP: net.ParseIP("192.0.2.2"), SrcPort: port, DstPort: 80, Protocol: 6}}
+	return probe.Partition(info, partitions)
+}
+
+func TestPartitionedEnginesEachReceiveTheirOwnFlows(t *testing.T) {
+	srv := runJetStreamServer(t)
+	cfg := config.ProbeConfig{NATSURL: srv.ClientURL(), Subject: "test.packets", Partitions: 2}
+	inputs := []chan *model.PacketInfo{make(chan *model.PacketInfo, 64), make(chan *model.PacketInfo, 64)}
+	startPartitionAggregator(t, cfg, []int{0}, inputs[0])
+	startPartitionAggregator(t, cfg, []int{1}, inputs[1])
+
+	ports := make([]uint16, 20)
+	for i := range ports {
+		ports[i] = uint16(1000 + i)
+	}
+	publishPorts(t, cfg, ports...)
+
+	want := make([]int, 2)
+	for _, port := range ports {
+		want[portPartition(port, 2)]++
+	}
+	for partition, input := range inputs {
+		for _, port := range receivePorts(t, input, want[partition]) {
+			if got := portPartition(port, 2); got != partition {
+				t.Fatalf("engine %d received port %d of partition %d", partition, port, got)
+			}
+		}
+	}
+}
+
+func TestQueueGroupDeliversEachPacketOnce(t *testing.T) {
+	srv := runJetStreamServer(t)
+	cfg := config.ProbeConfig{NATSURL: srv.ClientURL(), Subject: "test.packets", Partitions: 2}
+	input := make(chan *model.PacketInfo, 64)
+	// Two engines consuming every partition share one input, as if one engine.
+	startPartitionAggregator(t, cfg, nil, input)
+	startPartitionAggregator(t, cfg, nil, input)
+
+	publishPorts(t, cfg, 1, 2, 3, 4, 5, 6, 7, 8)
+	got := receivePorts(t, input, 8)
+	seen := make(map[uint16]bool, len(got))
+	for _, port := range got {
+		if seen[port] {
+			t.Fatalf("received ports %v, want each port once", got)
+		}
+		seen[port] = true
+	}
+}
@@ -1,46 +1,79 @@
 package streamaggregator
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"time"
 
 	"Go2NetSpectra/internal/config"
 	"Go2NetSpectra/internal/engine/manager"
-	"Go2NetSpectra/internal/model"
 	"Go2NetSpectra/internal/probe"
 
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
 // StreamAggregator consumes packets from NATS and uses a model.Manager to aggregate them.
+// With JetStream enabled it reads through a pull consumer and acknowledges each
+// message once its packets are queued, so unacknowledged messages are
+// redelivered after a restart. When the manager falls behind, the overload
+// policy decides whether the callback waits or packets are dropped.
+//
+// Engines share the probe's partitions through a NATS queue group, or through
+// a shared durable consumer per partition with JetStream, so each packet is
+// aggregated by exactly one engine. Engines assigned disjoint partitions also
+// see every flow whole; otherwise the querier sums a flow's rows across engines.
 type StreamAggregator struct {
-	nc           *nats.Conn
-	sub          *nats.Subscription
-	manager      *manager.Manager
-	inputChannel chan<- *model.PacketInfo
-	natsURL      string
-	natsSubject  string
+	nc          *nats.Conn
+	subs        []*nats.Subscription
+	consumeCtxs []jetstream.ConsumeContext
+	manager     *manager.Manager
+	input       *manager.Input
+	probeCfg    config.ProbeConfig
+	queueGroup  string
+	partitions  []int // assigned partitions
 }
 
 // NewStreamAggregator creates a new real-time stream aggregator.
 func NewStreamAggregator(cfg *config.Config) (*StreamAggregator, error) {
+	partitions, err := probe.AssignedPartitions(cfg.Probe.Partitions, cfg.Aggregator.Cluster.Partitions)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Probe.JetStream.Enabled {
+		if _, err := probe.StreamConfig(cfg.Probe.JetStream, cfg.Probe.Subject); err != nil {
+			return nil, err
+		}
+		if _, err := probe.ConsumerConfig(cfg.Probe.JetStream, cfg.Probe.Subject); err != nil {
+			return nil, err
+		}
+	}
+
 	// The new manager will handle the actual aggregation.
 	mgr, err := manager.NewManager(cfg)
 	if err != nil {
 		return nil, err
 	}
 
+	queueGroup := cfg.Aggregator.Cluster.QueueGroup
+	if queueGroup == "" {
+		queueGroup = probe.DefaultQueueGroup
+	}
+
 	return &StreamAggregator{
-		manager:      mgr,
-		inputChannel: mgr.InputChannel(), // Get the channel from the manager
-		natsURL:      cfg.Probe.NATSURL,
-		natsSubject:  cfg.Probe.Subject,
+		manager:    mgr,
+		input:      mgr.Input(),
+		probeCfg:   cfg.Probe,
+		queueGroup: queueGroup,
+		partitions: partitions,
 	}, nil
 }
 
 // Start connects to NATS, starts the underlying manager, and begins processing messages.
 func (sa *StreamAggregator) Start() error {
-	log.Println("StreamAggregator starting for nats: ", sa.natsURL)
-	nc, err := nats.Connect(sa.natsURL)
+	log.Println("StreamAggregator starting for nats: ", sa.probeCfg.NATSURL)
+	nc, err := nats.Connect(sa.probeCfg.NATSURL)
 	if err != nil {
 		return err
 	}
@@ -49,25 +82,115 @@ func (sa *StreamAggregator) Start() error {
 	// The manager starts its own worker pool and snapshotter.
 	sa.manager.Start()
 
-	sa.sub, err = sa.nc.Subscribe(sa.natsSubject, sa.handlePacket)
+	if sa.probeCfg.JetStream.Enabled {
+		err = sa.consumeJetStream()
+	} else {
+		err = sa.subscribe()
+	}
 	if err != nil {
+		sa.stopConsuming()
 		sa.nc.Close()
 		sa.nc = nil
 		sa.manager.Stop()
 		return err
 	}
-	log.Printf("StreamAggregator subscribed to '%s'", sa.natsSubject)
 	return nil
 }
 
-// Stop gracefully shuts down the aggregator.
-func (sa *StreamAggregator) Stop() {
-	log.Println("StreamAggregator stopping...")
-	if sa.sub != nil {
-		if err := sa.sub.Unsubscribe(); err != nil {
+// Reload applies the tasks, writers and alert rules of cfg to the running
+// manager. NATS and partition settings are not reloaded.
+func (sa *StreamAggregator) Reload(cfg *config.Config) (*manager.ReloadResult, error) {
+	return sa.manager.Reload(cfg)
+}
+
+// subscribe joins the queue group on every assigned partition subject.
+func (sa *StreamAggregator) subscribe() error {
+	subjects := probe.PartitionSubjects(sa.probeCfg)
+	for _, partition := range sa.partitions {
+		sub, err := sa.nc.QueueSubscribe(subjects[partition], sa.queueGroup, sa.handlePacket)
+		if err != nil {
+			return fmt.Errorf("failed to subscribe to %s: %w", subjects[partition], err)
+		}
+		sa.subs = append(sa.subs, sub)
+		log.Printf("StreamAggregator subscribed to '%s' in queue group %s", subjects[partition], sa.queueGroup)
+	}
+	return nil
+}
+
+// consumeJetStream sets up the stream and a pull consumer per assigned
+// partition and starts delivering messages to handleJetStreamMsg. Engines
+// assigned the same partition pull from the same durable consumer.
+func (sa *StreamAggregator) consumeJetStream() error {
+	js, err := jetstream.New(sa.nc)
+	if err != nil {
+		return fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	jsCfg := sa.probeCfg.JetStream
+	stream, err := probe.EnsureStream(ctx, js, jsCfg, probe.AllPartitionsSubject(sa.probeCfg))
+	if err != nil {
+		return err
+	}
+	fetchBatch := jsCfg.FetchBatch
+	if fetchBatch <= 0 {
+		fetchBatch = probe.DefaultFetchBatch
+	}
+
+	subjects := probe.PartitionSubjects(sa.probeCfg)
+	for _, partition := range sa.partitions {
+		consumerCfg, err := probe.ConsumerConfig(jsCfg, subjects[partition])
+		if err != nil {
+			return err
+		}
+		if len(subjects) > 1 && consumerCfg.Durable != "" {
+			consumerCfg.Durable = fmt.Sprintf("%s-p%d", consumerCfg.Durable, partition)
+		}
+		consumer, err := stream.CreateOrUpdateConsumer(ctx, consumerCfg)
+		if err != nil {
+			return fmt.Errorf("failed to set up jetstream consumer: %w", err)
+		}
+
+		consumeCtx, err := consumer.Consume(sa.handleJetStreamMsg,
+			jetstream.PullMaxMessages(fetchBatch),
+			jetstream.ConsumeErrHandler(func(_ jetstream.ConsumeContext, err error) {
+				log.Printf("StreamAggregator jetstream consumer error: %v", err)
+			}),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to start jetstream consumer: %w", err)
+		}
+		sa.consumeCtxs = append(sa.consumeCtxs, consumeCtx)
+
+		if jsCfg.ReplayFrom != "" {
+			log.Printf("StreamAggregator replaying '%s' of stream %s from %s", subjects[partition], consumer.CachedInfo().Stream, jsCfg.ReplayFrom)
+		} else {
+			log.Printf("StreamAggregator consuming '%s' of stream %s with durable consumer %s", subjects[partition], consumer.CachedInfo().Stream, consumer.CachedInfo().Name)
+		}
+	}
+	return nil
+}
+
+// stopConsuming unsubscribes from every partition and stops the JetStream consumers.
+func (sa *StreamAggregator) stopConsuming() {
+	for _, sub := range sa.subs {
+		if err := sub.Unsubscribe(); err != nil {
 			log.Printf("StreamAggregator failed to unsubscribe: %v", err)
 		}
 	}
+	sa.subs = nil
+	for _, consumeCtx := range sa.consumeCtxs {
+		consumeCtx.Stop()
+	}
+	sa.consumeCtxs = nil
+}
+
+// Stop gracefully shuts down the aggregator.
+func (sa *StreamAggregator) Stop() {
+	log.Println("StreamAggregator stopping...")
+	sa.stopConsuming()
 	if sa.nc != nil {
 		if err := sa.nc.Drain(); err != nil {
 			log.Printf("StreamAggregator failed to drain NATS connection: %v", err)
@@ -80,14 +203,39 @@ func (sa *StreamAggregator) Stop() {
 	log.Println("StreamAggregator stopped.")
 }
 
-// handlePacket decodes the message and passes it to the manager's channel.
+// handlePacket decodes the message, a single packet or a batch, and passes
+// each packet to the manager's channel.
 func (sa *StreamAggregator) handlePacket(msg *nats.Msg) {
-	packet, err := probe.UnmarshalPacketInfo(msg.Data)
-	if err != nil {
+	if err := sa.dispatch(msg.Data); err != nil {
 		log.Printf("Error unmarshalling thrift packet: %v", err)
+	}
+}
+
+// handleJetStreamMsg dispatches a JetStream message and acknowledges it. A
+// message that cannot be decoded is terminated rather than redelivered.
+func (sa *StreamAggregator) handleJetStreamMsg(msg jetstream.Msg) {
+	if err := sa.dispatch(msg.Data()); err != nil {
+		log.Printf("Error unmarshalling thrift packet: %v", err)
+		if err := msg.Term(); err != nil {
+			log.Printf("StreamAggregator failed to terminate message: %v", err)
+		}
 		return
 	}
+	if err := msg.Ack(); err != nil {
+		log.Printf("StreamAggregator failed to acknowledge message: %v", err)
+	}
+}
 
-	// Pass the decoded packet to the manager's channel for concurrent processing.
-	sa.inputChannel <- &packet
+// dispatch decodes a packet message and passes its packets to the manager's
+// channel for concurrent processing. Packets the overload policy drops are
+// counted by the input and otherwise treated as delivered.
+func (sa *StreamAggregator) dispatch(data []byte) error {
+	packets, err := probe.UnmarshalPackets(data)
+	if err != nil {
+		return err
+	}
+	for i := range packets {
+		sa.input.Enqueue(&packets[i])
+	}
+	return nil
 }
@@ -6,15 +6,27 @@ import (
 	"testing"
 	"time"
 
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/engine/manager"
 	"Go2NetSpectra/internal/model"
 	"Go2NetSpectra/internal/probe"
 
 	"github.com/nats-io/nats.go"
 )
 
+// newInput returns a blocking input feeding ch.
+func newInput(t *testing.T, ch chan *model.PacketInfo) *manager.Input {
+	t.Helper()
+	input, err := manager.NewInput(ch, config.OverloadConfig{})
+	if err != nil {
+		t.Fatalf("NewInput() unexpected error: %v", err)
+	}
+	return input
+}
+
 func TestHandlePacketRoutesDecodedPacket(t *testing.T) {
 	input := make(chan *model.PacketInfo, 1)
-	aggregator := &StreamAggregator{inputChannel: input}
+	aggregator := &StreamAggregator{input: newInput(t, input)}
 
 	expected := &model.PacketInfo{
 		Timestamp: time.Unix(1700000020, 321),
@@ -54,9 +66,47 @@ func TestHandlePacketRoutesDecodedPacket(t *testing.T) {
 	}
 }
 
+func TestHandlePacketRoutesEveryPacketInBatch(t *testing.T) {
+	input := make(chan *model.PacketInfo, 3)
+	aggregator := &StreamAggregator{input: newInput(t, input)}
+
+	packets := make([]*model.PacketInfo, 3)
+	for i := range packets {
+		packets[i] = &model.PacketInfo{
+			Timestamp: time.Unix(1700000020, int64(i)),
+			Length:    100 + i,
+			FiveTuple: model.FiveTuple{
+				SrcIP:    net.ParseIP("192.0.2.10"),
+				DstIP:    net.ParseIP("198.51.100.20"),
+				SrcPort:  uint16(1000 + i),
+				DstPort:  80,
+				Protocol: 6,
+			},
+		}
+	}
+
+	payload, err := probe.MarshalPacketBatch(nil, packets, probe.EncodingZstd)
+	if err != nil {
+		t.Fatalf("MarshalPacketBatch() unexpected error: %v", err)
+	}
+
+	aggregator.handlePacket(&nats.Msg{Data: payload})
+
+	for i, want := range packets {
+		select {
+		case got := <-input:
+			if got.Length != want.Length || got.FiveTuple.SrcPort != want.FiveTuple.SrcPort {
+				t.Fatalf("decoded packet %d length/src port = %d/%d, want %d/%d", i, got.Length, got.FiveTuple.SrcPort, want.Length, want.FiveTuple.SrcPort)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("handlePacket() forwarded %d packets, want %d", i, len(packets))
+		}
+	}
+}
+
 func TestHandlePacketRejectsLegacyProtobufPayload(t *testing.T) {
 	input := make(chan *model.PacketInfo, 1)
-	aggregator := &StreamAggregator{inputChannel: input}
+	aggregator := &StreamAggregator{input: newInput(t, input)}
 
 	payload, err := hex.DecodeString("0a060880e2cfaa0612140a04c000020a1204c633641418bb0320fb412806188001")
 	if err != nil {
@@ -71,3 +121,35 @@ func TestHandlePacketRejectsLegacyProtobufPayload(t *testing.T) {
 	case <-time.After(100 * time.Millisecond):
 	}
 }
+
+func TestHandlePacketDropsWhenChannelFullUnderDropNewest(t *testing.T) {
+	ch := make(chan *model.PacketInfo, 1)
+	input, err := manager.NewInput(ch, config.OverloadConfig{Policy: manager.OverloadDropNewest})
+	if err != nil {
+		t.Fatalf("NewInput() unexpected error: %v", err)
+	}
+	aggregator := &StreamAggregator{input: input}
+
+	packets := []*model.PacketInfo{
+		{Timestamp: time.Unix(1700000020, 0), Length: 100, FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("192.0.2.10"), DstIP: net.ParseIP("198.51.100.20"), Protocol: 17}},
+		{Timestamp: time.Unix(1700000021, 0), Length: 200, FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("192.0.2.10"), DstIP: net.ParseIP("198.51.100.20"), Protocol: 17}},
+	}
+	payload, err := probe.MarshalPacketBatch(nil, packets, probe.EncodingZstd)
+	if err != nil {
+		t.Fatalf("MarshalPacketBatch() unexpected error: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		aggregator.handlePacket(&nats.Msg{Data: payload})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handlePacket() blocked on a full channel, want the packet dropped")
+	}
+	if got := (<-ch).Length; got != 100 {
+		t.Fatalf("queued packet length = %d, want 100", got)
+	}
+}
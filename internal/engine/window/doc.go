@@ -0,0 +1,2 @@
+// Package window runs aggregation tasks over tumbling or sliding windows aligned to wall-clock boundaries.
+package window
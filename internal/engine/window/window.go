@@ -0,0 +1,310 @@
+package window
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+// maxPanes bounds Size/Hop, since every packet is processed once per pane.
+const maxPanes = 60
+
+// Spec is a parsed window definition.
+type Spec struct {
+	Size   time.Duration
+	Hop    time.Duration // equal to Size for tumbling windows
+	Offset time.Duration
+}
+
+// ParseSpec parses a task's window config. ok is false when no window is configured.
+func ParseSpec(cfg config.WindowConfig) (spec Spec, ok bool, err error) {
+	if cfg.Size == "" {
+		return Spec{}, false, nil
+	}
+	if spec.Size, err = time.ParseDuration(cfg.Size); err != nil {
+		return Spec{}, false, fmt.Errorf("invalid window size: %w", err)
+	}
+	if spec.Size <= 0 {
+		return Spec{}, false, fmt.Errorf("window size must be a positive duration")
+	}
+	spec.Hop = spec.Size
+	if cfg.Hop != "" {
+		if spec.Hop, err = time.ParseDuration(cfg.Hop); err != nil {
+			return Spec{}, false, fmt.Errorf("invalid window hop: %w", err)
+		}
+		if spec.Hop <= 0 || spec.Hop > spec.Size || spec.Size%spec.Hop != 0 {
+			return Spec{}, false, fmt.Errorf("window hop %s must be positive and divide size %s", spec.Hop, spec.Size)
+		}
+		if spec.Size/spec.Hop > maxPanes {
+			return Spec{}, false, fmt.Errorf("window size %s is more than %d hops of %s", spec.Size, maxPanes, spec.Hop)
+		}
+	}
+	if cfg.Offset != "" {
+		if spec.Offset, err = time.ParseDuration(cfg.Offset); err != nil {
+			return Spec{}, false, fmt.Errorf("invalid window offset: %w", err)
+		}
+	}
+	return spec, true, nil
+}
+
+// Floor returns the latest window boundary at or before t.
+func (s Spec) Floor(t time.Time) time.Time {
+	hop := s.Hop.Nanoseconds()
+	n := t.UnixNano() - s.Offset.Nanoseconds()
+	k := n / hop
+	if n%hop < 0 {
+		k--
+	}
+	return time.Unix(0, k*hop+s.Offset.Nanoseconds())
+}
+
+// NewTask returns newTask() unchanged when cfg configures no window, and a
+// windowed Task built from it otherwise.
+func NewTask(cfg config.WindowConfig, newTask func() model.Task) (model.Task, error) {
+	spec, ok, err := ParseSpec(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return newTask(), nil
+	}
+	return New(spec, newTask, time.Now()), nil
+}
+
+// Closed is the final snapshot of a window.
+type Closed struct {
+	Snapshot any
+	Window   model.Window
+}
+
+// Task measures a task over aligned windows. A tumbling window needs one
+// instance of the task; a sliding window keeps Size/Hop instances, the panes,
+// which all see every packet and close one Hop apart. Windows close when
+// Advance is called, not on their own. It implements model.PartitionedTask and
+// model.Checkpointer.
+type Task struct {
+	spec Spec
+
+	mu     sync.RWMutex
+	panes  []model.Task
+	starts []time.Time // start of the window each pane measures
+	oldest int         // pane whose window closes next
+}
+
+// New creates the panes with newTask and opens the windows that contain now.
+// Windows already open at now only measure from now on.
+func New(spec Spec, newTask func() model.Task, now time.Time) *Task {
+	n := int(spec.Size / spec.Hop)
+	t := &Task{spec: spec, panes: make([]model.Task, n), starts: make([]time.Time, n)}
+	for i := range t.panes {
+		t.panes[i] = newTask()
+	}
+	t.align(now)
+	return t
+}
+
+// align reopens the panes, oldest first, as the windows that contain now.
+func (t *Task) align(now time.Time) {
+	n := len(t.panes)
+	latest := t.spec.Floor(now)
+	for i := 0; i < n; i++ {
+		t.starts[(t.oldest+i)%n] = latest.Add(-time.Duration(n-1-i) * t.spec.Hop)
+	}
+}
+
+// Align discards what the panes measured and reopens them as the windows
+// that contain now, which may be earlier than the windows they had open.
+func (t *Task) Align(now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, pane := range t.panes {
+		pane.Reset()
+	}
+	t.align(now)
+}
+
+// Spec returns the window definition.
+func (t *Task) Spec() Spec {
+	return t.spec
+}
+
+// NextClose returns when the oldest open window closes.
+func (t *Task) NextClose() time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.starts[t.oldest].Add(t.spec.Size)
+}
+
+// Advance closes every window that ended at or before now, oldest first, and
+// reopens its pane as the newest window. Once every pane has closed, the
+// windows that would have followed saw no packets and are skipped.
+func (t *Task) Advance(now time.Time) []Closed {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	n := len(t.panes)
+	var closed []Closed
+	for {
+		start := t.starts[t.oldest]
+		end := start.Add(t.spec.Size)
+		if end.After(now) {
+			break
+		}
+		if len(closed) == n {
+			t.align(now)
+			break
+		}
+		pane := t.panes[t.oldest]
+		closed = append(closed, Closed{Snapshot: pane.Snapshot(), Window: model.Window{Start: start, End: end}})
+		pane.Reset()
+		t.starts[t.oldest] = start.Add(time.Duration(n) * t.spec.Hop)
+		t.oldest = (t.oldest + 1) % n
+	}
+	return closed
+}
+
+// SnapshotWindow snapshots the oldest open window, which has seen the most
+// packets, and returns the window it covers.
+func (t *Task) SnapshotWindow() (any, model.Window) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	start := t.starts[t.oldest]
+	return t.panes[t.oldest].Snapshot(), model.Window{Start: start, End: start.Add(t.spec.Size)}
+}
+
+// ProcessPacket adds the packet to every open window.
+func (t *Task) ProcessPacket(packet *model.PacketInfo) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, pane := range t.panes {
+		pane.ProcessPacket(packet)
+	}
+}
+
+// Partition partitions every pane that keeps state per worker.
+func (t *Task) Partition(workers int) {
+	for _, pane := range t.panes {
+		if partitioned, ok := pane.(model.PartitionedTask); ok {
+			partitioned.Partition(workers)
+		}
+	}
+}
+
+// ProcessPacketOn adds the packet to every open window from worker.
+func (t *Task) ProcessPacketOn(worker int, packet *model.PacketInfo) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, pane := range t.panes {
+		if partitioned, ok := pane.(model.PartitionedTask); ok {
+			partitioned.ProcessPacketOn(worker, packet)
+		} else {
+			pane.ProcessPacket(packet)
+		}
+	}
+}
+
+// Snapshot returns the snapshot of the oldest open window.
+func (t *Task) Snapshot() interface{} {
+	snapshot, _ := t.SnapshotWindow()
+	return snapshot
+}
+
+// Reset clears every open window without moving it.
+func (t *Task) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, pane := range t.panes {
+		pane.Reset()
+	}
+}
+
+// Name returns the task's name.
+func (t *Task) Name() string {
+	return t.panes[0].Name()
+}
+
+// Query looks up a flow in the oldest open window.
+func (t *Task) Query(flow []byte) uint64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.panes[t.oldest].Query(flow)
+}
+
+// Fields returns the task's key fields.
+func (t *Task) Fields() []string {
+	return t.panes[0].Fields()
+}
+
+// DecodeFlowFunc returns the task's flow decoder.
+func (t *Task) DecodeFlowFunc() func(flow []byte, fields []string) string {
+	return t.panes[0].DecodeFlowFunc()
+}
+
+// AlerterMsg evaluates the rules against the oldest open window.
+func (t *Task) AlerterMsg(rules []config.AlerterRule) string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.panes[t.oldest].AlerterMsg(rules)
+}
+
+// checkpointState is the saved state of a Task: the start of the window each
+// pane measured and what the pane's own Checkpoint wrote.
+type checkpointState struct {
+	Starts []time.Time
+	Panes  [][]byte
+}
+
+// Checkpoint saves every open window with the time it started.
+func (t *Task) Checkpoint(w io.Writer) error {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	state := checkpointState{Starts: append([]time.Time(nil), t.starts...), Panes: make([][]byte, len(t.panes))}
+	for i, pane := range t.panes {
+		checkpointer, ok := pane.(model.Checkpointer)
+		if !ok {
+			return fmt.Errorf("task %s does not support checkpoints", pane.Name())
+		}
+		var buf bytes.Buffer
+		if err := checkpointer.Checkpoint(&buf); err != nil {
+			return err
+		}
+		state.Panes[i] = buf.Bytes()
+	}
+	return gob.NewEncoder(w).Encode(state)
+}
+
+// Restore loads each saved window into the pane that has the same window
+// open. Windows that have closed since the checkpoint are dropped; their
+// final snapshots were written before it was taken.
+func (t *Task) Restore(r io.Reader) error {
+	var state checkpointState
+	if err := gob.NewDecoder(r).Decode(&state); err != nil {
+		return fmt.Errorf("failed to decode window state: %w", err)
+	}
+	if len(state.Starts) != len(state.Panes) {
+		return fmt.Errorf("window state has %d starts for %d panes", len(state.Starts), len(state.Panes))
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for i, start := range state.Starts {
+		for j, open := range t.starts {
+			if !open.Equal(start) {
+				continue
+			}
+			checkpointer, ok := t.panes[j].(model.Checkpointer)
+			if !ok {
+				return fmt.Errorf("task %s does not support checkpoints", t.panes[j].Name())
+			}
+			if err := checkpointer.Restore(bytes.NewReader(state.Panes[i])); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
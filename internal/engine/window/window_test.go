@@ -0,0 +1,213 @@
+package window
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+// countTask counts packets; its snapshot is the count.
+type countTask struct {
+	mu      sync.Mutex
+	packets int
+}
+
+func (c *countTask) ProcessPacket(packet *model.PacketInfo) {
+	c.mu.Lock()
+	c.packets++
+	c.mu.Unlock()
+}
+
+func (c *countTask) Snapshot() interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.packets
+}
+
+func (c *countTask) Reset() {
+	c.mu.Lock()
+	c.packets = 0
+	c.mu.Unlock()
+}
+
+func (c *countTask) Name() string             { return "count" }
+func (c *countTask) Query(flow []byte) uint64 { return 0 }
+func (c *countTask) Fields() []string         { return nil }
+func (c *countTask) DecodeFlowFunc() func(flow []byte, fields []string) string {
+	return func(flow []byte, fields []string) string { return "" }
+}
+func (c *countTask) AlerterMsg(rules []config.AlerterRule) string { return "" }
+
+func (c *countTask) Checkpoint(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%d", c.Snapshot())
+	return err
+}
+
+func (c *countTask) Restore(r io.Reader) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, err := fmt.Fscan(r, &c.packets)
+	return err
+}
+
+func newCountTask() model.Task { return &countTask{} }
+
+func TestParseSpec(t *testing.T) {
+	spec, ok, err := ParseSpec(config.WindowConfig{Size: "1m", Hop: "20s", Offset: "5s"})
+	if err != nil || !ok {
+		t.Fatalf("ParseSpec() = %v, %v, want ok", ok, err)
+	}
+	if spec != (Spec{Size: time.Minute, Hop: 20 * time.Second, Offset: 5 * time.Second}) {
+		t.Fatalf("ParseSpec() = %+v, want 1m/20s/5s", spec)
+	}
+	if spec, ok, err := ParseSpec(config.WindowConfig{Size: "1m"}); err != nil || !ok || spec.Hop != time.Minute {
+		t.Fatalf("ParseSpec(tumbling) = %+v, %v, %v, want hop 1m", spec, ok, err)
+	}
+	if _, ok, err := ParseSpec(config.WindowConfig{}); err != nil || ok {
+		t.Fatalf("ParseSpec(empty) = %v, %v, want not ok", ok, err)
+	}
+
+	for _, cfg := range []config.WindowConfig{
+		{Size: "soon"},
+		{Size: "-1m"},
+		{Size: "1m", Hop: "25s"},
+		{Size: "1m", Hop: "2m"},
+		{Size: "1h", Hop: "1s"},
+		{Size: "1m", Offset: "later"},
+	} {
+		if _, _, err := ParseSpec(cfg); err == nil {
+			t.Fatalf("ParseSpec(%+v) error = nil, want non-nil", cfg)
+		}
+	}
+}
+
+func TestSpecFloorAlignsToEpochAndOffset(t *testing.T) {
+	spec := Spec{Size: time.Minute, Hop: time.Minute, Offset: 10 * time.Second}
+	tests := []struct {
+		t, want time.Time
+	}{
+		{time.Unix(1700000055, 0), time.Unix(1700000050, 0)},
+		{time.Unix(1700000050, 0), time.Unix(1700000050, 0)},
+		{time.Unix(1700000049, 0), time.Unix(1699999990, 0)},
+		{time.Unix(-1, 0), time.Unix(-50, 0)},
+	}
+	for _, tt := range tests {
+		if got := spec.Floor(tt.t); !got.Equal(tt.want) {
+			t.Fatalf("Floor(%d) = %d, want %d", tt.t.Unix(), got.Unix(), tt.want.Unix())
+		}
+	}
+}
+
+func TestTumblingWindowClosesOnBoundary(t *testing.T) {
+	start := time.Unix(1700000040, 0) // 1700000040 is a multiple of 60
+	task := New(Spec{Size: time.Minute, Hop: time.Minute}, newCountTask, start.Add(15*time.Second))
+	task.ProcessPacket(&model.PacketInfo{})
+	task.ProcessPacket(&model.PacketInfo{})
+
+	if got := task.NextClose(); !got.Equal(start.Add(time.Minute)) {
+		t.Fatalf("NextClose() = %v, want %v", got, start.Add(time.Minute))
+	}
+	if closed := task.Advance(start.Add(59 * time.Second)); len(closed) != 0 {
+		t.Fatalf("Advance(before end) closed %d windows, want 0", len(closed))
+	}
+
+	closed := task.Advance(start.Add(time.Minute))
+	if len(closed) != 1 {
+		t.Fatalf("Advance(end) closed %d windows, want 1", len(closed))
+	}
+	if closed[0].Snapshot != 2 || !closed[0].Window.Start.Equal(start) || !closed[0].Window.End.Equal(start.Add(time.Minute)) {
+		t.Fatalf("closed window = %+v, want 2 packets in [%v, %v)", closed[0], start, start.Add(time.Minute))
+	}
+	snapshot, window := task.SnapshotWindow()
+	if snapshot != 0 || !window.Start.Equal(start.Add(time.Minute)) {
+		t.Fatalf("SnapshotWindow() after close = %v, %+v, want 0 in the next window", snapshot, window)
+	}
+}
+
+func TestSlidingWindowsOverlap(t *testing.T) {
+	base := time.Unix(1700000040, 0)
+	task := New(Spec{Size: 3 * time.Minute, Hop: time.Minute}, newCountTask, base)
+	task.ProcessPacket(&model.PacketInfo{})
+
+	// The three open windows all saw the packet and close a minute apart.
+	closed := task.Advance(base.Add(time.Minute))
+	if len(closed) != 1 || closed[0].Snapshot != 1 || !closed[0].Window.Start.Equal(base.Add(-2*time.Minute)) {
+		t.Fatalf("first closed window = %+v, want 1 packet from %v", closed, base.Add(-2*time.Minute))
+	}
+	task.ProcessPacket(&model.PacketInfo{})
+
+	closed = task.Advance(base.Add(2 * time.Minute))
+	if len(closed) != 1 || closed[0].Snapshot != 2 {
+		t.Fatalf("second closed window = %+v, want 2 packets", closed)
+	}
+	if got := closed[0].Window; !got.Start.Equal(base.Add(-time.Minute)) || !got.End.Equal(base.Add(2*time.Minute)) {
+		t.Fatalf("second window = [%v, %v), want [%v, %v)", got.Start, got.End, base.Add(-time.Minute), base.Add(2*time.Minute))
+	}
+
+	// The window opened after the first close only saw the second packet.
+	closed = task.Advance(base.Add(4 * time.Minute))
+	if len(closed) != 2 || closed[0].Snapshot != 2 || closed[1].Snapshot != 1 {
+		t.Fatalf("later closed windows = %+v, want 2 then 1 packets", closed)
+	}
+}
+
+func TestAdvanceSkipsWindowsAfterEveryPaneClosed(t *testing.T) {
+	base := time.Unix(1700000040, 0)
+	task := New(Spec{Size: 2 * time.Minute, Hop: time.Minute}, newCountTask, base)
+
+	now := base.Add(time.Hour + 30*time.Second)
+	if closed := task.Advance(now); len(closed) != 2 {
+		t.Fatalf("Advance() after an hour closed %d windows, want 2", len(closed))
+	}
+	if got, want := task.NextClose(), base.Add(time.Hour+time.Minute); !got.Equal(want) {
+		t.Fatalf("NextClose() = %v, want %v", got, want)
+	}
+}
+
+func TestAlignMovesWindowsBack(t *testing.T) {
+	base := time.Unix(1700000040, 0)
+	task := New(Spec{Size: time.Minute, Hop: time.Minute}, newCountTask, base.Add(time.Hour))
+	task.ProcessPacket(&model.PacketInfo{})
+
+	task.Align(base.Add(10 * time.Second))
+	snapshot, window := task.SnapshotWindow()
+	if snapshot != 0 || !window.Start.Equal(base) {
+		t.Fatalf("SnapshotWindow() after Align = %v, %+v, want 0 from %v", snapshot, window, base)
+	}
+}
+
+func TestRestoreLoadsWindowsStillOpen(t *testing.T) {
+	base := time.Unix(1700000040, 0)
+	spec := Spec{Size: time.Minute, Hop: 20 * time.Second}
+	task := New(spec, newCountTask, base.Add(5*time.Second))
+	task.ProcessPacket(&model.PacketInfo{})
+	task.ProcessPacket(&model.PacketInfo{})
+	task.Advance(base.Add(20 * time.Second)) // the window from base-40s closes
+	task.ProcessPacket(&model.PacketInfo{})
+
+	var buf bytes.Buffer
+	if err := task.Checkpoint(&buf); err != nil {
+		t.Fatalf("Checkpoint() unexpected error: %v", err)
+	}
+
+	// Restarted after the window from base-20s closed, the windows from base
+	// and base+20s are restored and the one from base+40s starts empty.
+	restored := New(spec, newCountTask, base.Add(45*time.Second))
+	if err := restored.Restore(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Restore() unexpected error: %v", err)
+	}
+	snapshot, window := restored.SnapshotWindow()
+	if snapshot != 3 || !window.Start.Equal(base) {
+		t.Fatalf("SnapshotWindow() = %v, %+v, want 3 packets from %v", snapshot, window, base)
+	}
+	closed := restored.Advance(base.Add(100 * time.Second))
+	if len(closed) != 3 || closed[1].Snapshot != 1 || closed[2].Snapshot != 0 {
+		t.Fatalf("Advance() closed %+v, want 3, 1 and 0 packets", closed)
+	}
+}
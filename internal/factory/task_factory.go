@@ -10,8 +10,12 @@ import (
 
 // TaskGroup is a logical grouping of tasks and their associated writers.
 type TaskGroup struct {
+	Type    string // aggregator type the group was created for
 	Tasks   []model.Task
 	Writers []model.Writer
+	// WriterDefs holds the definition each writer was created from, in the
+	// same order as Writers.
+	WriterDefs []config.WriterDef
 }
 
 // TaskFactory defines a function that creates a group of tasks and their writers.
@@ -35,14 +39,9 @@ func Create(cfg *config.Config) ([]TaskGroup, error) {
 	for _, aggType := range cfg.Aggregator.Types {
 		log.Printf("Creating tasks and writers for aggregator type: '%s'\n", aggType)
 
-		factory, ok := registry[aggType]
-		if !ok {
-			return nil, fmt.Errorf("unknown aggregator type: '%s'", aggType)
-		}
-
-		group, err := factory(cfg)
+		group, err := CreateGroup(cfg, aggType)
 		if err != nil {
-			return nil, fmt.Errorf("error creating aggregator type '%s': %w", aggType, err)
+			return nil, err
 		}
 
 		taskGroups = append(taskGroups, *group)
@@ -50,3 +49,34 @@ func Create(cfg *config.Config) ([]TaskGroup, error) {
 
 	return taskGroups, nil
 }
+
+// CreateGroup creates the tasks and writers of a single aggregator type.
+func CreateGroup(cfg *config.Config, aggType string) (*TaskGroup, error) {
+	factory, ok := registry[aggType]
+	if !ok {
+		return nil, fmt.Errorf("unknown aggregator type: '%s'", aggType)
+	}
+
+	group, err := factory(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("error creating aggregator type '%s': %w", aggType, err)
+	}
+	group.Type = aggType
+
+	return group, nil
+}
+
+// WriterName returns the name of the writer def configured at index among
+// the writers of aggType: its own name, or one made of its type and position.
+func WriterName(aggType string, index int, def config.WriterDef) string {
+	if def.Name != "" {
+		return def.Name
+	}
+	return fmt.Sprintf("%s/%s/%d", aggType, def.Type, index)
+}
+
+// Registered reports whether an aggregator type has a factory.
+func Registered(aggType string) bool {
+	_, ok := registry[aggType]
+	return ok
+}
@@ -43,3 +43,57 @@ func TestCreateUsesRegisteredAggregators(t *testing.T) {
 		t.Fatalf("len(taskGroups) = %d, want 2", got)
 	}
 }
+
+func TestCreateRejectsUnknownKeyFields(t *testing.T) {
+	for _, agg := range []config.AggregatorConfig{
+		{
+			Types: []string{"exact"},
+			Exact: config.ExactAggregatorConfig{
+				Tasks: []config.ExactTaskDef{{Name: "typo", KeyFields: []string{"SrcIP", "DstPrt"}}},
+			},
+		},
+		{
+			Types: []string{"sketch"},
+			Sketch: config.SketchAggregatorConfig{
+				Tasks: []config.SketchTaskDef{{Name: "typo", FlowFields: []string{"SrcIP"}, ElementFields: []string{"Dst"}, Width: 8, Depth: 2}},
+			},
+		},
+	} {
+		if _, err := factory.Create(&config.Config{Aggregator: agg}); err == nil {
+			t.Fatalf("Create(%v) error = nil, want non-nil", agg.Types)
+		}
+	}
+}
+
+func TestCreateNamesWritersByDefinition(t *testing.T) {
+	dir := t.TempDir()
+	cfg := &config.Config{
+		Aggregator: config.AggregatorConfig{
+			Types: []string{"exact"},
+			Exact: config.ExactAggregatorConfig{
+				Writers: []config.WriterDef{
+					{Type: "gob", Enabled: true, SnapshotInterval: "1m", Gob: config.GobConfig{RootPath: dir + "/a"}},
+					{Type: "gob", Enabled: true, SnapshotInterval: "1m", Gob: config.GobConfig{RootPath: dir + "/b"}},
+					{Name: "archive", Type: "gob", Enabled: true, SnapshotInterval: "1m", Gob: config.GobConfig{RootPath: dir + "/c"}},
+				},
+				Tasks: []config.ExactTaskDef{{Name: "per_src", KeyFields: []string{"SrcIP"}, NumShards: 1}},
+			},
+		},
+	}
+
+	taskGroups, err := factory.Create(cfg)
+	if err != nil {
+		t.Fatalf("Create() unexpected error: %v", err)
+	}
+
+	want := []string{"exact/gob/0", "exact/gob/1", "archive"}
+	defs := taskGroups[0].WriterDefs
+	if len(defs) != len(want) {
+		t.Fatalf("len(WriterDefs) = %d, want %d", len(defs), len(want))
+	}
+	for i, def := range defs {
+		if def.Name != want[i] {
+			t.Fatalf("WriterDefs[%d].Name = %q, want %q", i, def.Name, want[i])
+		}
+	}
+}
@@ -0,0 +1,3 @@
+// Package keyfield is the registry of packet fields that exact and sketch
+// tasks aggregate on, shared by the tasks, their writers and the query service.
+package keyfield
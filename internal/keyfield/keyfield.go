@@ -0,0 +1,207 @@
+package keyfield
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// Field is a packet field tasks can be keyed on.
+type Field struct {
+	Name string
+	// Base is set on a prefix field such as "SrcIP/24" to the address field
+	// it masks, in whose column its values are stored.
+	Base string
+	// Size is the width of the field in a binary flow key.
+	Size int
+	// Column is the ClickHouse type of the field's flow_metrics column,
+	// which is Nullable there since a task keys on only some fields.
+	Column string
+
+	// Value extracts the field from a packet, as exact flows store it and
+	// ClickHouse receives it.
+	Value func(p *model.PacketInfo) any
+	// Encode writes the field of a packet into buf[:Size].
+	Encode func(buf []byte, p *model.PacketInfo)
+	// Decode returns the value of a field encoded in buf[:Size].
+	Decode func(buf []byte) any
+	// Format returns the string form of a value, used in exact flow keys
+	// and decoded sketch flows. Register defaults it to FormatValue.
+	Format func(v any) string
+}
+
+var (
+	registry = make(map[string]*Field)
+	ordered  []*Field
+)
+
+// Register adds a field to the registry. It panics if the name is taken or
+// contains a "/", which marks prefix fields.
+func Register(f Field) {
+	if _, exists := registry[f.Name]; exists {
+		panic(fmt.Sprintf("key field '%s' already registered", f.Name))
+	}
+	if strings.Contains(f.Name, "/") {
+		panic(fmt.Sprintf("key field '%s' contains a '/'", f.Name))
+	}
+	if f.Format == nil {
+		f.Format = FormatValue
+	}
+	registry[f.Name] = &f
+	ordered = append(ordered, &f)
+}
+
+// Lookup returns the field registered under name, or the prefix field it
+// names, such as "SrcIP/24".
+func Lookup(name string) (*Field, bool) {
+	f, err := lookup(name)
+	return f, err == nil
+}
+
+func lookup(name string) (*Field, error) {
+	if f, ok := registry[name]; ok {
+		return f, nil
+	}
+	if strings.Contains(name, "/") {
+		return lookupPrefix(name)
+	}
+	return nil, fmt.Errorf("unknown key field: %s", name)
+}
+
+// Resolve returns the fields named by names, in order, or an error naming
+// the first that is unknown, an invalid prefix or stored in the same column
+// as an earlier one, such as "SrcIP/24" after "SrcIP".
+func Resolve(names []string) ([]*Field, error) {
+	fields := make([]*Field, len(names))
+	columns := make(map[string]string, len(names))
+	for i, name := range names {
+		f, err := lookup(name)
+		if err != nil {
+			return nil, err
+		}
+		if prev, ok := columns[f.ColumnName()]; ok {
+			return nil, fmt.Errorf("key fields %s and %s share the %s column", prev, name, f.ColumnName())
+		}
+		columns[f.ColumnName()] = name
+		fields[i] = f
+	}
+	return fields, nil
+}
+
+// ColumnName returns the flow_metrics column the field's values go to.
+func (f *Field) ColumnName() string {
+	if f.Base != "" {
+		return f.Base
+	}
+	return f.Name
+}
+
+// All returns every registered field in registration order, which is the
+// order of their flow_metrics columns. Prefix fields are not included.
+func All() []*Field {
+	return ordered
+}
+
+// Size returns the width of a binary key made of fields.
+func Size(fields []*Field) int {
+	size := 0
+	for _, f := range fields {
+		size += f.Size
+	}
+	return size
+}
+
+// FormatValue formats the values the built-in fields extract.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10)
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+const (
+	ipSize     = net.IPv6len
+	uint8Size  = 1
+	uint16Size = 2
+	uint32Size = 4
+)
+
+func init() {
+	// Registration order is the column order of flow_metrics.
+	registerIP("SrcIP", func(p *model.PacketInfo) net.IP { return p.FiveTuple.SrcIP })
+	registerIP("DstIP", func(p *model.PacketInfo) net.IP { return p.FiveTuple.DstIP })
+	registerUint16("SrcPort", func(p *model.PacketInfo) uint16 { return p.FiveTuple.SrcPort })
+	registerUint16("DstPort", func(p *model.PacketInfo) uint16 { return p.FiveTuple.DstPort })
+	Register(Field{
+		Name:   "Protocol",
+		Size:   uint8Size,
+		Column: "UInt8",
+		Value:  func(p *model.PacketInfo) any { return p.FiveTuple.Protocol },
+		Encode: func(buf []byte, p *model.PacketInfo) { buf[0] = p.FiveTuple.Protocol },
+		Decode: func(buf []byte) any { return buf[0] },
+	})
+	registerUint32("TunnelID", func(p *model.PacketInfo) uint32 { return p.TunnelID })
+	registerUint16("OuterVLAN", func(p *model.PacketInfo) uint16 { return p.OuterVLAN })
+	registerUint16("InnerVLAN", func(p *model.PacketInfo) uint16 { return p.InnerVLAN })
+	registerUint32("MPLSLabel", func(p *model.PacketInfo) uint32 { return p.MPLSLabel })
+	registerUint32("InterfaceID", func(p *model.PacketInfo) uint32 { return p.InterfaceID })
+	registerIP("ExporterIP", func(p *model.PacketInfo) net.IP { return p.ExporterIP })
+}
+
+// registerIP registers an address field. Packets without the address, such
+// as captured packets for ExporterIP, have the empty string as its value.
+func registerIP(name string, get func(p *model.PacketInfo) net.IP) {
+	addressFields[name] = get
+	Register(Field{
+		Name:   name,
+		Size:   ipSize,
+		Column: "String",
+		Value: func(p *model.PacketInfo) any {
+			if ip := get(p); ip != nil {
+				return ip.String()
+			}
+			return ""
+		},
+		Encode: func(buf []byte, p *model.PacketInfo) {
+			// A missing address leaves no stale bytes of a pooled buffer.
+			if copy(buf[:ipSize], get(p).To16()) == 0 {
+				clear(buf[:ipSize])
+			}
+		},
+		Decode: func(buf []byte) any { return net.IP(buf[:ipSize]).String() },
+	})
+}
+
+func registerUint16(name string, get func(p *model.PacketInfo) uint16) {
+	Register(Field{
+		Name:   name,
+		Size:   uint16Size,
+		Column: "UInt16",
+		Value:  func(p *model.PacketInfo) any { return get(p) },
+		Encode: func(buf []byte, p *model.PacketInfo) { binary.BigEndian.PutUint16(buf, get(p)) },
+		Decode: func(buf []byte) any { return binary.BigEndian.Uint16(buf) },
+	})
+}
+
+func registerUint32(name string, get func(p *model.PacketInfo) uint32) {
+	Register(Field{
+		Name:   name,
+		Size:   uint32Size,
+		Column: "UInt32",
+		Value:  func(p *model.PacketInfo) any { return get(p) },
+		Encode: func(buf []byte, p *model.PacketInfo) { binary.BigEndian.PutUint32(buf, get(p)) },
+		Decode: func(buf []byte) any { return binary.BigEndian.Uint32(buf) },
+	})
+}
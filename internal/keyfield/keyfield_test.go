@@ -0,0 +1,149 @@
+package keyfield
+
+import (
+	"net"
+	"testing"
+
+	"Go2NetSpectra/internal/model"
+)
+
+func TestFieldsRoundTripThroughEncoding(t *testing.T) {
+	packet := &model.PacketInfo{
+		FiveTuple: model.FiveTuple{
+			SrcIP:    net.ParseIP("10.0.0.1").To4(),
+			DstIP:    net.ParseIP("2001:db8::2"),
+			SrcPort:  40000,
+			DstPort:  443,
+			Protocol: 6,
+		},
+		TunnelID:    4096,
+		OuterVLAN:   100,
+		InnerVLAN:   200,
+		MPLSLabel:   16,
+		InterfaceID: 3,
+	}
+	want := map[string]string{
+		"SrcIP":       "10.0.0.1",
+		"DstIP":       "2001:db8::2",
+		"SrcPort":     "40000",
+		"DstPort":     "443",
+		"Protocol":    "6",
+		"TunnelID":    "4096",
+		"OuterVLAN":   "100",
+		"InnerVLAN":   "200",
+		"MPLSLabel":   "16",
+		"InterfaceID": "3",
+		"ExporterIP":  "",
+	}
+	if len(All()) != len(want) {
+		t.Fatalf("All() = %d fields, want %d", len(All()), len(want))
+	}
+
+	buf := make([]byte, Size(All()))
+	for i := range buf {
+		buf[i] = 0xff // as a reused pool buffer would be
+	}
+	offset := 0
+	for _, field := range All() {
+		field.Encode(buf[offset:], packet)
+		offset += field.Size
+	}
+
+	offset = 0
+	for _, field := range All() {
+		if got := field.Format(field.Value(packet)); got != want[field.Name] {
+			t.Fatalf("%s value = %q, want %q", field.Name, got, want[field.Name])
+		}
+		decoded := field.Format(field.Decode(buf[offset:]))
+		offset += field.Size
+		if field.Name == "ExporterIP" {
+			// Exact keys and sketch keys tell a missing address apart differently.
+			if decoded != "::" {
+				t.Fatalf("ExporterIP decoded = %q, want ::", decoded)
+			}
+			continue
+		}
+		if decoded != want[field.Name] {
+			t.Fatalf("%s decoded = %q, want %q", field.Name, decoded, want[field.Name])
+		}
+	}
+}
+
+func TestResolveRejectsUnknownFields(t *testing.T) {
+	fields, err := Resolve([]string{"DstIP", "Protocol"})
+	if err != nil {
+		t.Fatalf("Resolve() unexpected error: %v", err)
+	}
+	if len(fields) != 2 || fields[0].Name != "DstIP" || fields[1].Size != 1 {
+		t.Fatalf("Resolve() = %v, want DstIP and Protocol", fields)
+	}
+	if _, err := Resolve([]string{"SrcIP", "SrcMAC"}); err == nil {
+		t.Fatalf("Resolve(SrcMAC) error = nil, want non-nil")
+	}
+}
+
+func TestResolveRejectsFieldsSharingAColumn(t *testing.T) {
+	for _, names := range [][]string{
+		{"SrcIP", "SrcIP"},
+		{"SrcIP", "SrcIP/24"},
+		{"DstIP/16", "Protocol", "DstIP/24"},
+	} {
+		if _, err := Resolve(names); err == nil {
+			t.Fatalf("Resolve(%v) error = nil, want non-nil", names)
+		}
+	}
+	if _, err := Resolve([]string{"SrcIP/24", "DstIP/24"}); err != nil {
+		t.Fatalf("Resolve(SrcIP/24, DstIP/24) unexpected error: %v", err)
+	}
+}
+
+func TestPrefixFieldsMaskEachFamily(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		ip   string
+		want string
+	}{
+		{"SrcIP/24", "10.1.2.3", "10.1.2.0/24"},
+		{"SrcIP/24", "2001:db8::1", "2001:db8::1/128"},
+		{"SrcIP/48", "10.1.2.3", "10.1.2.3/32"},
+		{"SrcIP/48", "2001:db8:1:2::1", "2001:db8:1::/48"},
+		{"SrcIP/16/64", "10.1.2.3", "10.1.0.0/16"},
+		{"SrcIP/16/64", "2001:db8:1:2::1", "2001:db8:1:2::/64"},
+	} {
+		field, ok := Lookup(tc.name)
+		if !ok {
+			t.Fatalf("Lookup(%s) found nothing", tc.name)
+		}
+		if field.ColumnName() != "SrcIP" || field.Size != ipSize {
+			t.Fatalf("%s column/size = %s/%d, want SrcIP/%d", tc.name, field.ColumnName(), field.Size, ipSize)
+		}
+		packet := &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: net.ParseIP(tc.ip)}}
+		if got := field.Format(field.Value(packet)); got != tc.want {
+			t.Fatalf("%s value of %s = %q, want %q", tc.name, tc.ip, got, tc.want)
+		}
+		buf := make([]byte, field.Size)
+		field.Encode(buf, packet)
+		if got := field.Format(field.Decode(buf)); got != tc.want {
+			t.Fatalf("%s decoded %s = %q, want %q", tc.name, tc.ip, got, tc.want)
+		}
+	}
+
+	for _, name := range []string{"SrcIP/33/", "SrcIP/129", "SrcIP/x", "SrcIP/24/129", "SrcIP/-1", "SrcIP/024", "SrcIP/+24", "SrcIP/24/064", "SrcPort/8", "Nope/24"} {
+		if _, err := Resolve([]string{name}); err == nil {
+			t.Fatalf("Resolve(%s) error = nil, want non-nil", name)
+		}
+	}
+}
+
+func TestPrefixFieldsCacheOnlyCanonicalNames(t *testing.T) {
+	for _, name := range []string{"DstIP/20", "DstIP/020", "DstIP/+20", "DstIP/0020"} {
+		Lookup(name)
+	}
+	prefixMu.Lock()
+	defer prefixMu.Unlock()
+	for _, name := range []string{"DstIP/020", "DstIP/+20", "DstIP/0020"} {
+		if _, ok := prefixFields[name]; ok {
+			t.Fatalf("prefix field cache holds %q, want only DstIP/20", name)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package keyfield
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"Go2NetSpectra/internal/model"
+)
+
+// addressFields holds the getters of the address fields, which can be
+// masked to a prefix by naming them as "SrcIP/24".
+var addressFields = make(map[string]func(p *model.PacketInfo) net.IP)
+
+var (
+	prefixMu     sync.Mutex
+	prefixFields = make(map[string]*Field)
+)
+
+// lookupPrefix returns the prefix field for a name such as "SrcIP/24",
+// creating it on first use. Only valid names are cached, and since lengths
+// must be canonical there are a bounded number of them.
+func lookupPrefix(name string) (*Field, error) {
+	prefixMu.Lock()
+	defer prefixMu.Unlock()
+	if f, ok := prefixFields[name]; ok {
+		return f, nil
+	}
+	base, lengths, _ := strings.Cut(name, "/")
+	get, ok := addressFields[base]
+	if !ok {
+		return nil, fmt.Errorf("unknown key field: %s (only address fields take a prefix)", name)
+	}
+	v4, v6, err := parsePrefixLengths(lengths)
+	if err != nil {
+		return nil, fmt.Errorf("key field %s: %w", name, err)
+	}
+	f := newPrefixField(name, base, get, v4, v6)
+	prefixFields[name] = f
+	return f, nil
+}
+
+// parsePrefixLengths parses the lengths of "SrcIP/24" and "SrcIP/24/64". A
+// single length up to 32 masks IPv4 addresses and one above masks IPv6
+// addresses, leaving the other family whole; two set both.
+func parsePrefixLengths(s string) (v4, v6 int, err error) {
+	first, second, both := strings.Cut(s, "/")
+	a, err := parseLength(first)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid prefix length %q", first)
+	}
+	if !both {
+		switch {
+		case a >= 0 && a <= 8*net.IPv4len:
+			return a, 8 * net.IPv6len, nil
+		case a > 8*net.IPv4len && a <= 8*net.IPv6len:
+			return 8 * net.IPv4len, a, nil
+		}
+		return 0, 0, fmt.Errorf("prefix length %d out of range", a)
+	}
+	b, err := parseLength(second)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid IPv6 prefix length %q", second)
+	}
+	if a < 0 || a > 8*net.IPv4len {
+		return 0, 0, fmt.Errorf("IPv4 prefix length %d out of range", a)
+	}
+	if b < 0 || b > 8*net.IPv6len {
+		return 0, 0, fmt.Errorf("IPv6 prefix length %d out of range", b)
+	}
+	return a, b, nil
+}
+
+// parseLength parses a prefix length written in canonical decimal, so that
+// each prefix field has one name and the field cache stays bounded.
+func parseLength(s string) (int, error) {
+	if s == "" || len(s) > 3 || (len(s) > 1 && s[0] == '0') {
+		return 0, strconv.ErrSyntax
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return 0, strconv.ErrSyntax
+		}
+	}
+	return strconv.Atoi(s)
+}
+
+// newPrefixField masks the address of base to v4 or v6 bits by family. Its
+// values are networks in CIDR notation, stored in the column of base.
+func newPrefixField(name, base string, get func(p *model.PacketInfo) net.IP, v4, v6 int) *Field {
+	v4Mask := net.CIDRMask(v4, 8*net.IPv4len)
+	v6Mask := net.CIDRMask(v6, 8*net.IPv6len)
+	mask := func(ip net.IP) (net.IP, int) {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.Mask(v4Mask), v4
+		}
+		return ip.Mask(v6Mask), v6
+	}
+	cidr := func(ip net.IP) string {
+		network, bits := mask(ip)
+		return network.String() + "/" + strconv.Itoa(bits)
+	}
+	return &Field{
+		Name:   name,
+		Base:   base,
+		Size:   ipSize,
+		Column: "String",
+		Value: func(p *model.PacketInfo) any {
+			if ip := get(p); ip != nil {
+				return cidr(ip)
+			}
+			return ""
+		},
+		Encode: func(buf []byte, p *model.PacketInfo) {
+			ip := get(p)
+			if ip == nil {
+				clear(buf[:ipSize])
+				return
+			}
+			network, _ := mask(ip)
+			copy(buf[:ipSize], network.To16())
+		},
+		Decode: func(buf []byte) any { return cidr(net.IP(buf[:ipSize])) },
+		Format: FormatValue,
+	}
+}
@@ -0,0 +1,2 @@
+// Package metrics keeps the operational metrics of a service and serves them at /metrics in the Prometheus text format.
+package metrics
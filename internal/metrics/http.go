@@ -0,0 +1,182 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Handler serves the default registry.
+func Handler() http.Handler {
+	return Default.Handler()
+}
+
+// Handler serves the registry in the Prometheus text exposition format.
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		if err := r.WriteText(w); err != nil {
+			log.Printf("Error writing metrics: %v", err)
+		}
+	})
+}
+
+// WriteText writes every family, sorted by name, in the text exposition format.
+func (r *Registry) WriteText(w io.Writer) error {
+	r.mu.Lock()
+	families := make([]*family, 0, len(r.families))
+	for _, f := range r.families {
+		families = append(families, f)
+	}
+	r.mu.Unlock()
+	sort.Slice(families, func(i, j int) bool { return families[i].name < families[j].name })
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		f.writeText(bw)
+	}
+	return bw.Flush()
+}
+
+func (f *family) writeText(w *bufio.Writer) {
+	f.mu.Lock()
+	collect := f.collect
+	series := make([]*Series, 0, len(f.series))
+	for _, s := range f.series {
+		series = append(series, s)
+	}
+	f.mu.Unlock()
+
+	if collect != nil {
+		series = series[:0]
+		collect(func(value float64, labelValues ...string) {
+			if len(labelValues) != len(f.labels) {
+				log.Printf("Warning: metric %s takes labels %v, collected values %v", f.name, f.labels, labelValues)
+				return
+			}
+			s := &Series{labelValues: labelValues}
+			s.Set(value)
+			series = append(series, s)
+		})
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return strings.Join(series[i].labelValues, "\xff") < strings.Join(series[j].labelValues, "\xff")
+	})
+
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, escapeHelp(f.help))
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+	for _, s := range series {
+		if f.kind != kindHistogram {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, f.labelText(s.labelValues, "", ""), formatValue(s.Value()))
+			continue
+		}
+		s.mu.Lock()
+		var cumulative uint64
+		for i, upper := range f.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelText(s.labelValues, "le", formatValue(upper)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, f.labelText(s.labelValues, "le", "+Inf"), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, f.labelText(s.labelValues, "", ""), formatValue(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, f.labelText(s.labelValues, "", ""), s.count)
+		s.mu.Unlock()
+	}
+}
+
+// labelText renders {name="value",...}, with an extra label when extraName is set.
+func (f *family) labelText(values []string, extraName, extraValue string) string {
+	if len(values) == 0 && extraName == "" {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range f.labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", name, labelEscaper.Replace(values[i]))
+	}
+	if extraName != "" {
+		if len(f.labels) > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, "%s=\"%s\"", extraName, extraValue)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func formatValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// Serve serves the default registry at /metrics on addr until ctx is done.
+func Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err, ok := <-errCh:
+		if ok {
+			return fmt.Errorf("failed to serve metrics on %s: %w", addr, err)
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down metrics server: %w", err)
+	}
+	return nil
+}
+
+// Start serves the default registry on addr in the background until ctx is
+// done, logging any failure. An empty addr disables the endpoint.
+func Start(ctx context.Context, addr string) {
+	if addr == "" {
+		return
+	}
+	go func() {
+		log.Printf("Metrics server starting on %s", addr)
+		if err := Serve(ctx, addr); err != nil {
+			log.Printf("Error: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,243 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Namespace prefixes every metric name, followed by the service, e.g.
+// netspectra_probe_packets_captured_total.
+const Namespace = "netspectra"
+
+// DefaultBuckets are histogram upper bounds in seconds, for request latencies.
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+const (
+	kindCounter   = "counter"
+	kindGauge     = "gauge"
+	kindHistogram = "histogram"
+)
+
+// Registry holds metric families by name.
+type Registry struct {
+	mu       sync.Mutex
+	families map[string]*family
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+// Default is the registry the package-level constructors and Handler use.
+var Default = NewRegistry()
+
+// family is a metric name with its series, one per combination of label values.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	series map[string]*Series
+	// collect, when set, reports the series at scrape time instead.
+	collect func(emit func(value float64, labelValues ...string))
+}
+
+// Series is one labelled value of a metric.
+type Series struct {
+	labelValues []string
+	bits        atomic.Uint64 // float64 value of a counter or gauge
+
+	// Histogram state.
+	mu     sync.Mutex
+	counts []uint64 // per bucket, not cumulative
+	sum    float64
+	count  uint64
+}
+
+// register returns the family called name, creating it if needed. Asking for
+// an existing name with another kind or labels is a programming error.
+func (r *Registry) register(name, help, kind string, labels []string, buckets []float64) *family {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f, ok := r.families[name]; ok {
+		if f.kind != kind || !slices.Equal(f.labels, labels) {
+			panic(fmt.Sprintf("metrics: %s registered as %s%v, now as %s%v", name, f.kind, f.labels, kind, labels))
+		}
+		return f
+	}
+	f := &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: make(map[string]*Series)}
+	r.families[name] = f
+	return f
+}
+
+// with returns the series for labelValues, creating it on first use.
+func (f *family) with(labelValues []string) *Series {
+	if len(labelValues) != len(f.labels) {
+		panic(fmt.Sprintf("metrics: %s takes labels %v, got values %v", f.name, f.labels, labelValues))
+	}
+	key := strings.Join(labelValues, "\xff")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s, ok := f.series[key]
+	if !ok {
+		s = &Series{labelValues: slices.Clone(labelValues)}
+		if f.kind == kindHistogram {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+// Add adds v to a counter or gauge series.
+func (s *Series) Add(v float64) {
+	for {
+		old := s.bits.Load()
+		if s.bits.CompareAndSwap(old, math.Float64bits(math.Float64frombits(old)+v)) {
+			return
+		}
+	}
+}
+
+// Inc adds one to a counter or gauge series.
+func (s *Series) Inc() {
+	s.Add(1)
+}
+
+// Set sets a gauge series to v.
+func (s *Series) Set(v float64) {
+	s.bits.Store(math.Float64bits(v))
+}
+
+// Value returns the current value of a counter or gauge series.
+func (s *Series) Value() float64 {
+	return math.Float64frombits(s.bits.Load())
+}
+
+// Counter is a value that only goes up, such as packets seen.
+type Counter struct{ f *family }
+
+// NewCounter registers a counter in the default registry.
+func NewCounter(name, help string, labels ...string) *Counter {
+	return Default.NewCounter(name, help, labels...)
+}
+
+// NewCounter registers a counter, or returns the one already registered as name.
+func (r *Registry) NewCounter(name, help string, labels ...string) *Counter {
+	return &Counter{f: r.register(name, help, kindCounter, labels, nil)}
+}
+
+// With returns the series for labelValues, for callers that update it often.
+func (c *Counter) With(labelValues ...string) *Series {
+	return c.f.with(labelValues)
+}
+
+// Inc adds one to the series for labelValues.
+func (c *Counter) Inc(labelValues ...string) {
+	c.f.with(labelValues).Add(1)
+}
+
+// Add adds v, which must not be negative, to the series for labelValues.
+func (c *Counter) Add(v float64, labelValues ...string) {
+	c.f.with(labelValues).Add(v)
+}
+
+// Gauge is a value that goes up and down, such as open sessions.
+type Gauge struct{ f *family }
+
+// NewGauge registers a gauge in the default registry.
+func NewGauge(name, help string, labels ...string) *Gauge {
+	return Default.NewGauge(name, help, labels...)
+}
+
+// NewGauge registers a gauge, or returns the one already registered as name.
+func (r *Registry) NewGauge(name, help string, labels ...string) *Gauge {
+	return &Gauge{f: r.register(name, help, kindGauge, labels, nil)}
+}
+
+// With returns the series for labelValues.
+func (g *Gauge) With(labelValues ...string) *Series {
+	return g.f.with(labelValues)
+}
+
+// Set sets the series for labelValues to v.
+func (g *Gauge) Set(v float64, labelValues ...string) {
+	g.f.with(labelValues).Set(v)
+}
+
+// Add adds v to the series for labelValues.
+func (g *Gauge) Add(v float64, labelValues ...string) {
+	g.f.with(labelValues).Add(v)
+}
+
+// Histogram counts observations, such as durations, in buckets.
+type Histogram struct{ f *family }
+
+// NewHistogram registers a histogram in the default registry.
+func NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	return Default.NewHistogram(name, help, buckets, labels...)
+}
+
+// NewHistogram registers a histogram with the given upper bounds, or
+// DefaultBuckets when nil, or returns the one already registered as name.
+func (r *Registry) NewHistogram(name, help string, buckets []float64, labels ...string) *Histogram {
+	if buckets == nil {
+		buckets = DefaultBuckets
+	}
+	buckets = slices.Clone(buckets)
+	sort.Float64s(buckets)
+	return &Histogram{f: r.register(name, help, kindHistogram, labels, buckets)}
+}
+
+// Observe records v in the series for labelValues.
+func (h *Histogram) Observe(v float64, labelValues ...string) {
+	s := h.f.with(labelValues)
+	i, _ := slices.BinarySearch(h.f.buckets, v)
+	s.mu.Lock()
+	if i < len(s.counts) {
+		s.counts[i]++
+	}
+	s.sum += v
+	s.count++
+	s.mu.Unlock()
+}
+
+// NewCounterFunc registers, in the default registry, a counter whose series
+// collect reports when the metrics are scraped. It suits totals another
+// component already keeps. Registering name again replaces collect.
+func NewCounterFunc(name, help string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	Default.NewCounterFunc(name, help, labels, collect)
+}
+
+// NewCounterFunc registers a counter reported by collect at scrape time.
+func (r *Registry) NewCounterFunc(name, help string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	r.registerFunc(name, help, kindCounter, labels, collect)
+}
+
+// NewGaugeFunc registers, in the default registry, a gauge whose series
+// collect reports when the metrics are scraped. Registering name again
+// replaces collect.
+func NewGaugeFunc(name, help string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	Default.NewGaugeFunc(name, help, labels, collect)
+}
+
+// NewGaugeFunc registers a gauge reported by collect at scrape time.
+func (r *Registry) NewGaugeFunc(name, help string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	r.registerFunc(name, help, kindGauge, labels, collect)
+}
+
+func (r *Registry) registerFunc(name, help, kind string, labels []string, collect func(emit func(value float64, labelValues ...string))) {
+	f := r.register(name, help, kind, labels, nil)
+	f.mu.Lock()
+	f.collect = collect
+	f.mu.Unlock()
+}
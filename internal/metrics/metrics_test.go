@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := r.WriteText(&buf); err != nil {
+		t.Fatalf("WriteText() unexpected error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestWriteTextCounterAndGauge(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_packets_total", "Packets seen.", "interface")
+	c.Add(3, "eth0")
+	c.Inc("eth0")
+	c.Inc(`we"ird`)
+	r.NewGauge("test_depth", "Queue depth.").Set(7)
+
+	want := `# HELP test_depth Queue depth.
+# TYPE test_depth gauge
+test_depth 7
+# HELP test_packets_total Packets seen.
+# TYPE test_packets_total counter
+test_packets_total{interface="eth0"} 4
+test_packets_total{interface="we\"ird"} 1
+`
+	if got := scrape(t, r); got != want {
+		t.Fatalf("WriteText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestWriteTextHistogram(t *testing.T) {
+	r := NewRegistry()
+	h := r.NewHistogram("test_seconds", "Durations.", []float64{1, 0.1}, "op")
+	h.Observe(0.05, "get")
+	h.Observe(0.1, "get")
+	h.Observe(0.5, "get")
+	h.Observe(3, "get")
+
+	got := scrape(t, r)
+	for _, line := range []string{
+		`test_seconds_bucket{op="get",le="0.1"} 2`,
+		`test_seconds_bucket{op="get",le="1"} 3`,
+		`test_seconds_bucket{op="get",le="+Inf"} 4`,
+		`test_seconds_sum{op="get"} 3.65`,
+		`test_seconds_count{op="get"} 4`,
+	} {
+		if !strings.Contains(got, line+"\n") {
+			t.Fatalf("WriteText() missing %q in\n%s", line, got)
+		}
+	}
+}
+
+func TestFuncMetricsCollectAtScrape(t *testing.T) {
+	r := NewRegistry()
+	depth := 1.0
+	r.NewGaugeFunc("test_depth", "Queue depth.", []string{"queue"}, func(emit func(float64, ...string)) {
+		emit(depth, "input")
+	})
+	depth = 5
+	if got := scrape(t, r); !strings.Contains(got, `test_depth{queue="input"} 5`+"\n") {
+		t.Fatalf("WriteText() = %q, want depth 5", got)
+	}
+}
+
+func TestRegisterReturnsExistingFamily(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "Total.", "a").Inc("x")
+	r.NewCounter("test_total", "Total.", "a").Inc("x")
+	if got := r.NewCounter("test_total", "Total.", "a").With("x").Value(); got != 2 {
+		t.Fatalf("counter value = %v, want 2", got)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("registering test_total as a gauge did not panic")
+		}
+	}()
+	r.NewGauge("test_total", "Total.", "a")
+}
+
+func TestHandlerServesTextFormat(t *testing.T) {
+	r := NewRegistry()
+	r.NewCounter("test_total", "Total.").Inc()
+	rec := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("Content-Type = %q, want text/plain", ct)
+	}
+	if !strings.Contains(rec.Body.String(), "test_total 1\n") {
+		t.Fatalf("body = %q, want test_total 1", rec.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+var (
+	rpcDuration = NewHistogram(Namespace+"_rpc_duration_seconds",
+		"Time to serve a Thrift RPC, by service and method.", nil, "service", "method")
+	rpcErrors = NewCounter(Namespace+"_rpc_errors_total",
+		"Thrift RPCs that returned an error, by service and method.", "service", "method")
+)
+
+// ThriftMiddleware records the latency and errors of every method of a Thrift
+// processor under the given service name. Wrap a processor with
+// thrift.WrapProcessor(processor, metrics.ThriftMiddleware("query")).
+func ThriftMiddleware(service string) thrift.ProcessorMiddleware {
+	return func(method string, next thrift.TProcessorFunction) thrift.TProcessorFunction {
+		return thrift.WrappedTProcessorFunction{
+			Wrapped: func(ctx context.Context, seqID int32, in, out thrift.TProtocol) (bool, thrift.TException) {
+				start := time.Now()
+				ok, err := next.Process(ctx, seqID, in, out)
+				rpcDuration.Observe(time.Since(start).Seconds(), service, method)
+				if err != nil {
+					rpcErrors.Inc(service, method)
+				}
+				return ok, err
+			},
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package model
+
+// SymmetricHash hashes the 5-tuple with its endpoints in a fixed order, so
+// both directions of a flow hash alike. The result is stable across processes
+// and hosts; different seeds give unrelated hashes, so decisions such as
+// sampling and partitioning taken on the same flows do not correlate.
+func (t *FiveTuple) SymmetricHash(seed uint64) uint64 {
+	srcIP, dstIP := t.SrcIP.To16(), t.DstIP.To16()
+	srcPort, dstPort := t.SrcPort, t.DstPort
+	if compareEndpoints(srcIP, srcPort, dstIP, dstPort) > 0 {
+		srcIP, dstIP = dstIP, srcIP
+		srcPort, dstPort = dstPort, srcPort
+	}
+
+	// FNV-1a, finished with the murmur3 finalizer so every output bit depends
+	// on the seed and the whole tuple.
+	const (
+		offset64 = 14695981039346656037
+		prime64  = 1099511628211
+	)
+	h := uint64(offset64) ^ seed
+	for _, ip := range [2][]byte{srcIP, dstIP} {
+		for _, b := range ip {
+			h = (h ^ uint64(b)) * prime64
+		}
+	}
+	for _, b := range [5]byte{byte(srcPort >> 8), byte(srcPort), byte(dstPort >> 8), byte(dstPort), t.Protocol} {
+		h = (h ^ uint64(b)) * prime64
+	}
+
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func compareEndpoints(ipA []byte, portA uint16, ipB []byte, portB uint16) int {
+	for i := 0; i < len(ipA) && i < len(ipB); i++ {
+		if ipA[i] != ipB[i] {
+			return int(ipA[i]) - int(ipB[i])
+		}
+	}
+	if len(ipA) != len(ipB) {
+		return len(ipA) - len(ipB)
+	}
+	return int(portA) - int(portB)
+}
@@ -14,9 +14,56 @@ type FiveTuple struct {
 	Protocol uint8
 }
 
+// TCP flag bits as they appear in the TCP header flags byte.
+const (
+	TCPFlagFIN uint8 = 1 << iota
+	TCPFlagSYN
+	TCPFlagRST
+	TCPFlagPSH
+	TCPFlagACK
+	TCPFlagURG
+	TCPFlagECE
+	TCPFlagCWR
+)
+
 // PacketInfo holds the metadata extracted from a single packet.
 type PacketInfo struct {
-	Timestamp time.Time
-	FiveTuple FiveTuple
-	Length    int
+	Timestamp   time.Time
+	FiveTuple   FiveTuple
+	Length      int
+	TCPFlags    uint8  // Zero for non-TCP packets.
+	TunnelID    uint32 // VXLAN/GENEVE VNI or GRE key of the tunnel the packet arrived in, zero if none.
+	OuterVLAN   uint16 // First 802.1Q/802.1ad tag, zero if untagged.
+	InnerVLAN   uint16 // Second tag of a QinQ frame, zero if absent.
+	MPLSLabel   uint32 // Top of the MPLS label stack, zero if absent.
+	InterfaceID uint32 // Probe capture interface the packet was seen on, zero if unknown.
+	SampleRate  uint32 // N when the probe forwarded 1 in N packets, zero or one if unsampled.
+
+	// Set by flow-export collectors, where one update summarises a flow record.
+	ExporterIP net.IP        // Router or switch that exported the record, nil for captured packets.
+	Packets    uint64        // Packets in the record, whose bytes are Length; zero for a single packet.
+	Duration   time.Duration // Time the record spans, ending at Timestamp.
+}
+
+// Weight is the number of original packets this packet stands for after sampling.
+func (p *PacketInfo) Weight() uint64 {
+	if p.SampleRate > 1 {
+		return uint64(p.SampleRate)
+	}
+	return 1
+}
+
+// PacketCount is the number of original packets this update stands for.
+func (p *PacketInfo) PacketCount() uint64 {
+	return max(p.Packets, 1) * p.Weight()
+}
+
+// ByteCount is the number of original bytes this update stands for.
+func (p *PacketInfo) ByteCount() uint64 {
+	return uint64(p.Length) * p.Weight()
+}
+
+// FirstSeen is when the traffic behind this update started.
+func (p *PacketInfo) FirstSeen() time.Time {
+	return p.Timestamp.Add(-p.Duration)
 }
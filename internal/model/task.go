@@ -1,6 +1,11 @@
 package model
 
-import "Go2NetSpectra/internal/config"
+import (
+	"io"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+)
 
 // Task defines a single, self-contained aggregation task (e.g., exact count or sketch).
 type Task interface {
@@ -13,3 +18,32 @@ type Task interface {
 	DecodeFlowFunc() func(flow []byte, fields []string) string
 	AlerterMsg(rules []config.AlerterRule) string
 }
+
+// PartitionedTask is implemented by tasks that can keep separate state for
+// each worker. With flow dispatch the manager calls Partition once before any
+// packet arrives, then ProcessPacketOn from each worker with its own index, so
+// workers never touch each other's state. Snapshot merges the partitions.
+type PartitionedTask interface {
+	Task
+	Partition(workers int)
+	ProcessPacketOn(worker int, packet *PacketInfo)
+}
+
+// Checkpointer is implemented by tasks whose state can outlive the process.
+// Restore loads what Checkpoint wrote for a task with the same definition. It
+// is called before the task sees any packet.
+type Checkpointer interface {
+	Checkpoint(w io.Writer) error
+	Restore(r io.Reader) error
+}
+
+// ExpiringTask is implemented by tasks whose records can end before the
+// period does, as NetFlow flows do on idle and active timeouts. The manager
+// sweeps such tasks: ExpireFlows removes the records that have ended by now
+// and returns them as a payload for the task's writers, or nil when none
+// have. When ExpiresFlows is true the task is also left out of period resets.
+type ExpiringTask interface {
+	Task
+	ExpiresFlows() bool
+	ExpireFlows(now time.Time) interface{}
+}
@@ -2,11 +2,33 @@ package model
 
 import "time"
 
+// Window is the measurement interval a snapshot covers. A snapshot taken
+// before the window closes has an End in the future.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// InputStats counts the packets offered to an engine since it started.
+type InputStats struct {
+	Enqueued      uint64 // accepted into the packet channel
+	Dropped       uint64 // refused by the overload policy
+	Processed     uint64 // handed to the tasks by a worker
+	QueueDepth    int    // packets waiting in the channel
+	QueueCapacity int
+}
+
+// StatsWriter is implemented by writers that record the engine's input
+// counters with every snapshot.
+type StatsWriter interface {
+	WriteStats(stats InputStats, timestamp string) error
+}
+
 // Writer defines a generic interface for writing aggregator data to a persistent store.
 type Writer interface {
 	// Write takes a data payload and persists it.
 	// The implementation is expected to know how to handle the payload type it receives.
-	Write(payload interface{}, timestamp, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error
+	Write(payload interface{}, timestamp string, window Window, name string, fields []string, decodeFlowFunc func(flow []byte, fields []string) string) error
 
 	// Interval returns the configured snapshot interval for this writer.
 	Interval() time.Duration
@@ -0,0 +1,200 @@
+package probe
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"Go2NetSpectra/internal/model"
+
+	v1 "Go2NetSpectra/api/gen/thrift/v1"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+	"github.com/klauspost/compress/zstd"
+	"github.com/pierrec/lz4/v4"
+)
+
+// Packet messages on the bus are either a bare thrift PacketInfo, as sent by
+// probes without batching, or a framed PacketBatch:
+//
+//	'G' 'N' | version | encoding | thrift PacketBatch, compressed per encoding
+//
+// A thrift struct never starts with 'G': its first byte is a field type or STOP.
+const (
+	batchMagic0      byte = 'G'
+	batchMagic1      byte = 'N'
+	batchVersion     byte = 1
+	batchHeaderSize       = 4
+	maxBatchDataSize      = 64 << 20
+)
+
+// Encoding identifies how the payload of a packet batch is compressed.
+type Encoding uint8
+
+const (
+	EncodingNone Encoding = iota
+	EncodingZstd
+	EncodingLZ4
+)
+
+var encodingNames = map[Encoding]string{
+	EncodingNone: "none",
+	EncodingZstd: "zstd",
+	EncodingLZ4:  "lz4",
+}
+
+var errBatchTooLarge = errors.New("packet batch exceeds size limit")
+
+// ParseEncoding converts a configured compression name into an Encoding; empty means none.
+func ParseEncoding(name string) (Encoding, error) {
+	if name == "" {
+		return EncodingNone, nil
+	}
+	for encoding, encodingName := range encodingNames {
+		if name == encodingName {
+			return encoding, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown compression %q, want none, zstd or lz4", name)
+}
+
+func (e Encoding) String() string {
+	if name, ok := encodingNames[e]; ok {
+		return name
+	}
+	return fmt.Sprintf("encoding(%d)", uint8(e))
+}
+
+var (
+	zstdEncoder = sync.OnceValues(func() (*zstd.Encoder, error) {
+		return zstd.NewWriter(nil)
+	})
+	zstdDecoder = sync.OnceValues(func() (*zstd.Decoder, error) {
+		return zstd.NewReader(nil, zstd.WithDecoderConcurrency(0), zstd.WithDecoderMaxMemory(maxBatchDataSize))
+	})
+)
+
+// MarshalPacketBatch encodes packets into a single framed batch message.
+func MarshalPacketBatch(dst []byte, packets []*model.PacketInfo, encoding Encoding) ([]byte, error) {
+	batch := &v1.PacketBatch{Packets: make([]*v1.PacketInfo, 0, len(packets))}
+	for _, packetInfo := range packets {
+		thriftPacket, err := packetInfoToThrift(packetInfo)
+		if err != nil {
+			return nil, err
+		}
+		batch.Packets = append(batch.Packets, thriftPacket)
+	}
+	return marshalThriftBatch(dst, batch, encoding)
+}
+
+func marshalThriftBatch(dst []byte, batch *v1.PacketBatch, encoding Encoding) ([]byte, error) {
+	if _, ok := encodingNames[encoding]; !ok {
+		return nil, fmt.Errorf("unsupported batch encoding %s", encoding)
+	}
+
+	serializer := packetSerializerPool.Get().(*thrift.TSerializer)
+	defer packetSerializerPool.Put(serializer)
+
+	payload, err := serializer.Write(context.Background(), batch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal packet batch: %w", err)
+	}
+
+	dst = append(dst[:0], batchMagic0, batchMagic1, batchVersion, byte(encoding))
+	switch encoding {
+	case EncodingZstd:
+		encoder, err := zstdEncoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd encoder: %w", err)
+		}
+		return encoder.EncodeAll(payload, dst), nil
+	case EncodingLZ4:
+		buf := bytes.NewBuffer(dst)
+		writer := lz4.NewWriter(buf)
+		if _, err := writer.Write(payload); err != nil {
+			return nil, fmt.Errorf("failed to compress packet batch: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress packet batch: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return append(dst, payload...), nil
+	}
+}
+
+// UnmarshalPackets decodes a packet message, batched or not, into its packets.
+func UnmarshalPackets(data []byte) ([]model.PacketInfo, error) {
+	if !isPacketBatch(data) {
+		packetInfo, err := UnmarshalPacketInfo(data)
+		if err != nil {
+			return nil, err
+		}
+		return []model.PacketInfo{packetInfo}, nil
+	}
+
+	if len(data) < batchHeaderSize {
+		return nil, fmt.Errorf("packet batch header truncated")
+	}
+	if data[2] != batchVersion {
+		return nil, fmt.Errorf("unsupported packet batch version %d", data[2])
+	}
+	payload, err := decompressBatch(Encoding(data[3]), data[batchHeaderSize:])
+	if err != nil {
+		return nil, err
+	}
+
+	batch := v1.NewPacketBatch()
+	deserializer := packetDeserializerPool.Get().(*thrift.TDeserializer)
+	defer packetDeserializerPool.Put(deserializer)
+
+	if err := deserializer.Read(context.Background(), batch, payload); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal packet batch: %w", err)
+	}
+
+	packets := make([]model.PacketInfo, 0, len(batch.Packets))
+	for _, thriftPacket := range batch.Packets {
+		packetInfo, err := packetInfoFromThrift(thriftPacket)
+		if err != nil {
+			return nil, err
+		}
+		packets = append(packets, packetInfo)
+	}
+	return packets, nil
+}
+
+func isPacketBatch(data []byte) bool {
+	return len(data) >= 2 && data[0] == batchMagic0 && data[1] == batchMagic1
+}
+
+func decompressBatch(encoding Encoding, payload []byte) ([]byte, error) {
+	switch encoding {
+	case EncodingNone:
+		return payload, nil
+	case EncodingZstd:
+		decoder, err := zstdDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create zstd decoder: %w", err)
+		}
+		data, err := decoder.DecodeAll(payload, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress zstd packet batch: %w", err)
+		}
+		return data, nil
+	case EncodingLZ4:
+		reader := io.LimitReader(lz4.NewReader(bytes.NewReader(payload)), maxBatchDataSize+1)
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress lz4 packet batch: %w", err)
+		}
+		if len(data) > maxBatchDataSize {
+			return nil, errBatchTooLarge
+		}
+		return data, nil
+	default:
+		return nil, fmt.Errorf("unsupported batch encoding %s", encoding)
+	}
+}
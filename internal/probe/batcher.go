@@ -0,0 +1,113 @@
+package probe
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"Go2NetSpectra/internal/model"
+
+	v1 "Go2NetSpectra/api/gen/thrift/v1"
+)
+
+// defaultFlushInterval bounds how long a partial batch waits when no interval is configured.
+const defaultFlushInterval = 100 * time.Millisecond
+
+// packetBatcher groups packets into framed batches and hands each one to send
+// once it holds maxPackets packets or the flush interval elapses.
+type packetBatcher struct {
+	maxPackets int
+	encoding   Encoding
+	send       func(data []byte) error // must not keep data after returning
+
+	mu      sync.Mutex
+	pending []*v1.PacketInfo
+	buffer  []byte
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newPacketBatcher(maxPackets int, interval time.Duration, encoding Encoding, send func(data []byte) error) *packetBatcher {
+	if maxPackets < 1 {
+		maxPackets = 1
+	}
+	if interval <= 0 {
+		interval = defaultFlushInterval
+	}
+
+	b := &packetBatcher{
+		maxPackets: maxPackets,
+		encoding:   encoding,
+		send:       send,
+		pending:    make([]*v1.PacketInfo, 0, maxPackets),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go b.flushPeriodically(interval)
+	return b
+}
+
+// Add queues a copy of packetInfo, sending the batch if it is now full.
+func (b *packetBatcher) Add(packetInfo *model.PacketInfo) error {
+	thriftPacket, err := packetInfoToThrift(packetInfo)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pending = append(b.pending, thriftPacket)
+	if len(b.pending) < b.maxPackets {
+		return nil
+	}
+	return b.flushLocked()
+}
+
+// Flush sends the queued packets, if any.
+func (b *packetBatcher) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flushLocked()
+}
+
+// Close stops the periodic flush and sends what is still queued.
+func (b *packetBatcher) Close() error {
+	close(b.stop)
+	<-b.done
+	return b.Flush()
+}
+
+func (b *packetBatcher) flushLocked() error {
+	if len(b.pending) == 0 {
+		return nil
+	}
+
+	data, err := marshalThriftBatch(b.buffer, &v1.PacketBatch{Packets: b.pending}, b.encoding)
+	clear(b.pending)
+	b.pending = b.pending[:0]
+	if err != nil {
+		return err
+	}
+	b.buffer = data[:0]
+	return b.send(data)
+}
+
+func (b *packetBatcher) flushPeriodically(interval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.Flush(); err != nil {
+				log.Printf("failed to publish packet batch: %v", err)
+			}
+		case <-b.stop:
+			return
+		}
+	}
+}
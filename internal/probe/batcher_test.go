@@ -0,0 +1,83 @@
+package probe
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type capturedBatches struct {
+	mu      sync.Mutex
+	batches [][]byte
+	sent    chan struct{}
+}
+
+func newCapturedBatches() *capturedBatches {
+	return &capturedBatches{sent: make(chan struct{}, 16)}
+}
+
+func (c *capturedBatches) send(data []byte) error {
+	c.mu.Lock()
+	c.batches = append(c.batches, append([]byte(nil), data...))
+	c.mu.Unlock()
+	c.sent <- struct{}{}
+	return nil
+}
+
+func (c *capturedBatches) packetCounts(t *testing.T) []int {
+	t.Helper()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	counts := make([]int, len(c.batches))
+	for i, data := range c.batches {
+		packets, err := UnmarshalPackets(data)
+		if err != nil {
+			t.Fatalf("UnmarshalPackets(batch %d) unexpected error: %v", i, err)
+		}
+		counts[i] = len(packets)
+	}
+	return counts
+}
+
+func TestPacketBatcherFlushesFullBatches(t *testing.T) {
+	captured := newCapturedBatches()
+	batcher := newPacketBatcher(4, time.Hour, EncodingLZ4, captured.send)
+
+	for _, packet := range batchTestPackets(10) {
+		if err := batcher.Add(packet); err != nil {
+			t.Fatalf("Add() unexpected error: %v", err)
+		}
+	}
+	if counts := captured.packetCounts(t); len(counts) != 2 || counts[0] != 4 || counts[1] != 4 {
+		t.Fatalf("batches before Close() = %v, want [4 4]", counts)
+	}
+
+	if err := batcher.Close(); err != nil {
+		t.Fatalf("Close() unexpected error: %v", err)
+	}
+	if counts := captured.packetCounts(t); len(counts) != 3 || counts[2] != 2 {
+		t.Fatalf("batches after Close() = %v, want [4 4 2]", counts)
+	}
+}
+
+func TestPacketBatcherFlushesOnInterval(t *testing.T) {
+	captured := newCapturedBatches()
+	batcher := newPacketBatcher(1000, 10*time.Millisecond, EncodingZstd, captured.send)
+	defer batcher.Close()
+
+	for _, packet := range batchTestPackets(3) {
+		if err := batcher.Add(packet); err != nil {
+			t.Fatalf("Add() unexpected error: %v", err)
+		}
+	}
+
+	select {
+	case <-captured.sent:
+	case <-time.After(2 * time.Second):
+		t.Fatal("packet batcher did not flush a partial batch after the interval")
+	}
+	if counts := captured.packetCounts(t); len(counts) != 1 || counts[0] != 3 {
+		t.Fatalf("batches after interval = %v, want [3]", counts)
+	}
+}
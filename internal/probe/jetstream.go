@@ -0,0 +1,118 @@
+package probe
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// Defaults for the optional fields of config.JetStreamConfig.
+const (
+	DefaultStreamName   = "NETSPECTRA_PACKETS"
+	DefaultConsumerName = "ns-engine"
+	DefaultFetchBatch   = 256
+	DefaultMaxPending   = 4096
+	defaultAckWait      = 30 * time.Second
+	defaultMaxDeliver   = 5
+	// replayInactiveThreshold is how long the server keeps an idle replay consumer.
+	replayInactiveThreshold = 5 * time.Minute
+)
+
+// StreamConfig describes the stream that stores packet messages published on subject.
+// Old messages are discarded once any configured limit is reached.
+func StreamConfig(cfg config.JetStreamConfig, subject string) (jetstream.StreamConfig, error) {
+	streamCfg := jetstream.StreamConfig{
+		Name:      DefaultStreamName,
+		Subjects:  []string{subject},
+		Retention: jetstream.LimitsPolicy,
+		Discard:   jetstream.DiscardOld,
+		Storage:   jetstream.FileStorage,
+		MaxBytes:  cfg.MaxBytes,
+		MaxMsgs:   cfg.MaxMsgs,
+		Replicas:  max(cfg.Replicas, 1),
+	}
+	if cfg.Stream != "" {
+		streamCfg.Name = cfg.Stream
+	}
+	if cfg.MaxAge != "" {
+		maxAge, err := time.ParseDuration(cfg.MaxAge)
+		if err != nil {
+			return jetstream.StreamConfig{}, fmt.Errorf("invalid jetstream max_age %q: %w", cfg.MaxAge, err)
+		}
+		streamCfg.MaxAge = maxAge
+	}
+	if cfg.MaxBytes < 0 || cfg.MaxMsgs < 0 {
+		return jetstream.StreamConfig{}, fmt.Errorf("invalid jetstream limits: max_bytes %d, max_msgs %d, want >= 0", cfg.MaxBytes, cfg.MaxMsgs)
+	}
+	return streamCfg, nil
+}
+
+// ConsumerConfig describes the pull consumer the engine reads packets with.
+//
+// Normally it is the durable consumer, which resumes where the previous engine
+// stopped. With ReplayFrom set it is an ephemeral consumer starting at that
+// time or sequence instead, so reprocessing a past window leaves the durable
+// consumer's position untouched.
+func ConsumerConfig(cfg config.JetStreamConfig, subject string) (jetstream.ConsumerConfig, error) {
+	consumerCfg := jetstream.ConsumerConfig{
+		Durable:       DefaultConsumerName,
+		DeliverPolicy: jetstream.DeliverAllPolicy,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		AckWait:       defaultAckWait,
+		MaxDeliver:    defaultMaxDeliver,
+		FilterSubject: subject,
+	}
+	if cfg.Consumer != "" {
+		consumerCfg.Durable = cfg.Consumer
+	}
+	if cfg.AckWait != "" {
+		ackWait, err := time.ParseDuration(cfg.AckWait)
+		if err != nil {
+			return jetstream.ConsumerConfig{}, fmt.Errorf("invalid jetstream ack_wait %q: %w", cfg.AckWait, err)
+		}
+		consumerCfg.AckWait = ackWait
+	}
+	if cfg.MaxDeliver < 0 {
+		return jetstream.ConsumerConfig{}, fmt.Errorf("invalid jetstream max_deliver %d, want >= 0", cfg.MaxDeliver)
+	}
+	if cfg.MaxDeliver > 0 {
+		consumerCfg.MaxDeliver = cfg.MaxDeliver
+	}
+
+	if cfg.ReplayFrom == "" {
+		return consumerCfg, nil
+	}
+	consumerCfg.Durable = ""
+	consumerCfg.InactiveThreshold = replayInactiveThreshold
+	if seq, err := strconv.ParseUint(cfg.ReplayFrom, 10, 64); err == nil {
+		consumerCfg.DeliverPolicy = jetstream.DeliverByStartSequencePolicy
+		consumerCfg.OptStartSeq = seq
+		return consumerCfg, nil
+	}
+	start, err := time.Parse(time.RFC3339, cfg.ReplayFrom)
+	if err != nil {
+		return jetstream.ConsumerConfig{}, fmt.Errorf("invalid jetstream replay_from %q, want an RFC3339 time or a stream sequence", cfg.ReplayFrom)
+	}
+	consumerCfg.DeliverPolicy = jetstream.DeliverByStartTimePolicy
+	consumerCfg.OptStartTime = &start
+	return consumerCfg, nil
+}
+
+// EnsureStream creates the packet stream, or applies the configured limits to
+// an existing one. Both the probe and the engine call it, so either may start first.
+func EnsureStream(ctx context.Context, js jetstream.JetStream, cfg config.JetStreamConfig, subject string) (jetstream.Stream, error) {
+	streamCfg, err := StreamConfig(cfg, subject)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := js.CreateOrUpdateStream(ctx, streamCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up jetstream stream %s: %w", streamCfg.Name, err)
+	}
+	return stream, nil
+}
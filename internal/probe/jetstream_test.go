@@ -0,0 +1,63 @@
+package probe
+
+import (
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+func TestStreamConfigAppliesLimits(t *testing.T) {
+	got, err := StreamConfig(config.JetStreamConfig{MaxAge: "2h", MaxBytes: 1 << 30}, "packets")
+	if err != nil {
+		t.Fatalf("StreamConfig() unexpected error: %v", err)
+	}
+	if got.Name != DefaultStreamName || len(got.Subjects) != 1 || got.Subjects[0] != "packets" {
+		t.Fatalf("StreamConfig() name/subjects = %s/%v, want %s/[packets]", got.Name, got.Subjects, DefaultStreamName)
+	}
+	if got.MaxAge != 2*time.Hour || got.MaxBytes != 1<<30 || got.Discard != jetstream.DiscardOld {
+		t.Fatalf("StreamConfig() limits = age %v bytes %d discard %v, want 2h, 1GiB, old", got.MaxAge, got.MaxBytes, got.Discard)
+	}
+
+	if _, err := StreamConfig(config.JetStreamConfig{MaxAge: "forever"}, "packets"); err == nil {
+		t.Fatal("StreamConfig(max_age forever) error = nil, want non-nil")
+	}
+}
+
+func TestConsumerConfigUsesDurableConsumer(t *testing.T) {
+	got, err := ConsumerConfig(config.JetStreamConfig{AckWait: "10s"}, "packets")
+	if err != nil {
+		t.Fatalf("ConsumerConfig() unexpected error: %v", err)
+	}
+	if got.Durable != DefaultConsumerName || got.DeliverPolicy != jetstream.DeliverAllPolicy {
+		t.Fatalf("ConsumerConfig() durable/deliver = %q/%v, want %q/all", got.Durable, got.DeliverPolicy, DefaultConsumerName)
+	}
+	if got.AckPolicy != jetstream.AckExplicitPolicy || got.AckWait != 10*time.Second || got.MaxDeliver != defaultMaxDeliver {
+		t.Fatalf("ConsumerConfig() acks = %v/%v/%d, want explicit/10s/%d", got.AckPolicy, got.AckWait, got.MaxDeliver, defaultMaxDeliver)
+	}
+}
+
+func TestConsumerConfigReplaysWithEphemeralConsumer(t *testing.T) {
+	bySeq, err := ConsumerConfig(config.JetStreamConfig{ReplayFrom: "42"}, "packets")
+	if err != nil {
+		t.Fatalf("ConsumerConfig(replay 42) unexpected error: %v", err)
+	}
+	if bySeq.Durable != "" || bySeq.DeliverPolicy != jetstream.DeliverByStartSequencePolicy || bySeq.OptStartSeq != 42 {
+		t.Fatalf("ConsumerConfig(replay 42) = durable %q policy %v seq %d, want ephemeral from sequence 42", bySeq.Durable, bySeq.DeliverPolicy, bySeq.OptStartSeq)
+	}
+
+	byTime, err := ConsumerConfig(config.JetStreamConfig{ReplayFrom: "2025-01-02T03:04:05Z"}, "packets")
+	if err != nil {
+		t.Fatalf("ConsumerConfig(replay time) unexpected error: %v", err)
+	}
+	want := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	if byTime.DeliverPolicy != jetstream.DeliverByStartTimePolicy || byTime.OptStartTime == nil || !byTime.OptStartTime.Equal(want) {
+		t.Fatalf("ConsumerConfig(replay time) = policy %v start %v, want start time %v", byTime.DeliverPolicy, byTime.OptStartTime, want)
+	}
+
+	if _, err := ConsumerConfig(config.JetStreamConfig{ReplayFrom: "yesterday"}, "packets"); err == nil {
+		t.Fatal("ConsumerConfig(replay yesterday) error = nil, want non-nil")
+	}
+}
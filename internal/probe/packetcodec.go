@@ -38,7 +38,7 @@ func packetInfoToThrift(packetInfo *model.PacketInfo) (*v1.PacketInfo, error) {
 		return nil, errNilPacketInfo
 	}
 
-	return &v1.PacketInfo{
+	thriftPacket := &v1.PacketInfo{
 		TimestampUnixNano: packetInfo.Timestamp.UnixNano(),
 		FiveTuple: &v1.FiveTuple{
 			SrcIP:    append([]byte(nil), packetInfo.FiveTuple.SrcIP...),
@@ -48,7 +48,37 @@ func packetInfoToThrift(packetInfo *model.PacketInfo) (*v1.PacketInfo, error) {
 			Protocol: int32(packetInfo.FiveTuple.Protocol),
 		},
 		Length: int64(packetInfo.Length),
-	}, nil
+	}
+	if packetInfo.TCPFlags != 0 {
+		tcpFlags := int32(packetInfo.TCPFlags)
+		thriftPacket.TCPFlags = &tcpFlags
+	}
+	if packetInfo.TunnelID != 0 {
+		tunnelID := int64(packetInfo.TunnelID)
+		thriftPacket.TunnelID = &tunnelID
+	}
+	if packetInfo.OuterVLAN != 0 {
+		outerVLAN := int32(packetInfo.OuterVLAN)
+		thriftPacket.OuterVlan = &outerVLAN
+	}
+	if packetInfo.InnerVLAN != 0 {
+		innerVLAN := int32(packetInfo.InnerVLAN)
+		thriftPacket.InnerVlan = &innerVLAN
+	}
+	if packetInfo.MPLSLabel != 0 {
+		mplsLabel := int32(packetInfo.MPLSLabel)
+		thriftPacket.MplsLabel = &mplsLabel
+	}
+	if packetInfo.InterfaceID != 0 {
+		interfaceID := int64(packetInfo.InterfaceID)
+		thriftPacket.InterfaceID = &interfaceID
+	}
+	if packetInfo.SampleRate > 1 {
+		sampleRate := int64(packetInfo.SampleRate)
+		thriftPacket.SampleRate = &sampleRate
+	}
+
+	return thriftPacket, nil
 }
 
 // MarshalPacketInfo encodes PacketInfo into Thrift bytes.
@@ -91,6 +121,13 @@ func packetInfoFromThrift(packet *v1.PacketInfo) (model.PacketInfo, error) {
 			DstPort:  uint16(packet.FiveTuple.DstPort),
 			Protocol: uint8(packet.FiveTuple.Protocol),
 		},
+		TCPFlags:    uint8(packet.GetTCPFlags()),
+		TunnelID:    uint32(packet.GetTunnelID()),
+		OuterVLAN:   uint16(packet.GetOuterVlan()),
+		InnerVLAN:   uint16(packet.GetInnerVlan()),
+		MPLSLabel:   uint32(packet.GetMplsLabel()),
+		InterfaceID: uint32(packet.GetInterfaceID()),
+		SampleRate:  uint32(packet.GetSampleRate()),
 	}, nil
 }
 
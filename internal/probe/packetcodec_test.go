@@ -74,6 +74,13 @@ func TestMarshalPacketInfoRoundTrip(t *testing.T) {
 			DstPort:  8443,
 			Protocol: 6,
 		},
+		TCPFlags:    model.TCPFlagSYN | model.TCPFlagACK,
+		TunnelID:    0xABCDEF,
+		OuterVLAN:   100,
+		InnerVLAN:   200,
+		MPLSLabel:   1048575,
+		InterfaceID: 3,
+		SampleRate:  64,
 	}
 
 	data, err := MarshalPacketInfo(nil, original)
@@ -107,6 +114,36 @@ func TestMarshalPacketInfoRoundTrip(t *testing.T) {
 	if decoded.Length != original.Length {
 		t.Fatalf("decoded length = %d, want %d", decoded.Length, original.Length)
 	}
+	if decoded.TCPFlags != original.TCPFlags {
+		t.Fatalf("decoded tcp flags = %#x, want %#x", decoded.TCPFlags, original.TCPFlags)
+	}
+	if decoded.TunnelID != original.TunnelID {
+		t.Fatalf("decoded tunnel id = %d, want %d", decoded.TunnelID, original.TunnelID)
+	}
+	if decoded.OuterVLAN != original.OuterVLAN || decoded.InnerVLAN != original.InnerVLAN {
+		t.Fatalf("decoded vlans = %d/%d, want %d/%d", decoded.OuterVLAN, decoded.InnerVLAN, original.OuterVLAN, original.InnerVLAN)
+	}
+	if decoded.MPLSLabel != original.MPLSLabel {
+		t.Fatalf("decoded mpls label = %d, want %d", decoded.MPLSLabel, original.MPLSLabel)
+	}
+	if decoded.InterfaceID != original.InterfaceID {
+		t.Fatalf("decoded interface id = %d, want %d", decoded.InterfaceID, original.InterfaceID)
+	}
+	if decoded.SampleRate != original.SampleRate {
+		t.Fatalf("decoded sample rate = %d, want %d", decoded.SampleRate, original.SampleRate)
+	}
+}
+
+func TestPacketInfoToThriftOmitsZeroTCPFlags(t *testing.T) {
+	thriftPacket, err := packetInfoToThrift(&model.PacketInfo{
+		FiveTuple: model.FiveTuple{Protocol: 17},
+	})
+	if err != nil {
+		t.Fatalf("packetInfoToThrift() unexpected error: %v", err)
+	}
+	if thriftPacket.IsSetTCPFlags() {
+		t.Fatalf("packetInfoToThrift() tcp flags = %d, want unset", thriftPacket.GetTCPFlags())
+	}
 }
 
 func TestPacketInfoFromThriftRejectsMissingFiveTuple(t *testing.T) {
@@ -129,3 +166,130 @@ func TestUnmarshalPacketInfoRejectsLegacyProtobufPayload(t *testing.T) {
 		t.Fatal("UnmarshalPacketInfo(legacy protobuf payload) error = nil, want non-nil")
 	}
 }
+
+func batchTestPackets(n int) []*model.PacketInfo {
+	packets := make([]*model.PacketInfo, n)
+	for i := range packets {
+		packets[i] = &model.PacketInfo{
+			Timestamp: time.Unix(1700000000, int64(i)),
+			Length:    64 + i,
+			FiveTuple: model.FiveTuple{
+				SrcIP:    net.IPv4(10, 0, byte(i>>8), byte(i)).To4(),
+				DstIP:    net.ParseIP("2001:db8::1"),
+				SrcPort:  uint16(40000 + i),
+				DstPort:  443,
+				Protocol: 6,
+			},
+			TCPFlags:    model.TCPFlagACK,
+			InterfaceID: 2,
+		}
+	}
+	return packets
+}
+
+func TestMarshalPacketBatchRoundTrip(t *testing.T) {
+	original := batchTestPackets(300)
+
+	for _, encoding := range []Encoding{EncodingNone, EncodingZstd, EncodingLZ4} {
+		t.Run(encoding.String(), func(t *testing.T) {
+			data, err := MarshalPacketBatch(nil, original, encoding)
+			if err != nil {
+				t.Fatalf("MarshalPacketBatch() unexpected error: %v", err)
+			}
+			if Encoding(data[3]) != encoding {
+				t.Fatalf("batch header encoding = %s, want %s", Encoding(data[3]), encoding)
+			}
+
+			decoded, err := UnmarshalPackets(data)
+			if err != nil {
+				t.Fatalf("UnmarshalPackets() unexpected error: %v", err)
+			}
+			if len(decoded) != len(original) {
+				t.Fatalf("decoded %d packets, want %d", len(decoded), len(original))
+			}
+			for i, want := range original {
+				got := decoded[i]
+				if !got.Timestamp.Equal(want.Timestamp) || got.Length != want.Length {
+					t.Fatalf("decoded packet %d timestamp/length = %v/%d, want %v/%d", i, got.Timestamp, got.Length, want.Timestamp, want.Length)
+				}
+				if !got.FiveTuple.SrcIP.Equal(want.FiveTuple.SrcIP) || got.FiveTuple.SrcPort != want.FiveTuple.SrcPort {
+					t.Fatalf("decoded packet %d src = %v:%d, want %v:%d", i, got.FiveTuple.SrcIP, got.FiveTuple.SrcPort, want.FiveTuple.SrcIP, want.FiveTuple.SrcPort)
+				}
+				if got.TCPFlags != want.TCPFlags || got.InterfaceID != want.InterfaceID {
+					t.Fatalf("decoded packet %d flags/interface = %#x/%d, want %#x/%d", i, got.TCPFlags, got.InterfaceID, want.TCPFlags, want.InterfaceID)
+				}
+			}
+		})
+	}
+}
+
+func TestMarshalPacketBatchCompresses(t *testing.T) {
+	original := batchTestPackets(300)
+
+	plain, err := MarshalPacketBatch(nil, original, EncodingNone)
+	if err != nil {
+		t.Fatalf("MarshalPacketBatch(none) unexpected error: %v", err)
+	}
+	for _, encoding := range []Encoding{EncodingZstd, EncodingLZ4} {
+		compressed, err := MarshalPacketBatch(nil, original, encoding)
+		if err != nil {
+			t.Fatalf("MarshalPacketBatch(%s) unexpected error: %v", encoding, err)
+		}
+		if len(compressed) >= len(plain) {
+			t.Fatalf("MarshalPacketBatch(%s) size = %d, want less than uncompressed %d", encoding, len(compressed), len(plain))
+		}
+	}
+}
+
+func TestUnmarshalPacketsReadsSinglePacketMessages(t *testing.T) {
+	original := batchTestPackets(1)[0]
+	data, err := MarshalPacketInfo(nil, original)
+	if err != nil {
+		t.Fatalf("MarshalPacketInfo() unexpected error: %v", err)
+	}
+
+	decoded, err := UnmarshalPackets(data)
+	if err != nil {
+		t.Fatalf("UnmarshalPackets() unexpected error: %v", err)
+	}
+	if len(decoded) != 1 {
+		t.Fatalf("decoded %d packets, want 1", len(decoded))
+	}
+	if !decoded[0].FiveTuple.SrcIP.Equal(original.FiveTuple.SrcIP) {
+		t.Fatalf("decoded src ip = %v, want %v", decoded[0].FiveTuple.SrcIP, original.FiveTuple.SrcIP)
+	}
+}
+
+func TestUnmarshalPacketsRejectsBadBatchHeader(t *testing.T) {
+	data, err := MarshalPacketBatch(nil, batchTestPackets(2), EncodingNone)
+	if err != nil {
+		t.Fatalf("MarshalPacketBatch() unexpected error: %v", err)
+	}
+
+	tests := map[string][]byte{
+		"truncated":        data[:3],
+		"unknown version":  append([]byte{batchMagic0, batchMagic1, 9, 0}, data[batchHeaderSize:]...),
+		"unknown encoding": append([]byte{batchMagic0, batchMagic1, batchVersion, 9}, data[batchHeaderSize:]...),
+		"corrupt zstd":     append([]byte{batchMagic0, batchMagic1, batchVersion, byte(EncodingZstd)}, data[batchHeaderSize:]...),
+	}
+	for name, payload := range tests {
+		if _, err := UnmarshalPackets(payload); err == nil {
+			t.Fatalf("UnmarshalPackets(%s) error = nil, want non-nil", name)
+		}
+	}
+}
+
+func TestParseEncoding(t *testing.T) {
+	for name, want := range map[string]Encoding{"": EncodingNone, "none": EncodingNone, "zstd": EncodingZstd, "lz4": EncodingLZ4} {
+		got, err := ParseEncoding(name)
+		if err != nil {
+			t.Fatalf("ParseEncoding(%q) unexpected error: %v", name, err)
+		}
+		if got != want {
+			t.Fatalf("ParseEncoding(%q) = %s, want %s", name, got, want)
+		}
+	}
+	if _, err := ParseEncoding("gzip"); err == nil {
+		t.Fatal("ParseEncoding(gzip) error = nil, want non-nil")
+	}
+}
@@ -0,0 +1,88 @@
+package probe
+
+import (
+	"fmt"
+	"strconv"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+const (
+	// partitionHashSeed keeps partitioning independent of flow sampling.
+	partitionHashSeed = 0x9e3779b97f4a7c15
+	// MaxPartitions bounds probe.partitions.
+	MaxPartitions = 1024
+	// DefaultQueueGroup is the NATS queue group engines join on each partition.
+	DefaultQueueGroup = "ns-engine"
+)
+
+// ValidatePartitions checks the configured partition count.
+func ValidatePartitions(partitions int) error {
+	if partitions < 0 || partitions > MaxPartitions {
+		return fmt.Errorf("invalid partitions %d, want 0..%d", partitions, MaxPartitions)
+	}
+	return nil
+}
+
+// PartitionSubject returns the subject that carries one partition of subject.
+func PartitionSubject(subject string, partition int) string {
+	return subject + "." + strconv.Itoa(partition)
+}
+
+// PartitionSubjects returns the subjects the probe publishes to, indexed by
+// partition. Without partitioning it is just the configured subject.
+func PartitionSubjects(cfg config.ProbeConfig) []string {
+	if cfg.Partitions <= 1 {
+		return []string{cfg.Subject}
+	}
+	subjects := make([]string, cfg.Partitions)
+	for i := range subjects {
+		subjects[i] = PartitionSubject(cfg.Subject, i)
+	}
+	return subjects
+}
+
+// AllPartitionsSubject returns a subject that matches every partition.
+func AllPartitionsSubject(cfg config.ProbeConfig) string {
+	if cfg.Partitions <= 1 {
+		return cfg.Subject
+	}
+	return cfg.Subject + ".*"
+}
+
+// Partition returns the partition of the packet's flow. Both directions of a
+// flow, as seen by any probe, land in the same partition.
+func Partition(packetInfo *model.PacketInfo, partitions int) int {
+	if partitions <= 1 {
+		return 0
+	}
+	return int(packetInfo.FiveTuple.SymmetricHash(partitionHashSeed) % uint64(partitions))
+}
+
+// AssignedPartitions validates the partitions an engine was assigned out of
+// partitions and returns them. An empty assignment means every partition.
+func AssignedPartitions(partitions int, assigned []int) ([]int, error) {
+	if err := ValidatePartitions(partitions); err != nil {
+		return nil, err
+	}
+	total := max(partitions, 1)
+	if len(assigned) == 0 {
+		all := make([]int, total)
+		for i := range all {
+			all[i] = i
+		}
+		return all, nil
+	}
+	seen := make(map[int]bool, len(assigned))
+	for _, partition := range assigned {
+		if partition < 0 || partition >= total {
+			return nil, fmt.Errorf("invalid assigned partition %d, want 0..%d", partition, total-1)
+		}
+		if seen[partition] {
+			return nil, fmt.Errorf("partition %d assigned twice", partition)
+		}
+		seen[partition] = true
+	}
+	return assigned, nil
+}
@@ -0,0 +1,74 @@
+package probe
+
+import (
+	"net"
+	"slices"
+	"testing"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+func TestPartitionIsSymmetricAndInRange(t *testing.T) {
+	seen := make(map[int]bool)
+	for port := uint16(1000); port < 1100; port++ {
+		forward := &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"), SrcPort: port, DstPort: 443, Protocol: 6}}
+		reverse := &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("10.0.0.2"), DstIP: net.ParseIP("10.0.0.1"), SrcPort: 443, DstPort: port, Protocol: 6}}
+
+		got := Partition(forward, 4)
+		if got < 0 || got >= 4 {
+			t.Fatalf("Partition(port %d) = %d, want 0..3", port, got)
+		}
+		if back := Partition(reverse, 4); back != got {
+			t.Fatalf("Partition(reverse port %d) = %d, want %d", port, back, got)
+		}
+		seen[got] = true
+	}
+	if len(seen) != 4 {
+		t.Fatalf("100 flows used partitions %v, want all 4", seen)
+	}
+
+	if got := Partition(&model.PacketInfo{}, 1); got != 0 {
+		t.Fatalf("Partition(unpartitioned) = %d, want 0", got)
+	}
+}
+
+func TestPartitionSubjects(t *testing.T) {
+	cfg := config.ProbeConfig{Subject: "packets"}
+	if got := PartitionSubjects(cfg); !slices.Equal(got, []string{"packets"}) {
+		t.Fatalf("PartitionSubjects(unpartitioned) = %v, want [packets]", got)
+	}
+	if got := AllPartitionsSubject(cfg); got != "packets" {
+		t.Fatalf("AllPartitionsSubject(unpartitioned) = %q, want packets", got)
+	}
+
+	cfg.Partitions = 3
+	if got := PartitionSubjects(cfg); !slices.Equal(got, []string{"packets.0", "packets.1", "packets.2"}) {
+		t.Fatalf("PartitionSubjects(3) = %v, want [packets.0 packets.1 packets.2]", got)
+	}
+	if got := AllPartitionsSubject(cfg); got != "packets.*" {
+		t.Fatalf("AllPartitionsSubject(3) = %q, want packets.*", got)
+	}
+}
+
+func TestAssignedPartitions(t *testing.T) {
+	all, err := AssignedPartitions(3, nil)
+	if err != nil {
+		t.Fatalf("AssignedPartitions(3, nil) unexpected error: %v", err)
+	}
+	if !slices.Equal(all, []int{0, 1, 2}) {
+		t.Fatalf("AssignedPartitions(3, nil) = %v, want [0 1 2]", all)
+	}
+	if got, err := AssignedPartitions(0, nil); err != nil || !slices.Equal(got, []int{0}) {
+		t.Fatalf("AssignedPartitions(0, nil) = %v, %v, want [0], nil", got, err)
+	}
+
+	for _, assigned := range [][]int{{3}, {-1}, {1, 1}} {
+		if _, err := AssignedPartitions(3, assigned); err == nil {
+			t.Fatalf("AssignedPartitions(3, %v) error = nil, want non-nil", assigned)
+		}
+	}
+	if _, err := AssignedPartitions(MaxPartitions+1, nil); err == nil {
+		t.Fatal("AssignedPartitions(too many) error = nil, want non-nil")
+	}
+}
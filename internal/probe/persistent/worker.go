@@ -18,10 +18,11 @@ import (
 	"github.com/google/gopacket/pcapgo"
 )
 
-// PacketContainer holds both the raw packet and the parsed info.
+// PacketContainer holds both the raw frame and the parsed info.
 type PacketContainer struct {
-	RawPacket  gopacket.Packet
-	PacketInfo *model.PacketInfo
+	CaptureInfo gopacket.CaptureInfo
+	Data        []byte
+	PacketInfo  *model.PacketInfo
 }
 
 // Worker manages a pool of goroutines for persistently writing packets to disk.
@@ -169,7 +170,7 @@ func (w *Worker) runTextWorker(file *os.File) {
 func (w *Worker) runPcapWorker(pcapWriter *pcapgo.Writer) func(*os.File) {
 	return func(file *os.File) {
 		for container := range w.packetChan {
-			if err := pcapWriter.WritePacket(container.RawPacket.Metadata().CaptureInfo, container.RawPacket.Data()); err != nil {
+			if err := pcapWriter.WritePacket(container.CaptureInfo, container.Data); err != nil {
 				log.Printf("PersistentWorker (pcap): Error writing packet: %v", err)
 			}
 		}
@@ -1,8 +1,12 @@
 package probe
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
 	"sync"
+	"time"
 
 	"Go2NetSpectra/internal/config"
 	"Go2NetSpectra/internal/model"
@@ -10,23 +14,51 @@ import (
 
 	"github.com/google/gopacket"
 	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
 )
 
+// publishAckTimeout bounds how long Close waits for JetStream to acknowledge outstanding publishes.
+const publishAckTimeout = 5 * time.Second
+
 var publisherBufferPool = sync.Pool{
 	New: func() any {
 		return make([]byte, 0, 256)
 	},
 }
 
-// Publisher is responsible for publishing packet data to a NATS topic.
+// Publisher is responsible for publishing packet data to a NATS topic, or to a
+// JetStream stream when JetStream is enabled. With several partitions each
+// packet goes to the subject of its flow's partition.
 type Publisher struct {
 	nc                *nats.Conn
-	subject           string
+	js                jetstream.JetStream
+	subjects          []string // indexed by partition
 	persistenceWorker *persistent.Worker
+	batchers          []*packetBatcher // one per partition when batching
+	sampler           *Sampler
 }
 
 // NewPublisher creates a new NATS publisher.
 func NewPublisher(cfg config.ProbeConfig) (*Publisher, error) {
+	encoding, err := ParseEncoding(cfg.Batch.Compression)
+	if err != nil {
+		return nil, fmt.Errorf("invalid batch config: %w", err)
+	}
+	sampler, err := NewSampler(cfg.Sampling)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sampling config: %w", err)
+	}
+	if err := ValidatePartitions(cfg.Partitions); err != nil {
+		return nil, err
+	}
+	var flushInterval time.Duration
+	if cfg.Batch.FlushInterval != "" {
+		flushInterval, err = time.ParseDuration(cfg.Batch.FlushInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid batch flush interval %q: %w", cfg.Batch.FlushInterval, err)
+		}
+	}
+
 	nc, err := nats.Connect(cfg.NATSURL)
 	if err != nil {
 		return nil, err
@@ -34,8 +66,33 @@ func NewPublisher(cfg config.ProbeConfig) (*Publisher, error) {
 	log.Printf("Connected to NATS server at %s", cfg.NATSURL)
 
 	p := &Publisher{
-		nc:      nc,
-		subject: cfg.Subject,
+		nc:       nc,
+		subjects: PartitionSubjects(cfg),
+		sampler:  sampler,
+	}
+	if len(p.subjects) > 1 {
+		log.Printf("Publishing to %d partitions %s", len(p.subjects), AllPartitionsSubject(cfg))
+	}
+	if sampler != nil {
+		log.Printf("Sampling 1 in %d packets (%s mode)", sampler.Rate(), sampler.Mode())
+	}
+
+	if cfg.JetStream.Enabled {
+		if err := p.setupJetStream(cfg); err != nil {
+			nc.Close()
+			return nil, err
+		}
+	}
+
+	// Batching or compression switches to framed PacketBatch messages.
+	if cfg.Batch.MaxPackets > 1 || encoding != EncodingNone {
+		p.batchers = make([]*packetBatcher, len(p.subjects))
+		for i, subject := range p.subjects {
+			p.batchers[i] = newPacketBatcher(cfg.Batch.MaxPackets, flushInterval, encoding, func(data []byte) error {
+				return p.send(subject, data)
+			})
+		}
+		log.Printf("Publishing packet batches of up to %d packets (%s)", max(cfg.Batch.MaxPackets, 1), encoding)
 	}
 
 	// Initialize persistence worker if enabled
@@ -50,18 +107,38 @@ func NewPublisher(cfg config.ProbeConfig) (*Publisher, error) {
 	return p, nil
 }
 
-// Publish serializes a PacketInfo to Thrift and publishes it to the configured NATS subject.
-// If persistence is enabled, it also enqueues the packet for local writing.
-func (p *Publisher) Publish(rawPacket gopacket.Packet, packetInfo *model.PacketInfo) error {
+// Publish serializes a PacketInfo to Thrift and publishes it to the subject of its flow's partition.
+// If persistence is enabled, it also enqueues the packet for local writing; data
+// is kept until then, while packetInfo is copied so callers may reuse it.
+//
+// With sampling enabled, packets the sampler drops are only persisted, and the
+// packets that are sent have their SampleRate set so the engine can scale them up.
+func (p *Publisher) Publish(ci gopacket.CaptureInfo, data []byte, packetInfo *model.PacketInfo) error {
 	// Asynchronously write to local file if persistence is enabled
 	if p.persistenceWorker != nil {
+		info := *packetInfo
+		info.FiveTuple.SrcIP = append(net.IP(nil), packetInfo.FiveTuple.SrcIP...)
+		info.FiveTuple.DstIP = append(net.IP(nil), packetInfo.FiveTuple.DstIP...)
 		container := &persistent.PacketContainer{
-			RawPacket:  rawPacket,
-			PacketInfo: packetInfo,
+			CaptureInfo: ci,
+			Data:        data,
+			PacketInfo:  &info,
 		}
 		p.persistenceWorker.Enqueue(container)
 	}
 
+	if p.sampler != nil {
+		if !p.sampler.Keep(packetInfo) {
+			return nil
+		}
+		packetInfo.SampleRate = p.sampler.Rate()
+	}
+
+	partition := Partition(packetInfo, len(p.subjects))
+	if p.batchers != nil {
+		return p.batchers[partition].Add(packetInfo)
+	}
+
 	buffer := publisherBufferPool.Get().([]byte)
 	data, err := MarshalPacketInfo(buffer, packetInfo)
 	if err != nil {
@@ -70,11 +147,62 @@ func (p *Publisher) Publish(rawPacket gopacket.Packet, packetInfo *model.PacketI
 	}
 	defer publisherBufferPool.Put(data[:0])
 
-	return p.nc.Publish(p.subject, data)
+	return p.send(p.subjects[partition], data)
 }
 
-// Close drains and closes the NATS connection and stops the persistence worker.
+func (p *Publisher) setupJetStream(cfg config.ProbeConfig) error {
+	maxPending := cfg.JetStream.MaxPending
+	if maxPending <= 0 {
+		maxPending = DefaultMaxPending
+	}
+	js, err := jetstream.New(p.nc,
+		jetstream.WithPublishAsyncMaxPending(maxPending),
+		jetstream.WithPublishAsyncErrHandler(func(_ jetstream.JetStream, msg *nats.Msg, err error) {
+			log.Printf("JetStream did not store packet message on %s: %v", msg.Subject, err)
+		}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	stream, err := EnsureStream(ctx, js, cfg.JetStream, AllPartitionsSubject(cfg))
+	if err != nil {
+		return err
+	}
+
+	p.js = js
+	log.Printf("Publishing to JetStream stream %s", stream.CachedInfo().Config.Name)
+	return nil
+}
+
+// send publishes one encoded message. The caller may reuse data once it
+// returns: both paths copy it into the connection's write buffer, and the
+// JetStream error handler only looks at the subject.
+func (p *Publisher) send(subject string, data []byte) error {
+	if p.js != nil {
+		_, err := p.js.PublishAsync(subject, data)
+		return err
+	}
+	return p.nc.Publish(subject, data)
+}
+
+// Close flushes any pending batch, waits briefly for outstanding JetStream
+// acknowledgements, drains and closes the NATS connection and stops the persistence worker.
 func (p *Publisher) Close() {
+	for _, batcher := range p.batchers {
+		if err := batcher.Close(); err != nil {
+			log.Printf("failed to publish final packet batch: %v", err)
+		}
+	}
+	if p.js != nil {
+		select {
+		case <-p.js.PublishAsyncComplete():
+		case <-time.After(publishAckTimeout):
+			log.Printf("JetStream did not acknowledge %d packet messages before shutdown", p.js.PublishAsyncPending())
+		}
+	}
 	if p.persistenceWorker != nil {
 		p.persistenceWorker.Stop()
 	}
@@ -0,0 +1,80 @@
+package probe
+
+import (
+	"fmt"
+	"math/rand/v2"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+// SamplingMode selects how the probe picks the packets it forwards.
+type SamplingMode uint8
+
+const (
+	SamplingNone SamplingMode = iota
+	SamplingPacket
+	SamplingFlow
+)
+
+var samplingModeNames = map[SamplingMode]string{
+	SamplingNone:   "none",
+	SamplingPacket: "packet",
+	SamplingFlow:   "flow",
+}
+
+func (m SamplingMode) String() string {
+	if name, ok := samplingModeNames[m]; ok {
+		return name
+	}
+	return fmt.Sprintf("sampling(%d)", uint8(m))
+}
+
+// samplingHashSeed keeps flow sampling independent of partitioning, which
+// hashes the same flows with partitionHashSeed.
+const samplingHashSeed = 0
+
+// Sampler keeps one in Rate packets. Packet mode draws every packet
+// independently; flow mode keeps or drops whole bidirectional flows, so the
+// flows it forwards are complete and their TCP state stays meaningful.
+type Sampler struct {
+	mode SamplingMode
+	rate uint32
+}
+
+// NewSampler builds a Sampler from the probe config. It returns nil when
+// sampling is disabled, which callers treat as keeping every packet.
+func NewSampler(cfg config.SamplingConfig) (*Sampler, error) {
+	mode := SamplingNone
+	switch cfg.Mode {
+	case "", "none":
+	case "packet":
+		mode = SamplingPacket
+	case "flow":
+		mode = SamplingFlow
+	default:
+		return nil, fmt.Errorf("unknown sampling mode %q, want none, packet or flow", cfg.Mode)
+	}
+	if mode == SamplingNone || cfg.Rate <= 1 {
+		return nil, nil
+	}
+	return &Sampler{mode: mode, rate: cfg.Rate}, nil
+}
+
+// Rate returns N of the 1-in-N sampling.
+func (s *Sampler) Rate() uint32 {
+	return s.rate
+}
+
+// Mode returns how packets are picked.
+func (s *Sampler) Mode() SamplingMode {
+	return s.mode
+}
+
+// Keep reports whether packetInfo should be forwarded.
+func (s *Sampler) Keep(packetInfo *model.PacketInfo) bool {
+	if s.mode == SamplingFlow {
+		return packetInfo.FiveTuple.SymmetricHash(samplingHashSeed)%uint64(s.rate) == 0
+	}
+	return rand.Uint32N(s.rate) == 0
+}
@@ -0,0 +1,88 @@
+package probe
+
+import (
+	"net"
+	"testing"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+)
+
+func mustNewSampler(t *testing.T, cfg config.SamplingConfig) *Sampler {
+	t.Helper()
+	sampler, err := NewSampler(cfg)
+	if err != nil {
+		t.Fatalf("NewSampler(%+v) unexpected error: %v", cfg, err)
+	}
+	if sampler == nil {
+		t.Fatalf("NewSampler(%+v) = nil, want sampler", cfg)
+	}
+	return sampler
+}
+
+func TestNewSamplerDisabled(t *testing.T) {
+	for _, cfg := range []config.SamplingConfig{
+		{},
+		{Mode: "none", Rate: 100},
+		{Mode: "packet", Rate: 1},
+		{Mode: "flow"},
+	} {
+		sampler, err := NewSampler(cfg)
+		if err != nil {
+			t.Fatalf("NewSampler(%+v) unexpected error: %v", cfg, err)
+		}
+		if sampler != nil {
+			t.Fatalf("NewSampler(%+v) = %+v, want nil", cfg, sampler)
+		}
+	}
+
+	if _, err := NewSampler(config.SamplingConfig{Mode: "bytes", Rate: 10}); err == nil {
+		t.Fatal("NewSampler(mode bytes) error = nil, want non-nil")
+	}
+}
+
+func TestSamplerFlowModeKeepsBothDirections(t *testing.T) {
+	sampler := mustNewSampler(t, config.SamplingConfig{Mode: "flow", Rate: 8})
+
+	kept := 0
+	for port := uint16(1024); port < 1024+800; port++ {
+		forward := &model.PacketInfo{FiveTuple: model.FiveTuple{
+			SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2"),
+			SrcPort: port, DstPort: 443, Protocol: 6,
+		}}
+		reverse := &model.PacketInfo{FiveTuple: model.FiveTuple{
+			SrcIP: net.ParseIP("10.0.0.2"), DstIP: net.ParseIP("10.0.0.1"),
+			SrcPort: 443, DstPort: port, Protocol: 6,
+		}}
+
+		keep := sampler.Keep(forward)
+		if sampler.Keep(reverse) != keep {
+			t.Fatalf("Keep() differs between directions of port %d", port)
+		}
+		if sampler.Keep(forward) != keep {
+			t.Fatalf("Keep() not stable for port %d", port)
+		}
+		if keep {
+			kept++
+		}
+	}
+
+	if kept < 50 || kept > 150 {
+		t.Fatalf("Keep() kept %d of 800 flows, want about 100", kept)
+	}
+}
+
+func TestSamplerPacketModeKeepsOneInN(t *testing.T) {
+	sampler := mustNewSampler(t, config.SamplingConfig{Mode: "packet", Rate: 10})
+	packet := &model.PacketInfo{FiveTuple: model.FiveTuple{SrcIP: net.ParseIP("10.0.0.1"), DstIP: net.ParseIP("10.0.0.2")}}
+
+	kept := 0
+	for range 100000 {
+		if sampler.Keep(packet) {
+			kept++
+		}
+	}
+	if kept < 9000 || kept > 11000 {
+		t.Fatalf("Keep() kept %d of 100000 packets, want about 10000", kept)
+	}
+}
@@ -26,18 +26,20 @@ func NewSubscriber(cfg config.ProbeConfig) (*Subscriber, error) {
 		return nil, err
 	}
 	log.Printf("Connected to NATS server at %s", cfg.NATSURL)
-	return &Subscriber{nc: nc, subject: cfg.Subject}, nil
+	return &Subscriber{nc: nc, subject: AllPartitionsSubject(cfg)}, nil
 }
 
 // Start subscribes to the given subject and starts processing messages with the provided handler.
 func (s *Subscriber) Start(handler PacketHandler) error {
 	sub, err := s.nc.Subscribe(s.subject, func(msg *nats.Msg) {
-		info, err := UnmarshalPacketInfo(msg.Data)
+		packets, err := UnmarshalPackets(msg.Data)
 		if err != nil {
 			log.Printf("Error decoding thrift packet: %v", err)
 			return
 		}
-		handler(info)
+		for _, info := range packets {
+			handler(info)
+		}
 	})
 	if err != nil {
 		return err
@@ -0,0 +1,196 @@
+package protocol
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"Go2NetSpectra/internal/model"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// Decoder extracts PacketInfo straight from frame bytes using a fixed set of
+// reusable gopacket DecodingLayers, so decoding a packet does not allocate.
+//
+// Unlike gopacket.DecodingLayerParser, which keeps one value per layer type,
+// Decoder hands every header to the same layerWalker as Parser as soon as it
+// is decoded. That lets the inner headers of a tunnel reuse the outer layer
+// values while producing the same PacketInfo as Parser.
+//
+// A Decoder is not safe for concurrent use.
+type Decoder struct {
+	parser    *Parser
+	first     gopacket.LayerType
+	raw       bool
+	container gopacket.DecodingLayerContainer
+
+	ethernet layers.Ethernet
+	sll      layers.LinuxSLL
+	loopback layers.Loopback
+	dot1q    layers.Dot1Q
+	mpls     mplsLayer
+	ipv4     layers.IPv4
+	ipv6     layers.IPv6
+	ipv6Ext  layers.IPv6ExtensionSkipper
+	tcp      layers.TCP
+	udp      layers.UDP
+	vxlan    layers.VXLAN
+	geneve   geneveLayer
+	gre      layers.GRE
+}
+
+// NewDecoder creates a Decoder for the given link type that keeps the outer headers.
+func NewDecoder(linkType layers.LinkType) (*Decoder, error) {
+	return defaultParser.NewDecoder(linkType)
+}
+
+// NewDecoder creates a Decoder for the given link type that applies the
+// parser's decapsulation settings.
+func (p *Parser) NewDecoder(linkType layers.LinkType) (*Decoder, error) {
+	d := &Decoder{parser: p}
+	switch linkType {
+	case layers.LinkTypeEthernet:
+		d.first = layers.LayerTypeEthernet
+	case layers.LinkTypeLinuxSLL:
+		d.first = layers.LayerTypeLinuxSLL
+	case layers.LinkTypeNull, layers.LinkTypeLoop:
+		d.first = layers.LayerTypeLoopback
+	case layers.LinkTypeRaw, layers.LinkTypeIPv4, layers.LinkTypeIPv6:
+		d.raw = true
+	default:
+		return nil, fmt.Errorf("unsupported link type %s", linkType)
+	}
+
+	var container gopacket.DecodingLayerSparse
+	for _, layer := range []gopacket.DecodingLayer{
+		&d.ethernet, &d.sll, &d.loopback, &d.dot1q, &d.mpls,
+		&d.ipv4, &d.ipv6, &d.ipv6Ext, &d.tcp, &d.udp,
+		&d.vxlan, &d.geneve, &d.gre,
+	} {
+		container = container.Put(layer).(gopacket.DecodingLayerSparse)
+	}
+	d.container = container
+
+	return d, nil
+}
+
+// DecodeInto decodes a captured frame into a caller-provided PacketInfo.
+//
+// The addresses are copied into the capacity already held by info's IP
+// slices, so reusing the same PacketInfo keeps the call allocation-free and
+// leaves nothing pointing into data.
+func (d *Decoder) DecodeInto(data []byte, ci gopacket.CaptureInfo, info *model.PacketInfo) error {
+	if info == nil {
+		return fmt.Errorf("nil packet info")
+	}
+
+	srcIP, dstIP := info.FiveTuple.SrcIP[:0], info.FiveTuple.DstIP[:0]
+	*info = model.PacketInfo{
+		Timestamp: ci.Timestamp,
+		Length:    ci.Length,
+	}
+
+	typ := d.first
+	if d.raw {
+		typ = ipLayerType(data)
+	}
+
+	w := layerWalker{parser: d.parser, info: info}
+	for len(data) > 0 {
+		layer, ok := d.container.Decoder(typ)
+		if !ok {
+			break
+		}
+		if err := layer.DecodeFromBytes(data, gopacket.NilDecodeFeedback); err != nil {
+			break
+		}
+		if !w.visit(d.walkerLayer(layer)) {
+			break
+		}
+		typ = layer.NextLayerType()
+		data = layer.LayerPayload()
+	}
+
+	if !w.haveIP {
+		return fmt.Errorf("not an IP packet")
+	}
+
+	info.FiveTuple = w.fiveTuple
+	info.FiveTuple.SrcIP = append(srcIP, w.fiveTuple.SrcIP...)
+	info.FiveTuple.DstIP = append(dstIP, w.fiveTuple.DstIP...)
+
+	return nil
+}
+
+// walkerLayer unwraps the local layer adapters to the gopacket types the walker expects.
+func (d *Decoder) walkerLayer(layer gopacket.DecodingLayer) gopacket.Layer {
+	switch layer {
+	case gopacket.DecodingLayer(&d.mpls):
+		return &d.mpls.MPLS
+	case gopacket.DecodingLayer(&d.geneve):
+		return &d.geneve.Geneve
+	}
+	return layer.(gopacket.Layer)
+}
+
+// ipLayerType guesses the IP version of an untyped payload from its first nibble.
+func ipLayerType(data []byte) gopacket.LayerType {
+	if len(data) == 0 {
+		return gopacket.LayerTypeZero
+	}
+	switch data[0] >> 4 {
+	case 4:
+		return layers.LayerTypeIPv4
+	case 6:
+		return layers.LayerTypeIPv6
+	}
+	return gopacket.LayerTypeZero
+}
+
+// mplsLayer decodes a single MPLS label stack entry; gopacket only provides a
+// Packet decoder for MPLS.
+type mplsLayer struct {
+	layers.MPLS
+}
+
+func (m *mplsLayer) CanDecode() gopacket.LayerClass {
+	return layers.LayerTypeMPLS
+}
+
+func (m *mplsLayer) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	if len(data) < 4 {
+		df.SetTruncated()
+		return fmt.Errorf("MPLS label stack entry too short")
+	}
+	entry := binary.BigEndian.Uint32(data[:4])
+	m.Label = entry >> 12
+	m.TrafficClass = uint8(entry>>9) & 0x7
+	m.StackBottom = entry&0x100 != 0
+	m.TTL = uint8(entry)
+	m.BaseLayer = layers.BaseLayer{Contents: data[:4], Payload: data[4:]}
+	return nil
+}
+
+// NextLayerType mirrors gopacket's MPLS decoder, guessing the payload once the stack ends.
+func (m *mplsLayer) NextLayerType() gopacket.LayerType {
+	if !m.StackBottom {
+		return layers.LayerTypeMPLS
+	}
+	return ipLayerType(m.Payload)
+}
+
+// geneveLayer makes layers.Geneve reusable: upstream lacks CanDecode and
+// appends options across calls.
+type geneveLayer struct {
+	layers.Geneve
+}
+
+func (g *geneveLayer) CanDecode() gopacket.LayerClass {
+	return layers.LayerTypeGeneve
+}
+
+func (g *geneveLayer) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	g.Options = g.Options[:0]
+	return g.Geneve.DecodeFromBytes(data, df)
+}
@@ -0,0 +1,172 @@
+package protocol
+
+import (
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func ethernetPacket(t *testing.T, etherType layers.EthernetType, stack ...gopacket.SerializableLayer) gopacket.Packet {
+	ethernet := &layers.Ethernet{
+		SrcMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		DstMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 6},
+		EthernetType: etherType,
+	}
+	data := serializeLayers(t, append([]gopacket.SerializableLayer{ethernet}, stack...)...)
+	return gopacket.NewPacket(data, layers.LayerTypeEthernet, gopacket.Default)
+}
+
+func decoderTestPackets(t *testing.T) []struct {
+	name     string
+	linkType layers.LinkType
+	packet   gopacket.Packet
+} {
+	return []struct {
+		name     string
+		linkType layers.LinkType
+		packet   gopacket.Packet
+	}{
+		{name: "vxlan", linkType: layers.LinkTypeRaw, packet: vxlanPacket(t)},
+		{name: "geneve", linkType: layers.LinkTypeRaw, packet: genevePacket(t)},
+		{name: "gre", linkType: layers.LinkTypeRaw, packet: grePacket(t)},
+		{name: "ipip", linkType: layers.LinkTypeRaw, packet: ipipPacket(t)},
+		{
+			name:     "qinq",
+			linkType: layers.LinkTypeEthernet,
+			packet: ethernetPacket(t, layers.EthernetTypeQinQ,
+				&layers.Dot1Q{VLANIdentifier: 100, Type: layers.EthernetTypeDot1Q},
+				&layers.Dot1Q{VLANIdentifier: 200, Type: layers.EthernetTypeIPv4},
+				innerTCPLayers()[0], innerTCPLayers()[1],
+			),
+		},
+		{
+			name:     "mpls",
+			linkType: layers.LinkTypeEthernet,
+			packet: ethernetPacket(t, layers.EthernetTypeMPLSUnicast,
+				&layers.MPLS{Label: 3000, TTL: 64},
+				&layers.MPLS{Label: 4000, StackBottom: true, TTL: 64},
+				innerTCPLayers()[0], innerTCPLayers()[1],
+			),
+		},
+		{
+			name:     "ipv6 udp",
+			linkType: layers.LinkTypeEthernet,
+			packet: ethernetPacket(t, layers.EthernetTypeIPv6,
+				&layers.IPv6{Version: 6, HopLimit: 64, NextHeader: layers.IPProtocolUDP, SrcIP: net.ParseIP("2001:db8::1"), DstIP: net.ParseIP("2001:db8::2")},
+				&layers.UDP{SrcPort: 5353, DstPort: 53},
+			),
+		},
+	}
+}
+
+func mustNewDecoder(t testing.TB, parser *Parser, linkType layers.LinkType) *Decoder {
+	t.Helper()
+	decoder, err := parser.NewDecoder(linkType)
+	if err != nil {
+		t.Fatalf("NewDecoder(%s) unexpected error: %v", linkType, err)
+	}
+	return decoder
+}
+
+func TestDecoderMatchesParser(t *testing.T) {
+	ci := gopacket.CaptureInfo{Timestamp: time.Unix(1700000000, 0), Length: 1500}
+
+	for _, mode := range []string{"outer", "inner"} {
+		parser := mustNewParser(t, config.DecapConfig{Mode: mode})
+		for _, tt := range decoderTestPackets(t) {
+			t.Run(mode+"/"+tt.name, func(t *testing.T) {
+				tt.packet.Metadata().CaptureInfo = ci
+
+				var want, got model.PacketInfo
+				if err := parser.ParseInto(tt.packet, &want); err != nil {
+					t.Fatalf("ParseInto() unexpected error: %v", err)
+				}
+				decoder := mustNewDecoder(t, parser, tt.linkType)
+				if err := decoder.DecodeInto(tt.packet.Data(), ci, &got); err != nil {
+					t.Fatalf("DecodeInto() unexpected error: %v", err)
+				}
+
+				if !got.FiveTuple.SrcIP.Equal(want.FiveTuple.SrcIP) || !got.FiveTuple.DstIP.Equal(want.FiveTuple.DstIP) {
+					t.Fatalf("DecodeInto() ips = %v -> %v, want %v -> %v", got.FiveTuple.SrcIP, got.FiveTuple.DstIP, want.FiveTuple.SrcIP, want.FiveTuple.DstIP)
+				}
+				got.FiveTuple.SrcIP, got.FiveTuple.DstIP = nil, nil
+				want.FiveTuple.SrcIP, want.FiveTuple.DstIP = nil, nil
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("DecodeInto() = %+v, want %+v", got, want)
+				}
+			})
+		}
+	}
+}
+
+func TestDecoderDecodeIntoDoesNotAllocate(t *testing.T) {
+	for _, tt := range decoderTestPackets(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder := mustNewDecoder(t, mustNewParser(t, config.DecapConfig{Mode: "inner"}), tt.linkType)
+			data := tt.packet.Data()
+			ci := gopacket.CaptureInfo{Length: len(data), CaptureLength: len(data)}
+
+			var info model.PacketInfo
+			if err := decoder.DecodeInto(data, ci, &info); err != nil {
+				t.Fatalf("DecodeInto() unexpected error: %v", err)
+			}
+			allocs := testing.AllocsPerRun(100, func() {
+				if err := decoder.DecodeInto(data, ci, &info); err != nil {
+					t.Fatalf("DecodeInto() unexpected error: %v", err)
+				}
+			})
+			if allocs != 0 {
+				t.Fatalf("DecodeInto() allocs = %v, want 0", allocs)
+			}
+		})
+	}
+}
+
+func TestDecoderDoesNotRetainFrame(t *testing.T) {
+	decoder := mustNewDecoder(t, defaultParser, layers.LinkTypeRaw)
+	data := ipipPacket(t).Data()
+
+	var info model.PacketInfo
+	if err := decoder.DecodeInto(data, gopacket.CaptureInfo{}, &info); err != nil {
+		t.Fatalf("DecodeInto() unexpected error: %v", err)
+	}
+	for i := range data {
+		data[i] = 0
+	}
+	if !info.FiveTuple.SrcIP.Equal(outerSrc) || !info.FiveTuple.DstIP.Equal(outerDst) {
+		t.Fatalf("DecodeInto() ips after clearing frame = %v -> %v, want %v -> %v", info.FiveTuple.SrcIP, info.FiveTuple.DstIP, outerSrc, outerDst)
+	}
+}
+
+func TestDecoderRejectsNonIPFrames(t *testing.T) {
+	decoder := mustNewDecoder(t, defaultParser, layers.LinkTypeEthernet)
+	packet := ethernetPacket(t, layers.EthernetTypeARP, &layers.ARP{
+		AddrType:          layers.LinkTypeEthernet,
+		Protocol:          layers.EthernetTypeIPv4,
+		HwAddressSize:     6,
+		ProtAddressSize:   4,
+		Operation:         layers.ARPRequest,
+		SourceHwAddress:   []byte{0, 1, 2, 3, 4, 5},
+		SourceProtAddress: []byte{192, 0, 2, 1},
+		DstHwAddress:      []byte{0, 0, 0, 0, 0, 0},
+		DstProtAddress:    []byte{192, 0, 2, 2},
+	})
+
+	var info model.PacketInfo
+	if err := decoder.DecodeInto(packet.Data(), gopacket.CaptureInfo{}, &info); err == nil {
+		t.Fatal("DecodeInto(arp) error = nil, want non-nil")
+	}
+}
+
+func TestNewDecoderRejectsUnsupportedLinkType(t *testing.T) {
+	if _, err := NewDecoder(layers.LinkTypeIEEE802_11); err == nil {
+		t.Fatal("NewDecoder(802.11) error = nil, want non-nil")
+	}
+}
@@ -3,24 +3,75 @@ package protocol
 import (
 	"fmt"
 
+	"Go2NetSpectra/internal/config"
 	"Go2NetSpectra/internal/model"
 
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 )
 
+const (
+	decapModeOuter = "outer"
+	decapModeInner = "inner"
+)
+
+// Parser extracts PacketInfo from decoded packets, looking through the
+// configured tunnel encapsulations when asked to use the inner headers.
+type Parser struct {
+	inner   bool
+	tunnels tunnelSet
+}
+
+// defaultParser keeps the outer headers and records the ID of any known tunnel.
+var defaultParser = &Parser{tunnels: allTunnels}
+
+// NewParser creates a Parser from the probe decapsulation settings.
+func NewParser(cfg config.DecapConfig) (*Parser, error) {
+	p := &Parser{}
+	switch cfg.Mode {
+	case "", decapModeOuter:
+	case decapModeInner:
+		p.inner = true
+	default:
+		return nil, fmt.Errorf("unknown decap mode %q, want %q or %q", cfg.Mode, decapModeOuter, decapModeInner)
+	}
+
+	tunnels, err := parseTunnelSet(cfg.Tunnels)
+	if err != nil {
+		return nil, err
+	}
+	p.tunnels = tunnels
+
+	return p, nil
+}
+
 // ParsePacket uses gopacket to decode a raw packet and extract key information.
 func ParsePacket(packet gopacket.Packet) (*model.PacketInfo, error) {
+	return defaultParser.Parse(packet)
+}
+
+// ParsePacketInto decodes a raw packet into a caller-provided PacketInfo.
+func ParsePacketInto(packet gopacket.Packet, info *model.PacketInfo) error {
+	return defaultParser.ParseInto(packet, info)
+}
+
+// Parse decodes a raw packet into a newly allocated PacketInfo.
+func (p *Parser) Parse(packet gopacket.Packet) (*model.PacketInfo, error) {
 	info := &model.PacketInfo{}
-	if err := ParsePacketInto(packet, info); err != nil {
+	if err := p.ParseInto(packet, info); err != nil {
 		return nil, err
 	}
 
 	return info, nil
 }
 
-// ParsePacketInto decodes a raw packet into a caller-provided PacketInfo.
-func ParsePacketInto(packet gopacket.Packet, info *model.PacketInfo) error {
+// ParseInto decodes a raw packet into a caller-provided PacketInfo.
+//
+// Layers are walked in order. The first IP header and the transport header
+// that follows it form the outer 5-tuple. In inner mode every enabled tunnel
+// (or IP-in-IP header) found after it replaces the headers with the ones it
+// carries, so the innermost 5-tuple wins.
+func (p *Parser) ParseInto(packet gopacket.Packet, info *model.PacketInfo) error {
 	if info == nil {
 		return fmt.Errorf("nil packet info")
 	}
@@ -32,36 +83,144 @@ func ParsePacketInto(packet gopacket.Packet, info *model.PacketInfo) error {
 		info.Length = meta.Length
 	}
 
-	var fiveTuple model.FiveTuple
-
-	// Get IP layer
-	if ipLayer := packet.Layer(layers.LayerTypeIPv4); ipLayer != nil {
-		ip, _ := ipLayer.(*layers.IPv4)
-		fiveTuple.SrcIP = ip.SrcIP
-		fiveTuple.DstIP = ip.DstIP
-		fiveTuple.Protocol = uint8(ip.Protocol)
-	} else if ipLayer := packet.Layer(layers.LayerTypeIPv6); ipLayer != nil {
-		ip, _ := ipLayer.(*layers.IPv6)
-		fiveTuple.SrcIP = ip.SrcIP
-		fiveTuple.DstIP = ip.DstIP
-		fiveTuple.Protocol = uint8(ip.NextHeader)
-	} else {
-		return fmt.Errorf("not an IP packet")
+	w := layerWalker{parser: p, info: info}
+	for _, layer := range packet.Layers() {
+		if !w.visit(layer) {
+			break
+		}
 	}
+	// For other protocols like ICMP, the ports will be 0, which is correct.
 
-	// Get transport layer
-	if tcpLayer := packet.Layer(layers.LayerTypeTCP); tcpLayer != nil {
-		tcp, _ := tcpLayer.(*layers.TCP)
-		fiveTuple.SrcPort = uint16(tcp.SrcPort)
-		fiveTuple.DstPort = uint16(tcp.DstPort)
-	} else if udpLayer := packet.Layer(layers.LayerTypeUDP); udpLayer != nil {
-		udp, _ := udpLayer.(*layers.UDP)
-		fiveTuple.SrcPort = uint16(udp.SrcPort)
-		fiveTuple.DstPort = uint16(udp.DstPort)
+	if !w.haveIP {
+		return fmt.Errorf("not an IP packet")
 	}
-	// For other protocols like ICMP, the ports will be 0, which is correct.
 
-	info.FiveTuple = fiveTuple
+	info.FiveTuple = w.fiveTuple
 
 	return nil
 }
+
+// layerWalker applies the decapsulation rules to a packet's layers, one at a
+// time and in order. It is shared by Parser and Decoder so both agree on
+// which headers form the 5-tuple.
+type layerWalker struct {
+	parser        *Parser
+	info          *model.PacketInfo
+	fiveTuple     model.FiveTuple
+	vlanTags      int
+	haveMPLS      bool
+	haveIP        bool
+	haveTransport bool
+	crossedTunnel bool
+}
+
+// visit records the fields carried by layer and reports whether the walk should continue.
+func (w *layerWalker) visit(layer gopacket.Layer) bool {
+	info := w.info
+	switch l := layer.(type) {
+	case *layers.Dot1Q:
+		// Only the link-layer tags in front of the outer IP header identify the tenant.
+		if w.haveIP {
+			return true
+		}
+		switch w.vlanTags {
+		case 0:
+			info.OuterVLAN = l.VLANIdentifier
+		case 1:
+			info.InnerVLAN = l.VLANIdentifier
+		}
+		w.vlanTags++
+	case *layers.MPLS:
+		if w.haveIP || w.haveMPLS {
+			return true
+		}
+		info.MPLSLabel = l.Label
+		w.haveMPLS = true
+	case *layers.IPv4, *layers.IPv6:
+		if w.haveIP {
+			if !w.parser.inner {
+				return false
+			}
+			// An IP header right after another one, with no tunnel header in between, is IP-in-IP.
+			if !w.crossedTunnel && (w.haveTransport || !w.parser.tunnels.has(tunnelIPIP)) {
+				return false
+			}
+		}
+		w.fiveTuple = model.FiveTuple{}
+		setIPFields(&w.fiveTuple, l)
+		info.TCPFlags = 0
+		w.haveIP, w.haveTransport, w.crossedTunnel = true, false, false
+	case *layers.TCP:
+		if !w.haveIP || w.haveTransport {
+			return true
+		}
+		w.fiveTuple.SrcPort = uint16(l.SrcPort)
+		w.fiveTuple.DstPort = uint16(l.DstPort)
+		info.TCPFlags = tcpFlags(l)
+		w.haveTransport = true
+	case *layers.UDP:
+		if !w.haveIP || w.haveTransport {
+			return true
+		}
+		w.fiveTuple.SrcPort = uint16(l.SrcPort)
+		w.fiveTuple.DstPort = uint16(l.DstPort)
+		w.haveTransport = true
+	case *layers.VXLAN, *layers.Geneve, *layers.GRE:
+		kind, id, hasID := tunnelHeader(l)
+		if !w.haveIP || !w.parser.tunnels.has(kind) {
+			return false
+		}
+		if hasID {
+			info.TunnelID = id
+		}
+		if !w.parser.inner {
+			return false
+		}
+		w.crossedTunnel = true
+	}
+	return true
+}
+
+// setIPFields copies the addresses and protocol of an IPv4 or IPv6 header.
+func setIPFields(ft *model.FiveTuple, layer gopacket.Layer) {
+	switch ip := layer.(type) {
+	case *layers.IPv4:
+		ft.SrcIP = ip.SrcIP
+		ft.DstIP = ip.DstIP
+		ft.Protocol = uint8(ip.Protocol)
+	case *layers.IPv6:
+		ft.SrcIP = ip.SrcIP
+		ft.DstIP = ip.DstIP
+		ft.Protocol = uint8(ip.NextHeader)
+	}
+}
+
+// tcpFlags packs the flag bits of a decoded TCP header into a single byte.
+func tcpFlags(tcp *layers.TCP) uint8 {
+	var flags uint8
+	if tcp.FIN {
+		flags |= model.TCPFlagFIN
+	}
+	if tcp.SYN {
+		flags |= model.TCPFlagSYN
+	}
+	if tcp.RST {
+		flags |= model.TCPFlagRST
+	}
+	if tcp.PSH {
+		flags |= model.TCPFlagPSH
+	}
+	if tcp.ACK {
+		flags |= model.TCPFlagACK
+	}
+	if tcp.URG {
+		flags |= model.TCPFlagURG
+	}
+	if tcp.ECE {
+		flags |= model.TCPFlagECE
+	}
+	if tcp.CWR {
+		flags |= model.TCPFlagCWR
+	}
+	return flags
+}
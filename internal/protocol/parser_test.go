@@ -1,11 +1,13 @@
 package protocol
 
 import (
+	"net"
 	"testing"
 
 	"Go2NetSpectra/internal/model"
 
 	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
 	"github.com/google/gopacket/pcap"
 )
 
@@ -90,3 +92,111 @@ func TestParsePacketIntoMatchesParsePacket(t *testing.T) {
 		t.Fatalf("ParsePacketInto() protocol = %d, want %d", reusedInfo.FiveTuple.Protocol, parsed.FiveTuple.Protocol)
 	}
 }
+
+func TestParsePacketIntoExtractsTCPFlags(t *testing.T) {
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolTCP,
+		SrcIP:    net.ParseIP("192.0.2.1"),
+		DstIP:    net.ParseIP("192.0.2.2"),
+	}
+	tcp := &layers.TCP{
+		SrcPort: 40000,
+		DstPort: 443,
+		SYN:     true,
+		ACK:     true,
+	}
+	if err := tcp.SetNetworkLayerForChecksum(ip); err != nil {
+		t.Fatalf("SetNetworkLayerForChecksum() unexpected error: %v", err)
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, ip, tcp); err != nil {
+		t.Fatalf("SerializeLayers() unexpected error: %v", err)
+	}
+	packet := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeIPv4, gopacket.Default)
+
+	var info model.PacketInfo
+	if err := ParsePacketInto(packet, &info); err != nil {
+		t.Fatalf("ParsePacketInto() unexpected error: %v", err)
+	}
+
+	want := model.TCPFlagSYN | model.TCPFlagACK
+	if info.TCPFlags != want {
+		t.Fatalf("ParsePacketInto() tcp flags = %#x, want %#x", info.TCPFlags, want)
+	}
+	if info.FiveTuple.DstPort != 443 {
+		t.Fatalf("ParsePacketInto() dst port = %d, want 443", info.FiveTuple.DstPort)
+	}
+}
+
+func TestParsePacketIntoExtractsVLANAndMPLS(t *testing.T) {
+	ethernet := &layers.Ethernet{
+		SrcMAC: net.HardwareAddr{0, 1, 2, 3, 4, 5},
+		DstMAC: net.HardwareAddr{0, 1, 2, 3, 4, 6},
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.ParseIP("192.0.2.1"),
+		DstIP:    net.ParseIP("192.0.2.2"),
+	}
+	udp := &layers.UDP{SrcPort: 5000, DstPort: 53}
+
+	tests := []struct {
+		name      string
+		stack     []gopacket.SerializableLayer
+		outerVLAN uint16
+		innerVLAN uint16
+		mplsLabel uint32
+	}{
+		{
+			name: "qinq",
+			stack: []gopacket.SerializableLayer{
+				&layers.Ethernet{SrcMAC: ethernet.SrcMAC, DstMAC: ethernet.DstMAC, EthernetType: layers.EthernetTypeQinQ},
+				&layers.Dot1Q{VLANIdentifier: 100, Type: layers.EthernetTypeDot1Q},
+				&layers.Dot1Q{VLANIdentifier: 200, Type: layers.EthernetTypeIPv4},
+				ip, udp,
+			},
+			outerVLAN: 100,
+			innerVLAN: 200,
+		},
+		{
+			name: "mpls stack",
+			stack: []gopacket.SerializableLayer{
+				&layers.Ethernet{SrcMAC: ethernet.SrcMAC, DstMAC: ethernet.DstMAC, EthernetType: layers.EthernetTypeMPLSUnicast},
+				&layers.MPLS{Label: 3000, TTL: 64},
+				&layers.MPLS{Label: 4000, StackBottom: true, TTL: 64},
+				ip, udp,
+			},
+			mplsLabel: 3000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := gopacket.NewSerializeBuffer()
+			if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, tt.stack...); err != nil {
+				t.Fatalf("SerializeLayers() unexpected error: %v", err)
+			}
+			packet := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+
+			var info model.PacketInfo
+			if err := ParsePacketInto(packet, &info); err != nil {
+				t.Fatalf("ParsePacketInto() unexpected error: %v", err)
+			}
+			if info.OuterVLAN != tt.outerVLAN || info.InnerVLAN != tt.innerVLAN {
+				t.Fatalf("ParsePacketInto() vlans = %d/%d, want %d/%d", info.OuterVLAN, info.InnerVLAN, tt.outerVLAN, tt.innerVLAN)
+			}
+			if info.MPLSLabel != tt.mplsLabel {
+				t.Fatalf("ParsePacketInto() mpls label = %d, want %d", info.MPLSLabel, tt.mplsLabel)
+			}
+			if info.FiveTuple.DstPort != 53 {
+				t.Fatalf("ParsePacketInto() dst port = %d, want 53", info.FiveTuple.DstPort)
+			}
+		})
+	}
+}
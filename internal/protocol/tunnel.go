@@ -0,0 +1,64 @@
+package protocol
+
+import (
+	"fmt"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// tunnelKind identifies an encapsulation the parser can look through.
+type tunnelKind uint8
+
+const (
+	tunnelVXLAN tunnelKind = 1 << iota
+	tunnelGENEVE
+	tunnelGRE
+	tunnelIPIP
+)
+
+// tunnelSet is a bitmask of enabled tunnelKinds.
+type tunnelSet uint8
+
+const allTunnels = tunnelSet(tunnelVXLAN | tunnelGENEVE | tunnelGRE | tunnelIPIP)
+
+var tunnelNames = map[string]tunnelKind{
+	"vxlan":  tunnelVXLAN,
+	"geneve": tunnelGENEVE,
+	"gre":    tunnelGRE,
+	"ipip":   tunnelIPIP,
+}
+
+func (s tunnelSet) has(kind tunnelKind) bool {
+	return s&tunnelSet(kind) != 0
+}
+
+// parseTunnelSet converts configured tunnel names into a tunnelSet; no names enables all.
+func parseTunnelSet(names []string) (tunnelSet, error) {
+	if len(names) == 0 {
+		return allTunnels, nil
+	}
+
+	var set tunnelSet
+	for _, name := range names {
+		kind, ok := tunnelNames[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown tunnel type %q", name)
+		}
+		set |= tunnelSet(kind)
+	}
+	return set, nil
+}
+
+// tunnelHeader reports the kind of a tunnel layer and its ID (VNI or GRE key), if it carries one.
+func tunnelHeader(layer gopacket.Layer) (kind tunnelKind, id uint32, hasID bool) {
+	switch l := layer.(type) {
+	case *layers.VXLAN:
+		return tunnelVXLAN, l.VNI, l.ValidIDFlag
+	case *layers.Geneve:
+		return tunnelGENEVE, l.VNI, true
+	case *layers.GRE:
+		return tunnelGRE, l.Key, l.KeyPresent
+	}
+	return 0, 0, false
+}
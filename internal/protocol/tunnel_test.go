@@ -0,0 +1,195 @@
+package protocol
+
+import (
+	"net"
+	"testing"
+
+	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/model"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+var (
+	outerSrc = net.ParseIP("198.51.100.1").To4()
+	outerDst = net.ParseIP("198.51.100.2").To4()
+	innerSrc = net.ParseIP("10.0.0.1").To4()
+	innerDst = net.ParseIP("10.0.0.2").To4()
+)
+
+func serializeLayers(t *testing.T, serializable ...gopacket.SerializableLayer) []byte {
+	t.Helper()
+	buf := gopacket.NewSerializeBuffer()
+	if err := gopacket.SerializeLayers(buf, gopacket.SerializeOptions{FixLengths: true}, serializable...); err != nil {
+		t.Fatalf("SerializeLayers() unexpected error: %v", err)
+	}
+	return append([]byte(nil), buf.Bytes()...)
+}
+
+func innerTCPLayers() []gopacket.SerializableLayer {
+	return []gopacket.SerializableLayer{
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolTCP, SrcIP: innerSrc, DstIP: innerDst},
+		&layers.TCP{SrcPort: 51000, DstPort: 80, SYN: true},
+	}
+}
+
+func vxlanPacket(t *testing.T) gopacket.Packet {
+	stack := []gopacket.SerializableLayer{
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: outerSrc, DstIP: outerDst},
+		&layers.UDP{SrcPort: 40000, DstPort: 4789},
+		&layers.VXLAN{ValidIDFlag: true, VNI: 5001},
+		&layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 5},
+			DstMAC:       net.HardwareAddr{0, 1, 2, 3, 4, 6},
+			EthernetType: layers.EthernetTypeIPv4,
+		},
+	}
+	stack = append(stack, innerTCPLayers()...)
+	return gopacket.NewPacket(serializeLayers(t, stack...), layers.LayerTypeIPv4, gopacket.Default)
+}
+
+func genevePacket(t *testing.T) gopacket.Packet {
+	inner := serializeLayers(t, innerTCPLayers()...)
+	// Version 0, no options, protocol IPv4, VNI 0x000102.
+	header := []byte{0x00, 0x00, 0x08, 0x00, 0x00, 0x01, 0x02, 0x00}
+	data := serializeLayers(t,
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolUDP, SrcIP: outerSrc, DstIP: outerDst},
+		&layers.UDP{SrcPort: 40000, DstPort: 6081},
+		gopacket.Payload(append(header, inner...)),
+	)
+	return gopacket.NewPacket(data, layers.LayerTypeIPv4, gopacket.Default)
+}
+
+func grePacket(t *testing.T) gopacket.Packet {
+	stack := []gopacket.SerializableLayer{
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolGRE, SrcIP: outerSrc, DstIP: outerDst},
+		&layers.GRE{KeyPresent: true, Key: 42, Protocol: layers.EthernetTypeIPv4},
+	}
+	stack = append(stack, innerTCPLayers()...)
+	return gopacket.NewPacket(serializeLayers(t, stack...), layers.LayerTypeIPv4, gopacket.Default)
+}
+
+func ipipPacket(t *testing.T) gopacket.Packet {
+	stack := []gopacket.SerializableLayer{
+		&layers.IPv4{Version: 4, TTL: 64, Protocol: layers.IPProtocolIPv4, SrcIP: outerSrc, DstIP: outerDst},
+	}
+	stack = append(stack, innerTCPLayers()...)
+	return gopacket.NewPacket(serializeLayers(t, stack...), layers.LayerTypeIPv4, gopacket.Default)
+}
+
+func mustNewParser(t *testing.T, cfg config.DecapConfig) *Parser {
+	t.Helper()
+	parser, err := NewParser(cfg)
+	if err != nil {
+		t.Fatalf("NewParser(%+v) unexpected error: %v", cfg, err)
+	}
+	return parser
+}
+
+func TestParserInnerModeUsesInnerFiveTuple(t *testing.T) {
+	parser := mustNewParser(t, config.DecapConfig{Mode: "inner"})
+
+	tests := []struct {
+		name     string
+		packet   gopacket.Packet
+		tunnelID uint32
+	}{
+		{name: "vxlan", packet: vxlanPacket(t), tunnelID: 5001},
+		{name: "geneve", packet: genevePacket(t), tunnelID: 0x000102},
+		{name: "gre", packet: grePacket(t), tunnelID: 42},
+		{name: "ipip", packet: ipipPacket(t), tunnelID: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var info model.PacketInfo
+			if err := parser.ParseInto(tt.packet, &info); err != nil {
+				t.Fatalf("ParseInto() unexpected error: %v", err)
+			}
+			if !info.FiveTuple.SrcIP.Equal(innerSrc) || !info.FiveTuple.DstIP.Equal(innerDst) {
+				t.Fatalf("ParseInto() ips = %v -> %v, want %v -> %v", info.FiveTuple.SrcIP, info.FiveTuple.DstIP, innerSrc, innerDst)
+			}
+			if info.FiveTuple.Protocol != uint8(layers.IPProtocolTCP) {
+				t.Fatalf("ParseInto() protocol = %d, want %d", info.FiveTuple.Protocol, layers.IPProtocolTCP)
+			}
+			if info.FiveTuple.SrcPort != 51000 || info.FiveTuple.DstPort != 80 {
+				t.Fatalf("ParseInto() ports = %d -> %d, want 51000 -> 80", info.FiveTuple.SrcPort, info.FiveTuple.DstPort)
+			}
+			if info.TCPFlags != model.TCPFlagSYN {
+				t.Fatalf("ParseInto() tcp flags = %#x, want %#x", info.TCPFlags, model.TCPFlagSYN)
+			}
+			if info.TunnelID != tt.tunnelID {
+				t.Fatalf("ParseInto() tunnel id = %d, want %d", info.TunnelID, tt.tunnelID)
+			}
+		})
+	}
+}
+
+func TestParserOuterModeKeepsOuterHeaders(t *testing.T) {
+	parser := mustNewParser(t, config.DecapConfig{})
+
+	var info model.PacketInfo
+	if err := parser.ParseInto(vxlanPacket(t), &info); err != nil {
+		t.Fatalf("ParseInto() unexpected error: %v", err)
+	}
+	if !info.FiveTuple.SrcIP.Equal(outerSrc) || !info.FiveTuple.DstIP.Equal(outerDst) {
+		t.Fatalf("ParseInto() ips = %v -> %v, want %v -> %v", info.FiveTuple.SrcIP, info.FiveTuple.DstIP, outerSrc, outerDst)
+	}
+	if info.FiveTuple.Protocol != uint8(layers.IPProtocolUDP) || info.FiveTuple.DstPort != 4789 {
+		t.Fatalf("ParseInto() protocol/dst port = %d/%d, want %d/4789", info.FiveTuple.Protocol, info.FiveTuple.DstPort, layers.IPProtocolUDP)
+	}
+	if info.TCPFlags != 0 {
+		t.Fatalf("ParseInto() tcp flags = %#x, want 0", info.TCPFlags)
+	}
+	if info.TunnelID != 5001 {
+		t.Fatalf("ParseInto() tunnel id = %d, want 5001", info.TunnelID)
+	}
+}
+
+func TestParserResetsReusedPacketInfo(t *testing.T) {
+	parser := mustNewParser(t, config.DecapConfig{})
+	plain := gopacket.NewPacket(serializeLayers(t, innerTCPLayers()...), layers.LayerTypeIPv4, gopacket.Default)
+
+	var info model.PacketInfo
+	if err := parser.ParseInto(vxlanPacket(t), &info); err != nil {
+		t.Fatalf("ParseInto(vxlan) unexpected error: %v", err)
+	}
+	if err := parser.ParseInto(plain, &info); err != nil {
+		t.Fatalf("ParseInto(plain) unexpected error: %v", err)
+	}
+	if info.TunnelID != 0 {
+		t.Fatalf("ParseInto(plain) tunnel id = %d, want 0 after a tunnelled packet", info.TunnelID)
+	}
+}
+
+func TestParserSkipsDisabledTunnels(t *testing.T) {
+	parser := mustNewParser(t, config.DecapConfig{Mode: "inner", Tunnels: []string{"gre"}})
+
+	var info model.PacketInfo
+	if err := parser.ParseInto(vxlanPacket(t), &info); err != nil {
+		t.Fatalf("ParseInto() unexpected error: %v", err)
+	}
+	if !info.FiveTuple.SrcIP.Equal(outerSrc) {
+		t.Fatalf("ParseInto() src ip = %v, want outer %v", info.FiveTuple.SrcIP, outerSrc)
+	}
+	if info.TunnelID != 0 {
+		t.Fatalf("ParseInto() tunnel id = %d, want 0 for a disabled tunnel", info.TunnelID)
+	}
+
+	if err := parser.ParseInto(ipipPacket(t), &info); err != nil {
+		t.Fatalf("ParseInto() unexpected error: %v", err)
+	}
+	if !info.FiveTuple.SrcIP.Equal(outerSrc) || info.FiveTuple.Protocol != uint8(layers.IPProtocolIPv4) {
+		t.Fatalf("ParseInto() = %v proto %d, want outer %v proto %d", info.FiveTuple.SrcIP, info.FiveTuple.Protocol, outerSrc, layers.IPProtocolIPv4)
+	}
+}
+
+func TestNewParserRejectsInvalidConfig(t *testing.T) {
+	if _, err := NewParser(config.DecapConfig{Mode: "middle"}); err == nil {
+		t.Fatal("NewParser(mode middle) error = nil, want non-nil")
+	}
+	if _, err := NewParser(config.DecapConfig{Tunnels: []string{"mpls"}}); err == nil {
+		t.Fatal("NewParser(tunnel mpls) error = nil, want non-nil")
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"Go2NetSpectra/internal/config"
+	"Go2NetSpectra/internal/keyfield"
 
 	"github.com/ClickHouse/clickhouse-go/v2"
 )
@@ -18,11 +19,11 @@ import (
 type AggregationRequest struct {
 	EndTime  *time.Time
 	TaskName string
-	SrcIP    string
-	DstIP    string
-	SrcPort  *int32
-	DstPort  *int32
-	Protocol *int32
+	// FlowKeys matches flows on registered key fields by name, such as
+	// "SrcPort" or "ExporterIP".
+	FlowKeys map[string]any
+	// ConnState matches flows whose latest inferred TCP state has this name, e.g. "syn_sent".
+	ConnState string
 }
 
 // TaskSummary represents an aggregate summary row for a task.
@@ -31,6 +32,11 @@ type TaskSummary struct {
 	TotalBytes   int64
 	TotalPackets int64
 	FlowCount    int64
+	SYNCount     int64
+	FINCount     int64
+	RSTCount     int64
+	SampleRate   uint32 // Largest probe sampling rate behind the totals; 1 when exact.
+	Estimated    bool   // Totals were scaled up from sampled traffic.
 }
 
 // QueryTotalCountsResponse contains aggregate summaries.
@@ -51,6 +57,19 @@ type FlowLifecycle struct {
 	LastSeen     time.Time
 	TotalPackets int64
 	TotalBytes   int64
+	SYNCount     int64
+	FINCount     int64
+	RSTCount     int64
+	ACKCount     int64
+	TCPFlags     uint8
+	ConnState    string
+	SampleRate   uint32 // Largest probe sampling rate behind the counters; 1 when exact.
+	Estimated    bool   // Counters were scaled up from sampled traffic.
+	// Lifecycles counts the records the flow was split into, by flow
+	// timeouts or period resets, and EndReason is why the latest one ended;
+	// empty while it is in progress.
+	Lifecycles int64
+	EndReason  string
 }
 
 // HeavyHittersRequest defines the supported heavy-hitter query filters.
@@ -63,8 +82,10 @@ type HeavyHittersRequest struct {
 
 // HeavyHitter represents a single heavy-hitter result row.
 type HeavyHitter struct {
-	Flow  string
-	Value int64
+	Flow       string
+	Value      int64
+	SampleRate uint32 // Probe sampling rate of the sketch snapshot; 1 when not sampled.
+	Estimated  bool   // Value was scaled up from sampled traffic.
 }
 
 // HeavyHittersResponse contains heavy-hitter query results.
@@ -72,11 +93,44 @@ type HeavyHittersResponse struct {
 	Hitters []HeavyHitter
 }
 
+// InterfaceCountersRequest defines the supported interface counter filters.
+type InterfaceCountersRequest struct {
+	EndTime *time.Time
+	// AgentIP matches counters reported by this sFlow agent.
+	AgentIP string
+	// IfIndex matches counters of this SNMP interface index.
+	IfIndex *int64
+}
+
+// InterfaceCounters is the latest counter sample of one sFlow agent interface.
+// Counters are the agent's running totals.
+type InterfaceCounters struct {
+	AgentIP     string
+	IfIndex     uint32
+	Timestamp   time.Time
+	Speed       uint64 // bits per second
+	Status      uint32 // bit 0 admin up, bit 1 oper up
+	InOctets    uint64
+	InPackets   uint64
+	InErrors    uint64
+	InDiscards  uint64
+	OutOctets   uint64
+	OutPackets  uint64
+	OutErrors   uint64
+	OutDiscards uint64
+}
+
+// InterfaceCountersResponse contains interface counter query results.
+type InterfaceCountersResponse struct {
+	Counters []InterfaceCounters
+}
+
 // Querier defines the interface for querying flow data.
 type Querier interface {
 	AggregateFlows(ctx context.Context, req *AggregationRequest) (*QueryTotalCountsResponse, error)
 	TraceFlow(ctx context.Context, req *TraceFlowRequest) (*FlowLifecycle, error)
 	QueryHeavyHitters(ctx context.Context, req *HeavyHittersRequest) (*HeavyHittersResponse, error)
+	QueryInterfaceCounters(ctx context.Context, req *InterfaceCountersRequest) (*InterfaceCountersResponse, error)
 }
 
 // clickhouseQuerier implements the Querier interface for ClickHouse.
@@ -91,14 +145,6 @@ func uint64ToInt64(value uint64, field string) (int64, error) {
 	return int64(value), nil
 }
 
-var traceFlowKeys = map[string]struct{}{
-	"DstIP":    {},
-	"DstPort":  {},
-	"Protocol": {},
-	"SrcIP":    {},
-	"SrcPort":  {},
-}
-
 // NewClickHouseQuerier creates a new querier for ClickHouse.
 func NewClickHouseQuerier(cfg config.ClickHouseConfig) (Querier, error) {
 	conn, err := connect(cfg)
@@ -140,41 +186,49 @@ func connect(cfg config.ClickHouseConfig) (clickhouse.Conn, error) {
 	return conn, nil
 }
 
-func appendAggregationFilters(whereClauses []string, args []any, req *AggregationRequest) ([]string, []any) {
+func appendAggregationFilters(whereClauses []string, args []any, req *AggregationRequest) ([]string, []any, error) {
 	if req.TaskName != "" {
 		whereClauses = append(whereClauses, "TaskName = ?")
 		args = append(args, req.TaskName)
 	}
-	if req.SrcIP != "" {
-		whereClauses = append(whereClauses, "SrcIP = ?")
-		args = append(args, req.SrcIP)
-	}
-	if req.DstIP != "" {
-		whereClauses = append(whereClauses, "DstIP = ?")
-		args = append(args, req.DstIP)
-	}
-	if req.SrcPort != nil {
-		whereClauses = append(whereClauses, "SrcPort = ?")
-		args = append(args, *req.SrcPort)
-	}
-	if req.DstPort != nil {
-		whereClauses = append(whereClauses, "DstPort = ?")
-		args = append(args, *req.DstPort)
+	for key := range req.FlowKeys {
+		// Prefix networks are matched on their address column by name.
+		if field, ok := keyfield.Lookup(key); !ok || field.Base != "" {
+			return nil, nil, fmt.Errorf("unsupported flow key: %s", key)
+		}
 	}
-	if req.Protocol != nil {
-		whereClauses = append(whereClauses, "Protocol = ?")
-		args = append(args, *req.Protocol)
+	for _, field := range keyfield.All() {
+		if value, ok := req.FlowKeys[field.Name]; ok {
+			whereClauses = append(whereClauses, field.Name+" = ?")
+			args = append(args, value)
+		}
 	}
 
-	return whereClauses, args
+	return whereClauses, args, nil
+}
+
+// flowKeyColumns lists the flow_metrics key columns, which together with
+// the task name identify a flow.
+func flowKeyColumns() string {
+	names := make([]string, 0, len(keyfield.All()))
+	for _, field := range keyfield.All() {
+		names = append(names, field.Name)
+	}
+	return strings.Join(names, ", ")
 }
 
 func appendTraceFlowFilters(whereClauses []string, args []any, flowKeys map[string]string) ([]string, []any, error) {
 	sortedKeys := make([]string, 0, len(flowKeys))
 	for key := range flowKeys {
-		if _, ok := traceFlowKeys[key]; !ok {
+		// Only registered key fields are flow_metrics columns.
+		field, ok := keyfield.Lookup(key)
+		if !ok {
 			return nil, nil, fmt.Errorf("unsupported flow key: %s", key)
 		}
+		// A prefix field is stored in its address column as a network.
+		if field.Base != "" {
+			return nil, nil, fmt.Errorf("unsupported flow key: %s (filter %s on the network in CIDR notation)", key, field.Base)
+		}
 		sortedKeys = append(sortedKeys, key)
 	}
 	slices.Sort(sortedKeys)
@@ -187,15 +241,19 @@ func appendTraceFlowFilters(whereClauses []string, args []any, flowKeys map[stri
 	return whereClauses, args, nil
 }
 
-// QueryHeavyHitters builds and executes a dynamic heavy hitters query.
+// QueryHeavyHitters builds and executes a dynamic heavy hitters query. Each
+// engine's latest value for a flow is taken first and then summed, so a flow
+// whose packets were spread over several engines is counted once per engine.
 func (q *clickhouseQuerier) QueryHeavyHitters(ctx context.Context, req *HeavyHittersRequest) (*HeavyHittersResponse, error) {
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(`
-		SELECT Flow, LatestValue
+		SELECT Flow, SUM(LatestValue) AS TotalValue, max(LatestSampleRate) AS SampleRate
 		FROM (
 			SELECT
 				Flow,
-				argMax(Value, Timestamp) AS LatestValue
+				EngineID,
+				argMax(Value, Timestamp) AS LatestValue,
+				argMax(SampleRate, Timestamp) AS LatestSampleRate
 			FROM heavy_hitters
 	`)
 
@@ -215,9 +273,10 @@ func (q *clickhouseQuerier) QueryHeavyHitters(ctx context.Context, req *HeavyHit
 
 	queryBuilder.WriteString(" WHERE " + strings.Join(whereClauses, " AND "))
 	queryBuilder.WriteString(`
-			GROUP BY Flow
+			GROUP BY Flow, EngineID
 		)
-		ORDER BY LatestValue DESC
+		GROUP BY Flow
+		ORDER BY TotalValue DESC
 		LIMIT ?
 	`)
 	args = append(args, req.Limit)
@@ -234,20 +293,23 @@ func (q *clickhouseQuerier) QueryHeavyHitters(ctx context.Context, req *HeavyHit
 			hitter HeavyHitter
 			value  uint64
 		)
-		if err := rows.Scan(&hitter.Flow, &value); err != nil {
+		if err := rows.Scan(&hitter.Flow, &value, &hitter.SampleRate); err != nil {
 			return nil, fmt.Errorf("failed to scan heavy hitter row: %w", err)
 		}
 		hitter.Value, err = uint64ToInt64(value, "heavy_hitter.value")
 		if err != nil {
 			return nil, err
 		}
+		hitter.Estimated = hitter.SampleRate > 1
 		hitters = append(hitters, hitter)
 	}
 
 	return &HeavyHittersResponse{Hitters: hitters}, nil
 }
 
-// AggregateFlows builds and executes a dynamic aggregation query.
+// AggregateFlows builds and executes a dynamic aggregation query. The latest
+// snapshot of each flow is taken per engine and summed across engines, while
+// a flow seen by several engines still counts once.
 func (q *clickhouseQuerier) AggregateFlows(ctx context.Context, req *AggregationRequest) (*QueryTotalCountsResponse, error) {
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(`
@@ -255,19 +317,32 @@ func (q *clickhouseQuerier) AggregateFlows(ctx context.Context, req *Aggregation
 			TaskName,
 			SUM(LatestByteCount) AS TotalBytes,
 			SUM(LatestPacketCount) AS TotalPackets,
-			COUNT(*) AS FlowCount
+			uniqExact(FlowKey) AS FlowCount,
+			SUM(LatestSYNCount) AS TotalSYN,
+			SUM(LatestFINCount) AS TotalFIN,
+			SUM(LatestRSTCount) AS TotalRST,
+			max(LatestSampleRate) AS SampleRate
 		FROM (
 			SELECT
 				TaskName,
+				tuple(` + flowKeyColumns() + `) AS FlowKey,
 				argMax(ByteCount, Timestamp) AS LatestByteCount,
-				argMax(PacketCount, Timestamp) AS LatestPacketCount
+				argMax(PacketCount, Timestamp) AS LatestPacketCount,
+				argMax(SYNCount, Timestamp) AS LatestSYNCount,
+				argMax(FINCount, Timestamp) AS LatestFINCount,
+				argMax(RSTCount, Timestamp) AS LatestRSTCount,
+				argMax(SampleRate, Timestamp) AS LatestSampleRate,
+				argMax(ConnState, Timestamp) AS LatestConnState
 			FROM flow_metrics
 	`)
 
 	whereClauses := make([]string, 0, 7)
 	args := make([]any, 0, 7)
 
-	whereClauses, args = appendAggregationFilters(whereClauses, args, req)
+	whereClauses, args, err := appendAggregationFilters(whereClauses, args, req)
+	if err != nil {
+		return nil, err
+	}
 	if req.EndTime != nil {
 		whereClauses = append(whereClauses, "Timestamp <= ?")
 		args = append(args, *req.EndTime)
@@ -278,7 +353,14 @@ func (q *clickhouseQuerier) AggregateFlows(ctx context.Context, req *Aggregation
 	}
 
 	queryBuilder.WriteString(`
-			GROUP BY TaskName, SrcIP, DstIP, SrcPort, DstPort, Protocol
+			GROUP BY TaskName, ` + flowKeyColumns() + `, EngineID
+	`)
+	// The state filter applies to each flow's latest state, not to any historical snapshot row.
+	if req.ConnState != "" {
+		queryBuilder.WriteString(" HAVING LatestConnState = ?")
+		args = append(args, req.ConnState)
+	}
+	queryBuilder.WriteString(`
 		)
 		GROUP BY TaskName
 	`)
@@ -296,8 +378,11 @@ func (q *clickhouseQuerier) AggregateFlows(ctx context.Context, req *Aggregation
 			totalBytes   uint64
 			totalPackets uint64
 			flowCount    uint64
+			totalSYN     uint64
+			totalFIN     uint64
+			totalRST     uint64
 		)
-		if err := rows.Scan(&summary.TaskName, &totalBytes, &totalPackets, &flowCount); err != nil {
+		if err := rows.Scan(&summary.TaskName, &totalBytes, &totalPackets, &flowCount, &totalSYN, &totalFIN, &totalRST, &summary.SampleRate); err != nil {
 			return nil, fmt.Errorf("failed to scan aggregation result: %w", err)
 		}
 		summary.TotalBytes, err = uint64ToInt64(totalBytes, "aggregation.total_bytes")
@@ -312,22 +397,69 @@ func (q *clickhouseQuerier) AggregateFlows(ctx context.Context, req *Aggregation
 		if err != nil {
 			return nil, err
 		}
+		summary.SYNCount, err = uint64ToInt64(totalSYN, "aggregation.syn_count")
+		if err != nil {
+			return nil, err
+		}
+		summary.FINCount, err = uint64ToInt64(totalFIN, "aggregation.fin_count")
+		if err != nil {
+			return nil, err
+		}
+		summary.RSTCount, err = uint64ToInt64(totalRST, "aggregation.rst_count")
+		if err != nil {
+			return nil, err
+		}
+		summary.Estimated = summary.SampleRate > 1
 		summaries = append(summaries, summary)
 	}
 
 	return &QueryTotalCountsResponse{Summaries: summaries}, nil
 }
 
-// TraceFlow executes a query to trace the lifecycle of a single flow.
+// TraceFlow executes a query to trace the lifecycle of a single flow. The
+// cumulative counters are read per engine and then summed across engines.
 func (q *clickhouseQuerier) TraceFlow(ctx context.Context, req *TraceFlowRequest) (*FlowLifecycle, error) {
+	// Every snapshot row of a flow record carries its running totals, so the
+	// inner query keeps the latest of each record, told apart by engine and
+	// RecordID: a flow that expired or was reset starts a new record. Rows
+	// written before RecordID existed hold 0 and fall back to their start
+	// time. An engine counts each record once, and every engine of a cluster
+	// sees the lifecycles of a flow split across them, so Lifecycles is the
+	// most records any one engine holds.
 	var queryBuilder strings.Builder
 	queryBuilder.WriteString(`
 		SELECT
-			min(StartTime) AS FirstSeen,
-			max(EndTime) AS LastSeen,
-			max(PacketCount) AS TotalPackets,
-			max(ByteCount) AS TotalBytes
-		FROM flow_metrics
+			min(RecordFirstSeen) AS FirstSeen,
+			max(RecordLastSeen) AS LastSeen,
+			SUM(RecordPackets) AS TotalPackets,
+			SUM(RecordBytes) AS TotalBytes,
+			SUM(RecordSYN) AS TotalSYN,
+			SUM(RecordFIN) AS TotalFIN,
+			SUM(RecordRST) AS TotalRST,
+			SUM(RecordACK) AS TotalACK,
+			groupBitOr(RecordTCPFlags) AS TCPFlags,
+			argMax(RecordConnState, RecordLastSnapshot) AS ConnState,
+			max(RecordSampleRate) AS SampleRate,
+			max(EngineRecords) AS Lifecycles,
+			argMax(RecordEndReason, RecordLastSnapshot) AS EndReason
+		FROM (
+			SELECT
+				EngineID,
+				min(StartTime) AS RecordFirstSeen,
+				max(EndTime) AS RecordLastSeen,
+				max(PacketCount) AS RecordPackets,
+				max(ByteCount) AS RecordBytes,
+				max(SYNCount) AS RecordSYN,
+				max(FINCount) AS RecordFIN,
+				max(RSTCount) AS RecordRST,
+				max(ACKCount) AS RecordACK,
+				groupBitOr(TCPFlags) AS RecordTCPFlags,
+				argMax(ConnState, Timestamp) AS RecordConnState,
+				max(Timestamp) AS RecordLastSnapshot,
+				max(SampleRate) AS RecordSampleRate,
+				max(EndReason) AS RecordEndReason,
+				count() OVER (PARTITION BY EngineID) AS EngineRecords
+			FROM flow_metrics
 	`)
 
 	whereClauses := make([]string, 0, len(req.FlowKeys)+2)
@@ -349,14 +481,25 @@ func (q *clickhouseQuerier) TraceFlow(ctx context.Context, req *TraceFlowRequest
 	if len(whereClauses) > 0 {
 		queryBuilder.WriteString(" WHERE " + strings.Join(whereClauses, " AND "))
 	}
+	queryBuilder.WriteString(`
+			GROUP BY EngineID, RecordID, if(RecordID = 0, StartTime, toDateTime(0))
+		)
+	`)
 
 	var (
 		result       FlowLifecycle
 		totalPackets uint64
 		totalBytes   uint64
+		totalSYN     uint64
+		totalFIN     uint64
+		totalRST     uint64
+		totalACK     uint64
+		lifecycles   uint64
 	)
 	row := q.conn.QueryRow(ctx, queryBuilder.String(), args...)
-	if err := row.Scan(&result.FirstSeen, &result.LastSeen, &totalPackets, &totalBytes); err != nil {
+	if err := row.Scan(&result.FirstSeen, &result.LastSeen, &totalPackets, &totalBytes,
+		&totalSYN, &totalFIN, &totalRST, &totalACK, &result.TCPFlags, &result.ConnState, &result.SampleRate,
+		&lifecycles, &result.EndReason); err != nil {
 		return nil, fmt.Errorf("failed to scan flow lifecycle result: %w", err)
 	}
 	result.TotalPackets, err = uint64ToInt64(totalPackets, "trace.total_packets")
@@ -367,6 +510,94 @@ func (q *clickhouseQuerier) TraceFlow(ctx context.Context, req *TraceFlowRequest
 	if err != nil {
 		return nil, err
 	}
+	result.SYNCount, err = uint64ToInt64(totalSYN, "trace.syn_count")
+	if err != nil {
+		return nil, err
+	}
+	result.FINCount, err = uint64ToInt64(totalFIN, "trace.fin_count")
+	if err != nil {
+		return nil, err
+	}
+	result.RSTCount, err = uint64ToInt64(totalRST, "trace.rst_count")
+	if err != nil {
+		return nil, err
+	}
+	result.ACKCount, err = uint64ToInt64(totalACK, "trace.ack_count")
+	if err != nil {
+		return nil, err
+	}
+	result.Lifecycles, err = uint64ToInt64(lifecycles, "trace.lifecycles")
+	if err != nil {
+		return nil, err
+	}
+	result.Estimated = result.SampleRate > 1
 
 	return &result, nil
 }
+
+func appendInterfaceCounterFilters(whereClauses []string, args []any, req *InterfaceCountersRequest) ([]string, []any) {
+	if req.AgentIP != "" {
+		whereClauses = append(whereClauses, "AgentIP = ?")
+		args = append(args, req.AgentIP)
+	}
+	if req.IfIndex != nil {
+		whereClauses = append(whereClauses, "IfIndex = ?")
+		args = append(args, *req.IfIndex)
+	}
+	if req.EndTime != nil {
+		whereClauses = append(whereClauses, "Timestamp <= ?")
+		args = append(args, *req.EndTime)
+	}
+	return whereClauses, args
+}
+
+// QueryInterfaceCounters returns the latest sFlow counter sample of every
+// matching agent interface.
+func (q *clickhouseQuerier) QueryInterfaceCounters(ctx context.Context, req *InterfaceCountersRequest) (*InterfaceCountersResponse, error) {
+	var queryBuilder strings.Builder
+	queryBuilder.WriteString(`
+		SELECT
+			AgentIP,
+			IfIndex,
+			max(Timestamp) AS LastSample,
+			argMax(IfSpeed, Timestamp),
+			argMax(IfStatus, Timestamp),
+			argMax(InOctets, Timestamp),
+			argMax(InPackets, Timestamp),
+			argMax(InErrors, Timestamp),
+			argMax(InDiscards, Timestamp),
+			argMax(OutOctets, Timestamp),
+			argMax(OutPackets, Timestamp),
+			argMax(OutErrors, Timestamp),
+			argMax(OutDiscards, Timestamp)
+		FROM interface_counters
+	`)
+
+	whereClauses, args := appendInterfaceCounterFilters(nil, nil, req)
+	if len(whereClauses) > 0 {
+		queryBuilder.WriteString(" WHERE " + strings.Join(whereClauses, " AND "))
+	}
+	queryBuilder.WriteString(`
+		GROUP BY AgentIP, IfIndex
+		ORDER BY AgentIP, IfIndex
+	`)
+
+	rows, err := q.conn.Query(ctx, queryBuilder.String(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute interface counters query: %w", err)
+	}
+	defer rows.Close()
+
+	var counters []InterfaceCounters
+	for rows.Next() {
+		var c InterfaceCounters
+		if err := rows.Scan(&c.AgentIP, &c.IfIndex, &c.Timestamp, &c.Speed, &c.Status,
+			&c.InOctets, &c.InPackets, &c.InErrors, &c.InDiscards,
+			&c.OutOctets, &c.OutPackets, &c.OutErrors, &c.OutDiscards); err != nil {
+			return nil, fmt.Errorf("failed to scan interface counters row: %w", err)
+		}
+		counters = append(counters, c)
+	}
+
+	return &InterfaceCountersResponse{Counters: counters}, nil
+}
@@ -4,6 +4,7 @@ import (
 	"math"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestAppendTraceFlowFiltersSortsFlowKeys(t *testing.T) {
@@ -50,18 +51,42 @@ func TestAppendTraceFlowFiltersRejectsUnsupportedKeys(t *testing.T) {
 	}
 }
 
+func TestAppendTraceFlowFiltersRejectsPrefixKeys(t *testing.T) {
+	_, _, err := appendTraceFlowFilters(nil, nil, map[string]string{
+		"SrcIP/24": "10.0.0.0/24",
+	})
+	if err == nil {
+		t.Fatal("appendTraceFlowFilters(SrcIP/24) error = nil, want non-nil")
+	}
+
+	// The network itself is traced on the address column.
+	whereClauses, args, err := appendTraceFlowFilters(nil, nil, map[string]string{
+		"SrcIP": "10.0.0.0/24",
+	})
+	if err != nil {
+		t.Fatalf("appendTraceFlowFilters(SrcIP) unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(whereClauses, []string{"SrcIP = ?"}) || !reflect.DeepEqual(args, []any{"10.0.0.0/24"}) {
+		t.Fatalf("appendTraceFlowFilters(SrcIP) = %#v, %#v", whereClauses, args)
+	}
+}
+
 func TestAppendAggregationFiltersIncludesSupportedFields(t *testing.T) {
-	srcPort := int32(443)
-	protocol := int32(6)
 	req := &AggregationRequest{
 		TaskName: "demo-task",
-		SrcIP:    "10.0.0.1",
-		SrcPort:  &srcPort,
-		Protocol: &protocol,
+		FlowKeys: map[string]any{
+			"Protocol": int32(6),
+			"SrcPort":  int32(443),
+			"SrcIP":    "10.0.0.1",
+		},
 	}
 
-	whereClauses, args := appendAggregationFilters(nil, nil, req)
+	whereClauses, args, err := appendAggregationFilters(nil, nil, req)
+	if err != nil {
+		t.Fatalf("appendAggregationFilters() unexpected error: %v", err)
+	}
 
+	// Filters follow the column order of the key field registry.
 	wantClauses := []string{
 		"TaskName = ?",
 		"SrcIP = ?",
@@ -78,6 +103,78 @@ func TestAppendAggregationFiltersIncludesSupportedFields(t *testing.T) {
 	}
 }
 
+func TestAppendAggregationFiltersIncludesEncapsulationFields(t *testing.T) {
+	req := &AggregationRequest{
+		FlowKeys: map[string]any{
+			"ExporterIP":  "192.0.2.254",
+			"InterfaceID": int64(2),
+			"MPLSLabel":   int32(3000),
+			"InnerVLAN":   int32(200),
+			"OuterVLAN":   int32(100),
+			"TunnelID":    int64(5001),
+		},
+	}
+
+	whereClauses, args, err := appendAggregationFilters(nil, nil, req)
+	if err != nil {
+		t.Fatalf("appendAggregationFilters() unexpected error: %v", err)
+	}
+
+	wantClauses := []string{
+		"TunnelID = ?",
+		"OuterVLAN = ?",
+		"InnerVLAN = ?",
+		"MPLSLabel = ?",
+		"InterfaceID = ?",
+		"ExporterIP = ?",
+	}
+	if !reflect.DeepEqual(whereClauses, wantClauses) {
+		t.Fatalf("appendAggregationFilters() clauses = %#v, want %#v", whereClauses, wantClauses)
+	}
+
+	wantArgs := []any{int64(5001), int32(100), int32(200), int32(3000), int64(2), "192.0.2.254"}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("appendAggregationFilters() args = %#v, want %#v", args, wantArgs)
+	}
+}
+
+func TestAppendAggregationFiltersRejectsUnsupportedKeys(t *testing.T) {
+	for _, key := range []string{"DropTable", "SrcIP/24"} {
+		req := &AggregationRequest{FlowKeys: map[string]any{key: "x"}}
+		if _, _, err := appendAggregationFilters(nil, nil, req); err == nil {
+			t.Fatalf("appendAggregationFilters(%s) error = nil, want non-nil", key)
+		}
+	}
+}
+
+func TestFlowKeyColumnsFollowRegistry(t *testing.T) {
+	want := "SrcIP, DstIP, SrcPort, DstPort, Protocol, TunnelID, OuterVLAN, InnerVLAN, MPLSLabel, InterfaceID, ExporterIP"
+	if got := flowKeyColumns(); got != want {
+		t.Fatalf("flowKeyColumns() = %q, want %q", got, want)
+	}
+}
+
+func TestAppendInterfaceCounterFilters(t *testing.T) {
+	ifIndex := int64(7)
+	end := time.Unix(1700000000, 0)
+	req := &InterfaceCountersRequest{AgentIP: "192.0.2.1", IfIndex: &ifIndex, EndTime: &end}
+
+	whereClauses, args := appendInterfaceCounterFilters(nil, nil, req)
+
+	wantClauses := []string{"AgentIP = ?", "IfIndex = ?", "Timestamp <= ?"}
+	if !reflect.DeepEqual(whereClauses, wantClauses) {
+		t.Fatalf("appendInterfaceCounterFilters() clauses = %#v, want %#v", whereClauses, wantClauses)
+	}
+	wantArgs := []any{"192.0.2.1", int64(7), end}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Fatalf("appendInterfaceCounterFilters() args = %#v, want %#v", args, wantArgs)
+	}
+
+	if whereClauses, _ := appendInterfaceCounterFilters(nil, nil, &InterfaceCountersRequest{}); len(whereClauses) != 0 {
+		t.Fatalf("appendInterfaceCounterFilters(empty) clauses = %#v, want none", whereClauses)
+	}
+}
+
 func TestUint64ToInt64(t *testing.T) {
 	got, err := uint64ToInt64(42, "demo")
 	if err != nil {
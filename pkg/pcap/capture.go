@@ -0,0 +1,139 @@
+package pcap
+
+import (
+	"fmt"
+
+	"Go2NetSpectra/internal/config"
+
+	"github.com/google/gopacket/layers"
+	"github.com/google/gopacket/pcap"
+)
+
+const (
+	// DefaultSnapLen is the number of bytes captured per packet when none is configured.
+	DefaultSnapLen int32 = 1600
+	maxSnapLen     int32 = 262144
+)
+
+// linkTypeNames lists the data link types an interface can be switched to;
+// all of them are understood by protocol.Decoder.
+var linkTypeNames = map[string]layers.LinkType{
+	"ethernet":  layers.LinkTypeEthernet,
+	"linux_sll": layers.LinkTypeLinuxSLL,
+	"raw":       layers.LinkTypeRaw,
+	"null":      layers.LinkTypeNull,
+	"loop":      layers.LinkTypeLoop,
+	"ipv4":      layers.LinkTypeIPv4,
+	"ipv6":      layers.LinkTypeIPv6,
+}
+
+// ValidateCapture checks the capture settings, compiling the BPF filter so a
+// bad expression is reported before any handle is opened.
+func ValidateCapture(cfg config.CaptureConfig) error {
+	if cfg.SnapLen < 0 || cfg.SnapLen > maxSnapLen {
+		return fmt.Errorf("invalid snaplen %d, want 0..%d", cfg.SnapLen, maxSnapLen)
+	}
+	if cfg.BufferSize < 0 {
+		return fmt.Errorf("invalid buffer size %d, want >= 0", cfg.BufferSize)
+	}
+	return validateFilter(cfg.BPFFilter, layers.LinkTypeEthernet, cfg)
+}
+
+// ValidateInterface checks the per-interface overrides on top of the shared capture settings.
+func ValidateInterface(iface config.InterfaceConfig, cfg config.CaptureConfig) error {
+	if iface.Name == "" {
+		return fmt.Errorf("interface name is empty")
+	}
+	linkType := layers.LinkTypeEthernet
+	if iface.LinkType != "" {
+		var err error
+		if linkType, err = parseLinkType(iface.LinkType); err != nil {
+			return fmt.Errorf("interface %s: %w", iface.Name, err)
+		}
+	}
+	if err := validateFilter(interfaceFilter(iface, cfg), linkType, cfg); err != nil {
+		return fmt.Errorf("interface %s: %w", iface.Name, err)
+	}
+	return nil
+}
+
+// OpenInterface opens a live capture on iface using the snaplen, promiscuous
+// mode and buffer size from cfg, then applies the interface's link type and
+// BPF filter.
+func OpenInterface(iface config.InterfaceConfig, cfg config.CaptureConfig) (*pcap.Handle, error) {
+	inactive, err := pcap.NewInactiveHandle(iface.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer inactive.CleanUp()
+
+	if err := inactive.SetSnapLen(int(snapLen(cfg))); err != nil {
+		return nil, fmt.Errorf("set snaplen: %w", err)
+	}
+	if err := inactive.SetPromisc(cfg.PromiscuousEnabled()); err != nil {
+		return nil, fmt.Errorf("set promiscuous mode: %w", err)
+	}
+	if err := inactive.SetTimeout(pcap.BlockForever); err != nil {
+		return nil, fmt.Errorf("set timeout: %w", err)
+	}
+	if cfg.BufferSize > 0 {
+		if err := inactive.SetBufferSize(cfg.BufferSize); err != nil {
+			return nil, fmt.Errorf("set buffer size: %w", err)
+		}
+	}
+
+	handle, err := inactive.Activate()
+	if err != nil {
+		return nil, err
+	}
+	if iface.LinkType != "" {
+		linkType, err := parseLinkType(iface.LinkType)
+		if err == nil {
+			err = handle.SetLinkType(linkType)
+		}
+		if err != nil {
+			handle.Close()
+			return nil, fmt.Errorf("set link type %q: %w", iface.LinkType, err)
+		}
+	}
+	// The filter is compiled for the handle's link type, so it goes on last.
+	if filter := interfaceFilter(iface, cfg); filter != "" {
+		if err := handle.SetBPFFilter(filter); err != nil {
+			handle.Close()
+			return nil, fmt.Errorf("invalid BPF filter %q: %w", filter, err)
+		}
+	}
+	return handle, nil
+}
+
+func validateFilter(filter string, linkType layers.LinkType, cfg config.CaptureConfig) error {
+	if filter == "" {
+		return nil
+	}
+	if _, err := pcap.CompileBPFFilter(linkType, int(snapLen(cfg)), filter); err != nil {
+		return fmt.Errorf("invalid BPF filter %q: %w", filter, err)
+	}
+	return nil
+}
+
+func interfaceFilter(iface config.InterfaceConfig, cfg config.CaptureConfig) string {
+	if iface.BPFFilter != "" {
+		return iface.BPFFilter
+	}
+	return cfg.BPFFilter
+}
+
+func parseLinkType(name string) (layers.LinkType, error) {
+	linkType, ok := linkTypeNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown link type %q", name)
+	}
+	return linkType, nil
+}
+
+func snapLen(cfg config.CaptureConfig) int32 {
+	if cfg.SnapLen == 0 {
+		return DefaultSnapLen
+	}
+	return cfg.SnapLen
+}
@@ -1,7 +1,10 @@
 package pcap
 
 import (
+	"fmt"
+	"io"
 	"log"
+	"net"
 
 	"Go2NetSpectra/internal/model"
 	"Go2NetSpectra/internal/protocol"
@@ -13,6 +16,7 @@ import (
 // Reader reads packets from a pcap file.
 type Reader struct {
 	handle        *pcap.Handle
+	parser        *protocol.Parser
 	total, failed int
 }
 
@@ -25,6 +29,22 @@ func NewReader(filePath string) (*Reader, error) {
 	return &Reader{handle: handle, total: 0, failed: 0}, nil
 }
 
+// SetParser replaces the packet parser, e.g. to decapsulate tunnels. A nil parser restores the default.
+func (r *Reader) SetParser(parser *protocol.Parser) {
+	r.parser = parser
+}
+
+// SetFilter applies a BPF filter to the packets read from the file. An empty expression keeps every packet.
+func (r *Reader) SetFilter(expr string) error {
+	if expr == "" {
+		return nil
+	}
+	if err := r.handle.SetBPFFilter(expr); err != nil {
+		return fmt.Errorf("invalid BPF filter %q: %w", expr, err)
+	}
+	return nil
+}
+
 // Close closes the pcap handle.
 func (r *Reader) Close() {
 	r.handle.Close()
@@ -32,18 +52,72 @@ func (r *Reader) Close() {
 
 // ReadPackets reads all packets from the pcap file and sends the parsed
 // PacketInfo to the provided channel.
+//
+// Frames are decoded with a reusable protocol.Decoder; link types it does not
+// support fall back to building a full gopacket.Packet per frame.
 func (r *Reader) ReadPackets(out chan<- *model.PacketInfo) {
 	defer func() {
 		log.Println("Total packets read:", r.total, "Failed to parse:", r.failed)
 	}()
+
+	decoder, err := r.newDecoder()
+	if err != nil {
+		log.Printf("Falling back to gopacket decoding: %v", err)
+		r.readPacketsSlow(out)
+		return
+	}
+
+	for {
+		data, ci, err := r.handle.ZeroCopyReadPacketData()
+		if err != nil {
+			if err != io.EOF {
+				log.Printf("Error reading packet: %v", err)
+			}
+			return
+		}
+		r.total++
+
+		// The slot keeps the PacketInfo and its addresses in a single allocation.
+		slot := &packetSlot{}
+		slot.info.FiveTuple.SrcIP = slot.addrs[:0:net.IPv6len]
+		slot.info.FiveTuple.DstIP = slot.addrs[net.IPv6len:net.IPv6len]
+		if err := decoder.DecodeInto(data, ci, &slot.info); err != nil {
+			r.failed++
+			continue
+		}
+		out <- &slot.info
+	}
+}
+
+// packetSlot backs a PacketInfo sent by ReadPackets together with room for its addresses.
+type packetSlot struct {
+	info  model.PacketInfo
+	addrs [2 * net.IPv6len]byte
+}
+
+func (r *Reader) newDecoder() (*protocol.Decoder, error) {
+	if r.parser == nil {
+		return protocol.NewDecoder(r.handle.LinkType())
+	}
+	return r.parser.NewDecoder(r.handle.LinkType())
+}
+
+func (r *Reader) readPacketsSlow(out chan<- *model.PacketInfo) {
 	packetSource := gopacket.NewPacketSource(r.handle, r.handle.LinkType())
 	for packet := range packetSource.Packets() {
 		r.total++
 		var packetInfo model.PacketInfo
-		if err := protocol.ParsePacketInto(packet, &packetInfo); err != nil {
+		if err := r.parsePacketInto(packet, &packetInfo); err != nil {
 			r.failed++
 			continue
 		}
 		out <- &packetInfo
 	}
 }
+
+func (r *Reader) parsePacketInto(packet gopacket.Packet, info *model.PacketInfo) error {
+	if r.parser == nil {
+		return protocol.ParsePacketInto(packet, info)
+	}
+	return r.parser.ParseInto(packet, info)
+}
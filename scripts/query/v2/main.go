@@ -18,17 +18,19 @@ const queryClientTransportBufferSize = 32 * 1024
 func main() {
 	// Command-line flags
 	serverAddr := flag.String("addr", "localhost:50051", "The gRPC server address")
-	mode := flag.String("mode", "heavyhitters", "Query mode: 'aggregate', 'trace', 'heavyhitters', or 'superspreader'")
+	mode := flag.String("mode", "heavyhitters", "Query mode: 'aggregate', 'trace', 'heavyhitters', 'superspreader', or 'counters'")
 	taskName := flag.String("task", "", "The name of the task to query")
 	flowKey := flag.String("key", "", "The flow key for trace mode (e.g., \"SrcIP=1.2.3.4,DstPort=443\")")
 	hhType := flag.Int("type", 0, "Query type for heavyhitters (0 for count, 1 for size)")
 	limit := flag.Int("limit", 10, "Limit for heavy hitters/super spreader query")
+	connState := flag.String("state", "", "Only aggregate flows in this TCP state (e.g., syn_sent, established, reset)")
+	agent := flag.String("agent", "", "Only show interface counters of this sFlow agent (counters mode)")
 	defaultEnd := time.Now().UTC().Add(8 * time.Hour).Format(time.RFC3339)
 	endTimeStr := flag.String("end", defaultEnd, "End time in RFC3339 format (e.g., 2025-09-12T15:10:00Z).")
 
 	flag.Parse()
 
-	if *taskName == "" && *mode != "aggregate" {
+	if *taskName == "" && *mode != "aggregate" && *mode != "counters" {
 		log.Fatal("error: -task flag is required for this mode")
 	}
 
@@ -44,7 +46,7 @@ func main() {
 
 	switch *mode {
 	case "aggregate":
-		doAggregateQuery(ctx, client, *taskName, *endTimeStr)
+		doAggregateQuery(ctx, client, *taskName, *connState, *endTimeStr)
 	case "trace":
 		if *flowKey == "" {
 			log.Fatal("error: -key flag is required for trace mode")
@@ -54,13 +56,15 @@ func main() {
 		doHeavyHittersQuery(ctx, client, *taskName, *hhType, *limit, *endTimeStr)
 	case "superspreader":
 		doSuperSpreaderQuery(ctx, client, *taskName, *limit, *endTimeStr)
+	case "counters":
+		doInterfaceCountersQuery(ctx, client, *agent, *endTimeStr)
 	default:
-		log.Fatalf("unknown mode %q; use 'aggregate', 'trace', 'heavyhitters', or 'superspreader'", *mode)
+		log.Fatalf("unknown mode %q; use 'aggregate', 'trace', 'heavyhitters', 'superspreader', or 'counters'", *mode)
 	}
 }
 
 // doAggregateQuery performs an aggregation query.
-func doAggregateQuery(ctx context.Context, client *v1.QueryServiceClient, taskName string, endTime string) {
+func doAggregateQuery(ctx context.Context, client *v1.QueryServiceClient, taskName, connState string, endTime string) {
 	log.Printf("Executing aggregation query for task: %s", taskName)
 	log.Printf("Query params - End time: %s", endTime)
 
@@ -70,6 +74,9 @@ func doAggregateQuery(ctx context.Context, client *v1.QueryServiceClient, taskNa
 	if taskName != "" {
 		req.TaskName = &taskName
 	}
+	if connState != "" {
+		req.ConnState = &connState
+	}
 
 	resp, err := client.AggregateFlows(ctx, req)
 	if err != nil {
@@ -86,6 +93,10 @@ func doAggregateQuery(ctx context.Context, client *v1.QueryServiceClient, taskNa
 		log.Printf("    Total Flows:   %d", summary.FlowCount)
 		log.Printf("    Total Packets: %d", summary.TotalPackets)
 		log.Printf("    Total Bytes:   %d", summary.TotalBytes)
+		log.Printf("    SYN/FIN/RST:   %d/%d/%d", summary.GetSynCount(), summary.GetFinCount(), summary.GetRstCount())
+		if summary.GetEstimated() {
+			log.Printf("    Estimated:     scaled up from 1-in-%d sampling", summary.GetSampleRate())
+		}
 	}
 	log.Println("---------------------------")
 }
@@ -118,6 +129,17 @@ func doTraceQuery(ctx context.Context, client *v1.QueryServiceClient, taskName,
 	log.Printf("  Last Seen:     %s", time.Unix(0, resp.LastSeenUnixNano).Format(time.RFC3339))
 	log.Printf("  Total Packets: %d", resp.TotalPackets)
 	log.Printf("  Total Bytes:   %d", resp.TotalBytes)
+	log.Printf("  TCP Flags:     %#02x (SYN %d, FIN %d, RST %d, ACK %d)",
+		resp.GetTCPFlags(), resp.GetSynCount(), resp.GetFinCount(), resp.GetRstCount(), resp.GetAckCount())
+	log.Printf("  Conn State:    %s", resp.GetConnState())
+	endReason := resp.GetEndReason()
+	if endReason == "" {
+		endReason = "in progress"
+	}
+	log.Printf("  Records:       %d (latest: %s)", resp.GetLifecycles(), endReason)
+	if resp.GetEstimated() {
+		log.Printf("  Estimated:     scaled up from 1-in-%d sampling", resp.GetSampleRate())
+	}
 	log.Println("-----------------------------")
 }
 
@@ -164,7 +186,7 @@ func doHeavyHittersQuery(ctx context.Context, client *v1.QueryServiceClient, tas
 	log.Printf("% -4s | % -40s | %s", "Rank", "Flow", "Value")
 	log.Println(strings.Repeat("-", 60))
 	for i, hitter := range resp.Hitters {
-		log.Printf("% -4d | % -40s | %d", i+1, hitter.Flow, hitter.Value)
+		log.Printf("% -4d | % -40s | %d%s", i+1, hitter.Flow, hitter.Value, estimateSuffix(hitter))
 	}
 	log.Println("-----------------------------")
 }
@@ -195,11 +217,47 @@ func doSuperSpreaderQuery(ctx context.Context, client *v1.QueryServiceClient, ta
 	log.Printf("% -4s | % -40s | %s", "Rank", "Flow", "Spread (Cardinality)")
 	log.Println(strings.Repeat("-", 60))
 	for i, hitter := range resp.Hitters {
-		log.Printf("% -4d | % -40s | %d", i+1, hitter.Flow, hitter.Value)
+		log.Printf("% -4d | % -40s | %d%s", i+1, hitter.Flow, hitter.Value, estimateSuffix(hitter))
 	}
 	log.Println("------------------------------")
 }
 
+// doInterfaceCountersQuery prints the latest sFlow counters of every agent interface.
+func doInterfaceCountersQuery(ctx context.Context, client *v1.QueryServiceClient, agent, endTime string) {
+	log.Printf("Executing interface counters query for agent: %q", agent)
+	log.Printf("Query params - End time: %s", endTime)
+
+	req := &v1.InterfaceCountersRequest{EndTimeUnixNano: parseAndConvert(endTime)}
+	if agent != "" {
+		req.AgentIP = &agent
+	}
+
+	resp, err := client.QueryInterfaceCounters(ctx, req)
+	if err != nil {
+		log.Fatalf("could not perform interface counters query: %v", err)
+	}
+
+	log.Printf("--- Interface Counters ---")
+	if len(resp.Counters) == 0 {
+		log.Println("No data returned.")
+		return
+	}
+	log.Printf("% -20s | % -8s | % -16s | % -16s | % -10s | %s", "Agent", "IfIndex", "In Octets", "Out Octets", "In Errors", "Out Errors")
+	log.Println(strings.Repeat("-", 90))
+	for _, c := range resp.Counters {
+		log.Printf("% -20s | % -8d | % -16d | % -16d | % -10d | %d", c.AgentIP, c.IfIndex, c.InOctets, c.OutOctets, c.InErrors, c.OutErrors)
+	}
+	log.Println("--------------------------")
+}
+
+// estimateSuffix marks values computed from sampled traffic.
+func estimateSuffix(hitter *v1.HeavyHitter) string {
+	if !hitter.GetEstimated() {
+		return ""
+	}
+	return fmt.Sprintf(" (est., 1-in-%d)", hitter.GetSampleRate())
+}
+
 func parseAndConvert(endTimeStr string) *int64 {
 	t, err := time.Parse(time.RFC3339, endTimeStr)
 	if err != nil {
@@ -0,0 +1,2 @@
+// Command reload asks a running ns-engine to reload its config file through the admin RPC.
+package main
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	v1 "Go2NetSpectra/api/gen/thrift/v1"
+
+	thrift "github.com/apache/thrift/lib/go/thrift"
+)
+
+const adminClientTransportBufferSize = 32 * 1024
+
+func main() {
+	serverAddr := flag.String("addr", "localhost:50053", "The ns-engine admin RPC address (aggregator.admin_listen_addr)")
+	flag.Parse()
+
+	client, transport, err := newAdminClient(*serverAddr)
+	if err != nil {
+		log.Fatalf("failed to connect to %s: %v", *serverAddr, err)
+	}
+	defer transport.Close()
+
+	// Final snapshots of removed tasks and writers are taken before the reply.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	resp, err := client.ReloadConfig(ctx, &v1.ReloadConfigRequest{})
+	if err != nil {
+		log.Fatalf("ReloadConfig failed: %v", err)
+	}
+	if !resp.GetApplied() {
+		log.Fatalf("config rejected, ns-engine keeps the running one: %s", resp.GetErrorText())
+	}
+
+	fmt.Printf("Tasks added:   %s\n", strings.Join(resp.GetTasksAdded(), ", "))
+	fmt.Printf("Tasks removed: %s\n", strings.Join(resp.GetTasksRemoved(), ", "))
+	fmt.Printf("Tasks kept:    %s\n", strings.Join(resp.GetTasksKept(), ", "))
+	fmt.Printf("Writers: %d started, %d stopped, %d kept\n", resp.GetWritersStarted(), resp.GetWritersStopped(), resp.GetWritersKept())
+	fmt.Printf("Alert rules: %d\n", resp.GetAlertRules())
+	for _, warning := range resp.GetWarnings() {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+}
+
+func newAdminClient(addr string) (*v1.AdminServiceClient, thrift.TTransport, error) {
+	conf := &thrift.TConfiguration{
+		ConnectTimeout: 5 * time.Second,
+		SocketTimeout:  time.Minute,
+	}
+	socket := thrift.NewTSocketConf(addr, conf)
+	transportFactory := thrift.NewTBufferedTransportFactory(adminClientTransportBufferSize)
+	transport, err := transportFactory.GetTransport(socket)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build thrift transport: %w", err)
+	}
+	if err := transport.Open(); err != nil {
+		return nil, nil, fmt.Errorf("open thrift transport: %w", err)
+	}
+
+	protocolFactory := thrift.NewTBinaryProtocolFactoryConf(conf)
+	return v1.NewAdminServiceClientFactory(transport, protocolFactory), transport, nil
+}